@@ -24,6 +24,7 @@ import (
 
 	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/bottle"
 	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/git"
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/key"
 	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/mirror"
 	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/oci"
 	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/pypi"
@@ -73,6 +74,7 @@ The first configuration file present is used.  Others are ignored.
 		newLoginCmd(action),
 		newLogoutCmd(action),
 		newConfigCmd(action),
+		key.NewKeyCmd(action),
 		newCompletionCmd(),
 		bottle.NewBottleCmd(action),
 		newUtilCmd(action),
@@ -53,9 +53,16 @@ Use part command to add part level labels.
 		newPushCmd(action),
 		newShowCmd(action),
 		newPullCmd(action),
+		newExportCmd(action),
+		newImportCmd(action),
 		newEditCmd(action),
 		newDeleteCmd(action),
 		newStatusCmd(action),
+		newLogCmd(action),
+		newCheckoutCmd(action),
+		newWatchCmd(action),
+		newLintCmd(action),
+		newReportCmd(action),
 		newGuiCmd(action),
 		newLabelCmd(action),
 		newBtlAnnotateCmd(action),
@@ -67,6 +74,9 @@ Use part command to add part level labels.
 		newBtlDescribeCmd(action),
 		newVerifyCmd(action),
 		newSignCmd(action),
+		newRevocationCmd(action),
+		newServeCmd(action),
+		newLineageCmd(action),
 	)
 	return cmd
 }
@@ -79,6 +89,16 @@ func PartSelectorFlags(flags *pflag.FlagSet, action *bottle.PartSelectorOptions)
 	flags.StringArrayVarP(&action.Artifacts, "artifact", "u", []string{}, "Retrieve only parts containing the provided public artifact type")
 }
 
+// EncryptionFlags adds flags for encrypting bottle parts.
+func EncryptionFlags(flags *pflag.FlagSet, action *bottleactions.EncryptionOptions) {
+	flags.StringArrayVar(&action.Recipients, "encrypt-for", []string{},
+		"Encrypt parts for a recipient, either the alias of a configured encryption key or a public key or certificate file")
+	flags.StringArrayVar(&action.Selectors, "encrypt-selector", []string{},
+		"Only encrypt parts matching the label selector. Format \"name=value\"")
+	flags.StringArrayVar(&action.Parts, "encrypt-part", []string{}, "Only encrypt the named parts")
+	flags.BoolVar(&action.Decrypt, "no-encryption", false, "Remove the encryption from all parts")
+}
+
 // CompressionLevelFlags adds a flag for changing the default compression level for part compression.
 func CompressionLevelFlags(flags *pflag.FlagSet, action *bottleactions.CompressionLevelOptions) {
 	flags.StringVarP(&action.Level, "compression-level", "z", "",
@@ -0,0 +1,51 @@
+/*
+Command checkout
+*/
+
+package bottle
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+)
+
+// newCheckoutCmd represents the checkout command.
+func newCheckoutCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Checkout{Action: tool}
+	uiOptions := ui.Options{}
+
+	cmd := &cobra.Command{
+		GroupID: "basic",
+		Use:     "checkout COMMIT",
+		Short:   "Restore a previous commit of a bottle from the local history",
+		Long: `Restores the parts and metadata of a bottle to a commit of its local history, see "ace-dt bottle log".
+COMMIT is the index of the commit, where 0 is the most recent commit, or its bottle ID, which can be abbreviated
+to a unique prefix of the digest.
+
+The parts are restored from the cache.  If the cache no longer holds every part of the commit, nothing is changed and
+the bottle must be pulled instead.  Checking out fails if parts have uncommitted changes, unless --force is given to
+discard them.  The next commit deprecates the checked out bottle, as usual.`,
+		Example: `
+Restore the commit before the most recent one:
+	ace-dt bottle checkout 1
+
+Restore a commit by bottle ID:
+	ace-dt bottle checkout sha256:84f8aac3c87f8663049d5bed1fe7a952f47b5f346ad9e22d5d5261f9e6383c76
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, args[0])
+			})
+		},
+	}
+
+	cmd.Flags().BoolVarP(&action.Force, "force", "f", false, "Discard uncommitted changes to the parts of the bottle")
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+
+	return cmd
+}
@@ -28,16 +28,61 @@ parts of a bottle to be processed and added to the bottle metadata.
 
 Commits will automatically deprecate the previous version (bottleID) of this bottle.
 This can be disabled by passing the --no-deprecate flag.
+
+By default directory parts only contain regular files and directories, symbolic links are followed and
+permissions are reduced to whether a file is executable.  The --fidelity flag archives directory parts in
+fidelity mode instead, preserving symbolic links, hard links (stored once) and exact permission bits.
+Symbolic links must resolve within the bottle.  With --fidelity=xattrs extended attributes are also preserved.
+The fidelity level is remembered for each part, and --fidelity=none returns to the default.
+Parts committed in fidelity mode can only be pulled by versions of ace-dt that support it.
+
+Parts are encrypted for the recipients given with --encrypt-for, following the OCI image encryption conventions.
+A recipient is the alias of a key in the encryptionKeys of the ace-dt configuration, or a PEM encoded public key or
+X.509 certificate file.  All parts are encrypted unless --encrypt-selector or --encrypt-part select a subset.  The
+bottle metadata, including part labels, remains unencrypted so parts can still be selected when pulling.  Encrypted
+parts stay encrypted in later commits, and --no-encryption removes the encryption.  Pulling an encrypted part
+requires one of the private keys in the encryptionKeys of the ace-dt configuration.
+
+With --report a report of the committed bottle is rendered into a file at the root of the bottle, see "ace-dt bottle
+report", which is then committed as a public artifact named "report".
+
+The label rules file at the root of the bottle, .label-rules.yaml, if there is one, labels the parts matching its rules.
+Rules match part names by glob or regular expression and parts by extension, media type and size, and set or remove
+labels.  Label values may be computed from the part attributes, such as {{ .Extension }}, {{ .MediaType }} and
+{{ .SizeBucket }}.  Rules apply in order and override labels set with "ace-dt bottle part label".  See
+"ace-dt bottle part list" to preview the labels.
+
+With --stats the statistics of the parts are computed and recorded in the bottle manifest: the number and total size
+of files, counts by extension and by media type, detected from the extension or content, and the rows and columns of
+CSV, JSON Lines and Parquet files.  Statistics are cached by the content digest of the part, so unchanged parts are not
+scanned again, and parts keep them in later commits until their content changes.  The statistics are shown by "ace-dt
+bottle show" and available to part selectors as the labels stats.files, stats.bytes, stats.type (the most common
+media type, with "/" replaced by "-"), stats.rows and stats.columns, for example --selector "stats.rows>1000".
+
+Each commit that changes the bottle is recorded in the local history of the bottle, with the --message if given.
+See "ace-dt bottle log" and "ace-dt bottle checkout".
 `,
 		Example: `
 Commit from current working directory:
 	ace-dt bottle commit
 
+Commit with a message for the local history:
+	ace-dt bottle commit -m "Remove duplicate samples"
+
 Commit a bottle at path <TESTSET>:
 	ace-dt bottle commit -d TESTSET
 
 View information prior to commit: 
 	ace-dt bottle status
+
+Commit preserving symbolic links, hard links and permissions in directory parts:
+	ace-dt bottle commit --fidelity
+
+Commit encrypting the parts labelled "export=controlled" for the configured key "exportControlled":
+	ace-dt bottle commit --encrypt-for exportControlled --encrypt-selector export=controlled
+
+Commit attaching a Markdown datasheet of the bottle as a public artifact:
+	ace-dt bottle commit --report markdown
 `,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -48,10 +93,23 @@ View information prior to commit:
 	}
 
 	CompressionLevelFlags(cmd.Flags(), &action.Compression)
+	EncryptionFlags(cmd.Flags(), &action.Encryption)
 
 	// Add flag no-deprecate to disable deprecation
 	cmd.Flags().BoolVar(&action.NoDeprecate, "no-deprecate", false, "Disable deprecation of previous bottle version")
 
+	cmd.Flags().StringVarP(&action.Message, "message", "m", "", "Message describing the commit in the local bottle history")
+	cmd.Flags().BoolVar(&action.Stats, "stats", false, "Compute statistics of the parts, such as file counts, media types and rows")
+
+	cmd.Flags().StringVar(&action.Fidelity, "fidelity", "",
+		`Archive directory parts preserving links and permissions, one of "links", "xattrs" or "none"`)
+	cmd.Flags().Lookup("fidelity").NoOptDefVal = "links"
+
+	cmd.Flags().StringVar(&action.Report.Format, "report", "",
+		`Attach a report of the bottle as a public artifact, one of "markdown" or "html"`)
+	cmd.Flags().StringVar(&action.Report.Template, "report-template", "",
+		"Path to a Go template used for the attached report instead of the built-in one")
+
 	// Add flag overrides function to override config with flags
 	action.Config.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
 		if action.Compression.Level != "" {
@@ -4,10 +4,17 @@
 package bottle
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
+	telemv1alpha2 "github.com/act3-ai/data-telemetry/v3/pkg/apis/config.telemetry.act3-ace.io/v1alpha2"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/flag"
 	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/oci"
 	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/go-common/pkg/redact"
 )
 
 // deleteCmd represents the delete command.
@@ -20,22 +27,53 @@ func newDeleteCmd(tool *actions.Action) *cobra.Command {
 		Short:   "Remove a bottle from remote oci storage",
 		Long: `Remove a data bottle from remote oci storage, based on
 the data bottle name and tag.
-This will remove the tag's manifest, which will orphan
+This will remove the bottle's manifest, every tag of the
+repository pointing at it, and its referrers, such as
+signatures and attached artifacts, which will orphan
 the data bottle's blobs, or parts, which will be deleted
 upon a routine garbage collection cycle.
+
+Referrers are left in place with --keep-referrers.  With
+--dry-run nothing is deleted, and the referrers, tags and
+manifest that would be deleted are listed instead.
+
+A warning is shown for each bottle listing the deleted
+bottle as a source, as known by the telemetry servers,
+the other bottles of the repository and the local bottle.
+Such bottles can no longer be traced to their sources.
 	
 A bottle reference uses one of the forms
   by tag                <registry>/<repository>/<name>:<tag>
   by name (latest tag)  <registry>/<repository>/<name>
   by digest             <registry>/<repository>/<name>@sha256:<sha>
 `,
+		Example: `Show what deleting a bottle would remove:
+  ace-dt bottle delete REG/REPO/MNIST:v1 --dry-run
+
+Delete a bottle, keeping its signatures:
+  ace-dt bottle delete REG/REPO/MNIST:v1 --keep-referrers`,
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: oci.RefCompletion(action.DataTool),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			action.Ref = args[0]
-			return action.Run(cmd.Context())
+			return action.Run(cmd.Context(), cmd.OutOrStdout())
 		},
 	}
 
+	cmd.Flags().BoolVar(&action.DryRun, "dry-run", false, "List what would be deleted without deleting anything")
+	cmd.Flags().BoolVar(&action.KeepReferrers, "keep-referrers", false,
+		"Leave the referrers of the bottle, such as signatures, in place")
+	flag.TelemetryURLFlags(cmd.Flags(), &action.Telemetry)
+
+	// Add flag overrides function to override config with flags
+	action.Config.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
+		if action.Telemetry.URL != "" {
+			c.Telemetry = []telemv1alpha2.Location{
+				{URL: redact.SecretURL(action.Telemetry.URL)},
+			}
+		}
+		return nil
+	})
+
 	return cmd
 }
@@ -0,0 +1,69 @@
+/* Command export
+ */
+
+package bottle
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	telemv1alpha2 "github.com/act3-ai/data-telemetry/v3/pkg/apis/config.telemetry.act3-ace.io/v1alpha2"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/flag"
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/go-common/pkg/redact"
+)
+
+// newExportCmd represents the export command.
+func newExportCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Export{Action: tool}
+	uiOptions := ui.Options{}
+
+	cmd := &cobra.Command{
+		GroupID: "remote",
+		Use:     "export {BOTTLE_REFERENCE|BOTTLE_DIR} FILE",
+		Short:   "Exports a bottle to an OCI image layout or tarball",
+		Long: `Exports a bottle, with its manifest, config, parts, signatures and other referrers, to an OCI image layout.
+The layout is written as a tarball if FILE ends in ".tar", and as a directory otherwise.  An existing layout
+directory is added to.
+
+The bottle is either a local bottle directory, which is committed first like with "ace-dt bottle push", or a
+reference to a bottle in a registry.  Parts can be selected with the same flags as "ace-dt bottle pull", leaving the
+other parts out of the layout.  Bottles exported with a subset of their parts can only be imported into a bottle
+directory, with a matching part selection.
+
+The exported bottle is tagged in the layout with --tag, "latest" by default.  Use "ace-dt bottle import" to push the
+bottle to a registry or to pull it into a bottle directory.`,
+		Example: `Export the bottle in the current directory to a tarball:
+  ace-dt bottle export . mnist.tar
+
+Export the training parts of a remote bottle to an OCI layout directory:
+  ace-dt bottle export REG/REPO/MNIST:v1 mnist-layout --selector split=train`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, args[0], args[1])
+			})
+		},
+	}
+
+	PartSelectorFlags(cmd.Flags(), &action.PartSelector)
+	cmd.Flags().StringVar(&action.Tag, "tag", "latest", "Tag of the bottle in the OCI image layout")
+	flag.TelemetryURLFlags(cmd.Flags(), &action.Telemetry)
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+
+	// Add flag overrides function to override config with flags
+	action.Config.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
+		if action.Telemetry.URL != "" {
+			c.Telemetry = []telemv1alpha2.Location{
+				{URL: redact.SecretURL(action.Telemetry.URL)},
+			}
+		}
+		return nil
+	})
+
+	return cmd
+}
@@ -0,0 +1,54 @@
+/* Command import
+ */
+
+package bottle
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+)
+
+// newImportCmd represents the import command.
+func newImportCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Import{Action: tool}
+	uiOptions := ui.Options{}
+
+	cmd := &cobra.Command{
+		GroupID: "remote",
+		Use:     "import FILE [BOTTLE_REFERENCE]",
+		Short:   "Imports a bottle from an OCI image layout or tarball",
+		Long: `Imports a bottle exported with "ace-dt bottle export" from an OCI image layout directory or tarball.
+
+With a bottle reference the bottle, with its signatures and other referrers, is pushed to the registry unchanged.
+Bottles exported with a subset of their parts can not be pushed to a registry.  Otherwise the bottle is pulled into
+the current directory, or the directory supplied with the -d option, like with "ace-dt bottle pull".  Parts can be
+selected with the same flags as "ace-dt bottle pull".  In both cases the bottle ID and signatures are preserved.
+
+The bottle is selected in the layout with --tag, which is only required if the layout has more than one tag.`,
+		Example: `Push an exported bottle to a registry:
+  ace-dt bottle import mnist.tar REG/REPO/MNIST:v1
+
+Pull an exported bottle into the directory mnist:
+  ace-dt bottle import mnist.tar -d mnist`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var bottleRef string
+			if len(args) > 1 {
+				bottleRef = args[1]
+			}
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, args[0], bottleRef)
+			})
+		},
+	}
+
+	PartSelectorFlags(cmd.Flags(), &action.PartSelector)
+	cmd.Flags().StringVar(&action.Tag, "tag", "", "Tag of the bottle in the OCI image layout")
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+
+	return cmd
+}
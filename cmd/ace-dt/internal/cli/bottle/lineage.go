@@ -0,0 +1,77 @@
+package bottle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	telemv1alpha2 "github.com/act3-ai/data-telemetry/v3/pkg/apis/config.telemetry.act3-ace.io/v1alpha2"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/flag"
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/lineage"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/go-common/pkg/redact"
+)
+
+// newLineageCmd represents the lineage command.
+func newLineageCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Lineage{Action: tool}
+
+	cmd := &cobra.Command{
+		GroupID: "remote",
+		Use:     "lineage [BOTTLE_REFERENCE|DIR]",
+		Short:   "Shows the graph of bottles related through sources and deprecations",
+		Long: `Shows the lineage of a bottle: its ancestors, the bottles it lists as sources or deprecates, and its descendants,
+the bottles listing it as a source or deprecating it.  Relations are followed recursively up to --depth.
+
+The bottle is given by a bottle reference, or a bottle directory.  The bottle in the working directory is used if
+neither is given.
+
+Related bottles are resolved by bottle ID with the configured telemetry hosts, and by scanning the tags of the
+repository of the bottle reference along with any repositories given with --repository.  Descendants can only be
+found in the scanned repositories.  Bottles that cannot be resolved are shown as not found, and cycles are reported.
+
+The graph is rendered as one of: ` + strings.Join(lineage.Formats, ", "),
+		Example: `
+Show the lineage of the bottle in the current working directory:
+	ace-dt bottle lineage
+
+Show the ancestors of a remote bottle, two levels deep:
+	ace-dt bottle lineage --direction ancestors --depth 2 REG/REPO/NAME:TAG
+
+Render the lineage with Graphviz, also searching another repository:
+	ace-dt bottle lineage -o dot --repository REG/OTHER/NAME REG/REPO/NAME:TAG | dot -Tsvg > lineage.svg
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var arg string
+			if len(args) > 0 {
+				arg = args[0]
+			}
+			return action.Run(cmd.Context(), cmd.OutOrStdout(), arg)
+		},
+	}
+
+	cmd.Flags().IntVar(&action.Depth, "depth", 0, "Maximum number of relations followed from the bottle, unlimited if 0")
+	cmd.Flags().StringVar(&action.Direction, "direction", string(lineage.Both),
+		`Relations to follow, one of "ancestors", "descendants" or "both"`)
+	cmd.Flags().StringVarP(&action.Output, "output", "o", lineage.FormatTree,
+		"Output format, one of "+strings.Join(lineage.Formats, ", "))
+	cmd.Flags().StringArrayVar(&action.Repositories, "repository", []string{},
+		"Additional repository to scan for related bottles")
+	flag.TelemetryURLFlags(cmd.Flags(), &action.Telemetry)
+
+	// Add flag overrides function to override config with flags
+	action.Config.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
+		if action.Telemetry.URL != "" {
+			c.Telemetry = []telemv1alpha2.Location{
+				{URL: redact.SecretURL(action.Telemetry.URL)},
+			}
+		}
+		return nil
+	})
+
+	return cmd
+}
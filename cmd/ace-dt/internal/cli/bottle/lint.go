@@ -0,0 +1,77 @@
+package bottle
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/lint"
+)
+
+// newLintCmd represents the lint command.
+func newLintCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Lint{Action: tool}
+
+	cmd := &cobra.Command{
+		GroupID: "basic",
+		Use:     "lint",
+		Short:   "Checks the bottle metadata and parts against quality rules",
+		Long: `Checks the local bottle, including uncommitted changes, against the best practices of the Bottle Creator Guide.
+Each finding names the rule that produced it, and its severity.  The command fails if any finding has the severity
+given by --fail-on or higher, so it can be used in CI to block pushing low quality bottles.
+
+The available rules are: ` + strings.Join(lint.Rules(), ", ") + `
+
+Rules are configured with a YAML file given by --rules.  Rules and settings missing from the file keep their defaults.
+The severity of each rule is one of "error", "warning" or "off".  The default rules are:
+
+	description:
+	  severity: error
+	  minLength: 20          # minimum length of the bottle description
+	authors:
+	  severity: error        # at least one author, each with a name and email
+	license:
+	  severity: warning
+	  keys: [license]        # label or annotation keys holding the license
+	sources:
+	  severity: warning      # at least one source
+	sourceReferences:
+	  severity: error        # sources must have a URI, bottle sources a valid bottle ID
+	metrics:
+	  severity: warning      # at least one metric
+	labelKeys:
+	  severity: warning
+	  pattern: ^([a-z0-9]([-a-z0-9.]*[a-z0-9])?/)?[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	partLabels:
+	  severity: warning      # every part has a label
+	partSize:
+	  severity: warning
+	  max: 10Gi              # maximum size of a part
+	deprecatedMediaTypes:
+	  severity: warning      # parts using legacy media types
+`,
+		Example: `
+Lint the bottle in the current working directory:
+	ace-dt bottle lint
+
+Lint with a custom rule set, also failing on warnings, and output the findings as JSON:
+	ace-dt bottle lint --rules lint.yaml --fail-on warning --json
+
+Lint without requiring metrics:
+	ace-dt bottle lint --disable metrics
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&action.Rules, "rules", "", "Path to a YAML file configuring the lint rules")
+	cmd.Flags().StringSliceVar(&action.Disable, "disable", []string{}, "Rules to disable")
+	cmd.Flags().StringVar(&action.FailOn, "fail-on", string(lint.SeverityError),
+		`Minimum severity of findings that fail the lint, one of "error", "warning" or "off"`)
+	cmd.Flags().BoolVar(&action.JSON, "json", false, "Output the findings as JSON")
+
+	return cmd
+}
@@ -0,0 +1,36 @@
+/*
+Command log
+*/
+
+package bottle
+
+import (
+	"github.com/spf13/cobra"
+
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+)
+
+// newLogCmd represents the log command.
+func newLogCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Log{Action: tool}
+
+	cmd := &cobra.Command{
+		GroupID: "basic",
+		Use:     "log",
+		Short:   "Show the local history of commits of a bottle",
+		Long: `Lists the commits recorded in the local history of a bottle, most recent first.  Each commit that changes the
+bottle ID is recorded, with its creation time and the message given to "ace-dt bottle commit --message".
+The commit currently checked out is marked with "*".  The index or bottle ID of a commit can be given to
+"ace-dt bottle checkout" to restore it.`,
+		Example: `
+Show the history of the bottle in the current working directory:
+	ace-dt bottle log
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	return cmd
+}
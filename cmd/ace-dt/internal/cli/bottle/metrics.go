@@ -18,6 +18,7 @@ func newBtlMetricCmd(tool *actions.Action) *cobra.Command {
 
 	cmd.AddCommand(
 		newMetricAddCmd(tool),
+		newMetricImportCmd(tool),
 		newMetricRemoveCmd(tool),
 		newMetricListCmd(tool),
 	)
@@ -51,6 +52,58 @@ Add metric loss with a negative value of -3.14 (use '--' when value is negative)
 	return addMetricCmd
 }
 
+func newMetricImportCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.MetricImport{Action: tool}
+
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "import metrics from the output files of a training run",
+		Long: `Import metrics into a bottle from the files written by training code and experiment trackers.
+
+The format is detected from FILE, or given with --format:
+  json, yaml     an object of metrics, nested objects are flattened joining keys with "/", arrays of numbers are the
+                 values by step, and an array of objects holds a record of metrics per step
+  csv            a column per metric and a row per step, or name and value columns with a row per value
+  tensorboard    a TensorBoard event file, or a log directory with event files, whose scalar summaries are imported
+  mlflow         an MLflow run directory, or its metrics directory, with a file of values per metric
+A step, epoch or iteration key or column gives the step of the values.  Values that are not numbers are ignored.
+
+Metrics recorded more than once are reduced to one value by aggregation: last (the default, the value of the highest
+step), first, min, max or mean.  --aggregate sets the aggregation of all metrics, or with PATTERN=AGGREGATION of the
+metrics with names matching the pattern, where later flags take precedence.  In patterns "*" matches any characters,
+including "/", and "?" any one character.
+
+Imported metrics replace the metrics of the bottle with the same name, and are described by their aggregation and
+source file unless --desc gives a description.`,
+		Example: `
+Import the final metrics of an MLflow run, keeping the lowest validation loss:
+	ace-dt bottle metric import mlruns/0/4f2c9a/ --aggregate "val_loss=min"
+
+Import the accuracy and loss of a TensorBoard log directory as the best values:
+	ace-dt bottle metric import logs/ --include "*/epoch_accuracy" --include "*/epoch_loss" --aggregate "*accuracy=max" --aggregate "*loss=min"
+
+Import evaluation results, prefixing their names:
+	ace-dt bottle metric import eval.json --prefix "eval/" --desc "f1=F1 score on the held out test set"
+
+Show the metrics a CSV history would import:
+	ace-dt bottle metric import history.csv --dry-run
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), args[0], cmd.OutOrStdout())
+		},
+	}
+
+	importCmd.Flags().StringVar(&action.Format, "format", "", "Format of the metrics: json, yaml, csv, tensorboard or mlflow (detected by default)")
+	importCmd.Flags().StringArrayVar(&action.Aggregations, "aggregate", nil, "Aggregation of metrics recorded more than once, as AGGREGATION or PATTERN=AGGREGATION")
+	importCmd.Flags().StringToStringVar(&action.Descriptions, "desc", nil, "Description of a metric, as NAME=DESCRIPTION")
+	importCmd.Flags().StringArrayVar(&action.Include, "include", nil, "Pattern of the names of the metrics to import (all by default)")
+	importCmd.Flags().StringVar(&action.Prefix, "prefix", "", "Prefix added to the names of the imported metrics")
+	importCmd.Flags().BoolVar(&action.DryRun, "dry-run", false, "Show the metrics that would be imported without changing the bottle")
+
+	return importCmd
+}
+
 func newMetricListCmd(tool *actions.Action) *cobra.Command {
 	action := &actions.MetricList{Action: tool}
 
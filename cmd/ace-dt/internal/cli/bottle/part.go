@@ -38,7 +38,10 @@ func newPartListCmd(tool *actions.Action) *cobra.Command {
 		Long: `This commands shows information about parts that are in this bottle.
 By default, the parts information shown are name, size, and labels.
 User has the options of showing digest of a part, in lieu of labels,
-by passing in the flag --digest, -D`,
+by passing in the flag --digest, -D
+
+If the bottle has a label rules file, .label-rules.yaml, the labels shown are those the next commit sets from the
+rules, so the rules can be previewed before committing.`,
 		Example: `
 List parts that are in bottle at current working directory:
 	ace-dt bottle part list
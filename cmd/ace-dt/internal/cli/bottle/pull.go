@@ -36,7 +36,13 @@ Pull a bottle using any of the following bottle reference forms
 	by tag                <registry>/<repository>/<name>:<tag>
 	by digest             <registry>/<repository>/<name>@<digest>
 	by bottle ID          bottle:<digest>
-where <digest> is often of the form sha256:<sha256 digest, lower case hex encoded>.`,
+where <digest> is often of the form sha256:<sha256 digest, lower case hex encoded>.
+
+Encrypted parts are decrypted with the private keys of the encryptionKeys in the ace-dt configuration.
+Parts can be selected by label without decrypting them.
+
+With --verify, the bottle's signatures are fetched and verified against a trust policy file before any part is
+pulled. The pull is aborted if the trust policy is not satisfied. See ace-dt bottle verify for the trust policy.`,
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: oci.RefCompletion(action.DataTool),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -47,6 +53,10 @@ where <digest> is often of the form sha256:<sha256 digest, lower case hex encode
 	}
 
 	PartSelectorFlags(cmd.Flags(), &action.PartSelector)
+	cmd.Flags().BoolVar(&action.RestoreXattrs, "xattrs", false,
+		"Restore extended attributes of directory parts committed with --fidelity=xattrs")
+	cmd.Flags().BoolVar(&action.SparseFiles, "sparse", false, "Write blocks of zeros in directory parts as sparse file holes")
+	cmd.Flags().StringVar(&action.Verify, "verify", "", "Only pull the bottle if its signatures satisfy the trust policy file")
 	flag.TelemetryURLFlags(cmd.Flags(), &action.Telemetry)
 	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
 
@@ -69,6 +79,9 @@ Use bottle ID to pull a bottle from the best available location:
 Pull a bottle that is publicly available:
   ace-dt bottle pull us-central1-docker.pkg.dev/aw-df16163b-7044-4662-93fa-ec0/public-down-auth-up/mnist:v2.1 -d mnist
 
+Pull a bottle only if it is signed by signers trusted by the trust policy file trustpolicy.yaml:
+  ace-dt bottle pull REG/REPO/TESTSET:TAG --verify trustpolicy.yaml
+
 `
 	return cmd
 }
@@ -25,7 +25,7 @@ func newPushCmd(tool *actions.Action) *cobra.Command {
 
 	cmd := &cobra.Command{
 		GroupID: "remote",
-		Use:     "push BOTTLE_REFERENCE",
+		Use:     "push [BOTTLE_REFERENCE...]",
 		Short:   "Archives, compresses, and uploads bottle to an OCI registry",
 		Long: `The files at the specified location are archived and compressed using Zstandard compression, and uploaded to the specified OCI registry.
 	
@@ -33,11 +33,17 @@ A bottle reference follows the form <registry>/<repository>/<name>:<tag>
 
 Pushing a bottle with altered data or metadata will automatically deprecate 
 the previous version (bottleID) of this bottle. This can be disabled 
-by passing the --no-deprecate flag.`,
-		Args: cobra.ExactArgs(1),
+by passing the --no-deprecate flag.
+
+Parts may be encrypted before they are uploaded, see "ace-dt bottle commit --help" for the encryption flags.
+
+Given several bottle references, the bottle is pushed to all of them in one operation.  Each part is read from the
+cache once, uploaded once per registry, and mounted into the other repositories of the same registry.  A failure to
+push to one destination does not stop the push to the others, and the outcome of each destination is reported.  Without
+a bottle reference, the bottle is pushed to the pushDestinations of the ace-dt configuration.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
-				action.Ref = args[0]
+				action.Refs = args
 				return action.Run(ctx)
 			})
 		},
@@ -47,6 +53,7 @@ by passing the --no-deprecate flag.`,
 	cmd.Flags().BoolVar(&action.NoDeprecate, "no-deprecate", false, "Disable deprecation of previous bottle version")
 
 	CompressionLevelFlags(cmd.Flags(), &action.Compression)
+	EncryptionFlags(cmd.Flags(), &action.Encryption)
 	flag.TelemetryURLFlags(cmd.Flags(), &action.Telemetry)
 	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
 
@@ -67,12 +74,21 @@ by passing the --no-deprecate flag.`,
 To push the bottle TESTSET to the registry REGISTRY/REPO/NAME:TAG:
 	ace-dt bottle push REGISTRY/REPO/NAME:TAG -d ./TESTSET
 
+To push the bottle TESTSET to a registry and its mirror in one operation:
+	ace-dt bottle push REGISTRY/REPO/NAME:TAG MIRROR/REPO/NAME:TAG -d ./TESTSET
+
+To push the bottle TESTSET to the pushDestinations of the configuration (see ace-dt config --help):
+	ace-dt bottle push -d ./TESTSET
+
 To add a telemetry server, and send metadata after the push, set the telemetry URL and username (see ace-dt config --help):
 	export ACE_DT_TELEMETRY_URL=http://127.0.0.1:8100
 	export ACE_DT_TELEMETRY_USERNAME=exampleuser
 Then push like normal:
 	ace-dt bottle push REGISTRY/REPO/NAME:TAG -d ./TESTSET
 
+To encrypt all parts for the configured encryption key "exportControlled" before pushing:
+	ace-dt bottle push REGISTRY/REPO/NAME:TAG -d ./TESTSET --encrypt-for exportControlled
+
 Share a bottle with other users by giving them the bottle reference
 OR, share the bottle ID for Telemetry Server support.
 `
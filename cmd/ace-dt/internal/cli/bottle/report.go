@@ -0,0 +1,64 @@
+package bottle
+
+import (
+	"github.com/spf13/cobra"
+
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/report"
+)
+
+// newReportCmd represents the report command.
+func newReportCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Report{Action: tool}
+
+	cmd := &cobra.Command{
+		GroupID: "basic",
+		Use:     "report",
+		Short:   "Renders a human readable datasheet or model card of the bottle",
+		Long: `Renders a report of the local bottle, such as a datasheet or model card, for reviewers.  The report includes the
+description, authors, sources, metrics, labels and annotations of the bottle, its parts with their sizes and labels,
+its public artifacts and its signatures.
+
+The built-in templates render Markdown, or a self-contained static HTML page.  A Go template file given by --template
+is used instead.  Templates whose name ends in .html are HTML templates, others are text templates.  Templates are
+executed with the following data:
+
+	.BottleID     bottle ID, empty for attached reports
+	.Bottle       bottle definition (.Description, .Authors, .Sources, .Metrics, .Labels, .Annotations,
+	              .PublicArtifacts)
+	.Parts        parts ordered by name (.Name, .Size, .Digest, .MediaType, .Labels)
+	.TotalSize    total size of the parts
+	.Signatures   signatures (.Identity, .KeyID, .Digest), empty for attached reports
+
+along with the functions "bytes" (human readable size), "short" (short digest), "labels" (formatted label set),
+"cell" (escape text for a Markdown table) and "join".
+
+With --attach the report is written to a file at the root of the bottle, by default report.md or report.html, and
+added as a public artifact named "report", to be included by the next commit.  Attached reports leave out the bottle
+ID, the signatures and their own part, since those change when the report is committed.  "ace-dt bottle commit
+--report" attaches the report as part of the commit.
+`,
+		Example: `
+Print a Markdown datasheet of the bottle in the current working directory:
+	ace-dt bottle report
+
+Write an HTML report of the bottle at path <TESTSET>:
+	ace-dt bottle report -d TESTSET --format html -o datasheet.html
+
+Render a model card with a custom template and attach it to the bottle:
+	ace-dt bottle report --template model-card.md --attach
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&action.Format, "format", "f", report.FormatMarkdown,
+		`Format of the built-in template, one of "markdown" or "html"`)
+	cmd.Flags().StringVar(&action.Template, "template", "", "Path to a Go template used instead of the built-in one")
+	cmd.Flags().StringVarP(&action.Output, "output", "o", "", "Write the report to a file instead of standard output")
+	cmd.Flags().BoolVar(&action.Attach, "attach", false, "Attach the report to the bottle as a public artifact")
+
+	return cmd
+}
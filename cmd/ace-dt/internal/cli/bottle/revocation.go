@@ -0,0 +1,76 @@
+package bottle
+
+import (
+	"github.com/spf13/cobra"
+
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+)
+
+// newRevocationCmd is the top level command that aggregates subcommands for managing signing key revocation lists.
+func newRevocationCmd(tool *actions.Action) *cobra.Command {
+	cmd := &cobra.Command{
+		GroupID: "basic",
+		Use:     "revocation",
+		Short:   "Signing key revocation list operations",
+		Long: `This command group provides subcommands for managing lists of revoked signing keys.
+
+Signatures made by a revoked key, or with a certificate issued by a revoked key, fail verification with a trust policy
+listing the revocation list in its revocationLists, regardless of when they were made. Keys are identified by the
+fingerprint of their public key, as shown in the KEY column of ace-dt bottle verify --policy.
+
+A revocation list is a local file, or a signed revocation list artifact in a registry. The signatures of a revocation
+list artifact must satisfy the trust policy statement for its repository before the list is used.`,
+	}
+
+	cmd.AddCommand(
+		newRevocationAddCmd(tool),
+		newRevocationPushCmd(tool),
+	)
+	return cmd
+}
+
+func newRevocationAddCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.RevocationAdd{Action: tool}
+
+	cmd := &cobra.Command{
+		Use:   "add LIST_FILE FINGERPRINT...",
+		Short: "Revoke signing keys in a revocation list file",
+		Long:  `Adds the signing keys with the fingerprints to the revocation list file, creating the file if it does not exist.`,
+		Example: `
+Revoke a compromised key:
+	ace-dt bottle revocation add revoked.yaml sha256:5baaf83dc508530e90467d90579d5abb2458cd3ebdfec249e3bf76cf451a4728 --reason "key compromised"
+`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), args[0], args[1:], cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&action.Reason, "reason", "", "Reason the keys are revoked, reported for rejected signatures")
+
+	return cmd
+}
+
+func newRevocationPushCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.RevocationPush{Action: tool}
+
+	cmd := &cobra.Command{
+		Use:   "push LIST_FILE REFERENCE PRIVATE_KEY_ALIAS",
+		Short: "Push a signed revocation list to a registry",
+		Long: `Pushes the revocation list file to a registry as a revocation list artifact, signed with the signing key
+with the alias in the ace-dt config. The signature is pushed as a referrer of the artifact, and the reference is only
+tagged once the signature is pushed.`,
+		Example: `
+Publish a revocation list signed with the key 'SecurityOffice':
+	ace-dt bottle revocation push revoked.yaml REG/SECURITY/REVOCATIONS:latest SecurityOffice
+`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), args[0], args[1], args[2], cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().DurationVar(&action.Expiry, "expiry", 0, "How long the signature of the revocation list is valid, 0 for no expiry")
+
+	return cmd
+}
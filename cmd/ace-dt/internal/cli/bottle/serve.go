@@ -0,0 +1,68 @@
+/* Command serve
+ */
+
+package bottle
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	telemv1alpha2 "github.com/act3-ai/data-telemetry/v3/pkg/apis/config.telemetry.act3-ace.io/v1alpha2"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/flag"
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/oci"
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/go-common/pkg/config"
+	"github.com/act3-ai/go-common/pkg/redact"
+)
+
+// serveCmd represents the serve command.
+func newServeCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Serve{Action: tool}
+
+	cmd := &cobra.Command{
+		GroupID: "remote",
+		Use:     "serve [-l PORT] BOTTLE_REFERENCE",
+		Short:   "Serves the files of a remote bottle over HTTP and WebDAV",
+		Long: `Runs a read-only HTTP and WebDAV server presenting the parts of a remote bottle, and the files within directory parts.
+Parts are fetched into the cache the first time they are accessed, so only the data that is read is transferred.
+Directory listings and range requests are supported.
+
+A bottle reference uses one of the forms
+  by name (latest tag)  <registry>/<repository>/<name>
+  by tag                <registry>/<repository>/<name>:<tag>
+  by digest             <registry>/<repository>/<name>@<digest>
+  by bottle ID          bottle:<digest>`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: oci.RefCompletion(action.DataTool),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), args[0])
+		},
+	}
+
+	cmd.Flags().StringVarP(&action.Listen, "listen", "l", config.EnvOr("ACE_DT_BOTTLE_LISTEN", "localhost:8102"),
+		`Interface and port to listen on.
+Use :8102 to listen all on interfaces on the standard port.`)
+	flag.TelemetryURLFlags(cmd.Flags(), &action.Telemetry)
+
+	// Add flag overrides function to override config with flags
+	action.Config.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
+		if action.Telemetry.URL != "" {
+			c.Telemetry = []telemv1alpha2.Location{
+				{URL: redact.SecretURL(action.Telemetry.URL)},
+			}
+		}
+		return nil
+	})
+
+	cmd.Example = `Serve the bottle REG/REPO/TESTSET:TAG on the default port:
+  ace-dt bottle serve REG/REPO/TESTSET:TAG
+
+Mount the served bottle with a WebDAV client:
+  ace-dt bottle serve REG/REPO/TESTSET:TAG -l :8102
+  rclone mount --webdav-url http://localhost:8102 :webdav: /mnt/testset --read-only
+`
+	return cmd
+}
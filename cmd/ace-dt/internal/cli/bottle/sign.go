@@ -26,8 +26,12 @@ The signature layer, manifest, and config are written to the .signature director
 
 It is possible to directly sign a bottle manifest digest without adding private key metadata to ace-dt config. This use case requires using all --key-path, --key-api, --key-id, and --user-id flags. Supported key management system apis include gitlab.
 
+Keys that cannot be stored on disk can be held by a notation signer plugin, set with the plugin field of the key in the ace-dt config or the --key-plugin flag in place of --key-path. The plugin executable notation-<plugin> is found in the notation plugin directory or on the PATH, and is asked to sign with the key --key-id following the notation plugin specification.
+
 Current supported api's include no-kms and gitlab.
 
+With --expiry, the signature expires after the given duration, such as 720h. Trust policy verification rejects expired signatures.
+
 Signing a bottle with altered data or metadata will automatically deprecate the previous version (bottleID) of this bottle. This can be disabled by passing the --no-deprecate flag.`,
 		Args: cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -44,8 +48,10 @@ Signing a bottle with altered data or metadata will automatically deprecate the
 	// ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
 
 	cmd.Flags().BoolVar(&action.NoDeprecate, "no-deprecate", false, "Disable deprecation of previous bottle version")
+	cmd.Flags().DurationVar(&action.Expiry, "expiry", 0, "How long the signature is valid, 0 for a signature that does not expire")
 	// cmd.Flags().StringVar(&action.DigestAlg, "digest-algorithm", "sha256", "Algorithm to use when calculating digests.")
 	cmd.Flags().StringVar(&action.KeyPath, "key-path", "", "Path to a PEM formatted private key.")
+	cmd.Flags().StringVar(&action.KeyPlugin, "key-plugin", "", "Name of a notation signer plugin holding the key, used instead of --key-path.")
 	cmd.Flags().StringVar(&action.KeyAPI, "key-api", "", "The API added to the signed annotations, which is used during verification to locate the corresponding public key. Current api's supported are no-kms and gitlab.")
 	cmd.Flags().StringVar(&action.KeyID, "key-id", "", "The title/ID of the signing key.")
 	cmd.Flags().StringVar(&action.UserIdentity, "user-id", "", "The key owner's identity, typically a username for the KeyAPI.")
@@ -53,18 +59,19 @@ Signing a bottle with altered data or metadata will automatically deprecate the
 	// Add flag overrides function to override config with flags.
 	action.Config.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
 		// override config if all key metadata is specified, otherwise fail if some but not all is specified.
-		if action.KeyPath != "" && action.KeyAPI != "" && action.KeyID != "" && action.UserIdentity != "" {
+		if (action.KeyPath != "" || action.KeyPlugin != "") && action.KeyAPI != "" && action.KeyID != "" && action.UserIdentity != "" {
 			c.SigningKeys = []v1alpha1.SigningKey{
 				{
 					Alias:        "overridekey",
 					KeyPath:      action.KeyPath,
+					Plugin:       action.KeyPlugin,
 					KeyAPI:       action.KeyAPI,
 					UserIdentity: action.UserIdentity,
 					KeyID:        action.KeyID,
 				},
 			}
-		} else if action.KeyPath != "" || action.KeyAPI != "" || action.KeyID != "" || action.UserIdentity != "" {
-			return fmt.Errorf("insufficient signing key metadata, please ensure to specify all metadata with flags when directly providing a key: KeyPath = %s, KeyAPI = %s, UserIdentity = %s, KeyID = %s", action.KeyPath, action.KeyAPI, action.UserIdentity, action.KeyID)
+		} else if action.KeyPath != "" || action.KeyPlugin != "" || action.KeyAPI != "" || action.KeyID != "" || action.UserIdentity != "" {
+			return fmt.Errorf("insufficient signing key metadata, please ensure to specify all metadata with flags when directly providing a key: KeyPath = %s, KeyPlugin = %s, KeyAPI = %s, UserIdentity = %s, KeyID = %s", action.KeyPath, action.KeyPlugin, action.KeyAPI, action.UserIdentity, action.KeyID)
 		}
 
 		return nil
@@ -76,6 +83,9 @@ To sign a manifest digest with alias 'MyPrivateKey':
 
 To sign a manifest digest by directly providing private key metadata:
 	ace-dt bottle sign --key-path PATH/TO/PRIVATE.KEY --key-api KMS_API --key-id KEY_TITLE --user-id KEY_OWNER_ID
+
+To sign a manifest digest with a key held by the signer plugin notation-PLUGIN_NAME:
+	ace-dt bottle sign --key-plugin PLUGIN_NAME --key-api KMS_API --key-id KEY_ID --user-id KEY_OWNER_ID
 `
 	return cmd
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/oci"
 	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
 )
 
@@ -16,26 +17,45 @@ func newVerifyCmd(tool *actions.Action) *cobra.Command {
 
 	cmd := &cobra.Command{
 		GroupID: "basic",
-		Use:     "verify",
+		Use:     "verify [BOTTLE_REFERENCE]",
 		Short:   "Verifies all local signatures of a bottle's manifest digest.",
 		Long: `Verifies all local signatures of the bottle's manifest digest. In order to ensure the local signatures are up-to-date use ace-dt bottle pull prior to signature verification.
 
-It is optional to provide locally discovered public keys for verification. For each local public key provide the path to the key. The key's fingerprint will be used to correlate keys with signatures.
-Ex: ace-dt bottle verify <path> <path> ...
+If a bottle reference is given, the signatures of the remote bottle are verified instead. The bottle manifest is
+resolved and its signatures are discovered as referrers in the registry and verified in memory, without pulling the
+bottle's parts.
 
 Notice:
   If the signer provided insufficient metadata to discover the appropriate public key for verification,
   it will default to the no key management system verification method - which is notably insecure with ECDSA keys.
 
+Trust policy:
+  With --policy, signatures are verified against a trust policy file instead. A signature is trusted if its signing
+  certificate chains to a trusted root certificate, or its key is pinned, in the trust stores of the policy statement
+  for the bottle, and its signer is a trusted identity of the statement. Local bottles use the statement with the
+  registry scope "*", remote bottles the statement for their repository. The bottle passes verification if the
+  statement's required number of distinct signers is met. The trust policy statement each signature satisfied or
+  failed is reported.
 
+  Expired signatures, signatures made outside the validity period of their signing certificate, and certificates
+  that are not currently valid are rejected. So are signatures of keys in the revocation lists of the trust policy,
+  see ace-dt bottle revocation. The fingerprint of each signing key and the reason a signature was rejected are
+  reported.
 `,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: oci.RefCompletion(action.DataTool),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				action.Ref = args[0]
+			}
 			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
-				return action.Run(ctx, args...)
+				return action.Run(ctx)
 			})
 		},
 	}
 
+	cmd.Flags().StringVar(&action.Policy, "policy", "", "Verify signers with a trust policy file")
+
 	// TODO: Do we need ui options?
 	// ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
 
@@ -45,6 +65,12 @@ Notice:
 	cmd.Example = `
 To verify a manifest digest:
 	ace-dt bottle verify
+
+To verify the signers of a bottle with a trust policy:
+	ace-dt bottle verify --policy trustpolicy.yaml
+
+To verify the signers of a remote bottle without pulling it:
+	ace-dt bottle verify REG/REPO/NAME:TAG --policy trustpolicy.yaml
 `
 	return cmd
 }
@@ -0,0 +1,77 @@
+/*
+Command watch
+*/
+
+package bottle
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	telemv1alpha2 "github.com/act3-ai/data-telemetry/v3/pkg/apis/config.telemetry.act3-ace.io/v1alpha2"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/flag"
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	actions "github.com/act3-ai/data-tool/internal/actions/bottle"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/go-common/pkg/redact"
+)
+
+// newWatchCmd represents the watch command.
+func newWatchCmd(tool *actions.Action) *cobra.Command {
+	action := &actions.Watch{Action: tool}
+	uiOptions := ui.Options{}
+
+	cmd := &cobra.Command{
+		GroupID: "basic",
+		Use:     "watch",
+		Short:   "Commits a bottle each time its files settle after a change",
+		Long: `Watches the files of a bottle, and commits the bottle once they have been unchanged for the --interval.
+The bottle is also committed after the first interval, to pick up changes made before watching started.  With --push
+the bottle is pushed to the reference after each commit that changes the bottle ID.  The watch runs until it is
+interrupted.
+
+Hidden files and directories are ignored, like when committing, except for part label files.  On Linux changes are
+reported by inotify, and a file written to is considered in use until it is closed, so a file still being written
+is never committed.  On other platforms the bottle is scanned for changes every second, and only the interval
+protects files still being written.
+
+A failed commit or push is reported and retried after the next change.`,
+		Example: `Commit the bottle in the current directory after 5 minutes without changes:
+	ace-dt bottle watch --interval 5m
+
+Commit and push the bottle at path <TESTSET> after each change:
+	ace-dt bottle watch -d TESTSET --push REGISTRY/REPO/NAME:TAG`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx)
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&action.Quiet, "interval", 30*time.Second,
+		"How long the bottle files must be unchanged before committing")
+	cmd.Flags().StringVar(&action.Ref, "push", "", "Bottle reference to push the bottle to after each commit")
+
+	CompressionLevelFlags(cmd.Flags(), &action.Compression)
+	flag.TelemetryURLFlags(cmd.Flags(), &action.Telemetry)
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+
+	// Add flag overrides function to override config with flags
+	action.Config.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
+		if action.Telemetry.URL != "" {
+			c.Telemetry = []telemv1alpha2.Location{
+				{URL: redact.SecretURL(action.Telemetry.URL)},
+			}
+		}
+		if action.Compression.Level != "" {
+			c.CompressionLevel = action.Compression.Level
+		}
+		return nil
+	})
+
+	return cmd
+}
@@ -15,7 +15,7 @@ func newGenKeyCmd(tool *actions.DataTool) *cobra.Command {
 		Use:     "gen-key-pair DESTINATION_PATH",
 		Aliases: []string{"keygen", "genkeys"},
 		Short:   "generates a key pair used for signing/verifying data bottles, writing them to the destination path.",
-		Long:    `Generates an ECDSA public-private key pair, which is used for signing and verifying signatures of manifest digests. The public key is written to DESTINATION_PATH/bottle.pub while the private key is written to DESTINATION_PATH/bottle.key. The prefix "bottle" may be optionally changed with the --prefix flag. Any existing key names will be overwritten with the new key pair.`,
+		Long:    `Generates an ECDSA public-private key pair, which is used for signing and verifying signatures of manifest digests. The public key is written to DESTINATION_PATH/bottle.pub while the private key is written to DESTINATION_PATH/bottle.key. The prefix "bottle" may be optionally changed with the --prefix flag. Any existing key names will be overwritten with the new key pair. Use the ace-dt key commands to add the keys to the configuration, encrypt the private key, or issue keys from a local certificate authority instead.`,
 		Args:    cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return action.Run(cmd.Context(), args[0], action.Prefix)
@@ -0,0 +1,206 @@
+// Package key defines commands for managing signing keys.
+package key
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/actions/key"
+)
+
+// NewKeyCmd represents the base key command.
+func NewKeyCmd(tool *actions.DataTool) *cobra.Command {
+	action := &key.Action{DataTool: tool}
+	cmd := &cobra.Command{
+		GroupID: "setup",
+		Use:     "key",
+		Short:   "Signing key management",
+		Long: `This command group provides subcommands for managing the signing keys of the ace-dt configuration, creating a
+local certificate authority (CA) to issue signing certificates, and exporting keys for use in trust policies.
+
+Private keys may be encrypted with a passphrase. The passphrase is read from the environment variable
+ACE_DT_KEY_PASSPHRASE if it is set, otherwise it is prompted for when the key is used.`,
+	}
+
+	cmd.AddCommand(
+		newListCmd(action),
+		newAddCmd(action),
+		newRemoveCmd(action),
+		newEncryptCmd(action),
+		newExportCmd(action),
+		newCACmd(action),
+	)
+	return cmd
+}
+
+func newListCmd(base *key.Action) *cobra.Command {
+	action := &key.List{Action: base}
+
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the signing keys of the configuration",
+		Long: `Lists the signing keys of the ace-dt configuration with the fingerprints of their public keys, as shown in the
+KEY column of ace-dt bottle verify --policy, and when their certificates expire. The fingerprint of an encrypted key
+is only shown if it has a certificate or public key file next to it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+}
+
+func newAddCmd(base *key.Action) *cobra.Command {
+	action := &key.Add{Action: base}
+
+	cmd := &cobra.Command{
+		Use:   "add ALIAS [KEY_FILE]",
+		Short: "Add a signing key to the configuration file",
+		Long: `Adds a signing key with the alias to the ace-dt configuration file, the first configuration file present or the
+default configuration file if none are. The key is either the private key file KEY_FILE, or a key held by a notation
+signer plugin with --plugin, identified by --key-id.`,
+		Example: `
+Add a key created with ace-dt key ca issue:
+	ace-dt key add ReleaseKey ~/keys/release.key --identity release@example.com
+
+Add a key held by a signer plugin:
+	ace-dt key add HSMKey --plugin com.example.hsm --key-id release-key --identity release@example.com
+`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyPath := ""
+			if len(args) > 1 {
+				keyPath = args[1]
+			}
+			return action.Run(cmd.Context(), cmd.OutOrStdout(), args[0], keyPath)
+		},
+	}
+
+	cmd.Flags().StringVar(&action.Identity, "identity", "", "Identity of the key owner")
+	cmd.Flags().StringVar(&action.KeyID, "key-id", "", "Title of the key, the alias if not set")
+	cmd.Flags().StringVar(&action.API, "api", "cert-basic", "API used to access the key")
+	cmd.Flags().StringVar(&action.Plugin, "plugin", "", "Name of the notation signer plugin holding the key")
+	cmd.Flags().StringToStringVar(&action.PluginConfig, "plugin-config", nil, "Configuration passed to the signer plugin, as KEY=VALUE")
+	cobra.CheckErr(cmd.MarkFlagRequired("identity"))
+
+	return cmd
+}
+
+func newRemoveCmd(base *key.Action) *cobra.Command {
+	action := &key.Remove{Action: base}
+
+	return &cobra.Command{
+		Use:     "remove ALIAS",
+		Aliases: []string{"rm"},
+		Short:   "Remove a signing key from the configuration file",
+		Long:    `Removes the signing key with the alias from the ace-dt configuration file. The key files are not deleted.`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout(), args[0])
+		},
+	}
+}
+
+func newEncryptCmd(base *key.Action) *cobra.Command {
+	action := &key.Encrypt{Action: base}
+
+	return &cobra.Command{
+		Use:   "encrypt KEY_FILE",
+		Short: "Encrypt a private key file with a passphrase",
+		Long: `Encrypts the private key file in place with a passphrase, as a PKCS #8 encrypted private key that can also be
+read by other tools, such as openssl.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout(), args[0])
+		},
+	}
+}
+
+func newExportCmd(base *key.Action) *cobra.Command {
+	action := &key.Export{Action: base}
+
+	return &cobra.Command{
+		Use:   "export ALIAS DESTINATION_PATH",
+		Short: "Export the public key of a signing key for verification",
+		Long: `Exports the public key of the signing key with the alias to DESTINATION_PATH/ALIAS.pub, and its certificate
+chain to DESTINATION_PATH/ALIAS.crt if it has one, then prints a trust store pinning the key, to be added to a trust
+policy. The certificate chain may instead be added to a trust store as a certificate authority.`,
+		Example: `
+Export the key 'ReleaseKey' for the verifiers of its signatures:
+	ace-dt key export ReleaseKey ./trust >> policy.yaml
+`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout(), args[0], args[1])
+		},
+	}
+}
+
+// newCACmd is the command group for the local certificate authority.
+func newCACmd(base *key.Action) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ca",
+		Short: "Local certificate authority operations",
+		Long: `This command group provides subcommands for a small local certificate authority (CA), which issues signing
+certificates. Trust policies trusting the CA certificate trust the signatures of all keys issued by it.`,
+	}
+
+	cmd.AddCommand(
+		newCACreateCmd(base),
+		newCAIssueCmd(base),
+	)
+	return cmd
+}
+
+func newCACreateCmd(base *key.Action) *cobra.Command {
+	action := &key.CACreate{Action: base}
+
+	cmd := &cobra.Command{
+		Use:   "create DESTINATION_PATH",
+		Short: "Create a certificate authority",
+		Long: `Creates a CA key pair, written to DESTINATION_PATH/ca.key, and a self-signed CA certificate, written to
+DESTINATION_PATH/ca.crt. An existing CA key is not overwritten.`,
+		Example: `
+Create a CA with an encrypted key:
+	ace-dt key ca create ~/ca --name "Example Release CA" --encrypt
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout(), args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&action.Name, "name", "ace-dt CA", "Common name of the CA certificate")
+	cmd.Flags().DurationVar(&action.Validity, "validity", 5*365*24*time.Hour, "How long the CA certificate is valid")
+	cmd.Flags().BoolVar(&action.Encrypt, "encrypt", false, "Encrypt the CA private key with a passphrase")
+
+	return cmd
+}
+
+func newCAIssueCmd(base *key.Action) *cobra.Command {
+	action := &key.Issue{Action: base}
+
+	cmd := &cobra.Command{
+		Use:   "issue CA_KEY_FILE DESTINATION_PATH",
+		Short: "Issue a signing key and certificate from a certificate authority",
+		Long: `Creates a signing key pair with a certificate issued by the CA with the private key CA_KEY_FILE. The CA certificate
+is read from the file with the same name as CA_KEY_FILE and a .crt extension. The private key, public key and
+certificate chain are written to DESTINATION_PATH/PREFIX.key, .pub and .crt respectively. Use ace-dt key add to add the
+key to the configuration.`,
+		Example: `
+Issue a key for a release pipeline:
+	ace-dt key ca issue ~/ca/ca.key ~/keys --prefix release
+`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return action.Run(cmd.Context(), cmd.OutOrStdout(), args[0], args[1])
+		},
+	}
+
+	cmd.Flags().StringVarP(&action.Prefix, "prefix", "p", "signing", "Prefix of the key file names")
+	cmd.Flags().StringVar(&action.Name, "name", "", "Common name of the signing certificate, the prefix if not set")
+	cmd.Flags().BoolVar(&action.Encrypt, "encrypt", false, "Encrypt the private key with a passphrase")
+
+	return cmd
+}
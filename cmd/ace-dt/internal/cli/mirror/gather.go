@@ -39,7 +39,10 @@ To gather to a repository that does not support nested indexes:
 ace-dt mirror gather repos.list reg.example.com/project/repo:sync-45 --index-fallback
 
 To gather to a repository and only include manifests for specific platforms:
-ace-dt mirror gather repos.list reg.example.com/project/repo:sync-45 -p linux/arm/v8 -p linux/amd64`,
+ace-dt mirror gather repos.list reg.example.com/project/repo:sync-45 -p linux/arm/v8 -p linux/amd64
+
+To only gather images that pass a vulnerability policy:
+ace-dt mirror gather repos.list reg.example.com/project/repo:sync-45 --policy policy.yaml --policy-action drop`,
 
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -51,6 +54,8 @@ ace-dt mirror gather repos.list reg.example.com/project/repo:sync-45 -p linux/ar
 	cmd.Flags().BoolVar(&action.IndexFallback, "index-fallback", false, "Tells ace-dt to add indexes in annotations for registries that do not support nested indexes (i.e., not OCI 1.1 compliant).  This makes the references to the sub-indexes not real references therefore a garbage collection process might incorrectly delete the sub-indexes.  Therefore, this should only be used when necessary (e.g., when targeting Artifactory).")
 	cmd.Flags().StringToStringVarP(&action.ExtraAnnotations, "annotations", "a", map[string]string{}, "Define any additional annotations to add to the index of the gather repository.")
 	cmd.Flags().StringSliceVarP(&action.Platforms, "platforms", "p", []string{}, "Only gather images that match the specified platform(s). Warning: This will modify the manifest digest/reference.")
+	cmd.Flags().StringVar(&action.Policy, "policy", "", "Scan the images before they are gathered and apply this vulnerability policy file to them")
+	cmd.Flags().StringVar(&action.PolicyAction, "policy-action", "flag", "Action for images that fail the vulnerability policy, drop to not gather them or flag to annotate them with the violations")
 	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
 
 	return cmd
@@ -20,6 +20,8 @@ func NewOciCmd(tool *actions.DataTool) *cobra.Command {
 	cmd.AddCommand(
 		newPushDirCmd(action),
 		newTreeCmd(action),
+		newSignCmd(action),
+		newVerifyCmd(action),
 	)
 	return cmd
 }
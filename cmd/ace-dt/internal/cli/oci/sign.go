@@ -0,0 +1,44 @@
+package oci
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	"github.com/act3-ai/data-tool/internal/actions/oci"
+)
+
+// newSignCmd creates a new cobra.Command for the sign subcommand.
+func newSignCmd(base *oci.Action) *cobra.Command {
+	action := &oci.Sign{Action: base}
+	uiOptions := ui.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "sign [--oci-layout] IMAGE|OCILAYOUT PRIVATE_KEY_ALIAS",
+		Short: "Sign an image, index or other artifact in a registry or a local OCI directory.",
+		Long: `Signs the manifest or index of IMAGE with the signing key with the alias in the ace-dt config, and pushes the
+signature as a referrer of it.  Signatures are notation signatures, the same as those of bottles, so they can be
+verified by ace-dt oci verify and by the notation CLI, and signatures made by the notation CLI can be verified by
+ace-dt oci verify.
+
+If --oci-layout is set then the positional argument, OCILAYOUT, is used to specify an OCI-Layout directory.  It may be specified as a path and tag (path/to/dir:tag) or a path and digest (path/to/dir@sha256:deedbeef...).`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, cmd.OutOrStdout(), args[0], args[1])
+			})
+		},
+		Example: `  To sign the image "reg.example.com/image:v1" with the key 'ReleaseKey':
+		ace-dt oci sign reg.example.com/image:v1 ReleaseKey
+
+	To sign the gather index in ~/mirrorDir at my-tag with a signature that expires in 30 days:
+		ace-dt oci sign --oci-layout ~/mirrorDir:my-tag ReleaseKey --expiry 720h
+	`,
+	}
+
+	cmd.Flags().BoolVar(&action.OCILayout, "oci-layout", false, "Argument is a path and tag/digest in OCI image layout format")
+	cmd.Flags().DurationVar(&action.Expiry, "expiry", 0, "How long the signature is valid, 0 for signatures that do not expire")
+
+	return cmd
+}
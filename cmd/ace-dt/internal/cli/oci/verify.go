@@ -0,0 +1,45 @@
+package oci
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	"github.com/act3-ai/data-tool/internal/actions/oci"
+)
+
+// newVerifyCmd creates a new cobra.Command for the verify subcommand.
+func newVerifyCmd(base *oci.Action) *cobra.Command {
+	action := &oci.Verify{Action: base}
+	uiOptions := ui.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "verify [--oci-layout] IMAGE|OCILAYOUT --policy POLICY",
+		Short: "Verify the signatures of an image, index or other artifact in a registry or a local OCI directory.",
+		Long: `Verifies the signatures referring to the manifest or index of IMAGE against a trust policy file, the same as
+ace-dt bottle verify --policy.  Notation signatures made by ace-dt and by the notation CLI are verified.  Remote
+artifacts are verified against the trust policy statement for their repository, OCI-Layout directories against the
+statement with the registry scope "*".
+
+If --oci-layout is set then the positional argument, OCILAYOUT, is used to specify an OCI-Layout directory.  It may be specified as a path and tag (path/to/dir:tag) or a path and digest (path/to/dir@sha256:deedbeef...).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, cmd.OutOrStdout(), args[0])
+			})
+		},
+		Example: `  To verify the signers of the image "reg.example.com/image:v1":
+		ace-dt oci verify reg.example.com/image:v1 --policy trustpolicy.yaml
+
+	To verify the signers of the gather index in ~/mirrorDir at my-tag:
+		ace-dt oci verify --oci-layout ~/mirrorDir:my-tag --policy trustpolicy.yaml
+	`,
+	}
+
+	cmd.Flags().BoolVar(&action.OCILayout, "oci-layout", false, "Argument is a path and tag/digest in OCI image layout format")
+	cmd.Flags().StringVar(&action.Policy, "policy", "", "Verify signers with a trust policy file")
+	cobra.CheckErr(cmd.MarkFlagRequired("policy"))
+
+	return cmd
+}
@@ -18,5 +18,6 @@ func NewSBOMCmd(tool *actions.DataTool) *cobra.Command {
 	}
 	cmd.AddCommand(NewSBOMListCommand(action))
 	cmd.AddCommand(NewSBOMFetchCommand(action))
+	cmd.AddCommand(NewSBOMGenerateCommand(action))
 	return cmd
 }
@@ -0,0 +1,56 @@
+package sbom
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	sbomActions "github.com/act3-ai/data-tool/internal/actions/sbom"
+	"github.com/act3-ai/data-tool/internal/sbom/catalog"
+)
+
+// NewSBOMGenerateCommand creates a new sbom generate sub-command.
+func NewSBOMGenerateCommand(tool *sbomActions.Action) *cobra.Command {
+	action := &sbomActions.Generate{Action: tool}
+	uiOptions := ui.Options{}
+	cmd := &cobra.Command{
+		Use:   "generate [--oci-layout] IMAGE|OCILAYOUT",
+		Short: "Generate an SBOM for an image in a registry or a local OCI directory",
+		Long: `Generates an SBOM for IMAGE by cataloging the packages installed in it with syft, which must be installed.
+
+With --cataloger builtin the packages are cataloged in-process, without syft, reading the image layers with the registry
+credentials and endpoints of the ace-dt configuration. The builtin cataloger only catalogs the packages installed by
+dpkg, apk and rpm, Go binaries, and python, npm and java (maven) packages, so the vulnerabilities of other packages,
+such as ruby gems or rust crates, are not found in its SBOMs. The ecosystems it finds but does not catalog are logged.
+
+If --oci-layout is set then the positional argument, OCILAYOUT, is used to specify an OCI-Layout directory.  It may be specified as a path and tag (path/to/dir:tag) or a path and digest (path/to/dir@sha256:deedbeef...).`,
+		Example: `
+		To print the SPDX SBOM of an image:
+		ace-dt sbom generate reg.example.com/image:v1
+
+		To print the SPDX SBOM of an image without syft:
+		ace-dt sbom generate reg.example.com/image:v1 --cataloger builtin
+
+		To save the CycloneDX SBOM of the linux/arm64 image of a multi-platform image:
+		ace-dt sbom generate reg.example.com/image:v1 --format cyclonedx-json --platform linux/arm64 -o sbom.json
+
+		To attach an SBOM to an image in a local OCI directory:
+		ace-dt sbom generate --oci-layout ~/mirrorDir:my-tag --push -o sbom.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, cmd.OutOrStdout(), args[0])
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&action.OCILayout, "oci-layout", false, "Argument is a path and tag/digest in OCI image layout format")
+	cmd.Flags().StringVar(&action.Format, "format", string(catalog.FormatSPDX), "SBOM format, spdx-json or cyclonedx-json")
+	cmd.Flags().StringVar(&action.Platform, "platform", "", "Platform of the image to catalog in a multi-platform image, as os/arch[/variant]")
+	cmd.Flags().StringVarP(&action.Output, "output", "o", "-", "- for stdout or a file path to save the SBOM to")
+	cmd.Flags().BoolVar(&action.Push, "push", false, "Push the SBOM as a referrer of the image")
+	cmd.Flags().StringVar(&action.Cataloger, "cataloger", "syft", "Cataloger of the packages of the image, syft or builtin. The builtin cataloger does not require syft but catalogs fewer ecosystems")
+
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+	return cmd
+}
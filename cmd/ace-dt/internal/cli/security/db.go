@@ -0,0 +1,108 @@
+package security
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/act3-ai/data-tool/cmd/ace-dt/internal/cli/internal/ui"
+	securityActions "github.com/act3-ai/data-tool/internal/actions/security"
+)
+
+func newDBCommand(tool *securityActions.Action) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the vulnerability database used for scanning",
+		Long: `Manages the grype vulnerability database used by "ace-dt security scan".
+
+Hosts without access to the grype database servers scan with a database packaged as an OCI artifact by a connected
+host.  The artifact is transferred like any other image: gather it with "ace-dt mirror gather", or archive it with
+"ace-dt mirror archive", and import it on the disconnected host.`,
+	}
+	cmd.AddCommand(
+		newDBPackageCommand(tool),
+		newDBImportCommand(tool),
+		newDBStatusCommand(tool),
+	)
+	return cmd
+}
+
+func newDBPackageCommand(tool *securityActions.Action) *cobra.Command {
+	action := &securityActions.DBPackage{Action: tool}
+	uiOptions := ui.Options{}
+	cmd := &cobra.Command{
+		Use:   "package [--oci-layout] IMAGE|OCILAYOUT",
+		Short: "Package the vulnerability database as an OCI artifact",
+		Long: `Pushes the grype vulnerability database to IMAGE, annotated with the time it was built, its schema version and checksum.
+
+If --oci-layout is set then the positional argument, OCILAYOUT, is used to specify an OCI-Layout directory as a path and tag (path/to/dir:tag).`,
+		Example: `
+		To package the latest vulnerability database:
+		ace-dt security db package --update reg.example.com/security/grype-db:latest
+
+		To package the vulnerability database into a local OCI directory:
+		ace-dt security db package --oci-layout ~/grype-db:latest`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, cmd.OutOrStdout(), args[0])
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&action.OCILayout, "oci-layout", false, "Argument is a path and tag in OCI image layout format")
+	cmd.Flags().BoolVar(&action.Update, "update", false, "Update the vulnerability database before packaging it")
+
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+	return cmd
+}
+
+func newDBImportCommand(tool *securityActions.Action) *cobra.Command {
+	action := &securityActions.DBImport{Action: tool}
+	uiOptions := ui.Options{}
+	cmd := &cobra.Command{
+		Use:   "import [--oci-layout] IMAGE|OCILAYOUT",
+		Short: "Import a vulnerability database packaged as an OCI artifact",
+		Long: `Installs the vulnerability database packaged by "ace-dt security db package" at IMAGE into the grype database cache, replacing the database of the same schema.
+
+The database is installed in the directory set by GRYPE_DB_CACHE_DIR, or the default grype database cache directory, unless --cache-dir is set.
+
+If --oci-layout is set then the positional argument, OCILAYOUT, is used to specify an OCI-Layout directory.  It may be specified as a path and tag (path/to/dir:tag) or a path and digest (path/to/dir@sha256:deedbeef...).`,
+		Example: `
+		To import a mirrored vulnerability database:
+		ace-dt security db import reg.example.com/security/grype-db:latest
+
+		To import a vulnerability database from a local OCI directory:
+		ace-dt security db import --oci-layout ~/grype-db:latest`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, cmd.OutOrStdout(), args[0])
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&action.OCILayout, "oci-layout", false, "Argument is a path and tag/digest in OCI image layout format")
+	cmd.Flags().StringVar(&action.CacheDir, "cache-dir", "", "Grype database cache directory to import into")
+
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+	return cmd
+}
+
+func newDBStatusCommand(tool *securityActions.Action) *cobra.Command {
+	action := &securityActions.DBStatus{Action: tool}
+	uiOptions := ui.Options{}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the vulnerability database used for scanning",
+		Long:  `Shows the build time, age, schema version and checksum of the grype vulnerability database, failing if it is older than the maximum age.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ui.RunUI(cmd.Context(), uiOptions, func(ctx context.Context) error {
+				return action.Run(ctx, cmd.OutOrStdout())
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&action.MaxAge, "max-db-age", 0, "Fails if the vulnerability database is older, overriding vulnerabilityDBMaxAge of the configuration")
+
+	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
+	return cmd
+}
@@ -34,6 +34,18 @@ func newScanCommand(tool *securityActions.Action) *cobra.Command {
 		To set the lowest vulnerability level shown:
 		ace-dt security scan --gathered-image localhost:5000/gather:sync-1 --vulnerability-level=low
 
+		To fail the scan if the vulnerability database is more than a week old:
+		ace-dt security scan --gathered-image localhost:5000/gather:sync-1 --max-db-age 168h
+
+		To save SARIF and CycloneDX VEX reports and push them as referrers of each image:
+		ace-dt security scan --gathered-image localhost:5000/gather:sync-1 -o sarif=report.sarif -o cyclonedx-vex=vex.json --push-reports
+
+		To fail the scan if any image fails a vulnerability policy:
+		ace-dt security scan --gathered-image localhost:5000/gather:sync-1 --policy policy.yaml
+
+		To generate the SBOMs of the images without syft, cataloging fewer ecosystems:
+		ace-dt security scan --gathered-image localhost:5000/gather:sync-1 --cataloger builtin
+
 		To get multiple formatted reports:
 		ace-dt security scan --gathered-image localhost:5000/gather:sync-1 -o table=report.txt -o csv=report.csv -o markdown=report.md -o json=report.json
 		`,
@@ -50,11 +62,14 @@ func newScanCommand(tool *securityActions.Action) *cobra.Command {
 	cmd.Flags().StringVar(&action.GatherArtifactReference, "gathered-image", "", "Define an artifact reference created by Gather to scan for vulnerabilities")
 	// cmd.Flags().StringVar(&action.SaveReport, "report-file", "", "Saves the vulnerability report to user-specified location")
 	cmd.Flags().StringVar(&action.VulnerabilityLevel, "vulnerability-level", "medium", "The lowest level of vulnerability to display in reports and outputs. Options are 'critical', 'high', 'medium', 'low', 'negligable', or 'unknown'")
-	cmd.Flags().StringSliceVarP(&action.Output, "output", "o", []string{"table"}, "Define how you would like the output displayed. Supported types are json (default), markdown, csv, and table. Multiple values are supported.")
+	cmd.Flags().StringSliceVarP(&action.Output, "output", "o", []string{"table"}, "Define how you would like the output displayed. Supported types are json (default), markdown, csv, table, sarif and cyclonedx-vex. Multiple values are supported.")
 	cmd.Flags().BoolVar(&action.DryRun, "check", false, "Outputs scanning information without generating SBOMS (only applicable to --gathered-image input)")
 	cmd.Flags().BoolVar(&action.DisplayCVE, "display-cve", false, "Outputs the CVE information to file or stdout")
 	cmd.Flags().BoolVar(&action.DisplayPlatforms, "display-platforms", false, "Outputs a table of platform information to file or stdout")
-	cmd.Flags().BoolVar(&action.PushReport, "push-reports", false, "Pushes and attaches the vulnerability reports to each image.")
+	cmd.Flags().BoolVar(&action.PushReport, "push-reports", false, "Pushes and attaches the vulnerability reports to each image, including the sarif and cyclonedx-vex reports of the selected outputs.")
+	cmd.Flags().StringVar(&action.Policy, "policy", "", "Vulnerability policy file the images must pass, failing the scan if any image fails the policy")
+	cmd.Flags().StringVar(&action.Cataloger, "cataloger", "syft", "Cataloger generating the SBOMs of the images, syft or builtin. The builtin cataloger does not require syft but catalogs fewer ecosystems")
+	cmd.Flags().DurationVar(&action.MaxDatabaseAge, "max-db-age", 0, "Fails the scan if the vulnerability database is older, overriding vulnerabilityDBMaxAge of the configuration")
 
 	ui.AddOptionsFlags(cmd.Flags(), &uiOptions)
 	return cmd
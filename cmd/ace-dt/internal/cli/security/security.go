@@ -15,7 +15,10 @@ func NewSecurityCmd(tool *actions.DataTool) *cobra.Command {
 		Use:     "security",
 		Short:   "ace-dt security operations",
 	}
-	cmd.AddCommand(newScanCommand(action))
+	cmd.AddCommand(
+		newScanCommand(action),
+		newDBCommand(action),
+	)
 
 	return cmd
 }
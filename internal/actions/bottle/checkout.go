@@ -0,0 +1,77 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/ui"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Checkout represents the bottle checkout action.
+type Checkout struct {
+	*Action
+
+	Force bool // Discard uncommitted changes to the parts of the bottle
+}
+
+// Run runs the bottle checkout action, restoring the commit identified by spec, see bottle.FindCommit.
+func (action *Checkout) Run(ctx context.Context, spec string) error {
+	log := logger.FromContext(ctx)
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	log.InfoContext(ctx, "bottle checkout command activated")
+
+	cfg := action.Config.Get(ctx)
+	dir, err := bottle.FindBottleRootDir(action.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to find root bottle directory starting from %s: %w", action.Dir, err)
+	}
+	action.Dir = dir
+
+	records, err := bottle.ReadHistory(dir)
+	if err != nil {
+		return err
+	}
+	record, err := bottle.FindCommit(records, spec)
+	if err != nil {
+		return err
+	}
+
+	btl, err := bottle.LoadBottle(dir, bottle.WithCachePath(cfg.CachePath))
+	if err != nil {
+		return fmt.Errorf("failed to load bottle at %s: %w", dir, err)
+	}
+	if !action.Force {
+		// changes are only reported to a visitor
+		unchanged := func(bottle.PartInfo, bottle.PartStatus) (bool, error) { return false, nil }
+		_, changed, err := bottle.InspectBottleFiles(ctx, btl, bottle.Options{Visitor: unchanged})
+		if err != nil {
+			return fmt.Errorf("checking for uncommitted changes: %w", err)
+		}
+		if changed {
+			return errors.New("the bottle has uncommitted changes, commit them or use --force to discard them")
+		}
+	}
+
+	keys, err := decryptionKeys(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := bottle.Checkout(ctx, btl, record,
+		bottle.WithBlobInfoCache(cfg.CachePath),
+		bottle.WithDecryptionKeys(keys...),
+	); err != nil {
+		var missing *bottle.MissingLayersError
+		if errors.As(err, &missing) {
+			return fmt.Errorf("%w; pull it with \"ace-dt bottle pull bottle:%s\" instead", err, record.BottleID)
+		}
+		return fmt.Errorf("checking out bottle %s: %w", record.BottleID, err)
+	}
+
+	rootUI.Infof("Checked out bottle %s", record.BottleID)
+	log.InfoContext(ctx, "bottle checkout command completed")
+	return nil
+}
@@ -0,0 +1,93 @@
+package bottle
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/pkg/conf"
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+func Test_Checkout(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -6))
+	cacheDir := t.TempDir()
+	cfg := conf.New()
+	cfg.AddConfigOverride(conf.WithCachePath(cacheDir))
+
+	dir := t.TempDir()
+	tool := &Action{DataTool: &actions.DataTool{Config: cfg}, Dir: dir}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o777))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o666))
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	write("a.txt", "one\n")
+	write("d/x.txt", "x\n")
+	require.NoError(t, (&Init{Action: tool}).Run(ctx, &bytes.Buffer{}))
+	require.NoError(t, (&Commit{Action: tool, Message: "first"}).Run(ctx))
+
+	write("a.txt", "two\n") // same size
+	write("b.txt", "b\n")
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "d")))
+	require.NoError(t, (&Commit{Action: tool, Message: "second"}).Run(ctx))
+	require.NoError(t, (&Commit{Action: tool}).Run(ctx)) // unchanged, not recorded
+
+	records, err := bottle.ReadHistory(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "first", records[0].Message)
+	assert.Equal(t, "second", records[1].Message)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, (&Log{Action: tool}).Run(ctx, out))
+	assert.Contains(t, out.String(), records[1].BottleID.String())
+
+	t.Run("uncommitted changes", func(t *testing.T) {
+		write("c.txt", "c\n")
+		assert.ErrorContains(t, (&Checkout{Action: tool}).Run(ctx, "1"), "uncommitted changes")
+		assert.Equal(t, "two\n", read("a.txt"))
+		require.NoError(t, os.Remove(filepath.Join(dir, "c.txt")))
+	})
+
+	t.Run("previous", func(t *testing.T) {
+		require.NoError(t, (&Checkout{Action: tool, Force: true}).Run(ctx, "1"))
+		assert.Equal(t, "one\n", read("a.txt"))
+		assert.Equal(t, "x\n", read("d/x.txt"))
+		assert.NoFileExists(t, filepath.Join(dir, "b.txt"))
+		bottleID, err := bottle.ReadBottleIDFile(dir)
+		require.NoError(t, err)
+		assert.Equal(t, records[0].BottleID, bottleID)
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		require.NoError(t, (&Checkout{Action: tool}).Run(ctx, records[1].BottleID.Encoded()[:12]))
+		assert.Equal(t, "two\n", read("a.txt"))
+		assert.Equal(t, "b\n", read("b.txt"))
+		assert.NoDirExists(t, filepath.Join(dir, "d"))
+	})
+
+	t.Run("missing from cache", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(filepath.Join(cacheDir, "blobs")))
+		err := (&Checkout{Action: tool, Force: true}).Run(ctx, "1")
+		var missing *bottle.MissingLayersError
+		require.ErrorAs(t, err, &missing)
+		assert.ElementsMatch(t, []string{"a.txt", "d/"}, missing.Parts)
+		assert.Equal(t, "two\n", read("a.txt")) // unchanged
+	})
+}
@@ -2,17 +2,9 @@ package bottle
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"io/fs"
-	"log/slog"
-	"os"
-	"path/filepath"
-
-	"github.com/opencontainers/go-digest"
 
 	"github.com/act3-ai/data-tool/internal/bottle"
-	"github.com/act3-ai/data-tool/internal/ui"
 	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
 	"github.com/act3-ai/go-common/pkg/logger"
 )
@@ -22,7 +14,12 @@ type Commit struct {
 	*Action
 
 	Compression CompressionLevelOptions
-	NoDeprecate bool // Don't deprecate existing bottle
+	Encryption  EncryptionOptions
+	NoDeprecate bool   // Don't deprecate existing bottle
+	Fidelity    string // Fidelity level for archiving directory parts
+	Message     string // Message recorded in the local history of the bottle
+	Stats       bool   // Compute part statistics
+	Report      ReportOptions
 }
 
 // Run runs the bottle commit action.
@@ -31,95 +28,83 @@ func (action *Commit) Run(ctx context.Context) error {
 
 	log.InfoContext(ctx, "bottle commit command activated")
 
-	cfg, btl, err := action.prepare(ctx)
-	if err != nil {
+	if err := bottle.ValidateFidelity(action.Fidelity); err != nil {
 		return err
 	}
 
-	return commit(ctx, cfg, btl, action.NoDeprecate)
-}
-
-func commit(ctx context.Context, cfg *v1alpha1.Configuration, btl *bottle.Bottle, noDeprecate bool) error {
-	log := logger.FromContext(ctx)
-
-	rootUI := ui.FromContextOrNoop(ctx)
-
-	// for each public artifact in the bottle path ensure we have the digest calculated
-	if err := verifyArtifacts(btl, rootUI); err != nil {
+	cfg, btl, err := action.prepare(ctx)
+	if err != nil {
 		return err
 	}
 
-	// Before we commit, check if there is a bottleID in the .dt directory,
-	// This would mean that the bottle has already been committed / pulled / pushed
-	// and ace-dt now infers this a deprecating a bottle.
-	// Get bottleID from bottleID file if it exists
-	bottlePath := btl.GetPath()
-	defaultBottleIDPath := filepath.Join(bottlePath, ".dt", "bottleid")
-	var oldDigest digest.Digest
-
-	// check the bottlePath for a bottleID file, if it exists, then ReadBottleIDFile
-	if _, err := os.Stat(defaultBottleIDPath); err == nil {
-		oldDigest, err = bottle.ReadBottleIDFile(bottlePath)
-		if err != nil {
+	if action.Report.Format != "" || action.Report.Template != "" {
+		if btl, err = action.attachReport(ctx, cfg, btl); err != nil {
 			return err
 		}
 	}
 
-	// Access global flag compressionLevel
-	level := cfg.CompressionLevel
+	return commit(ctx, cfg, btl, commitOptions{
+		NoDeprecate: action.NoDeprecate,
+		Fidelity:    action.Fidelity,
+		Encryption:  action.Encryption,
+		Message:     action.Message,
+		Stats:       action.Stats,
+	})
+}
 
-	log.InfoContext(ctx, "Saving updated bottle")
-	if err := bottle.SaveUpdatesToSet(ctx, btl, bottle.SaveOptions{CompressLevel: level}); err != nil {
-		return err
+// attachReport processes the parts first, so the report shows their current digests, then attaches the report and
+// reloads the bottle to pick up the report part.
+func (action *Commit) attachReport(ctx context.Context, cfg *v1alpha1.Configuration, btl *bottle.Bottle) (*bottle.Bottle, error) {
+	encryptOpts, err := action.Encryption.encryptOptions(ctx, cfg)
+	if err != nil {
+		return nil, err
 	}
-
-	log.InfoContext(ctx, "Validating bottle data")
-	if err := btl.Definition.ValidateWithContext(ctx); err != nil {
-		return fmt.Errorf("failed to validate bottle before push: %w", err)
+	if err := bottle.SaveUpdatesToSet(ctx, btl, bottle.SaveOptions{
+		CompressLevel: cfg.CompressionLevel,
+		Fidelity:      action.Fidelity,
+		Encryption:    encryptOpts,
+		Stats:         action.Stats,
+	}); err != nil {
+		return nil, err
 	}
 
-	// if noDeprecate flag isn't set, then we deprecate the previous bottleID
-	// If the bottleID changed after saving the updates to the set,
-	// then we deprecate the previous bottleID (from default bottleID file read earlier)
-	if !noDeprecate && oldDigest.String() != "" && oldDigest != btl.GetBottleID() {
-		// save changes to bottle, adding the deprecated to the entry.yaml (bottle config)
-		if err := deprecate(log, rootUI, btl, oldDigest); err != nil {
-			return err
-		}
-		if err := btl.ConstructManifest(); err != nil { // reconstruct the updated information
-			return fmt.Errorf("resetting bottle configuration: %w", err)
-		}
+	if err := attachReport(ctx, btl, action.Report, ""); err != nil {
+		return nil, err
+	}
+	if err := btl.Save(); err != nil {
+		return nil, fmt.Errorf("saving bottle report artifact: %w", err)
 	}
 
-	log.InfoContext(ctx, "bottle commit command complete")
-
-	return bottle.SaveExtraBottleInfo(ctx, btl)
+	btl, err = LoadAndUpgradeBottle(ctx, cfg, action.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bottle at %s: %w", action.Dir, err)
+	}
+	return btl, nil
 }
 
-// deprecate deprecates the previous bottleID, then saves the new bottle configuration.
-func deprecate(log *slog.Logger, u *ui.Task, btl *bottle.Bottle, dgst digest.Digest) error {
-	log.Info("BottleID changed, deprecating previous bottleID")
-	btl.DeprecateBottleID(dgst)
+// commitOptions are the options of the actions that commit a bottle.
+type commitOptions struct {
+	NoDeprecate bool              // Don't deprecate existing bottle
+	Fidelity    string            // Fidelity level for archiving directory parts
+	Encryption  EncryptionOptions // Parts to encrypt and their recipients
+	Message     string            // Message recorded in the local history of the bottle
+	Stats       bool              // Compute part statistics
+}
 
-	if err := btl.Save(); err != nil {
+// commit commits the bottle with the configured compression level.
+func commit(ctx context.Context, cfg *v1alpha1.Configuration, btl *bottle.Bottle, options commitOptions) error {
+	encryptOpts, err := options.Encryption.encryptOptions(ctx, cfg)
+	if err != nil {
 		return err
 	}
-	u.Infof("Deprecating previous bottle %s", dgst)
-	return nil
-}
 
-// verifyArtifacts ensures that all public artifacts have a digest calculated.
-func verifyArtifacts(btl *bottle.Bottle, u *ui.Task) error {
-	for i, art := range btl.Definition.PublicArtifacts {
-		if art.Digest == "" {
-			if _, err := os.Stat(btl.NativePath(art.Path)); errors.Is(err, fs.ErrNotExist) {
-				u.Infof("PublicArtifact %q not found.\nPlease update bottle metadata to reference an existing file.", art.Path)
-				continue
-			}
-			if err := btl.CalculatePublicArtifactDigest(i); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	// Access global flag compressionLevel
+	return bottle.Commit(ctx, btl, bottle.CommitOptions{
+		CompressLevel: cfg.CompressionLevel,
+		Fidelity:      options.Fidelity,
+		Encryption:    encryptOpts,
+		NoDeprecate:   options.NoDeprecate,
+		Message:       options.Message,
+		Stats:         options.Stats,
+	})
 }
@@ -2,21 +2,52 @@ package bottle
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
 
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/lineage"
+	dtreg "github.com/act3-ai/data-tool/internal/registry"
+	telem "github.com/act3-ai/data-tool/pkg/telemetry"
 	"github.com/act3-ai/go-common/pkg/logger"
 )
 
 // Delete represents the bottle delete action.
 type Delete struct {
 	*Action
-	Ref string
+
+	Telemetry     actions.TelemetryOptions
+	Ref           string
+	KeepReferrers bool // Leave the referrers of the bottle, such as signatures, in place
+	DryRun        bool // Only show what would be deleted
+}
+
+// deletePlan is the content removed from a repository by a bottle delete.
+type deletePlan struct {
+	manifest  ocispec.Descriptor
+	referrers []ocispec.Descriptor // ordered so referrers come before the manifests they refer to
+	tags      []string
 }
 
 // Run runs the bottle delete action.
-func (action *Delete) Run(ctx context.Context) error {
+func (action *Delete) Run(ctx context.Context, out io.Writer) error {
 	log := logger.FromContext(ctx)
 
+	log.InfoContext(ctx, "bottle delete command activated")
+
 	repo, err := action.Config.Repository(ctx, action.Ref)
 	if err != nil {
 		return err
@@ -27,10 +58,212 @@ func (action *Delete) Run(ctx context.Context) error {
 		return fmt.Errorf("resolving image reference failed: %w", err)
 	}
 
-	if err := repo.Delete(ctx, target); err != nil {
+	plan := deletePlan{manifest: target}
+	if plan.referrers, err = findReferrers(ctx, repo, target); err != nil {
+		return err
+	}
+	if plan.tags, err = findTags(ctx, repo, target.Digest); err != nil {
+		log.InfoContext(ctx, "unable to list repository tags, only deleting the requested tag", "error", err)
+		if tag := repo.Reference.ReferenceOrDefault(); tag != target.Digest.String() {
+			plan.tags = []string{tag}
+		}
+	}
+
+	if err := action.warnDependents(ctx, out, repo, target); err != nil {
+		return err
+	}
+	if action.KeepReferrers && len(plan.referrers) > 0 {
+		if _, err := fmt.Fprintf(out, "Warning: keeping %d referrer(s) of %s, which will refer to a deleted manifest\n",
+			len(plan.referrers), target.Digest); err != nil {
+			return err
+		}
+	}
+
+	if action.DryRun {
+		return action.printPlan(out, repo, plan, "Would delete")
+	}
+
+	if !action.KeepReferrers {
+		for _, desc := range plan.referrers {
+			if err := repo.Delete(ctx, desc); err != nil && !errors.Is(err, errdef.ErrNotFound) {
+				return fmt.Errorf("deleting referrer %s: %w", desc.Digest, err)
+			}
+			if _, err := fmt.Fprintf(out, "Deleted referrer %s (%s)\n", desc.Digest, artifactType(desc)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tag := range plan.tags {
+		msg := "Deleted tag %s:%s\n"
+		switch err := dtreg.DeleteTag(ctx, repo, tag); {
+		case errors.Is(err, errdef.ErrUnsupported):
+			logger.V(log, 1).InfoContext(ctx, "registry does not support removing tags, relying on manifest deletion",
+				"tag", tag, "error", err)
+			msg = "Tag %s:%s is removed with the manifest, the registry does not support deleting tags\n"
+		case err != nil:
+			return err //nolint:wrapcheck
+		}
+		if _, err := fmt.Fprintf(out, msg, repo.Reference.Repository, tag); err != nil {
+			return err
+		}
+	}
+
+	if err := repo.Delete(ctx, target); err != nil && !errors.Is(err, errdef.ErrNotFound) {
 		return fmt.Errorf("deleting remote content: %w", err)
 	}
+	if _, err := fmt.Fprintf(out, "Deleted manifest %s\n", target.Digest); err != nil {
+		return err
+	}
 
 	log.InfoContext(ctx, "bottle delete command completed")
 	return nil
 }
+
+// printPlan writes the content of the plan to out, each line starting with the verb.
+func (action *Delete) printPlan(out io.Writer, repo *remote.Repository, plan deletePlan, verb string) error {
+	if !action.KeepReferrers {
+		for _, desc := range plan.referrers {
+			if _, err := fmt.Fprintf(out, "%s referrer %s (%s)\n", verb, desc.Digest, artifactType(desc)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, tag := range plan.tags {
+		if _, err := fmt.Fprintf(out, "%s tag %s:%s\n", verb, repo.Reference.Repository, tag); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(out, "%s manifest %s\n", verb, plan.manifest.Digest)
+	return err
+}
+
+// warnDependents writes a warning for each bottle listing the deleted bottle as a source, as known by telemetry, the
+// bottles in the same repository and the local bottle.  Failures to find dependents are logged and otherwise ignored,
+// only failures to write the warnings are returned.
+func (action *Delete) warnDependents(ctx context.Context, out io.Writer, repo content.Fetcher, target ocispec.Descriptor) error {
+	log := logger.FromContext(ctx)
+
+	bottleID, err := bottleConfigDigest(ctx, repo, target)
+	if err != nil {
+		log.InfoContext(ctx, "skipping dependents check", "error", err)
+		return nil
+	}
+
+	cfg := action.Config.Get(ctx)
+	var resolver lineage.MultiResolver
+	if len(cfg.Telemetry) > 0 {
+		telemAdapt := telem.NewAdapter(ctx, cfg.Telemetry, cfg.TelemetryUserName, telem.WithCredStore(action.Config.CredStore()))
+		resolver = append(resolver, &telemetryResolver{adapter: telemAdapt})
+	}
+	if scan, err := action.Config.Repository(ctx, action.Ref); err == nil {
+		scan.Reference.Reference = ""
+		resolver = append(resolver, lineage.NewRegistryResolver(map[string]lineage.Repository{scan.Reference.String(): scan}))
+	}
+
+	dependents, err := resolver.Dependents(ctx, bottleID)
+	if err != nil {
+		log.InfoContext(ctx, "unable to find dependent bottles", "error", err)
+	}
+	for _, id := range dependents {
+		def, location, err := resolver.Resolve(ctx, id)
+		if err != nil {
+			log.InfoContext(ctx, "unable to resolve dependent bottle", "bottleID", id, "error", err)
+			continue
+		}
+		if hasSource(id, def, bottleID) {
+			if _, err := fmt.Fprintf(out, "Warning: bottle %s (%s) lists %s as a source\n", id, location, bottleID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the local bottle, if any
+	if dir, err := bottle.FindBottleRootDir(action.Dir); err == nil {
+		if btl, err := LoadAndUpgradeBottle(ctx, cfg, dir); err == nil && hasSource("", &btl.Definition, bottleID) {
+			if _, err := fmt.Fprintf(out, "Warning: local bottle %s lists %s as a source\n", dir, bottleID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasSource returns true if the bottle lists the bottle ID as a source.
+func hasSource(id digest.Digest, def *cfgdef.Bottle, source digest.Digest) bool {
+	return slices.ContainsFunc(lineage.Outgoing(id, def), func(e lineage.Edge) bool {
+		return e.Relation == lineage.RelationSource && e.To == source
+	})
+}
+
+// bottleConfigDigest returns the bottle ID of the bottle manifest.
+func bottleConfigDigest(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) (digest.Digest, error) {
+	manBytes, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return "", fmt.Errorf("fetching manifest: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manBytes, &manifest); err != nil {
+		return "", fmt.Errorf("decoding manifest: %w", err)
+	}
+	if !mediatype.IsBottleConfig(manifest.Config.MediaType) {
+		return "", fmt.Errorf("manifest %s is not a bottle", desc.Digest)
+	}
+	return manifest.Config.Digest, nil
+}
+
+// findReferrers returns the referrers of the manifest, and their referrers in turn, each before the manifest it
+// refers to.
+func findReferrers(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	var result []ocispec.Descriptor
+	var visit func(desc ocispec.Descriptor) error
+	visit = func(desc ocispec.Descriptor) error {
+		referrers, err := repo.Predecessors(ctx, desc)
+		if err != nil {
+			return fmt.Errorf("finding referrers of %s: %w", desc.Digest, err)
+		}
+		for _, r := range referrers {
+			if slices.ContainsFunc(result, func(d ocispec.Descriptor) bool { return d.Digest == r.Digest }) {
+				continue
+			}
+			if err := visit(r); err != nil {
+				return err
+			}
+			result = append(result, r)
+		}
+		return nil
+	}
+	if err := visit(desc); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// findTags returns the tags of the repository pointing at the digest.
+func findTags(ctx context.Context, repo *remote.Repository, dgst digest.Digest) ([]string, error) {
+	var tags []string
+	err := repo.Tags(ctx, "", func(page []string) error {
+		for _, tag := range page {
+			desc, err := repo.Resolve(ctx, tag)
+			if err != nil {
+				return fmt.Errorf("resolving tag %s: %w", tag, err)
+			}
+			if desc.Digest == dgst {
+				tags = append(tags, tag)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	return tags, nil
+}
+
+// artifactType returns the artifact type of a referrer, or its media type if it has none.
+func artifactType(desc ocispec.Descriptor) string {
+	if desc.ArtifactType != "" {
+		return desc.ArtifactType
+	}
+	return desc.MediaType
+}
@@ -0,0 +1,71 @@
+package bottle
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+func Test_Delete(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -6))
+
+	// copy the example bottle to its own repository, with a second tag and a referrer
+	delRef := strings.Replace(destInfo.Ref, "/name:", "/deleted:", 1)
+	repo, err := config.Repository(ctx, delRef)
+	require.NoError(t, err)
+	src, err := config.GraphTarget(ctx, srcInfo.Ref)
+	require.NoError(t, err)
+	manDesc, err := oras.Copy(ctx, src, srcInfo.Ref, repo, "v1", oras.DefaultCopyOptions)
+	require.NoError(t, err)
+	require.NoError(t, repo.Tag(ctx, manDesc, "v2"))
+	referrer, err := oras.PackManifest(ctx, repo, oras.PackManifestVersion1_1, "application/vnd.example.report",
+		oras.PackManifestOptions{Subject: &manDesc})
+	require.NoError(t, err)
+
+	tool := &Action{DataTool: &actions.DataTool{Config: config}, Dir: t.TempDir()}
+
+	t.Run("dry run", func(t *testing.T) {
+		var out bytes.Buffer
+		action := &Delete{Action: tool, Ref: delRef, DryRun: true}
+		require.NoError(t, action.Run(ctx, &out))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Equal(t, []string{
+			"Would delete referrer " + referrer.Digest.String() + " (application/vnd.example.report)",
+			"Would delete tag " + repo.Reference.Repository + ":v1",
+			"Would delete tag " + repo.Reference.Repository + ":v2",
+			"Would delete manifest " + manDesc.Digest.String(),
+		}, lines)
+
+		exists, err := repo.Exists(ctx, manDesc)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		var out bytes.Buffer
+		action := &Delete{Action: tool, Ref: delRef}
+		require.NoError(t, action.Run(ctx, &out))
+		assert.Contains(t, out.String(), "Deleted tag "+repo.Reference.Repository+":v1\n")
+
+		for _, desc := range []ocispec.Descriptor{manDesc, referrer} {
+			exists, err := repo.Exists(ctx, desc)
+			require.NoError(t, err)
+			assert.False(t, exists, desc.Digest)
+		}
+		for _, tag := range []string{"v1", "v2"} {
+			_, err := repo.Resolve(ctx, tag)
+			assert.Error(t, err, tag)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package bottle
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/encrypt"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+)
+
+// EncryptionOptions defines options for encrypting bottle parts when committing.
+type EncryptionOptions struct {
+	Recipients []string // Aliases of configured encryption keys, or recipient files
+	Selectors  []string // Label selectors for the parts to encrypt
+	Parts      []string // Names of the parts to encrypt
+	Decrypt    bool     // Remove the encryption from all parts
+}
+
+// encryptOptions returns the bottle encryption options, or nil if the parts should keep their current encryption.
+func (opts *EncryptionOptions) encryptOptions(ctx context.Context, cfg *v1alpha1.Configuration) (*bottle.EncryptOptions, error) {
+	switch {
+	case opts.Decrypt && len(opts.Recipients) > 0:
+		return nil, errors.New("recipients can not be used when removing encryption")
+	case opts.Decrypt:
+		return &bottle.EncryptOptions{}, nil
+	case len(opts.Recipients) == 0 && (len(opts.Selectors) > 0 || len(opts.Parts) > 0):
+		return nil, errors.New("recipients are required for selecting parts to encrypt")
+	case len(opts.Recipients) == 0:
+		return nil, nil
+	}
+
+	recipients := make([]encrypt.Recipient, 0, len(opts.Recipients))
+	for _, r := range opts.Recipients {
+		path := r
+		for _, key := range cfg.EncryptionKeys {
+			if key.Alias == r {
+				path = key.RecipientPath
+				break
+			}
+		}
+		if path == "" {
+			return nil, fmt.Errorf("encryption key %q has no recipient", r)
+		}
+		rcpt, err := encrypt.LoadRecipient(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading recipient %q: %w", r, err)
+		}
+		recipients = append(recipients, rcpt)
+	}
+
+	options := &bottle.EncryptOptions{Recipients: recipients}
+	if len(opts.Selectors) > 0 || len(opts.Parts) > 0 {
+		selectorOpts := bottle.PartSelectorOptions{Labels: opts.Selectors, Names: opts.Parts}
+		selector, err := selectorOpts.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating part selector: %w", err)
+		}
+		options.Selector = selector
+	}
+	return options, nil
+}
+
+// decryptionKeys loads the private keys of the configured encryption keys.
+func decryptionKeys(cfg *v1alpha1.Configuration) ([]crypto.PrivateKey, error) {
+	keys := make([]crypto.PrivateKey, 0, len(cfg.EncryptionKeys))
+	for _, key := range cfg.EncryptionKeys {
+		if key.KeyPath == "" {
+			continue
+		}
+		privateKey, err := encrypt.LoadPrivateKey(key.KeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading encryption key %q: %w", key.Alias, err)
+		}
+		keys = append(keys, privateKey)
+	}
+	return keys, nil
+}
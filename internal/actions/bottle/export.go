@@ -0,0 +1,186 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/archive"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	intoci "github.com/act3-ai/data-tool/internal/oci"
+	tbtl "github.com/act3-ai/data-tool/internal/transfer/bottle"
+	"github.com/act3-ai/data-tool/internal/ui"
+	telem "github.com/act3-ai/data-tool/pkg/telemetry"
+	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// defaultLayoutTag is the tag of the bottle in an exported OCI image layout.
+const defaultLayoutTag = "latest"
+
+// Export represents the bottle export action.
+type Export struct {
+	*Action
+
+	Telemetry    actions.TelemetryOptions
+	PartSelector bottle.PartSelectorOptions
+	Tag          string // Tag of the bottle in the OCI image layout
+}
+
+// Run runs the bottle export action.  The source is a bottle directory or a bottle reference, the destination is a
+// tarball if it ends in ".tar" and an OCI image layout directory otherwise.
+func (action *Export) Run(ctx context.Context, source, dest string) error {
+	log := logger.FromContext(ctx)
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	log.InfoContext(ctx, "bottle export command activated")
+
+	tag := action.Tag
+	if tag == "" {
+		tag = defaultLayoutTag
+	}
+
+	selector, err := action.PartSelector.New(ctx)
+	if err != nil {
+		return fmt.Errorf("initializing part selector func: %w", err)
+	}
+
+	layoutDir := dest
+	tarball := isTarball(dest)
+	if tarball {
+		if layoutDir, err = os.MkdirTemp(filepath.Dir(dest), ".ace-dt-export-*"); err != nil {
+			return fmt.Errorf("creating temporary OCI layout directory: %w", err)
+		}
+		defer os.RemoveAll(layoutDir)
+	}
+
+	store, err := oci.NewWithContext(ctx, layoutDir)
+	if err != nil {
+		return fmt.Errorf("creating OCI layout store: %w", err)
+	}
+
+	var bottleID string
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		bottleID, err = action.exportLocal(ctx, source, store, tag, selector)
+		if err != nil {
+			return err
+		}
+	} else {
+		bottleID, err = action.exportRemote(ctx, source, store, tag, selector)
+		if err != nil {
+			return err
+		}
+	}
+
+	if tarball {
+		log.InfoContext(ctx, "writing OCI layout tarball", "path", dest)
+		if err := writeLayoutTarball(ctx, layoutDir, dest); err != nil {
+			return err
+		}
+	}
+
+	rootUI.Infof("Bottle export complete.  BottleID: %s\n", bottleID)
+	return nil
+}
+
+// exportLocal commits the bottle in dir, like push, and copies it into the store.
+func (action *Export) exportLocal(ctx context.Context, dir string, store *oci.Store, tag string,
+	selector bottle.PartSelectorFunc,
+) (string, error) {
+	action.Dir = dir
+	cfg, btl, err := action.prepare(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	logger.FromContext(ctx).InfoContext(ctx, "committing bottle")
+	if err := commit(ctx, cfg, btl, commitOptions{}); err != nil {
+		return "", err
+	}
+
+	opts := tbtl.ExportOptions{Concurrency: cfg.ConcurrentHTTP, Selector: selector}
+	if err := tbtl.ExportBottle(ctx, btl, store, tag, action.Config, opts); err != nil {
+		return "", fmt.Errorf("exporting bottle: %w", err)
+	}
+	return btl.GetBottleID().String(), nil
+}
+
+// exportRemote copies the bottle with the reference, with its signatures and other referrers, into the store.
+func (action *Export) exportRemote(ctx context.Context, bottleRef string, store *oci.Store, tag string,
+	selector bottle.PartSelectorFunc,
+) (string, error) {
+	log := logger.FromContext(ctx)
+
+	cfg := action.Config.Get(ctx)
+	telemAdapt := telem.NewAdapter(ctx, cfg.Telemetry, cfg.TelemetryUserName, telem.WithCredStore(action.Config.CredStore()))
+
+	log.InfoContext(ctx, "resolving reference with telemetry", "ref", bottleRef)
+	transferOpts := tbottle.TransferOptions{
+		Concurrency: cfg.ConcurrentHTTP,
+		CachePath:   cfg.CachePath,
+	}
+	src, desc, _, err := telemAdapt.ResolveWithTelemetry(ctx, bottleRef, action.Config, transferOpts)
+	if err != nil {
+		return "", fmt.Errorf("resolving bottle reference: %w", err)
+	}
+
+	log.InfoContext(ctx, "fetching bottle metadata")
+	cfgBytes, manBytes, err := tbottle.FetchBottleMetadata(ctx, src, desc, tbottle.PullOptions{TransferOptions: transferOpts})
+	if err != nil {
+		return "", fmt.Errorf("fetching bottle metadata: %w", err)
+	}
+
+	// the bottle only provides the part information for part selection
+	btl, err := bottle.NewBottle(
+		bottle.DisableDestinationCreate(true),
+		bottle.DisableCache(true),
+	)
+	if err != nil {
+		return "", fmt.Errorf("bottle initialization failed: %w", err)
+	}
+	manifestHandler := intoci.ManifestFromData(ocispec.MediaTypeImageManifest, manBytes)
+	if manifestHandler.GetStatus().Error != nil {
+		return "", fmt.Errorf("constructing manifest handler from raw manifest: %w", manifestHandler.GetStatus().Error)
+	}
+	btl.SetManifest(manifestHandler)
+	if err := btl.Configure(cfgBytes); err != nil {
+		return "", err
+	}
+
+	opts := tbtl.ExportOptions{Concurrency: cfg.ConcurrentHTTP, Selector: selector}
+	if err := tbtl.CopyBottle(ctx, btl, src, desc, store, tag, opts); err != nil {
+		return "", fmt.Errorf("exporting bottle: %w", err)
+	}
+	return btl.GetBottleID().String(), nil
+}
+
+// isTarball reports whether path names an OCI layout tarball instead of an OCI layout directory.
+func isTarball(path string) bool {
+	return strings.HasSuffix(path, ".tar")
+}
+
+// writeLayoutTarball archives the OCI layout directory into the tarball at path.
+func writeLayoutTarball(ctx context.Context, layoutDir, path string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating OCI layout tarball: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	// the empty ingest directory of the store is not part of the layout
+	_ = os.Remove(filepath.Join(layoutDir, "ingest"))
+
+	if err := archive.TarToStream(ctx, os.DirFS(layoutDir), f); err != nil {
+		return fmt.Errorf("archiving OCI layout: %w", err)
+	}
+	return nil
+}
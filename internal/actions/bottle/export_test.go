@@ -0,0 +1,78 @@
+package bottle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+func Test_ExportImport(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -6))
+	tool := &Action{DataTool: &actions.DataTool{Config: config}}
+	tarball := filepath.Join(t.TempDir(), "bottle.tar")
+
+	export := &Export{Action: tool}
+	require.NoError(t, export.Run(ctx, srcInfo.Ref, tarball))
+
+	t.Run("directory", func(t *testing.T) {
+		dir := t.TempDir()
+		importer := &Import{Action: &Action{DataTool: tool.DataTool, Dir: dir}}
+		require.NoError(t, importer.Run(ctx, tarball, ""))
+
+		for _, part := range []string{"part1.txt", "part2.txt"} {
+			assert.FileExists(t, filepath.Join(dir, part))
+		}
+		bottleID, err := os.ReadFile(filepath.Join(dir, ".dt", "bottleid"))
+		require.NoError(t, err)
+		assert.Equal(t, origDescs[1].Digest.String(), string(bottleID))
+	})
+
+	t.Run("registry", func(t *testing.T) {
+		destRef := strings.Replace(destInfo.Ref, "/name:", "/imported:", 1)
+		importer := &Import{Action: tool}
+		require.NoError(t, importer.Run(ctx, tarball, destRef))
+
+		repo, err := config.Repository(ctx, destRef)
+		require.NoError(t, err)
+		desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+		require.NoError(t, err)
+		assert.Equal(t, origDescs[0].Digest, desc.Digest)
+	})
+
+	t.Run("selected parts", func(t *testing.T) {
+		layout := filepath.Join(t.TempDir(), "layout")
+		export := &Export{Action: tool}
+		export.PartSelector.Names = []string{"part1.txt"}
+		require.NoError(t, export.Run(ctx, srcInfo.Ref, layout))
+
+		blobs, err := os.ReadDir(filepath.Join(layout, "blobs", "sha256"))
+		require.NoError(t, err)
+		assert.Len(t, blobs, 3) // manifest, config and part1.txt
+
+		// the partial bottle can be imported into a bottle directory, but not into a registry
+		dir := t.TempDir()
+		importer := &Import{Action: &Action{DataTool: tool.DataTool, Dir: dir}}
+		importer.PartSelector.Names = []string{"part1.txt"}
+		require.NoError(t, importer.Run(ctx, layout, ""))
+		assert.FileExists(t, filepath.Join(dir, "part1.txt"))
+
+		destRef := strings.Replace(destInfo.Ref, "/name:", "/partial:", 1)
+		importer = &Import{Action: tool}
+		err = importer.Run(ctx, layout, destRef)
+		require.ErrorContains(t, err, "missing 1 of the 2 parts")
+
+		repo, err := config.Repository(ctx, destRef)
+		require.NoError(t, err)
+		_, err = repo.Resolve(ctx, repo.Reference.Reference)
+		require.Error(t, err)
+	})
+}
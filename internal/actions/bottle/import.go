@@ -0,0 +1,189 @@
+package bottle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	dtreg "github.com/act3-ai/data-tool/internal/registry"
+	"github.com/act3-ai/data-tool/internal/ui"
+	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Import represents the bottle import action.
+type Import struct {
+	*Action
+
+	PartSelector bottle.PartSelectorOptions
+	Tag          string // Tag of the bottle in the OCI image layout, required if the layout has several tags
+}
+
+// Run runs the bottle import action.  The bottle in the OCI image layout directory or tarball at path is pushed to
+// the bottle reference, or pulled into the bottle directory if the reference is empty.
+func (action *Import) Run(ctx context.Context, path, bottleRef string) error {
+	log := logger.FromContext(ctx)
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	log.InfoContext(ctx, "bottle import command activated")
+
+	src, err := openLayout(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	tag, err := layoutTag(ctx, src, action.Tag)
+	if err != nil {
+		return err
+	}
+	desc, err := src.Resolve(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("resolving bottle in OCI layout: %w", err)
+	}
+
+	cfg := action.Config.Get(ctx)
+	transferOpts := tbottle.TransferOptions{
+		Concurrency: cfg.ConcurrentHTTP,
+		CachePath:   cfg.CachePath,
+	}
+
+	if bottleRef == "" {
+		keys, err := decryptionKeys(cfg)
+		if err != nil {
+			return err
+		}
+
+		log.InfoContext(ctx, "pulling bottle from OCI layout", "tag", tag, "pullPath", action.Dir)
+		pullOpts := tbottle.PullOptions{
+			TransferOptions:     transferOpts,
+			PartSelectorOptions: action.PartSelector,
+			DecryptionKeys:      keys,
+		}
+		if err := tbottle.Pull(ctx, src, desc, action.Dir, pullOpts); err != nil {
+			return fmt.Errorf("importing bottle: %w", err)
+		}
+		rootUI.Infof("Bottle import complete.  Bottle directory: %s\n", action.Dir)
+		return nil
+	}
+
+	// an export with a part selector only holds the selected parts, which is fine for a bottle directory but would
+	// push an incomplete bottle
+	if err := checkLayoutParts(ctx, src, desc); err != nil {
+		return err
+	}
+
+	repo, err := action.Config.GraphTarget(ctx, bottleRef)
+	if err != nil {
+		return fmt.Errorf("creating repository reference: %w", err)
+	}
+
+	log.InfoContext(ctx, "pushing bottle from OCI layout", "tag", tag, "ref", bottleRef)
+	extCopyOpts := oras.ExtendedCopyGraphOptions{
+		CopyGraphOptions: oras.CopyGraphOptions{Concurrency: cfg.ConcurrentHTTP},
+	}
+	if err := oras.ExtendedCopyGraph(ctx, src, repo, desc, extCopyOpts); err != nil {
+		return fmt.Errorf("importing bottle: %w", err)
+	}
+
+	// resolve the endpoint if necessary
+	regRef, err := dtreg.ParseEndpointOrDefault(action.Config, bottleRef)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	if err := repo.Tag(ctx, desc, regRef.String()); err != nil {
+		return fmt.Errorf("tagging bottle manifest: %w", err)
+	}
+
+	rootUI.Infof("Bottle import complete.  Reference: %s\n", bottleRef)
+	return nil
+}
+
+// checkLayoutParts returns an error if parts of the bottle with the manifest descriptor are missing from the OCI
+// layout, as they are when the bottle was exported with a part selector.
+func checkLayoutParts(ctx context.Context, src oras.ReadOnlyGraphTarget, desc ocispec.Descriptor) error {
+	data, err := content.FetchAll(ctx, src, desc)
+	if err != nil {
+		return fmt.Errorf("fetching bottle manifest from OCI layout: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("decoding bottle manifest: %w", err)
+	}
+
+	var missing int
+	for _, layer := range manifest.Layers {
+		exists, err := src.Exists(ctx, layer)
+		if err != nil {
+			return fmt.Errorf("checking for part in OCI layout: %w", err)
+		}
+		if !exists {
+			missing++
+		}
+	}
+	if missing > 0 {
+		return fmt.Errorf("OCI layout is missing %d of the %d parts of the bottle, as it was likely exported with a part selector; a partial bottle can only be imported into a bottle directory",
+			missing, len(manifest.Layers))
+	}
+	return nil
+}
+
+// openLayout opens the OCI image layout directory or tarball at path.
+func openLayout(ctx context.Context, path string) (oras.ReadOnlyGraphTarget, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening OCI layout: %w", err)
+	}
+	if info.IsDir() {
+		store, err := oci.NewWithContext(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("opening OCI layout directory: %w", err)
+		}
+		return store, nil
+	}
+	store, err := oci.NewFromTar(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("opening OCI layout tarball: %w", err)
+	}
+	return store, nil
+}
+
+// layoutTag returns the tag of the bottle in the OCI layout.  Without a requested tag the layout must contain exactly
+// one tag.
+func layoutTag(ctx context.Context, src oras.ReadOnlyGraphTarget, tag string) (string, error) {
+	if tag != "" {
+		return tag, nil
+	}
+
+	lister, ok := src.(registry.TagLister)
+	if !ok {
+		return defaultLayoutTag, nil
+	}
+	var tags []string
+	if err := lister.Tags(ctx, "", func(t []string) error {
+		tags = append(tags, t...)
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("listing OCI layout tags: %w", err)
+	}
+	slices.Sort(tags)
+
+	switch len(tags) {
+	case 0:
+		return "", errors.New("OCI layout does not contain a tagged bottle")
+	case 1:
+		return tags[0], nil
+	default:
+		return "", fmt.Errorf("OCI layout contains several tags, select one of %s", strings.Join(tags, ", "))
+	}
+}
@@ -0,0 +1,180 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/opencontainers/go-digest"
+	"oras.land/oras-go/v2/errdef"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/lineage"
+	"github.com/act3-ai/data-tool/internal/ref"
+	telem "github.com/act3-ai/data-tool/pkg/telemetry"
+	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Lineage represents the bottle lineage action.
+type Lineage struct {
+	*Action
+
+	Telemetry    actions.TelemetryOptions
+	Repositories []string // Additional repositories scanned for related bottles
+	Depth        int      // Maximum number of relations followed, unlimited if zero
+	Direction    string   // Relations followed, one of ancestors, descendants or both
+	Output       string   // Output format, one of tree, dot, mermaid or json
+}
+
+// Run runs the bottle lineage action.  The argument is a bottle reference or a bottle directory, the bottle in the
+// working directory is used if it is empty.
+func (action *Lineage) Run(ctx context.Context, out io.Writer, arg string) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "bottle lineage command activated")
+
+	direction := lineage.Direction(action.Direction)
+	switch direction {
+	case lineage.Ancestors, lineage.Descendants, lineage.Both:
+	default:
+		return fmt.Errorf("unknown direction %q, must be one of %s, %s or %s",
+			action.Direction, lineage.Ancestors, lineage.Descendants, lineage.Both)
+	}
+
+	cfg := action.Config.Get(ctx)
+	telemAdapt := telem.NewAdapter(ctx, cfg.Telemetry, cfg.TelemetryUserName, telem.WithCredStore(action.Config.CredStore()))
+
+	repositories := action.Repositories
+	var rootID digest.Digest
+	var root *cfgdef.Bottle
+	var location string
+
+	if info, err := os.Stat(arg); arg == "" || (err == nil && info.IsDir()) {
+		if arg != "" {
+			action.Dir = arg
+		}
+		_, btl, err := action.prepare(ctx)
+		if err != nil {
+			return err
+		}
+		rootID, root, location = btl.GetBottleID(), &btl.Definition, action.Dir
+	} else {
+		log.InfoContext(ctx, "resolving reference with telemetry", "ref", arg)
+		transferOpts := tbottle.TransferOptions{
+			Concurrency: cfg.ConcurrentHTTP,
+			CachePath:   cfg.CachePath,
+		}
+		src, desc, _, err := telemAdapt.ResolveWithTelemetry(ctx, arg, action.Config, transferOpts)
+		if err != nil {
+			return fmt.Errorf("resolving bottle reference: %w", err)
+		}
+		cfgBytes, _, err := tbottle.FetchBottleMetadata(ctx, src, desc, tbottle.PullOptions{TransferOptions: transferOpts})
+		if err != nil {
+			return fmt.Errorf("fetching bottle metadata: %w", err)
+		}
+		if root, err = bottle.DefinitionFromConfig(cfgBytes); err != nil {
+			return err
+		}
+		rootID, location = digest.FromBytes(cfgBytes), arg
+
+		// the repository of the bottle is scanned for related bottles
+		if r, err := ref.FromString(arg); err == nil && r.Scheme != ref.SchemeBottle {
+			repositories = append([]string{arg}, repositories...)
+		}
+	}
+
+	repos := make(map[string]lineage.Repository, len(repositories))
+	for _, name := range repositories {
+		repo, err := action.Config.Repository(ctx, name)
+		if err != nil {
+			return err
+		}
+		repo.Reference.Reference = ""
+		repos[repo.Reference.String()] = repo
+	}
+
+	var resolver lineage.MultiResolver
+	if len(cfg.Telemetry) > 0 {
+		resolver = append(resolver, &telemetryResolver{adapter: telemAdapt})
+	}
+	resolver = append(resolver, lineage.NewRegistryResolver(repos))
+
+	graph, err := lineage.Build(ctx, rootID, root, location, resolver, lineage.Options{
+		Direction: direction,
+		Depth:     action.Depth,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := lineage.Render(out, graph, action.Output); err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "bottle lineage command completed", "bottles", len(graph.Bottles), "cycles", len(graph.Cycles))
+	return nil
+}
+
+// telemetryResolver resolves bottles by their bottle ID with the telemetry hosts.  Dependents are found by reading
+// every bottle known by the telemetry hosts, once, on first use.
+type telemetryResolver struct {
+	adapter *telem.Adapter
+
+	indexed    bool
+	dependents map[digest.Digest][]digest.Digest
+}
+
+func (r *telemetryResolver) Resolve(ctx context.Context, id digest.Digest) (*cfgdef.Bottle, string, error) {
+	log := logger.FromContext(ctx)
+
+	cfgBytes, err := r.adapter.GetBottleConfig(ctx, id)
+	switch {
+	case errors.Is(err, errdef.ErrNotFound):
+		logger.V(log, 1).InfoContext(ctx, "bottle not found with telemetry", "bottleID", id)
+		return nil, "", fmt.Errorf("bottle %s: %w", id, err)
+	case err != nil:
+		return nil, "", fmt.Errorf("resolving bottle %s with telemetry: %w", id, err)
+	}
+	def, err := bottle.DefinitionFromConfig(cfgBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var location string
+	refs, err := r.adapter.FindBottle(ctx, ref.Ref{Scheme: ref.SchemeBottle, Digest: id.String()})
+	switch {
+	case err != nil:
+		logger.V(log, 1).InfoContext(ctx, "bottle location not found with telemetry", "bottleID", id, "error", err)
+	case len(refs) > 0:
+		location = refs[0].String()
+	}
+	return def, location, nil
+}
+
+func (r *telemetryResolver) Dependents(ctx context.Context, id digest.Digest) ([]digest.Digest, error) {
+	if !r.indexed {
+		r.dependents = make(map[digest.Digest][]digest.Digest)
+		if err := r.adapter.WalkBottles(ctx, func(dependent digest.Digest, cfgBytes []byte) error {
+			def, err := bottle.DefinitionFromConfig(cfgBytes)
+			if err != nil {
+				logger.V(logger.FromContext(ctx), 1).InfoContext(ctx, "skipping undecodable telemetry bottle",
+					"bottleID", dependent, "error", err)
+				return nil
+			}
+			for _, e := range lineage.Outgoing(dependent, def) {
+				r.dependents[e.To] = append(r.dependents[e.To], dependent)
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("finding dependents with telemetry: %w", err)
+		}
+		r.indexed = true
+	}
+	return r.dependents[id], nil
+}
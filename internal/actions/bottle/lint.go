@@ -0,0 +1,102 @@
+package bottle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/act3-ai/data-tool/internal/actions/internal/format"
+	"github.com/act3-ai/data-tool/internal/bottle/lint"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// ErrLintFailed is the error when a bottle has findings at or above the failing severity.
+var ErrLintFailed = errors.New("bottle lint failed")
+
+// Lint represents the bottle lint action.
+type Lint struct {
+	*Action
+
+	Rules   string   // Path to a lint rules file
+	Disable []string // Rules to disable
+	FailOn  string   // Minimum severity of findings that fail the lint
+	JSON    bool     // Output findings as JSON
+}
+
+// Run runs the bottle lint action.
+func (action *Lint) Run(ctx context.Context, out io.Writer) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "bottle lint command activated")
+
+	failOn, err := lint.ParseSeverity(action.FailOn)
+	if err != nil {
+		return err
+	}
+
+	cfg := lint.DefaultConfig()
+	if action.Rules != "" {
+		log.InfoContext(ctx, "loading lint rules", "path", action.Rules)
+		if cfg, err = lint.LoadConfig(action.Rules); err != nil {
+			return err
+		}
+	}
+	for _, rule := range action.Disable {
+		if err := cfg.SetSeverity(rule, lint.SeverityOff); err != nil {
+			return err
+		}
+	}
+
+	_, btl, err := action.prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	findings, err := lint.Lint(&btl.Definition, btl.GetParts(), cfg)
+	if err != nil {
+		return err
+	}
+
+	if action.JSON {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return fmt.Errorf("encoding findings: %w", err)
+		}
+	} else if err := printFindings(out, findings); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, f := range findings {
+		if failOn != lint.SeverityOff && f.Severity.AtLeast(failOn) {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d finding(s) with severity %s or higher", ErrLintFailed, failed, failOn)
+	}
+
+	log.InfoContext(ctx, "bottle lint command completed", "findings", len(findings))
+	return nil
+}
+
+func printFindings(out io.Writer, findings []lint.Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(out, "No lint findings")
+		return err
+	}
+
+	t := format.NewTable()
+	t.AddRow("SEVERITY", "RULE", "PART", "MESSAGE")
+	for _, f := range findings {
+		t.AddRow(f.Severity, f.Rule, f.Part, f.Message)
+	}
+	_, err := fmt.Fprintln(out, t.String())
+	return err
+}
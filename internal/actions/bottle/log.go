@@ -0,0 +1,58 @@
+package bottle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/act3-ai/data-tool/internal/actions/internal/format"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Log represents the bottle log action.
+type Log struct {
+	*Action
+}
+
+// Run runs the bottle log action.
+func (action *Log) Run(ctx context.Context, out io.Writer) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "bottle log command activated")
+
+	dir, err := bottle.FindBottleRootDir(action.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to find root bottle directory starting from %s: %w", action.Dir, err)
+	}
+
+	records, err := bottle.ReadHistory(dir)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(out, "No commits recorded for this bottle")
+		return err
+	}
+
+	current, _ := bottle.ReadBottleIDFile(dir) // empty if never committed
+
+	t := format.NewTable()
+	t.AddRow("", "INDEX", "BOTTLE ID", "CREATED", "MESSAGE")
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		marker := ""
+		if r.BottleID == current {
+			marker = "*"
+		}
+		t.AddRow(marker, strconv.Itoa(len(records)-1-i), r.BottleID, r.Created.Local().Format(time.RFC3339), r.Message)
+	}
+	if _, err := fmt.Fprintln(out, t); err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "bottle log command completed")
+	return nil
+}
@@ -0,0 +1,157 @@
+package bottle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	latest "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/data-tool/internal/actions/internal/format"
+	"github.com/act3-ai/data-tool/internal/bottle/metrics"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// MetricImport represents the bottle metric import action.
+type MetricImport struct {
+	*Action
+
+	Format       string            // Format of the metrics file, detected if empty
+	Aggregations []string          // Aggregations, either AGGREGATION for all metrics or PATTERN=AGGREGATION
+	Descriptions map[string]string // Descriptions by metric name, before adding the prefix
+	Include      []string          // Patterns of the metric names to import, all if empty
+	Prefix       string            // Prefix added to the imported metric names
+	DryRun       bool              // Show the metrics without adding them
+}
+
+// aggregationRule is an aggregation for the metrics matching a pattern.
+type aggregationRule struct {
+	pattern     *regexp.Regexp
+	aggregation metrics.Aggregation
+}
+
+// aggregationRules parses the aggregations, where later ones take precedence.
+func (action *MetricImport) aggregationRules() ([]aggregationRule, error) {
+	rules := make([]aggregationRule, 0, len(action.Aggregations))
+	for _, s := range action.Aggregations {
+		pattern, name, found := strings.Cut(s, "=")
+		if !found {
+			pattern, name = "*", s
+		}
+		agg, err := metrics.ParseAggregation(name)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, aggregationRule{pattern: metricPattern(pattern), aggregation: agg})
+	}
+	return rules, nil
+}
+
+// metricPattern returns the regular expression matching metric names to the pattern, where "*" matches any
+// characters, including "/", and "?" any one character.
+func metricPattern(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// Run runs the bottle metric import action.
+func (action *MetricImport) Run(ctx context.Context, file string, out io.Writer) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "metric import command activated")
+
+	rules, err := action.aggregationRules()
+	if err != nil {
+		return err
+	}
+
+	series, err := metrics.Read(file, metrics.Format(strings.ToLower(action.Format)))
+	if err != nil {
+		return err
+	}
+
+	_, btl, err := action.prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	include := make([]*regexp.Regexp, len(action.Include))
+	for i, pattern := range action.Include {
+		include[i] = metricPattern(pattern)
+	}
+
+	t := format.NewTable()
+	t.AddRow("METRIC", "VALUE", "AGGREGATION", "VALUES")
+	var imported int
+	for _, name := range slices.Sorted(maps.Keys(series)) {
+		if len(include) > 0 && !slices.ContainsFunc(include, func(re *regexp.Regexp) bool { return re.MatchString(name) }) {
+			logger.V(log, 1).InfoContext(ctx, "skipping metric not included", "name", name)
+			continue
+		}
+
+		agg := metrics.AggregateLast
+		for _, rule := range rules {
+			if rule.pattern.MatchString(name) {
+				agg = rule.aggregation
+			}
+		}
+		points := series[name]
+		value, err := agg.Apply(points)
+		if err != nil {
+			return fmt.Errorf("metric %s: %w", name, err)
+		}
+
+		description, ok := action.Descriptions[name]
+		if !ok {
+			description = fmt.Sprintf("imported from %s", filepath.Base(file))
+			if len(points) > 1 {
+				description = fmt.Sprintf("%s of %d values %s", agg, len(points), description)
+			}
+		}
+		metric := latest.Metric{
+			Name:        action.Prefix + name,
+			Description: description,
+			Value:       strconv.FormatFloat(value, 'g', -1, 64),
+		}
+		t.AddRow(metric.Name, metric.Value, string(agg), len(points))
+		imported++
+
+		if action.DryRun {
+			continue
+		}
+		log.InfoContext(ctx, "Adding imported metric to bottle", "name", metric.Name, "value", metric.Value)
+		if err := btl.AddMetricInfo(metric); err != nil {
+			return err
+		}
+	}
+	if imported == 0 {
+		return fmt.Errorf("no metrics of %s match %s", file, strings.Join(action.Include, ", "))
+	}
+
+	if _, err := fmt.Fprintln(out, t.String()); err != nil {
+		return err
+	}
+	if action.DryRun {
+		return nil
+	}
+
+	log.InfoContext(ctx, "Saving bottle with imported metrics")
+	return saveMetaChanges(ctx, btl)
+}
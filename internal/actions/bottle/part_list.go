@@ -30,6 +30,11 @@ func (action *PartList) Run(ctx context.Context, out io.Writer) error {
 		return err
 	}
 
+	// preview the labels the next commit sets from the label rules file
+	if _, err := bottle.ApplyLabelRules(ctx, btl); err != nil {
+		return err
+	}
+
 	if btl.NumParts() == 0 {
 		log.InfoContext(ctx, "bottle has no parts to show", "path", action.Dir)
 		return nil
@@ -7,6 +7,7 @@ import (
 
 	"github.com/act3-ai/data-tool/internal/actions"
 	"github.com/act3-ai/data-tool/internal/bottle"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
 	"github.com/act3-ai/data-tool/internal/ui"
 	telem "github.com/act3-ai/data-tool/pkg/telemetry"
 	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
@@ -17,8 +18,12 @@ import (
 type Pull struct {
 	*Action
 
-	Telemetry    actions.TelemetryOptions
-	PartSelector bottle.PartSelectorOptions
+	Telemetry     actions.TelemetryOptions
+	PartSelector  bottle.PartSelectorOptions
+	RestoreXattrs bool // Restore extended attributes of parts committed with them
+	SparseFiles   bool // Write blocks of zeros in directory parts as holes
+
+	Verify string // Path to a trust policy file the bottle's signatures must satisfy before pulling
 }
 
 // Run runs the bottle pull action.
@@ -39,6 +44,22 @@ func (action *Pull) Run(ctx context.Context, bottleRef string) error {
 		return fmt.Errorf("resolving bottle reference: %w", err)
 	}
 
+	if action.Verify != "" {
+		log.InfoContext(ctx, "verifying bottle signatures before pulling", "policy", action.Verify)
+		sigsHandler, err := sigcustom.FetchSignatures(ctx, src, desc)
+		if err != nil {
+			return err
+		}
+		if err := action.verifyPolicy(ctx, action.Verify, actions.RepositoryScope(bottleRef), sigsHandler); err != nil {
+			return fmt.Errorf("bottle %s not pulled: %w", bottleRef, err)
+		}
+	}
+
+	keys, err := decryptionKeys(cfg)
+	if err != nil {
+		return err
+	}
+
 	log.InfoContext(ctx, "pulling bottle", "reference", bottleRef, "pullPath", action.Dir)
 	pullOpts := tbottle.PullOptions{
 		TransferOptions: tbottle.TransferOptions{
@@ -46,6 +67,9 @@ func (action *Pull) Run(ctx context.Context, bottleRef string) error {
 			CachePath:   cfg.CachePath,
 		},
 		PartSelectorOptions: action.PartSelector,
+		RestoreXattrs:       action.RestoreXattrs,
+		SparseFiles:         action.SparseFiles,
+		DecryptionKeys:      keys,
 	}
 	err = tbottle.Pull(ctx, src, desc, action.Dir, pullOpts)
 	if err != nil {
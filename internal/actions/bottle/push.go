@@ -5,12 +5,9 @@ import (
 	"errors"
 	"fmt"
 
-	"oras.land/oras-go/v2/errdef"
-
-	"github.com/act3-ai/data-telemetry/v3/pkg/types"
-
 	"github.com/act3-ai/data-tool/internal/actions"
-	"github.com/act3-ai/data-tool/internal/ref"
+	"github.com/act3-ai/data-tool/internal/actions/internal/format"
+	"github.com/act3-ai/data-tool/internal/bottle"
 	tbtl "github.com/act3-ai/data-tool/internal/transfer/bottle"
 	"github.com/act3-ai/data-tool/internal/ui"
 	telem "github.com/act3-ai/data-tool/pkg/telemetry"
@@ -24,11 +21,12 @@ type Push struct {
 
 	Telemetry   actions.TelemetryOptions
 	Compression CompressionLevelOptions
+	Encryption  EncryptionOptions
 
 	NoOverwrite bool // Only push data if if doesn't already exist
 	NoDeprecate bool // Don't deprecate existing bottle
 
-	Ref string
+	Refs []string // Bottle references to push to, the configured push destinations if empty
 }
 
 // Run runs the bottle push action.
@@ -43,66 +41,107 @@ func (action *Push) Run(ctx context.Context) error {
 		return err
 	}
 
+	refs := action.Refs
+	if len(refs) == 0 {
+		refs = cfg.PushDestinations
+	}
+	if len(refs) == 0 {
+		return errors.New("no bottle reference given and no push destinations configured")
+	}
+
 	// first we must commit, this saves everything: manifest, config, archived parts, etc.
 	log.InfoContext(ctx, "committing bottle")
-	if err := commit(ctx, cfg, btl, action.NoDeprecate); err != nil {
+	if err := commit(ctx, cfg, btl, commitOptions{NoDeprecate: action.NoDeprecate, Encryption: action.Encryption}); err != nil {
 		return err
 	}
 
-	if action.NoOverwrite {
-		log.InfoContext(ctx, "checking for existing bottles")
-		parsedref := ref.RepoFromString(action.Ref)
-		repo, err := action.Config.Repository(ctx, parsedref.String())
-		if err != nil {
-			return fmt.Errorf("creating repository reference: %w", err)
-		}
-
-		_, err = repo.Resolve(ctx, repo.Reference.Reference)
-		switch {
-		case errors.Is(err, errdef.ErrNotFound):
-			// continue
-		case err != nil:
-			// unwanted error
-			return fmt.Errorf("checking if reference exists: %w", err)
-		default:
-			// reference already exists
-			return fmt.Errorf("bottle reference %s already exists. Please choose another repository or tag before pushing. %w",
-				action.Ref, err)
-		}
-
-	}
-
-	log.InfoContext(ctx, "pushing bottle with signatures")
 	pushOpts := tbtl.PushOptions{
 		TransferOptions: tbottle.TransferOptions{
 			Concurrency: cfg.ConcurrentHTTP,
 			CachePath:   cfg.CachePath,
 		},
 	}
-	if err := tbtl.PushBottle(ctx, btl, action.Config, action.Ref, pushOpts); err != nil {
-		return fmt.Errorf("pushing bottle and signatures: %w", err)
-	}
 
-	r, err := ref.FromString(action.Ref)
-	if err != nil {
-		return fmt.Errorf("invalid bottle reference %s: %w", action.Ref, err)
-	}
+	var pushed []string
+	if len(refs) == 1 {
+		if action.NoOverwrite {
+			if err := tbtl.CheckNoOverwrite(ctx, action.Config, refs[0]); err != nil {
+				return err
+			}
+		}
 
-	// Handle telemetry
-	rawManifest, err := btl.Manifest.GetManifestRaw()
-	if err != nil {
-		return fmt.Errorf("getting bottle manifest: %w", err)
+		log.InfoContext(ctx, "pushing bottle with signatures")
+		if err := tbtl.PushBottle(ctx, btl, action.Config, refs[0], pushOpts); err != nil {
+			return fmt.Errorf("pushing bottle and signatures: %w", err)
+		}
+		pushed = refs
+	} else {
+		pushed, err = action.pushAll(ctx, btl, refs, pushOpts)
+		if err != nil {
+			return err
+		}
 	}
 
+	// Handle telemetry
 	telemAdapt := telem.NewAdapter(ctx, cfg.Telemetry, cfg.TelemetryUserName, telem.WithCredStore(action.Config.CredStore()))
-	event := telemAdapt.NewEvent(r.String(), rawManifest, types.EventPush)
-
-	telemUrls, err := telemAdapt.NotifyTelemetry(ctx, btl.GetCache(), btl.Manifest.GetManifestDescriptor(), action.Dir, event)
+	telemUrls, err := tbtl.NotifyPush(ctx, btl, telemAdapt, pushed)
 	if err != nil {
-		return fmt.Errorf("notifying telemetry: %w", err)
+		return err
 	}
 
 	rootUI.Info(formatBottleURLs(telemUrls))
+	if len(pushed) < len(refs) {
+		return fmt.Errorf("bottle %s pushed to %d of %d destinations", btl.GetBottleID(), len(pushed), len(refs))
+	}
 	rootUI.Infof("Bottle push complete.  BottleID: %s\n", btl.GetBottleID())
 	return nil
 }
+
+// pushAll pushes the bottle to all references in one copy, reporting the outcome for each of them.  Returns the
+// references the bottle was pushed to.
+func (action *Push) pushAll(ctx context.Context, btl *bottle.Bottle, refs []string, pushOpts tbtl.PushOptions) ([]string, error) {
+	log := logger.FromContext(ctx)
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	failures := make(map[string]error)
+	dests := make([]string, 0, len(refs))
+	for _, reference := range refs {
+		if action.NoOverwrite {
+			if err := tbtl.CheckNoOverwrite(ctx, action.Config, reference); err != nil {
+				failures[reference] = err
+				continue
+			}
+		}
+		dests = append(dests, reference)
+	}
+
+	log.InfoContext(ctx, "pushing bottle with signatures", "destinations", len(dests))
+	results, err := tbtl.PushBottles(ctx, btl, action.Config, dests, pushOpts)
+	if err != nil {
+		return nil, fmt.Errorf("pushing bottle and signatures: %w", err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			failures[result.Reference] = result.Err
+		}
+	}
+
+	t := format.NewTable()
+	t.AddRow("DESTINATION", "STATUS")
+	pushed := make([]string, 0, len(refs))
+	for _, reference := range refs {
+		if err, failed := failures[reference]; failed {
+			log.InfoContext(ctx, "bottle push failed", "reference", reference, "error", err)
+			t.AddRow(reference, "failed: "+err.Error())
+			continue
+		}
+		t.AddRow(reference, "pushed")
+		pushed = append(pushed, reference)
+	}
+	rootUI.Info(t.String())
+
+	if len(pushed) == 0 {
+		return nil, errors.New("bottle push failed for all destinations")
+	}
+	return pushed, nil
+}
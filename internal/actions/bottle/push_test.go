@@ -188,8 +188,72 @@ func Test_VirtualParts(t *testing.T) {
 	t.Log("Validation successful")
 }
 
-// push pushes a bottle to an oras.GraphTarget identified by destInfo.
-func push(t *testing.T, ctx context.Context, btlDir string, destInfo *DestStoreInfo) { //nolint
+func Test_PushMultiple(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -6))
+
+	// a virtual part is copied to the destination registry and mounted in the source registry
+	pullDir := t.TempDir()
+	pull(t, ctx, pullDir, srcInfo, selectParts([]string{"part1.txt", "part2.txt"}, 1))
+
+	btl := loadCommitted(t, ctx, pullDir)
+
+	srcReg, _, _ := strings.Cut(srcInfo.Ref, "/")
+	destReg, _, _ := strings.Cut(destInfo.Ref, "/")
+	refs := []string{
+		destReg + "/multi/one:v1",
+		"127.0.0.1:1/unreachable/name:v1",
+		destReg + "/multi/two:v1",
+		srcReg + "/multi/copy:v1",
+	}
+	pushOpts := tbtl.PushOptions{
+		TransferOptions: tbottle.TransferOptions{
+			CachePath: blobInfoCacheDir,
+		},
+	}
+	results, err := tbtl.PushBottles(ctx, btl, config, refs, pushOpts)
+	if err != nil {
+		t.Fatalf("pushing bottle: error = %v", err)
+	}
+	if len(results) != len(refs) {
+		t.Fatalf("got %d results, want %d", len(results), len(refs))
+	}
+
+	for i, result := range results {
+		if result.Reference != refs[i] {
+			t.Errorf("result %d is for %s, want %s", i, result.Reference, refs[i])
+		}
+		if strings.Contains(result.Reference, "unreachable") {
+			if result.Err == nil {
+				t.Errorf("push to %s succeeded", result.Reference)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("pushing to %s: error = %v", result.Reference, result.Err)
+			continue
+		}
+
+		repo, err := config.GraphTarget(ctx, result.Reference)
+		if err != nil {
+			t.Fatalf("connecting to %s: error = %v", result.Reference, err)
+		}
+		for _, desc := range origDescs {
+			exists, err := repo.Exists(ctx, desc)
+			switch {
+			case err != nil:
+				t.Errorf("checking descriptor existence in %s: digest = '%s', error = %v", result.Reference, desc.Digest, err)
+			case !exists:
+				t.Errorf("descriptor not found in %s: mediatype = '%s', digest = '%s'", result.Reference, desc.MediaType, desc.Digest)
+			}
+		}
+		if _, err := repo.Resolve(ctx, result.Reference); err != nil {
+			t.Errorf("resolving %s: error = %v", result.Reference, err)
+		}
+	}
+}
+
+// loadCommitted loads and commits the bottle in btlDir.
+func loadCommitted(t *testing.T, ctx context.Context, btlDir string) *bottle.Bottle { //nolint
 	t.Helper()
 	cfg := config.Get(ctx)
 	btl, err := bottle.LoadBottle(btlDir,
@@ -204,9 +268,16 @@ func push(t *testing.T, ctx context.Context, btlDir string, destInfo *DestStoreI
 	}
 
 	// commit bottle
-	if err := commit(ctx, cfg, btl, false); err != nil {
+	if err := commit(ctx, cfg, btl, commitOptions{}); err != nil {
 		t.Fatalf("committing bottle: error = %v", err)
 	}
+	return btl
+}
+
+// push pushes a bottle to an oras.GraphTarget identified by destInfo.
+func push(t *testing.T, ctx context.Context, btlDir string, destInfo *DestStoreInfo) { //nolint
+	t.Helper()
+	btl := loadCommitted(t, ctx, btlDir)
 
 	pushOpts := tbtl.PushOptions{
 		TransferOptions: tbottle.TransferOptions{
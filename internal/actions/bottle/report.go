@@ -0,0 +1,159 @@
+package bottle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/report"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/data-tool/internal/util"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// reportArtifactName is the public artifact name of an attached report.
+const reportArtifactName = "report"
+
+// ReportOptions are the options for rendering a bottle report.
+type ReportOptions struct {
+	Format   string // Format of the built-in template
+	Template string // Path to a user supplied template, overriding the format
+}
+
+// Report represents the bottle report action.
+type Report struct {
+	*Action
+
+	ReportOptions
+	Output string // Path of the rendered report, standard output if empty
+	Attach bool   // Attach the report to the bottle as a public artifact
+}
+
+// Run runs the bottle report action.
+func (action *Report) Run(ctx context.Context, out io.Writer) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "bottle report command activated")
+
+	_, btl, err := action.prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	if action.Attach {
+		if err := attachReport(ctx, btl, action.ReportOptions, action.Output); err != nil {
+			return err
+		}
+		return saveMetaChanges(ctx, btl)
+	}
+
+	r := report.New(btl)
+	r.Signatures = localSignatures(ctx, btl)
+
+	if action.Output != "" {
+		f, err := os.Create(action.Output)
+		if err != nil {
+			return fmt.Errorf("creating report file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := report.Render(out, r, action.Format, action.Template); err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "bottle report command completed")
+	return nil
+}
+
+// localSignatures returns the signatures of the committed bottle found in the bottle directory.
+func localSignatures(ctx context.Context, btl *bottle.Bottle) []report.Signature {
+	log := logger.FromContext(ctx)
+
+	if err := btl.ConstructManifest(); err != nil {
+		log.InfoContext(ctx, "bottle manifest unavailable, skipping signatures", "error", err)
+		return nil
+	}
+	sigsHandler, err := sigcustom.LoadLocalSignatures(ctx, btl.Manifest.GetManifestDescriptor(),
+		filepath.Join(btl.GetPath(), ".signature"))
+	if err != nil {
+		log.InfoContext(ctx, "unable to load signatures", "error", err)
+		return nil
+	}
+
+	sigs := sigsHandler.Signatures()
+	result := make([]report.Signature, 0, len(sigs))
+	for _, sig := range sigs {
+		annos, err := sig.Annotations()
+		if err != nil {
+			log.InfoContext(ctx, "unable to read signature annotations", "error", err)
+		}
+		result = append(result, report.Signature{
+			Digest:   sig.GetDescriptor().Digest,
+			Identity: annos[sigcustom.AnnotationUserID],
+			KeyID:    annos[sigcustom.AnnotationKeyID],
+		})
+	}
+	return result
+}
+
+// attachReport renders the report into a file at the root of the bottle, and adds it as a public artifact.  The
+// report leaves out anything that changes when it is attached, namely the bottle ID, the signatures and its own part,
+// so attaching it again to an unchanged bottle reproduces the same file and bottle ID.
+func attachReport(ctx context.Context, btl *bottle.Bottle, options ReportOptions, path string) error {
+	log := logger.FromContext(ctx)
+
+	if path == "" {
+		name := "report" + report.Extension(options.Format)
+		if options.Template != "" {
+			name = "report" + filepath.Ext(options.Template)
+		}
+		path = filepath.Join(btl.GetPath(), name)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolving report path: %w", err)
+	}
+	if filepath.Dir(absPath) != filepath.Clean(btl.GetPath()) {
+		return fmt.Errorf("attached report %s must be a file at the root of the bottle directory %s", path, btl.GetPath())
+	}
+	partName := filepath.Base(absPath)
+	mediaType := report.MediaType(absPath)
+
+	r := report.New(btl)
+	r.BottleID = ""
+	r.Parts = slices.DeleteFunc(r.Parts, func(p report.Part) bool { return p.Name == partName })
+	def := btl.Definition.DeepCopy()
+	if !slices.ContainsFunc(def.PublicArtifacts, func(a cfgdef.PublicArtifact) bool { return a.Path == partName }) {
+		def.PublicArtifacts = append(def.PublicArtifacts,
+			cfgdef.PublicArtifact{Name: reportArtifactName, Path: partName, MediaType: mediaType})
+	}
+	r.Bottle = def
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, r, options.Format, options.Template); err != nil {
+		return err
+	}
+
+	// an unchanged report is not written, so the part is not processed again
+	if existing, err := os.ReadFile(absPath); err != nil || !bytes.Equal(existing, buf.Bytes()) {
+		log.InfoContext(ctx, "writing bottle report", "path", absPath)
+		if err := os.WriteFile(absPath, buf.Bytes(), 0o666); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	}
+
+	dgst, err := util.DigestFile(absPath)
+	if err != nil {
+		return fmt.Errorf("artifact digest: %w", err)
+	}
+	return btl.AddArtifact(reportArtifactName, absPath, mediaType, dgst)
+}
@@ -0,0 +1,107 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// RevocationAdd represents the bottle revocation add action.
+type RevocationAdd struct {
+	*Action
+
+	Reason string // Reason the keys are revoked
+}
+
+// Run runs the bottle revocation add action, adding the fingerprints to the revocation list file, which is created if
+// it does not exist.
+func (action *RevocationAdd) Run(ctx context.Context, listFile string, fingerprints []string, out io.Writer) error {
+	log := logger.FromContext(ctx)
+
+	list, err := sigcustom.LoadRevocationList(listFile)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		log.InfoContext(ctx, "creating revocation list", "file", listFile)
+		list = &sigcustom.RevocationList{}
+	case err != nil:
+		return err
+	}
+
+	for _, fp := range fingerprints {
+		if err := list.Add(digest.Digest(fp), action.Reason); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "Revoked key %s\n", fp); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+	}
+	return list.Write(listFile)
+}
+
+// RevocationPush represents the bottle revocation push action.
+type RevocationPush struct {
+	*Action
+
+	Expiry time.Duration // How long the signature of the revocation list is valid, 0 for no expiry
+}
+
+// Run runs the bottle revocation push action, pushing the revocation list file to the reference as a revocation list
+// artifact signed with the signing key.  The reference is only tagged once the signature is pushed.
+func (action *RevocationPush) Run(ctx context.Context, listFile, ref, keyAlias string, out io.Writer) error {
+	log := logger.FromContext(ctx)
+	cfg := action.Config.Get(ctx)
+
+	if err := actions.CheckExpiry(action.Expiry); err != nil {
+		return err
+	}
+	list, err := sigcustom.LoadRevocationList(listFile)
+	if err != nil {
+		return err
+	}
+	signerProvider, unsignedAnnos, err := actions.SigningKey(ctx, cfg, keyAlias)
+	if err != nil {
+		return err
+	}
+
+	repo, err := action.Config.Repository(ctx, ref)
+	if err != nil {
+		return err
+	}
+	desc, err := sigcustom.PackRevocationList(ctx, repo, list)
+	if err != nil {
+		return err
+	}
+	log.InfoContext(ctx, "pushed revocation list", "digest", desc.Digest)
+
+	// the signature is held in memory until pushed
+	sigsHandler, err := sigcustom.FetchSignatures(ctx, repo, desc)
+	if err != nil {
+		return err
+	}
+	sigsHandler.ExpiryDuration = action.Expiry
+	if err := sigsHandler.Sign(ctx, signerProvider, unsignedAnnos, nil); err != nil {
+		return fmt.Errorf("signing revocation list: %w", err)
+	}
+	if err := sigsHandler.Push(ctx, repo); err != nil {
+		return fmt.Errorf("pushing revocation list signature: %w", err)
+	}
+
+	tag := repo.Reference.ReferenceOrDefault()
+	if err := repo.Tag(ctx, desc, tag); err != nil {
+		return fmt.Errorf("tagging revocation list: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(out, "Pushed signed revocation list %s@%s with %d revoked keys\n", actions.RepositoryScope(ref), desc.Digest, len(list.Revoked)); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package bottle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/bottlefs"
+	"github.com/act3-ai/data-tool/internal/oci"
+	telem "github.com/act3-ai/data-tool/pkg/telemetry"
+	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
+	"github.com/act3-ai/go-common/pkg/httputil"
+	"github.com/act3-ai/go-common/pkg/httputil/promhttputil"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Serve represents the bottle serve action.
+type Serve struct {
+	*Action
+
+	Telemetry actions.TelemetryOptions
+	Listen    string
+}
+
+// Run runs the bottle serve action.
+func (action *Serve) Run(ctx context.Context, bottleRef string) error {
+	log := logger.FromContext(ctx)
+
+	cfg := action.Config.Get(ctx)
+	telemAdapt := telem.NewAdapter(ctx, cfg.Telemetry, cfg.TelemetryUserName, telem.WithCredStore(action.Config.CredStore()))
+
+	log.InfoContext(ctx, "resolving reference with telemetry", "ref", bottleRef)
+	transferOpts := tbottle.TransferOptions{
+		Concurrency: cfg.ConcurrentHTTP,
+		CachePath:   cfg.CachePath,
+	}
+	src, desc, _, err := telemAdapt.ResolveWithTelemetry(ctx, bottleRef, action.Config, transferOpts)
+	if err != nil {
+		return fmt.Errorf("resolving bottle reference: %w", err)
+	}
+
+	log.InfoContext(ctx, "fetching bottle metadata")
+	cfgBytes, manBytes, err := tbottle.FetchBottleMetadata(ctx, src, desc, tbottle.PullOptions{TransferOptions: transferOpts})
+	if err != nil {
+		return fmt.Errorf("fetching bottle metadata: %w", err)
+	}
+
+	// parts are extracted into a temporary working directory, the layers themselves are kept in the cache
+	workDir, err := os.MkdirTemp("", "ace-dt-bottle-serve-*")
+	if err != nil {
+		return fmt.Errorf("creating working directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	keys, err := decryptionKeys(cfg)
+	if err != nil {
+		return err
+	}
+
+	btl, err := bottle.NewBottle(
+		bottle.WithLocalPath(workDir),
+		bottle.WithCachePath(cfg.CachePath),
+		bottle.WithBlobInfoCache(cfg.CachePath),
+		bottle.WithDecryptionKeys(keys...),
+	)
+	if err != nil {
+		return fmt.Errorf("bottle initialization failed: %w", err)
+	}
+
+	manifestHandler := oci.ManifestFromData(ocispec.MediaTypeImageManifest, manBytes)
+	if manifestHandler.GetStatus().Error != nil {
+		return fmt.Errorf("constructing manifest handler from raw manifest: %w", manifestHandler.GetStatus().Error)
+	}
+	btl.SetManifest(manifestHandler)
+
+	if err := btl.Configure(cfgBytes); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", bottlefs.NewHandler(bottlefs.New(btl, src)))
+
+	handler := httputil.WrapHandler(mux,
+		httputil.ServerHeaderMiddleware(action.Config.UserAgent()),
+		httputil.TracingMiddleware,
+		httputil.LoggingMiddleware(log),
+		promhttputil.PrometheusMiddleware,
+	)
+
+	srv := &http.Server{
+		Addr: action.Listen,
+		// No WriteTimeout since the first request for a part waits on the part transfer
+		// and responses for large files may take a long time to send.
+		ReadHeaderTimeout: time.Second * 15,
+		ReadTimeout:       time.Second * 15,
+		IdleTimeout:       time.Second * 60,
+		Handler:           handler,
+	}
+
+	log.InfoContext(ctx, "serving bottle", "bottleID", btl.GetBottleID(), "listen", action.Listen)
+	return httputil.Serve(ctx, srv, 10*time.Second) //nolint:wrapcheck
+}
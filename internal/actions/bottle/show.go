@@ -1,9 +1,12 @@
 package bottle
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -12,6 +15,7 @@ import (
 	"github.com/act3-ai/bottle-schema/pkg/mediatype"
 	"github.com/act3-ai/data-tool/internal/actions/internal/format"
 	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/stats"
 	"github.com/act3-ai/data-tool/internal/oci"
 	"github.com/act3-ai/data-tool/internal/print"
 	telem "github.com/act3-ai/data-tool/pkg/telemetry"
@@ -207,8 +211,12 @@ func prettyPrintBtlInfo(btl *bottle.Bottle, partSelector bottle.PartSelectorFunc
 				sizeStr = "<unknown size, commit needed>"
 			}
 
-			entry := fmt.Sprintf("%s (%s, %s)\nlabels: %s\n\n", p.GetName(), sizeStr, compStr, p.GetLabels())
+			entry := fmt.Sprintf("%s (%s, %s)\nlabels: %s\n", p.GetName(), sizeStr, compStr, p.GetLabels())
 			partsEntry.WriteString(entry)
+			if pt, ok := p.(*bottle.PartTrack); ok && pt.Stats != nil {
+				partsEntry.WriteString("stats: " + formatStats(pt.Stats) + "\n")
+			}
+			partsEntry.WriteString("\n")
 		}
 	}
 
@@ -216,3 +224,27 @@ func prettyPrintBtlInfo(btl *bottle.Bottle, partSelector bottle.PartSelectorFunc
 
 	return t.String()
 }
+
+// formatStats formats part statistics on a line, media types by decreasing count.
+func formatStats(s *stats.Stats) string {
+	files := "files"
+	if s.Files == 1 {
+		files = "file"
+	}
+	fields := []string{fmt.Sprintf("%d %s, %s", s.Files, files, print.Bytes(s.Bytes))}
+	types := slices.Collect(maps.Keys(s.MediaTypes))
+	slices.SortStableFunc(types, func(a, b string) int {
+		return cmp.Or(cmp.Compare(s.MediaTypes[b], s.MediaTypes[a]), strings.Compare(a, b))
+	})
+	for _, mt := range types {
+		fields = append(fields, fmt.Sprintf("%s (%d)", mt, s.MediaTypes[mt]))
+	}
+	if s.TabularFiles > 0 {
+		table := fmt.Sprintf("%d rows", s.Rows)
+		if s.Columns > 0 {
+			table += fmt.Sprintf(" x %d columns", s.Columns)
+		}
+		fields = append(fields, table)
+	}
+	return strings.Join(fields, ", ")
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/act3-ai/go-common/pkg/logger"
 
+	"github.com/act3-ai/data-tool/internal/actions"
 	sigcustom "github.com/act3-ai/data-tool/internal/sign"
 	"github.com/act3-ai/data-tool/internal/ui"
-	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
 )
 
 // Sign represents a manifest digest sign action.
@@ -19,10 +19,13 @@ type Sign struct {
 
 	// DigestAlg    string
 	KeyPath      string
+	KeyPlugin    string
 	KeyAPI       string
 	UserIdentity string
 	KeyID        string
 
+	Expiry time.Duration // How long the signature is valid, 0 for signatures that do not expire
+
 	NoDeprecate bool // Don't deprecate existing bottle when committing.
 }
 
@@ -33,6 +36,10 @@ func (action *Sign) Run(ctx context.Context, keyAlias string) error {
 
 	log.InfoContext(ctx, "Bottle sign command activated")
 
+	if err := actions.CheckExpiry(action.Expiry); err != nil {
+		return err
+	}
+
 	// ensure bottle is ready to be signed.
 	log.InfoContext(ctx, "Prepping Bottle for signing", "bottlePath", action.Dir)
 	cfg, bottle, err := action.prepare(ctx)
@@ -41,55 +48,29 @@ func (action *Sign) Run(ctx context.Context, keyAlias string) error {
 	}
 
 	// commit creates a bottle manifest handler
-	if err := commit(ctx, cfg, bottle, action.NoDeprecate); err != nil {
+	if err := commit(ctx, cfg, bottle, commitOptions{NoDeprecate: action.NoDeprecate}); err != nil {
 		return err
 	}
 
 	bottleManifestDescriptor := bottle.Manifest.GetManifestDescriptor()
 	sigPath := filepath.Join(action.Dir, ".signature")
-	unsignedAnnos := make(map[string]string)
-	var sigHandler sigcustom.SigsHandler
-	var foundKey v1alpha1.SigningKey
-
-	// search for signing key in config.
-	for _, key := range cfg.SigningKeys {
-		if key.Alias == keyAlias {
-			foundKey = key
-			log.InfoContext(ctx, "key found", "alias", keyAlias)
-			break
-		}
-	}
-	// fail if key is not found or if incomplete metadata for key.
-	if foundKey.Alias == "" {
-		return fmt.Errorf("private key not found in configuration")
-	} else if foundKey.KeyPath == "" || foundKey.KeyAPI == "" || foundKey.UserIdentity == "" || foundKey.KeyID == "" {
-		return fmt.Errorf("private key metadata incomplete, please check ace-dt config: Alias: %s, KeyPath = %s, KeyAPI = %s, UserIdentity = %s, KeyID = %s", foundKey.Alias, foundKey.KeyPath, foundKey.KeyAPI, foundKey.UserIdentity, foundKey.KeyID)
-	}
 
-	// construct the map for the annotations to be signed.
-	unsignedAnnos[sigcustom.AnnotationUserID] = foundKey.UserIdentity
-	unsignedAnnos[sigcustom.AnnotationVerifyAPI] = foundKey.KeyAPI
-	unsignedAnnos[sigcustom.AnnotationKeyID] = foundKey.KeyID
+	signerProvider, unsignedAnnos, err := actions.SigningKey(ctx, cfg, keyAlias)
+	if err != nil {
+		return err
+	}
 
 	// Load a Notary style signature handler, which expects each signature to have its own manifest and signature
 	// blob
-	sigHandler, err = sigcustom.LoadLocalSignatures(ctx, bottleManifestDescriptor, sigPath)
+	sigHandler, err := sigcustom.LoadLocalSignatures(ctx, bottleManifestDescriptor, sigPath)
 	if err != nil {
 		return err
 	}
-
-	// HACK: We should come up with a more robust method for specifying cert files.
-	name := filepath.Base(foundKey.KeyPath)
-	i := strings.LastIndex(name, ".")
-	certPath := filepath.Join(filepath.Dir(foundKey.KeyPath), name[:i]+".crt")
-
-	// Create a file based private key provider. Note the key is not loaded until the key is used during signing.
-	log.InfoContext(ctx, "Constructing private key provider", "privateKeyPath", foundKey.KeyPath)
-	pkProvider := sigcustom.NewFilePrivateKeyProvider(foundKey.KeyPath, certPath)
+	sigHandler.ExpiryDuration = action.Expiry
 
 	// sign the bottle with the annotations.
 	log.InfoContext(ctx, "Beginning signing process")
-	err = sigHandler.Sign(ctx, pkProvider, unsignedAnnos, nil)
+	err = sigHandler.Sign(ctx, signerProvider, unsignedAnnos, nil)
 	if err != nil {
 		rootUI.Infof("Unable to sign Bottle %s", action.Dir)
 		return fmt.Errorf("ace-dt bottle sign: %w", err)
@@ -11,6 +11,8 @@ import (
 	"github.com/act3-ai/data-tool/internal/actions"
 	sigcustom "github.com/act3-ai/data-tool/internal/sign"
 	"github.com/act3-ai/data-tool/internal/ui"
+	telem "github.com/act3-ai/data-tool/pkg/telemetry"
+	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
 	"github.com/act3-ai/go-common/pkg/logger"
 )
 
@@ -20,38 +22,58 @@ type Verify struct {
 
 	// DigestAlg   string
 	Telemetry actions.TelemetryOptions
+
+	Policy string // Path to a trust policy file, enables trust verification of signers
+	Ref    string // Reference of a remote bottle to verify instead of the local bottle
 }
 
 // Run runs the bottle verify action.
-func (action *Verify) Run(ctx context.Context, args ...string) error {
+func (action *Verify) Run(ctx context.Context) error {
 	log := logger.FromContext(ctx)
 	rootUI := ui.FromContextOrNoop(ctx)
 
 	log.InfoContext(ctx, "Bottle verify command activated")
 
-	// prepare bottle and it's manifest for verification
-	log.InfoContext(ctx, "Prepping Bottle for signing", "bottlePath", action.Dir)
-	_, bottle, err := action.prepare(ctx)
-	if err != nil {
-		return err
-	}
-	err = bottle.ConstructManifest()
-	if err != nil {
-		return fmt.Errorf("constructing bottle manifest handler: %w", err)
-	}
+	var sigsHandler sigcustom.SigsHandler
+	var repository string
+	if action.Ref != "" {
+		var err error
+		sigsHandler, repository, err = action.fetchSignatures(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		// prepare bottle and it's manifest for verification
+		log.InfoContext(ctx, "Prepping Bottle for signing", "bottlePath", action.Dir)
+		_, bottle, err := action.prepare(ctx)
+		if err != nil {
+			return err
+		}
+		err = bottle.ConstructManifest()
+		if err != nil {
+			return fmt.Errorf("constructing bottle manifest handler: %w", err)
+		}
 
-	botManifestDesc := bottle.Manifest.GetManifestDescriptor()
-	sigPath := filepath.Join(action.Dir, ".signature")
+		botManifestDesc := bottle.Manifest.GetManifestDescriptor()
+		sigPath := filepath.Join(action.Dir, ".signature")
 
-	// Load a Notary style signature handler, which expects each signature to have its own manifest and signature
-	// blob
-	sigsHandler, err := sigcustom.LoadLocalSignatures(ctx, botManifestDesc, sigPath)
-	if err != nil {
-		return err
+		// Load a Notary style signature handler, which expects each signature to have its own manifest and signature
+		// blob
+		sigsHandler, err = sigcustom.LoadLocalSignatures(ctx, botManifestDesc, sigPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if action.Policy != "" {
+		if err := action.verifyPolicy(ctx, action.Policy, repository, sigsHandler); err != nil {
+			return fmt.Errorf("ace-dt bottle verify: %w", err)
+		}
+		return nil
 	}
 
 	// verify the bottle.
-	log.InfoContext(ctx, "Beginning verification process", "signedManifestDigest", botManifestDesc.Digest)
+	log.InfoContext(ctx, "Beginning verification process", "signedManifestDigest", sigsHandler.SignedSubject().Digest)
 	pass, err := sigsHandler.Verify(ctx)
 	switch {
 	case errors.Is(err, signature.SignatureNotFoundError{}):
@@ -76,3 +98,60 @@ func (action *Verify) Run(ctx context.Context, args ...string) error {
 	}
 	return nil
 }
+
+// fetchSignatures resolves the remote bottle and fetches its signatures into memory, without pulling the bottle.
+// The repository of the reference is returned for selecting a trust policy statement.
+func (action *Verify) fetchSignatures(ctx context.Context) (sigcustom.SigsHandler, string, error) {
+	log := logger.FromContext(ctx)
+
+	cfg := action.Config.Get(ctx)
+	telemAdapt := telem.NewAdapter(ctx, cfg.Telemetry, cfg.TelemetryUserName, telem.WithCredStore(action.Config.CredStore()))
+
+	log.InfoContext(ctx, "resolving reference with telemetry", "ref", action.Ref)
+	transferOpts := tbottle.TransferOptions{
+		Concurrency: cfg.ConcurrentHTTP,
+		CachePath:   cfg.CachePath,
+	}
+	src, desc, _, err := telemAdapt.ResolveWithTelemetry(ctx, action.Ref, action.Config, transferOpts)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolving bottle reference: %w", err)
+	}
+
+	sigsHandler, err := sigcustom.FetchSignatures(ctx, src, desc)
+	if err != nil {
+		return nil, "", err
+	}
+	return sigsHandler, actions.RepositoryScope(action.Ref), nil
+}
+
+// verifyPolicy verifies the signatures against the trust policy statement for the repository, reporting the
+// trust policy statement each signature satisfied or failed.
+func (action *Action) verifyPolicy(ctx context.Context, policyFile, repository string, sigsHandler sigcustom.SigsHandler) error {
+	log := logger.FromContext(ctx)
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	policy, err := action.LoadTrustPolicy(ctx, policyFile)
+	if err != nil {
+		return err
+	}
+
+	subject := sigsHandler.SignedSubject()
+	log.InfoContext(ctx, "Verifying signatures with trust policy", "policy", policyFile, "repository", repository, "signedManifestDigest", subject.Digest)
+	// local bottles and bottle IDs have no repository, so only the "*" statement applies
+	result, err := policy.Verify(subject, repository, sigsHandler.Signatures())
+	if err != nil {
+		return err
+	}
+	if len(result.Signatures) == 0 {
+		rootUI.Infof("No signatures found.")
+	} else {
+		rootUI.Info(actions.FormatPolicyResult(result))
+	}
+
+	if err := result.Err(); err != nil {
+		rootUI.Infof("Bottle failed verification.")
+		return err
+	}
+	rootUI.Infof("Bottle passed verification with trust policy statement %s.", result.Statement.Name)
+	return nil
+}
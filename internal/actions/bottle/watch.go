@@ -0,0 +1,97 @@
+package bottle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/bottle/watch"
+	"github.com/act3-ai/data-tool/internal/ui"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Watch represents the bottle watch action.
+type Watch struct {
+	*Action
+
+	Telemetry   actions.TelemetryOptions
+	Compression CompressionLevelOptions
+	Quiet       time.Duration // How long the bottle must be unchanged before committing
+	Ref         string        // Reference the bottle is pushed to after each commit, if not empty
+
+	pushed digest.Digest // bottle ID of the last push
+}
+
+// Run runs the bottle watch action, until the context is done.
+func (action *Watch) Run(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	log.InfoContext(ctx, "bottle watch command activated")
+
+	dir, err := bottle.FindBottleRootDir(action.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to find root bottle directory starting from %s: %w", action.Dir, err)
+	}
+	action.Dir = dir
+
+	rootUI.Infof("Watching bottle %s, committing after %s without changes", dir, action.Quiet)
+	if err := watch.Watch(ctx, dir, watch.Options{
+		Quiet:   action.Quiet,
+		Settled: action.settled,
+		Changed: func(path string) {
+			logger.V(log, 1).InfoContext(ctx, "bottle changed", "path", path)
+		},
+	}); err != nil {
+		return fmt.Errorf("watching bottle: %w", err)
+	}
+
+	log.InfoContext(ctx, "bottle watch command completed")
+	return nil
+}
+
+// settled commits the bottle, and pushes it if its bottle ID changed.  Commit and push failures are reported, and
+// retried after the next change, so the watch continues.
+func (action *Watch) settled(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	previous, _ := bottle.ReadBottleIDFile(action.Dir) // empty if not committed yet
+
+	commit := &Commit{Action: action.Action, Compression: action.Compression}
+	if err := commit.Run(ctx); err != nil {
+		log.ErrorContext(ctx, "committing bottle", "error", err)
+		rootUI.Infof("Commit failed, retrying after the next change: %v", err)
+		return nil
+	}
+
+	current, err := bottle.ReadBottleIDFile(action.Dir)
+	if err != nil {
+		return fmt.Errorf("reading committed bottle ID: %w", err)
+	}
+	if current != previous {
+		rootUI.Infof("Committed bottle %s", current)
+	}
+
+	if action.Ref == "" || current == action.pushed {
+		return nil
+	}
+	push := &Push{
+		Action:      action.Action,
+		Telemetry:   action.Telemetry,
+		Compression: action.Compression,
+		NoDeprecate: true, // already committed
+		Refs:        []string{action.Ref},
+	}
+	if err := push.Run(ctx); err != nil {
+		log.ErrorContext(ctx, "pushing bottle", "error", err)
+		rootUI.Infof("Push failed, retrying after the next change: %v", err)
+		return nil
+	}
+	action.pushed = current
+	return nil
+}
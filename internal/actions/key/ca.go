@@ -0,0 +1,140 @@
+package key
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	notationx509 "github.com/notaryproject/notation-core-go/x509"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// CACreate represents the key ca create action.
+type CACreate struct {
+	*Action
+
+	Name     string        // Common name of the CA certificate
+	Validity time.Duration // How long the CA certificate is valid
+	Encrypt  bool          // Encrypt the CA private key with a passphrase
+}
+
+// Run creates a CA key pair and self-signed CA certificate in destPath, named ca.key and ca.crt.
+func (action *CACreate) Run(ctx context.Context, out io.Writer, destPath string) error {
+	log := logger.FromContext(ctx)
+
+	keyPath := filepath.Join(destPath, "ca.key")
+	certPath := filepath.Join(destPath, "ca.crt")
+	if _, err := os.Stat(keyPath); err == nil {
+		return fmt.Errorf("CA key %s already exists", keyPath)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generating CA key: %w", err)
+	}
+	ca, err := sigcustom.MakeEcdsaCA(action.Name, key, action.Validity)
+	if err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "writing CA", "destPath", destPath)
+	if err := os.MkdirAll(destPath, 0o775); err != nil {
+		return fmt.Errorf("creating destination directory: %w", err)
+	}
+	if err := writePrivateKey(keyPath, ca.PrivateKey, action.Encrypt); err != nil {
+		return err
+	}
+	if err := os.WriteFile(certPath, sigcustom.CertificatesPEM(ca.Cert), 0o644); err != nil {
+		return fmt.Errorf("writing CA certificate: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(out, "CA key written to %s\nCA certificate written to %s\n", keyPath, certPath); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
+
+// Issue represents the key issue action.
+type Issue struct {
+	*Action
+
+	Prefix  string // File name prefix of the key files
+	Name    string // Common name of the signing certificate, the prefix if empty
+	Encrypt bool   // Encrypt the private key with a passphrase
+}
+
+// Run creates a key pair in destPath with a signing certificate issued by the CA with the private key caKeyPath.  The
+// certificate file holds the certificate chain from the signing certificate to the CA, as needed for signing.
+func (action *Issue) Run(ctx context.Context, out io.Writer, caKeyPath, destPath string) error {
+	log := logger.FromContext(ctx)
+
+	caKey, err := loadECDSAKey(caKeyPath)
+	if err != nil {
+		return err
+	}
+	caChain, err := notationx509.ReadCertificateFile(actions.CertPath(caKeyPath))
+	if err != nil {
+		return fmt.Errorf("reading CA certificate: %w", err)
+	}
+	if len(caChain) == 0 || !caChain[0].IsCA {
+		return errors.New("CA certificate not found, the CA key must have a CA certificate with the same name and a .crt extension")
+	}
+	if time.Now().After(caChain[0].NotAfter) {
+		return fmt.Errorf("CA certificate expired at %s", caChain[0].NotAfter)
+	}
+	if !caKey.PublicKey.Equal(caChain[0].PublicKey) {
+		return fmt.Errorf("CA key %s does not match the CA certificate %s", caKeyPath, actions.CertPath(caKeyPath))
+	}
+	ca := sigcustom.EcdsaCertPair{Cert: caChain[0], PrivateKey: caKey}
+
+	keypair, err := sigcustom.GenerateKeyPair()
+	if err != nil {
+		return fmt.Errorf("generating signing key: %w", err)
+	}
+	name := action.Name
+	if name == "" {
+		name = action.Prefix
+	}
+	leaf, err := sigcustom.NewEcdsaCertPair(name, keypair, &ca)
+	if err != nil {
+		return fmt.Errorf("issuing signing certificate: %w", err)
+	}
+	chain := append([]*x509.Certificate{leaf.Cert}, caChain...)
+
+	keyPath := filepath.Join(destPath, action.Prefix+".key")
+	pubPath := filepath.Join(destPath, action.Prefix+".pub")
+	certPath := filepath.Join(destPath, action.Prefix+".crt")
+	log.InfoContext(ctx, "writing signing key", "destPath", destPath)
+	if err := os.MkdirAll(destPath, 0o775); err != nil {
+		return fmt.Errorf("creating destination directory: %w", err)
+	}
+	if err := writePrivateKey(keyPath, leaf.PrivateKey, action.Encrypt); err != nil {
+		return err
+	}
+	if err := writePublicKey(pubPath, &leaf.PrivateKey.PublicKey); err != nil {
+		return err
+	}
+	if err := os.WriteFile(certPath, sigcustom.CertificatesPEM(chain...), 0o644); err != nil {
+		return fmt.Errorf("writing certificate: %w", err)
+	}
+
+	fp, err := sigcustom.FingerprintECDSA(&leaf.PrivateKey.PublicKey)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "Signing key written to %s\nCertificate chain written to %s\nFingerprint: %s\n", keyPath, certPath, fp); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package key
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+)
+
+// Add represents the key add action.
+type Add struct {
+	*Action
+
+	Identity     string            // Key owner's identity
+	KeyID        string            // Title of the key, the alias if empty
+	API          string            // API used to access the key
+	Plugin       string            // Signer plugin holding the key, used instead of a key path
+	PluginConfig map[string]string // Configuration passed to the signer plugin
+}
+
+// Run adds a signing key with the alias to the configuration file.  The key is held in the key file, or by the
+// signer plugin if one is set.
+func (action *Add) Run(ctx context.Context, out io.Writer, alias, keyPath string) error {
+	key := v1alpha1.SigningKey{
+		Alias:        alias,
+		KeyAPI:       action.API,
+		UserIdentity: action.Identity,
+		KeyID:        action.KeyID,
+		Plugin:       action.Plugin,
+		PluginConfig: action.PluginConfig,
+	}
+	switch {
+	case action.Plugin != "" && keyPath != "":
+		return fmt.Errorf("signing key %s must have either a key file or a plugin, not both", alias)
+	case action.Plugin == "" && keyPath == "":
+		return fmt.Errorf("signing key %s must have a key file or a plugin", alias)
+	case keyPath != "":
+		// the configuration may be used from other directories
+		absPath, err := filepath.Abs(keyPath)
+		if err != nil {
+			return fmt.Errorf("resolving key path: %w", err)
+		}
+		key.KeyPath = absPath
+	}
+	if key.KeyID == "" {
+		key.KeyID = alias
+	}
+	if key.UserIdentity == "" || key.KeyAPI == "" {
+		return fmt.Errorf("signing key %s must have an identity and API", alias)
+	}
+
+	file, err := action.editSigningKeys(ctx, func(keys []v1alpha1.SigningKey) ([]v1alpha1.SigningKey, error) {
+		if slices.ContainsFunc(keys, func(k v1alpha1.SigningKey) bool { return k.Alias == alias }) {
+			return nil, fmt.Errorf("signing key %s already exists", alias)
+		}
+		return append(keys, key), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "Added signing key %s to %s\n", alias, file); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
+
+// Remove represents the key remove action.
+type Remove struct {
+	*Action
+}
+
+// Run removes the signing key with the alias from the configuration file.  Key files are not deleted.
+func (action *Remove) Run(ctx context.Context, out io.Writer, alias string) error {
+	file, err := action.editSigningKeys(ctx, func(keys []v1alpha1.SigningKey) ([]v1alpha1.SigningKey, error) {
+		i := slices.IndexFunc(keys, func(k v1alpha1.SigningKey) bool { return k.Alias == alias })
+		if i < 0 {
+			return nil, fmt.Errorf("signing key %s not found", alias)
+		}
+		return slices.Delete(keys, i, i+1), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "Removed signing key %s from %s\n", alias, file); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
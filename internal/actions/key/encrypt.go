@@ -0,0 +1,38 @@
+package key
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+)
+
+// Encrypt represents the key encrypt action.
+type Encrypt struct {
+	*Action
+}
+
+// Run encrypts the private key file in place with a passphrase.
+func (action *Encrypt) Run(ctx context.Context, out io.Writer, keyPath string) error {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("reading private key: %w", err)
+	}
+	if sigcustom.IsEncryptedPrivateKeyPEM(data) {
+		return fmt.Errorf("private key %s is already encrypted", keyPath)
+	}
+	key, err := loadECDSAKey(keyPath)
+	if err != nil {
+		return err
+	}
+	if err := writePrivateKey(keyPath, key, true); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "Encrypted private key %s\n", keyPath); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
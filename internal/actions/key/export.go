@@ -0,0 +1,87 @@
+package key
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	notationx509 "github.com/notaryproject/notation-core-go/x509"
+	"sigs.k8s.io/yaml"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+)
+
+// Export represents the key export action.
+type Export struct {
+	*Action
+}
+
+// Run exports the public key, and the certificate chain if there is one, of the signing key with the alias to
+// destPath, and writes a trust store pinning the key, for use in a trust policy.
+func (action *Export) Run(ctx context.Context, out io.Writer, alias, destPath string) error {
+	cfg := action.Config.Get(ctx)
+
+	var key v1alpha1.SigningKey
+	for _, k := range cfg.SigningKeys {
+		if k.Alias == alias {
+			key = k
+			break
+		}
+	}
+	switch {
+	case key.Alias == "":
+		return fmt.Errorf("signing key %s not found in configuration", alias)
+	case key.Plugin != "":
+		return errors.New("keys held by signer plugins must be exported with the tools of the plugin")
+	}
+
+	if err := os.MkdirAll(destPath, 0o775); err != nil {
+		return fmt.Errorf("creating destination directory: %w", err)
+	}
+
+	// the public key is taken from the certificate, so that the private key is only loaded without one
+	pubPath := filepath.Join(destPath, alias+".pub")
+	certs, err := notationx509.ReadCertificateFile(actions.CertPath(key.KeyPath))
+	switch {
+	case err == nil && len(certs) > 0:
+		certPath := filepath.Join(destPath, alias+".crt")
+		if err := os.WriteFile(certPath, sigcustom.CertificatesPEM(certs...), 0o644); err != nil {
+			return fmt.Errorf("writing certificate: %w", err)
+		}
+		if err := writePublicKey(pubPath, certs[0].PublicKey); err != nil {
+			return err
+		}
+	default:
+		privKey, err := loadECDSAKey(key.KeyPath)
+		if err != nil {
+			return err
+		}
+		if err := writePublicKey(pubPath, &privKey.PublicKey); err != nil {
+			return err
+		}
+	}
+
+	store := map[string]any{
+		"trustStores": map[string]sigcustom.TrustStore{
+			alias: {Keys: []sigcustom.TrustedKey{{
+				Path:      pubPath,
+				Identity:  key.UserIdentity,
+				KeyID:     key.KeyID,
+				VerifyAPI: key.KeyAPI,
+			}}},
+		},
+	}
+	data, err := yaml.Marshal(store)
+	if err != nil {
+		return fmt.Errorf("encoding trust store: %w", err)
+	}
+	if _, err := fmt.Fprintf(out, "# Trust store for the signing key %s\n%s", alias, data); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+// Package key defines signing key management actions.
+package key
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/go-common/pkg/config"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Action represents a general key action.
+type Action struct {
+	*actions.DataTool
+}
+
+// configFile returns the configuration file to edit, the first configuration file present.  If none are, it is the
+// default configuration file when searched, otherwise the first configuration file given.
+func (action *Action) configFile() string {
+	files := action.Config.ConfigFiles
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	defaultFile := config.DefaultConfigPath("ace", "dt", "config.yaml")
+	if len(files) == 0 || slices.Contains(files, defaultFile) {
+		return defaultFile
+	}
+	return files[0]
+}
+
+// editSigningKeys edits the signing keys of the configuration file, returning the file edited.  Other settings and
+// the comments of the file are kept.
+func (action *Action) editSigningKeys(ctx context.Context, edit func([]v1alpha1.SigningKey) ([]v1alpha1.SigningKey, error)) (string, error) {
+	log := logger.FromContext(ctx)
+	file := action.configFile()
+
+	// the file is edited as a document node, so that comments are kept and defaults are not written back
+	var doc yamlv3.Node
+	data, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		log.InfoContext(ctx, "creating configuration file", "file", file)
+		data = []byte("apiVersion: " + v1alpha1.GroupVersion.String() + "\nkind: Configuration\n")
+	case err != nil:
+		return "", fmt.Errorf("reading configuration file: %w", err)
+	}
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("parsing configuration file %s: %w", file, err)
+	}
+	if doc.Kind == 0 {
+		// an empty file
+		doc = yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yamlv3.MappingNode {
+		return "", fmt.Errorf("parsing configuration file %s: not a mapping", file)
+	}
+
+	keys, idx, err := decodeSigningKeys(root)
+	if err != nil {
+		return "", fmt.Errorf("parsing signing keys of %s: %w", file, err)
+	}
+	keys, err = edit(keys)
+	if err != nil {
+		return "", err
+	}
+	if err := encodeSigningKeys(root, idx, keys); err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	enc := yamlv3.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", fmt.Errorf("encoding configuration: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("encoding configuration: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o775); err != nil {
+		return "", fmt.Errorf("creating configuration directory: %w", err)
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+		return "", fmt.Errorf("writing configuration file: %w", err)
+	}
+	return file, nil
+}
+
+// decodeSigningKeys decodes the signing keys of the configuration mapping node, returning the index of the keys
+// field in the node content, or -1 if there is none.
+func decodeSigningKeys(root *yamlv3.Node) ([]v1alpha1.SigningKey, int, error) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "keys" {
+			continue
+		}
+		// the configuration types are decoded with their JSON field names
+		data, err := yamlv3.Marshal(root.Content[i+1])
+		if err != nil {
+			return nil, -1, err
+		}
+		var keys []v1alpha1.SigningKey
+		if err := yaml.Unmarshal(data, &keys); err != nil {
+			return nil, -1, err
+		}
+		return keys, i, nil
+	}
+	return nil, -1, nil
+}
+
+// encodeSigningKeys sets the signing keys of the configuration mapping node, removing the keys field if there are
+// none.  idx is the index of the keys field in the node content, or -1 if there is none.
+func encodeSigningKeys(root *yamlv3.Node, idx int, keys []v1alpha1.SigningKey) error {
+	if len(keys) == 0 {
+		if idx >= 0 {
+			root.Content = slices.Delete(root.Content, idx, idx+2)
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("encoding signing keys: %w", err)
+	}
+	var value yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("encoding signing keys: %w", err)
+	}
+	if idx < 0 {
+		root.Content = append(root.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: "keys"}, value.Content[0])
+		return nil
+	}
+	// comments on the field are kept
+	prev := root.Content[idx+1]
+	value.Content[0].HeadComment = prev.HeadComment
+	value.Content[0].LineComment = prev.LineComment
+	value.Content[0].FootComment = prev.FootComment
+	root.Content[idx+1] = value.Content[0]
+	return nil
+}
+
+// writePrivateKey writes the private key to a PEM file, encrypting it with a passphrase from
+// sigcustom.ReadPassphrase if encrypt is set.
+func writePrivateKey(path string, key *ecdsa.PrivateKey, encrypt bool) error {
+	var data []byte
+	var err error
+	if encrypt {
+		data, err = encryptPrivateKey(key)
+	} else {
+		data, err = sigcustom.NewPrivateKeyProvider(key).PrivateKeyPEM()
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing private key: %w", err)
+	}
+	return nil
+}
+
+// encryptPrivateKey encrypts the private key with a passphrase from sigcustom.ReadPassphrase.
+func encryptPrivateKey(key crypto.PrivateKey) ([]byte, error) {
+	passphrase, err := sigcustom.ReadPassphrase(true)
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase for private key must not be empty")
+	}
+	return sigcustom.EncryptPrivateKeyPEM(key, passphrase)
+}
+
+// writePublicKey writes the public key to a PEM file.
+func writePublicKey(path string, key crypto.PublicKey) error {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return fmt.Errorf("marshaling public key: %w", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing public key: %w", err)
+	}
+	return nil
+}
+
+// loadECDSAKey loads an ECDSA private key file, which may be encrypted.
+func loadECDSAKey(path string) (*ecdsa.PrivateKey, error) {
+	key, err := sigcustom.LoadPrivateKeyFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading private key %s: %w", path, err)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an ECDSA key", path)
+	}
+	return ecKey, nil
+}
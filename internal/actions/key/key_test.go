@@ -0,0 +1,191 @@
+package key
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	ociactions "github.com/act3-ai/data-tool/internal/actions/oci"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/data-tool/pkg/conf"
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+// newAction creates a key action with the configuration file, loaded anew for each action as the commands are.
+func newAction(configFile string) *Action {
+	cfg := conf.New()
+	cfg.AddConfigFiles([]string{configFile})
+	return &Action{DataTool: &actions.DataTool{Config: cfg}}
+}
+
+func TestAddListRemove(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	original := "# ace-dt configuration\napiVersion: config.dt.act3-ace.io/v1alpha1\nkind: Configuration\n# shared cache\ncachePath: /tmp/cache # on tmpfs\n"
+	if err := os.WriteFile(configFile, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := actions.GenAndWriteKeyPair(ctx, dir, "release", false); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	add := &Add{Action: newAction(configFile), Identity: "release@example.com", API: "cert-basic"}
+	if err := add.Run(ctx, out, "release", filepath.Join(dir, "release.key")); err != nil {
+		t.Fatal(err)
+	}
+	if err := add.Run(ctx, out, "release", filepath.Join(dir, "release.key")); err == nil {
+		t.Error("expected an error adding a duplicate alias")
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), strings.TrimSuffix(original, "\n")) {
+		t.Errorf("other settings and comments of the configuration file were not kept:\n%s", data)
+	}
+
+	pubPEM, err := os.ReadFile(filepath.Join(dir, "release.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := (&List{Action: newAction(configFile)}).Run(ctx, out); err != nil {
+		t.Fatal(err)
+	}
+	if fp := sigcustom.FingerprintPEM(pubPEM).String(); !strings.Contains(out.String(), fp) {
+		t.Errorf("key list %q does not contain the fingerprint %s", out, fp)
+	}
+
+	if err := (&Remove{Action: newAction(configFile)}).Run(ctx, out, "release"); err != nil {
+		t.Fatal(err)
+	}
+	if len(newAction(configFile).Config.Get(ctx).SigningKeys) != 0 {
+		t.Error("expected the signing key to be removed")
+	}
+	data, err = os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("expected the configuration file to be restored, got:\n%s", data)
+	}
+}
+
+func TestCAIssueSignVerify(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	t.Setenv(sigcustom.PassphraseEnv, "correct horse")
+
+	out := &bytes.Buffer{}
+	caDir := filepath.Join(dir, "ca")
+	create := &CACreate{Action: newAction(configFile), Name: "Test CA", Validity: 24 * 60 * 60 * 1e9, Encrypt: true}
+	if err := create.Run(ctx, out, caDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := create.Run(ctx, out, caDir); err == nil {
+		t.Error("expected an error overwriting the CA")
+	}
+
+	keyDir := filepath.Join(dir, "keys")
+	issue := &Issue{Action: newAction(configFile), Prefix: "release", Encrypt: true}
+	if err := issue.Run(ctx, out, filepath.Join(caDir, "ca.key"), keyDir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(keyDir, "release.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sigcustom.IsEncryptedPrivateKeyPEM(data) {
+		t.Error("expected the issued private key to be encrypted")
+	}
+
+	add := &Add{Action: newAction(configFile), Identity: "release@example.com", API: "cert-basic"}
+	if err := add.Run(ctx, out, "release", filepath.Join(keyDir, "release.key")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the exported trust store is completed into a trust policy
+	trustDir := filepath.Join(dir, "trust")
+	out.Reset()
+	if err := (&Export{Action: newAction(configFile)}).Run(ctx, out, "release", trustDir); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"release.pub", "release.crt"} {
+		if _, err := os.Stat(filepath.Join(trustDir, name)); err != nil {
+			t.Errorf("expected exported file %s: %v", name, err)
+		}
+	}
+	policyFile := filepath.Join(dir, "trustpolicy.yaml")
+	policy := "version: \"1.0\"\n" + out.String() + `trustPolicies:
+- name: release
+  registryScopes: ["*"]
+  trustStores: [release]
+  trustedIdentities: ["*"]
+`
+	if err := os.WriteFile(policyFile, []byte(policy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	layout := filepath.Join(dir, "layout")
+	store, err := oci.New(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.example.test", oras.PackManifestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Tag(ctx, desc, "v1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ociAction := &ociactions.Action{DataTool: newAction(configFile).DataTool}
+	if err := (&ociactions.Sign{Action: ociAction, OCILayout: true}).Run(ctx, out, layout+":v1", "release"); err != nil {
+		t.Fatalf("signing with the issued key: %v", err)
+	}
+	out.Reset()
+	if err := (&ociactions.Verify{Action: ociAction, OCILayout: true, Policy: policyFile}).Run(ctx, out, layout+":v1"); err != nil {
+		t.Fatalf("verifying with the exported trust store: %v\n%s", err, out)
+	}
+}
+
+func TestIssueMismatchedCA(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+
+	out := &bytes.Buffer{}
+	for _, name := range []string{"ca", "other"} {
+		create := &CACreate{Action: newAction(configFile), Name: name, Validity: 24 * 60 * 60 * 1e9}
+		if err := create.Run(ctx, out, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the key of another CA next to the CA certificate
+	otherKey, err := os.ReadFile(filepath.Join(dir, "other", "ca.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ca", "ca.key"), otherKey, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &Issue{Action: newAction(configFile), Prefix: "release"}
+	err = issue.Run(ctx, out, filepath.Join(dir, "ca", "ca.key"), filepath.Join(dir, "keys"))
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a key mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package key
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	notationx509 "github.com/notaryproject/notation-core-go/x509"
+	"github.com/opencontainers/go-digest"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/actions/internal/format"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+)
+
+// List represents the key list action.
+type List struct {
+	*Action
+}
+
+// Run lists the signing keys of the configuration with the fingerprints of their public keys, as reported by trust
+// policy verification.
+func (action *List) Run(ctx context.Context, out io.Writer) error {
+	cfg := action.Config.Get(ctx)
+
+	t := format.NewTable()
+	t.AddRow("ALIAS", "KEY", "IDENTITY", "KEY ID", "FINGERPRINT", "CERTIFICATE EXPIRES")
+	for _, key := range cfg.SigningKeys {
+		source := key.KeyPath
+		if key.Plugin != "" {
+			source = "plugin:" + key.Plugin
+		}
+		fingerprint, expires := "-", "-"
+		if key.Plugin == "" {
+			fp, notAfter := keyFingerprint(key)
+			if fp != "" {
+				fingerprint = fp.String()
+			}
+			if notAfter != "" {
+				expires = notAfter
+			}
+		}
+		t.AddRow(key.Alias, source, key.UserIdentity, key.KeyID, fingerprint, expires)
+	}
+
+	if _, err := fmt.Fprintln(out, t.String()); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
+
+// keyFingerprint returns the fingerprint of a signing key file, and when its certificate expires.  The key is
+// identified from its certificate, its public key file, or the private key if it is not encrypted, in that order, so
+// that listing keys does not prompt for passphrases.  Empty values are returned if the key cannot be identified.
+func keyFingerprint(key v1alpha1.SigningKey) (digest.Digest, string) {
+	if certs, err := notationx509.ReadCertificateFile(actions.CertPath(key.KeyPath)); err == nil && len(certs) > 0 {
+		fp, err := sigcustom.FingerprintECDSA(certs[0].PublicKey)
+		if err != nil {
+			return "", ""
+		}
+		return fp, certs[0].NotAfter.Format("2006-01-02")
+	}
+
+	pubPath := strings.TrimSuffix(actions.CertPath(key.KeyPath), ".crt") + ".pub"
+	if data, err := os.ReadFile(pubPath); err == nil {
+		return sigcustom.FingerprintPEM(data), ""
+	}
+
+	data, err := os.ReadFile(key.KeyPath)
+	if err != nil || sigcustom.IsEncryptedPrivateKeyPEM(data) {
+		return "", ""
+	}
+	privKey, err := notationx509.ParsePrivateKeyPEM(data)
+	if err != nil {
+		return "", ""
+	}
+	ecKey, ok := privKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return "", ""
+	}
+	fp, err := sigcustom.FingerprintECDSA(&ecKey.PublicKey)
+	if err != nil {
+		return "", ""
+	}
+	return fp, ""
+}
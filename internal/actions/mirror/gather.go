@@ -3,6 +3,7 @@ package mirror
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -26,6 +27,13 @@ type Gather struct {
 
 	// Platforms defines the platform(s) for the images to be gathered. (Default behavior is to gather all available platforms.)
 	Platforms []string
+
+	// Policy is a vulnerability policy file.  Images are scanned before they are gathered, and the images that fail
+	// the policy are handled according to PolicyAction.
+	Policy string
+
+	// PolicyAction is drop to not gather images that fail the policy, or flag to annotate them with the violations.
+	PolicyAction string
 }
 
 // Run executes the actual gather operation.
@@ -50,6 +58,14 @@ func (action *Gather) Run(ctx context.Context, sourceFile string, dest string) e
 		return fmt.Errorf("resolving destination reference with endpoint resolution: %w", err)
 	}
 
+	var screen *policyScreen
+	if action.Policy != "" {
+		screen, err = newPolicyScreen(action.Policy, action.PolicyAction)
+		if err != nil {
+			return err
+		}
+	}
+
 	// create the gather opts
 	opts := mirror.GatherOptions{
 		Platforms:      action.Platforms,
@@ -65,6 +81,9 @@ func (action *Gather) Run(ctx context.Context, sourceFile string, dest string) e
 		Recursive:      action.Recursive,
 		Targeter:       action.Config,
 	}
+	if screen != nil {
+		opts.Screen = screen.screen
+	}
 
 	// run the gather function
 	idxDesc, err := mirror.Gather(ctx, action.Version(), opts)
@@ -81,6 +100,21 @@ func (action *Gather) Run(ctx context.Context, sourceFile string, dest string) e
 	opts.RootUI.Infof("Gather index: %s", referenceWithDigest.String())
 	opts.RootUI.Infof("Pushed index to destination: %s", destRef)
 
+	if screen != nil {
+		var failed int
+		for _, res := range screen.results {
+			if !res.Pass {
+				failed++
+				rootUI.Infof("%s failed the vulnerability policy: %s", res.Reference, strings.Join(res.Violations, "; "))
+			}
+		}
+		verb := "Flagged"
+		if screen.drop {
+			verb = "Dropped"
+		}
+		rootUI.Infof("%s %d of %d images that failed the vulnerability policy", verb, failed, len(screen.results))
+	}
+
 	return nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/act3-ai/data-tool/internal/actions"
 	"github.com/act3-ai/data-tool/internal/mirror/encoding"
 	"github.com/act3-ai/data-tool/internal/ref"
+	"github.com/act3-ai/data-tool/internal/security"
 	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
 	"github.com/act3-ai/go-common/pkg/logger"
 	"github.com/act3-ai/go-common/pkg/test"
@@ -124,6 +125,53 @@ func TestGatherRun(t *testing.T) {
 		}
 	})
 
+	t.Run("policy", func(t *testing.T) {
+		// the image fails the policy, the index passes
+		scanReference = func(ctx context.Context, reference string) (*security.ArtifactScanReport, error) {
+			if !strings.Contains(reference, "/low/source1@sha256:") {
+				return &security.ArtifactScanReport{}, nil
+			}
+			return &security.ArtifactScanReport{CriticalVulnerabilities: []security.Matches{
+				{Vulnerabilities: security.Vulnerability{ID: "CVE-2024-0001", Severity: "Critical"}},
+			}}, nil
+		}
+		defer func() { scanReference = security.ScanReference }()
+		policy := filepath.Join(dir, "policy.yaml")
+		rne(os.WriteFile(policy, []byte("maxSeverity: high\n"), 0o666))
+
+		for _, policyAction := range []string{"flag", "drop"} {
+			t.Run(policyAction, func(t *testing.T) {
+				rne := require.New(t).NoError
+
+				gather := Gather{
+					Action:       mAction,
+					Policy:       policy,
+					PolicyAction: policyAction,
+				}
+				gatherDest := u.Host + "/low/mirror-policy-" + policyAction + ":sync-1"
+				rne(gather.Run(ctx, sources, gatherDest))
+
+				cas3, err := remote.NewRepository(gatherDest)
+				rne(err)
+				cas3.PlainHTTP = true
+				_, data, err := oras.FetchBytes(ctx, cas3, "sync-1", oras.DefaultFetchBytesOptions)
+				rne(err)
+				var man ocispec.Index
+				rne(json.Unmarshal(data, &man))
+
+				results := map[string]string{}
+				for _, mdesc := range man.Manifests {
+					results[mdesc.Annotations[ref.AnnotationSrcRef]] = mdesc.Annotations[security.AnnotationPolicyResult]
+				}
+				if policyAction == "drop" {
+					assert.Equal(t, map[string]string{refIdx1: ""}, results)
+				} else {
+					assert.Equal(t, map[string]string{refIdx1: "pass", refImg1: "fail"}, results)
+				}
+			})
+		}
+	})
+
 	t.Run("index fallback", func(t *testing.T) {
 		rne := require.New(t).NoError
 
@@ -0,0 +1,62 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/act3-ai/data-tool/internal/security"
+)
+
+// scanReference scans images for the vulnerability policy.
+var scanReference = security.ScanReference
+
+// policyScreen scans the sources of a gather, dropping or flagging the sources that fail the vulnerability policy.
+type policyScreen struct {
+	policy *security.Policy
+	drop   bool
+
+	mu      sync.Mutex
+	results []security.PolicyResult
+}
+
+// screen scans the image with the reference and evaluates the policy for the source name.
+func (s *policyScreen) screen(ctx context.Context, name, reference string) (bool, map[string]string, error) {
+	report, err := scanReference(ctx, reference)
+	if err != nil {
+		return false, nil, err
+	}
+	result := s.policy.Evaluate(name, report, time.Now())
+
+	s.mu.Lock()
+	s.results = append(s.results, result)
+	s.mu.Unlock()
+
+	if result.Pass {
+		if s.drop {
+			return true, nil, nil
+		}
+		return true, map[string]string{security.AnnotationPolicyResult: "pass"}, nil
+	}
+	if s.drop {
+		return false, nil, nil
+	}
+	return true, map[string]string{
+		security.AnnotationPolicyResult:     "fail",
+		security.AnnotationPolicyViolations: strings.Join(result.Violations, "; "),
+	}, nil
+}
+
+// newPolicyScreen loads the policy file for a gather, with the action to take on failed images, drop or flag.
+func newPolicyScreen(file, policyAction string) (*policyScreen, error) {
+	if policyAction != "drop" && policyAction != "flag" {
+		return nil, fmt.Errorf("unknown policy action %q, expected drop or flag", policyAction)
+	}
+	policy, err := security.LoadPolicy(file)
+	if err != nil {
+		return nil, err
+	}
+	return &policyScreen{policy: policy, drop: policyAction == "drop"}, nil
+}
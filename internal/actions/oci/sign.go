@@ -0,0 +1,61 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Sign represents the oci sign action.
+type Sign struct {
+	*Action
+
+	OCILayout bool
+
+	Expiry time.Duration // How long the signature is valid, 0 for signatures that do not expire
+}
+
+// Run signs the manifest or index of the reference with the signing key, pushing the signature as a referrer of it.
+func (action *Sign) Run(ctx context.Context, out io.Writer, rawRef, keyAlias string) error {
+	log := logger.FromContext(ctx)
+	cfg := action.Config.Get(ctx)
+
+	if err := actions.CheckExpiry(action.Expiry); err != nil {
+		return err
+	}
+	signerProvider, unsignedAnnos, err := actions.SigningKey(ctx, cfg, keyAlias)
+	if err != nil {
+		return err
+	}
+
+	target, desc, _, err := action.ResolveTarget(ctx, rawRef, action.OCILayout)
+	if err != nil {
+		return err
+	}
+	log.InfoContext(ctx, "signing artifact", "digest", desc.Digest, "mediaType", desc.MediaType)
+
+	// the signature is held in memory until pushed
+	sigsHandler, err := sigcustom.FetchSignatures(ctx, target, desc)
+	if err != nil {
+		return err
+	}
+	existing := len(sigsHandler.SigManifests)
+	sigsHandler.ExpiryDuration = action.Expiry
+	if err := sigsHandler.Sign(ctx, signerProvider, unsignedAnnos, nil); err != nil {
+		return fmt.Errorf("signing %s: %w", rawRef, err)
+	}
+	if err := sigsHandler.Push(ctx, target); err != nil {
+		return fmt.Errorf("pushing signature: %w", err)
+	}
+
+	sigDesc := sigsHandler.SigManifests[existing].GetManifestDescriptor()
+	if _, err := fmt.Fprintf(out, "Signed %s@%s, signature %s\n", rawRef, desc.Digest, sigDesc.Digest); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-go"
+	notationreg "github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/signer"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/data-tool/pkg/conf"
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+// certTrustStore is a notation trust store of a single certificate.
+type certTrustStore struct {
+	cert *x509.Certificate
+}
+
+func (s certTrustStore) GetCertificates(context.Context, truststore.Type, string) ([]*x509.Certificate, error) {
+	return []*x509.Certificate{s.cert}, nil
+}
+
+// newSigningAction creates an oci action with the signing key "release", a self-signed key pair in dir, returning
+// the certificate of the key.
+func newSigningAction(ctx context.Context, t *testing.T, dir string) (*Action, *x509.Certificate) {
+	t.Helper()
+	if err := actions.GenAndWriteKeyPair(ctx, dir, "release", true); err != nil {
+		t.Fatal(err)
+	}
+	der, err := os.ReadFile(filepath.Join(dir, "release.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := conf.New()
+	cfg.AddConfigOverride(func(ctx context.Context, c *v1alpha1.Configuration) error {
+		c.SigningKeys = []v1alpha1.SigningKey{{
+			Alias:        "release",
+			KeyPath:      filepath.Join(dir, "release.key"),
+			KeyAPI:       "cert-basic",
+			UserIdentity: "release@example.com",
+			KeyID:        "release",
+		}}
+		return nil
+	})
+	return &Action{DataTool: &actions.DataTool{Config: cfg}}, cert
+}
+
+// writeTrustPolicy writes a trust policy trusting the certificate to dir.
+func writeTrustPolicy(t *testing.T, dir string, cert *x509.Certificate) string {
+	t.Helper()
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if err := os.WriteFile(filepath.Join(dir, "release.pem"), certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policyFile := filepath.Join(dir, "trustpolicy.yaml")
+	if err := os.WriteFile(policyFile, []byte(`
+version: "1.0"
+trustStores:
+  release:
+    certificates: [release.pem]
+trustPolicies:
+- name: release
+  registryScopes: ["*"]
+  trustStores: [release]
+  trustedIdentities: ["*"]
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return policyFile
+}
+
+// makeTestImage writes an image tagged v1 to an OCI image layout in dir.
+func makeTestImage(ctx context.Context, t *testing.T, dir string) (*oci.Store, ocispec.Descriptor) {
+	t.Helper()
+	store, err := oci.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.example.test", oras.PackManifestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Tag(ctx, desc, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	return store, desc
+}
+
+func TestSignVerifyNotationInterop(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+
+	keyDir := t.TempDir()
+	action, cert := newSigningAction(ctx, t, keyDir)
+	policyFile := writeTrustPolicy(t, keyDir, cert)
+
+	t.Run("notation verifies ace-dt signature", func(t *testing.T) {
+		layout := t.TempDir()
+		_, desc := makeTestImage(ctx, t, layout)
+
+		out := &bytes.Buffer{}
+		if err := (&Sign{Action: action, OCILayout: true}).Run(ctx, out, layout+":v1", "release"); err != nil {
+			t.Fatalf("signing: %v", err)
+		}
+		if !strings.HasPrefix(out.String(), "Signed ") {
+			t.Errorf("unexpected output %q", out)
+		}
+
+		doc := &trustpolicy.Document{
+			Version: "1.0",
+			TrustPolicies: []trustpolicy.TrustPolicy{{
+				Name:                  "release",
+				RegistryScopes:        []string{"*"},
+				SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: trustpolicy.LevelStrict.Name},
+				TrustStores:           []string{"ca:release"},
+				TrustedIdentities:     []string{"*"},
+			}},
+		}
+		v, err := verifier.New(doc, certTrustStore{cert: cert}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the signature is read back from the OCI image layout, as by notation verify --oci-layout
+		repo, err := notationreg.NewOCIRepository(layout, notationreg.RepositoryOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, outcomes, err := notation.Verify(ctx, v, repo, notation.VerifyOptions{
+			ArtifactReference:    "local/image@" + desc.Digest.String(),
+			MaxSignatureAttempts: 10,
+		})
+		if err != nil {
+			t.Fatalf("notation verification failed: %v", err)
+		}
+		if len(outcomes) != 1 {
+			t.Errorf("got %d verification outcomes, want 1", len(outcomes))
+		}
+	})
+
+	t.Run("ace-dt verifies notation signature", func(t *testing.T) {
+		layout := t.TempDir()
+		store, _ := makeTestImage(ctx, t, layout)
+
+		key, err := sigcustom.NewFilePrivateKeyProvider(filepath.Join(keyDir, "release.key"), "").PrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := signer.NewGenericSigner(key, []*x509.Certificate{cert})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// ace-dt signs with JWS envelopes, so sign with COSE to cover both envelope formats
+		if _, err := notation.Sign(ctx, s, notationreg.NewRepository(store), notation.SignOptions{
+			SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: cose.MediaTypeEnvelope},
+			ArtifactReference: "local/image:v1",
+		}); err != nil {
+			t.Fatalf("notation signing failed: %v", err)
+		}
+
+		out := &bytes.Buffer{}
+		if err := (&Verify{Action: action, OCILayout: true, Policy: policyFile}).Run(ctx, out, layout+":v1"); err != nil {
+			t.Fatalf("verification failed: %v\n%s", err, out)
+		}
+		if !strings.Contains(out.String(), "passed verification with trust policy statement release") {
+			t.Errorf("unexpected output %q", out)
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		layout := t.TempDir()
+		makeTestImage(ctx, t, layout)
+
+		out := &bytes.Buffer{}
+		if err := (&Verify{Action: action, OCILayout: true, Policy: policyFile}).Run(ctx, out, layout+":v1"); err == nil {
+			t.Errorf("expected unsigned image to fail verification")
+		}
+	})
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strings"
 
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content/oci"
 
+	"github.com/act3-ai/data-tool/internal/actions"
 	"github.com/act3-ai/data-tool/internal/print"
 )
 
@@ -37,7 +37,7 @@ func (action *Tree) Run(ctx context.Context, out io.Writer, rawRef string) error
 	switch {
 	case action.OCILayout:
 		// local directory in oci layout
-		srcPath, r, err := parseOCILayoutReference(rawRef)
+		srcPath, r, err := actions.ParseOCILayoutReference(rawRef)
 		if err != nil {
 			return err
 		}
@@ -74,17 +74,3 @@ func (action *Tree) Run(ctx context.Context, out io.Writer, rawRef string) error
 
 	return print.All(ctx, out, storage, node, o)
 }
-
-// parseOCILayoutReference parses the raw in format of <path>[:<tag>|@<digest>].
-func parseOCILayoutReference(raw string) (string, string, error) {
-	if idx := strings.LastIndex(raw, "@"); idx != -1 {
-		// `digest` found
-		return raw[:idx], raw[idx+1:], nil
-	}
-	// find `tag`
-	if idx := strings.LastIndex(raw, ":"); idx != -1 {
-		return raw[:idx], raw[idx+1:], nil
-	}
-
-	return "", "", fmt.Errorf(`directory path and reference must be separated by "@" for digests and ":" for tags in %q`, raw)
-}
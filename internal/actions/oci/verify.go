@@ -0,0 +1,63 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Verify represents the oci verify action.
+type Verify struct {
+	*Action
+
+	OCILayout bool
+
+	Policy string // Path to a trust policy file
+}
+
+// Run verifies the signatures referring to the manifest or index of the reference against the trust policy, reporting
+// the trust policy statement each signature satisfied or failed.
+func (action *Verify) Run(ctx context.Context, out io.Writer, rawRef string) error {
+	log := logger.FromContext(ctx)
+
+	policy, err := action.LoadTrustPolicy(ctx, action.Policy)
+	if err != nil {
+		return err
+	}
+
+	target, desc, repository, err := action.ResolveTarget(ctx, rawRef, action.OCILayout)
+	if err != nil {
+		return err
+	}
+	sigsHandler, err := sigcustom.FetchSignatures(ctx, target, desc)
+	if err != nil {
+		return err
+	}
+
+	// OCI image layouts have no repository, so only the "*" statement applies
+	log.InfoContext(ctx, "Verifying signatures with trust policy", "policy", action.Policy, "repository", repository, "digest", desc.Digest)
+	result, err := policy.Verify(desc, repository, sigsHandler.Signatures())
+	if err != nil {
+		return err
+	}
+	if len(result.Signatures) == 0 {
+		_, err = fmt.Fprintln(out, "No signatures found.")
+	} else {
+		_, err = fmt.Fprintln(out, actions.FormatPolicyResult(result))
+	}
+	if err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("%s@%s failed verification: %w", rawRef, desc.Digest, err)
+	}
+	if _, err := fmt.Fprintf(out, "%s@%s passed verification with trust policy statement %s.\n", rawRef, desc.Digest, result.Statement.Name); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/mirror/encoding"
+	"github.com/act3-ai/data-tool/internal/sbom/catalog"
+	"github.com/act3-ai/data-tool/internal/security"
+)
+
+// Generate represents the sbom generate action.
+type Generate struct {
+	*Action
+
+	OCILayout bool
+	Format    string // SBOM format, spdx-json or cyclonedx-json
+	Platform  string // Platform of the image to catalog in a multi-platform index
+	Output    string // File the SBOM is written to, - for stdout
+	Push      bool   // Push the SBOM as a referrer of the image
+	Cataloger string // Cataloger of the packages of the image, syft or builtin
+}
+
+// Run generates an SBOM of the image with the reference, cataloging the packages installed in it with syft, or
+// in-process with the builtin cataloger.
+func (action *Generate) Run(ctx context.Context, out io.Writer, rawRef string) error {
+	log := logger.FromContext(ctx)
+
+	format := catalog.Format(action.Format)
+	if !slices.Contains(catalog.Formats, format) {
+		return fmt.Errorf("unknown SBOM format %q, expected one of %v", action.Format, catalog.Formats)
+	}
+	if !slices.Contains(security.Catalogers, action.Cataloger) {
+		return fmt.Errorf("unknown cataloger %q, expected one of %v", action.Cataloger, security.Catalogers)
+	}
+
+	target, desc, _, err := action.ResolveTarget(ctx, rawRef, action.OCILayout)
+	if err != nil {
+		return err
+	}
+	if encoding.IsIndex(desc.MediaType) {
+		desc, err = selectPlatform(ctx, target, desc, action.Platform)
+		if err != nil {
+			return fmt.Errorf("selecting the image of %s: %w", rawRef, err)
+		}
+	}
+
+	var data []byte
+	if action.Cataloger == security.CatalogerBuiltin {
+		cat, err := catalog.Image(ctx, target, desc, rawRef)
+		if err != nil {
+			return fmt.Errorf("generating SBOM for %s: %w", rawRef, err)
+		}
+		data, err = cat.Encode(format, "ace-dt-"+action.Version())
+		if err != nil {
+			return err
+		}
+	} else {
+		source, err := syftSource(rawRef, desc, action.OCILayout)
+		if err != nil {
+			return err
+		}
+		data, err = security.SyftSBOM(ctx, source, format, action.Platform)
+		if err != nil {
+			return fmt.Errorf("generating SBOM for %s: %w", rawRef, err)
+		}
+	}
+
+	if action.Output == "-" {
+		if _, err := out.Write(data); err != nil {
+			return fmt.Errorf("writing SBOM: %w", err)
+		}
+	} else {
+		if err := os.WriteFile(action.Output, data, 0o644); err != nil {
+			return fmt.Errorf("writing SBOM: %w", err)
+		}
+		if _, err := fmt.Fprintf(out, "SBOM of %s@%s written to %s\n", rawRef, desc.Digest, action.Output); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+	}
+
+	if action.Push {
+		maniDesc, err := security.AttachSBOM(ctx, target, desc, data, format.ArtifactType())
+		if err != nil {
+			return err
+		}
+		log.InfoContext(ctx, "SBOM pushed", "reference", rawRef, "manifest", maniDesc.Digest)
+		if action.Output != "-" {
+			if _, err := fmt.Fprintf(out, "SBOM pushed as referrer %s\n", maniDesc.Digest); err != nil {
+				return fmt.Errorf("writing output: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// syftSource returns the syft source of the image with the manifest desc and the reference, the image of the
+// registry by digest, or the OCI image layout directory.
+func syftSource(rawRef string, desc ocispec.Descriptor, ociLayout bool) (string, error) {
+	if ociLayout {
+		path, _, err := actions.ParseOCILayoutReference(rawRef)
+		if err != nil {
+			return "", err
+		}
+		return "oci-dir:" + path, nil
+	}
+	ref, err := registry.ParseReference(rawRef)
+	if err != nil {
+		return "", fmt.Errorf("parsing reference %s: %w", rawRef, err)
+	}
+	ref.Reference = desc.Digest.String()
+	return "registry:" + ref.String(), nil
+}
+
+// selectPlatform returns the image manifest of the index for the platform, as os/arch[/variant].  The platform may be
+// empty if the index has a single image.
+func selectPlatform(ctx context.Context, storage content.Fetcher, desc ocispec.Descriptor, platform string) (ocispec.Descriptor, error) {
+	data, err := content.FetchAll(ctx, storage, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("fetching index: %w", err)
+	}
+	var idx ocispec.Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("decoding index: %w", err)
+	}
+
+	var platforms []string
+	var selected []ocispec.Descriptor
+	for _, m := range idx.Manifests {
+		// attestations are listed in indexes with an unknown platform
+		if m.Platform == nil || m.Platform.OS == "unknown" {
+			continue
+		}
+		p := strings.Join(slices.DeleteFunc([]string{m.Platform.OS, m.Platform.Architecture, m.Platform.Variant}, func(s string) bool { return s == "" }), "/")
+		platforms = append(platforms, p)
+		if platform == "" || platform == p {
+			selected = append(selected, ocispec.Descriptor{MediaType: m.MediaType, Digest: m.Digest, Size: m.Size})
+		}
+	}
+	switch {
+	case len(selected) == 1:
+		return selected[0], nil
+	case platform == "" && len(selected) > 1:
+		return ocispec.Descriptor{}, fmt.Errorf("the index has images for the platforms %v, select one with --platform", platforms)
+	default:
+		return ocispec.Descriptor{}, fmt.Errorf("no image for the platform %q, the index has images for the platforms %v", platform, platforms)
+	}
+}
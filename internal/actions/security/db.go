@@ -0,0 +1,122 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+
+	"github.com/act3-ai/data-tool/internal/actions"
+	"github.com/act3-ai/data-tool/internal/security"
+)
+
+// DBPackage represents the security db package action.
+type DBPackage struct {
+	*Action
+
+	OCILayout bool
+	Update    bool // Update the database before packaging it
+}
+
+// Run packages the grype vulnerability database as an artifact tagged with the reference.
+func (action *DBPackage) Run(ctx context.Context, out io.Writer, rawRef string) error {
+	log := logger.FromContext(ctx)
+
+	if action.Update {
+		log.InfoContext(ctx, "updating the grype db")
+		if err := security.UpdateDatabase(ctx); err != nil {
+			return err
+		}
+	}
+	status, err := security.GetDatabaseStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	var target oras.Target
+	var tag string
+	if action.OCILayout {
+		path, r, err := actions.ParseOCILayoutReference(rawRef)
+		if err != nil {
+			return err
+		}
+		store, err := oci.New(path)
+		if err != nil {
+			return fmt.Errorf("opening OCI image layout directory: %w", err)
+		}
+		target, tag = store, r
+	} else {
+		repo, err := action.Config.Repository(ctx, rawRef)
+		if err != nil {
+			return err
+		}
+		target, tag = repo, repo.Reference.ReferenceOrDefault()
+	}
+
+	desc, err := security.PackageDatabase(ctx, target, status)
+	if err != nil {
+		return err
+	}
+	if err := target.Tag(ctx, desc, tag); err != nil {
+		return fmt.Errorf("tagging the grype db %s: %w", rawRef, err)
+	}
+
+	if _, err := fmt.Fprintf(out, "Packaged the vulnerability database built %s as %s@%s\n",
+		status.Built.Format(time.RFC3339), rawRef, desc.Digest); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
+
+// DBImport represents the security db import action.
+type DBImport struct {
+	*Action
+
+	OCILayout bool
+	CacheDir  string // grype database cache directory, defaults to the directory grype uses
+}
+
+// Run imports the vulnerability database artifact with the reference into the grype database cache.
+func (action *DBImport) Run(ctx context.Context, out io.Writer, rawRef string) error {
+	target, desc, _, err := action.ResolveTarget(ctx, rawRef, action.OCILayout)
+	if err != nil {
+		return err
+	}
+	cacheDir := action.CacheDir
+	if cacheDir == "" {
+		cacheDir = security.DatabaseCacheDir()
+	}
+
+	status, err := security.ImportDatabase(ctx, target, desc, cacheDir)
+	if err != nil {
+		return fmt.Errorf("importing the grype db %s: %w", rawRef, err)
+	}
+	if _, err := fmt.Fprintf(out, "Imported the vulnerability database to %s\n%s\n", status.Path, status); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
+
+// DBStatus represents the security db status action.
+type DBStatus struct {
+	*Action
+
+	MaxAge time.Duration // Fails if the database is older, defaults to the configured maximum age
+}
+
+// Run prints the status of the grype vulnerability database, failing if it is too old to scan with.
+func (action *DBStatus) Run(ctx context.Context, out io.Writer) error {
+	status, err := security.GetDatabaseStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "%s\nLocation: %s\n", status, status.Path); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return status.CheckAge(action.maxDatabaseAge(ctx, action.MaxAge))
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	security "github.com/act3-ai/data-tool/internal/security"
 	"github.com/act3-ai/go-common/pkg/logger"
@@ -22,6 +24,9 @@ type Scan struct {
 	DisplayCVE              bool
 	DisplayPlatforms        bool
 	PushReport              bool
+	MaxDatabaseAge          time.Duration
+	Policy                  string // Vulnerability policy file the scanned images must pass
+	Cataloger               string // Cataloger generating the SBOMs of the images, syft or builtin
 }
 
 // Run executes the security scan Run() action.
@@ -29,6 +34,10 @@ func (action *Scan) Run(ctx context.Context) error {
 	cfg := action.Config.Get(ctx)
 	log := logger.FromContext(ctx)
 
+	if action.Cataloger != "" && !slices.Contains(security.Catalogers, action.Cataloger) {
+		return fmt.Errorf("unknown cataloger %q, expected one of %v", action.Cataloger, security.Catalogers)
+	}
+
 	// Build the scan options
 	opts := security.ScanOptions{
 		SourceFile:              action.SourceFile,
@@ -37,6 +46,19 @@ func (action *Scan) Run(ctx context.Context) error {
 		VulnerabilityLevel:      action.VulnerabilityLevel,
 		DryRun:                  action.DryRun,
 		PushReport:              action.PushReport,
+		Cataloger:               action.Cataloger,
+		MaxDatabaseAge:          action.maxDatabaseAge(ctx, action.MaxDatabaseAge),
+	}
+
+	var policy *security.Policy
+	if action.Policy != "" {
+		p, err := security.LoadPolicy(action.Policy)
+		if err != nil {
+			return err
+		}
+		policy = p
+		// the policy applies to vulnerabilities of all severities, the reports are still filtered by the level
+		opts.VulnerabilityLevel = "unknown"
 	}
 
 	log.InfoContext(ctx, "Scanning Artifacts...")
@@ -54,6 +76,18 @@ func (action *Scan) Run(ctx context.Context) error {
 		return nil
 	}
 
+	var failed int
+	if policy != nil {
+		now := time.Now()
+		for _, res := range results {
+			pr := policy.Evaluate(res.OriginatingReference(), &res.CalculatedResults, now)
+			res.Policy = &pr
+			if !pr.Pass {
+				failed++
+			}
+		}
+	}
+
 	outputMethods := map[string][]io.Writer{}
 	// parse the output
 	for _, o := range action.Output {
@@ -91,11 +125,40 @@ func (action *Scan) Run(ctx context.Context) error {
 				if err := security.PrintTable(writer, results, action.VulnerabilityLevel, action.DisplayCVE, action.DisplayPlatforms); err != nil {
 					return err
 				}
+			case security.ReportFormatSARIF, security.ReportFormatVEX:
+				if err := security.PrintReport(writer, method, results, action.VulnerabilityLevel, action.Version()); err != nil {
+					return err
+				}
 			default:
 				return fmt.Errorf("unknown printing directive: %s", action.Output)
 			}
 		}
 	}
 
+	// the reports in standard formats are pushed alongside the vulnerability results
+	if action.PushReport {
+		for _, format := range security.ReportFormats {
+			if _, ok := outputMethods[format]; !ok {
+				continue
+			}
+			if err := security.AttachReports(ctx, format, results, action.VulnerabilityLevel, action.Version()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if policy != nil {
+		policyResults := make([]security.PolicyResult, len(results))
+		for i, res := range results {
+			policyResults[i] = *res.Policy
+		}
+		if err := security.PrintPolicySummary(os.Stdout, policyResults); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d images failed the vulnerability policy %s", failed, len(results), action.Policy)
+		}
+	}
+
 	return nil
 }
@@ -2,6 +2,9 @@
 package security
 
 import (
+	"context"
+	"time"
+
 	"github.com/act3-ai/data-tool/internal/actions"
 )
 
@@ -9,3 +12,14 @@ import (
 type Action struct {
 	*actions.DataTool
 }
+
+// maxDatabaseAge returns the maximum age of the vulnerability database, the configured maximum unless set by a flag.
+func (action *Action) maxDatabaseAge(ctx context.Context, flag time.Duration) time.Duration {
+	if flag != 0 {
+		return flag
+	}
+	if maxAge := action.Config.Get(ctx).VulnerabilityDBMaxAge; maxAge != nil {
+		return maxAge.Duration
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/act3-ai/data-tool/internal/actions/internal/format"
+	"github.com/act3-ai/data-tool/internal/ref"
+	sigcustom "github.com/act3-ai/data-tool/internal/sign"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// CheckExpiry returns an error if the signature expiry is not supported by notation signatures.
+func CheckExpiry(expiry time.Duration) error {
+	if expiry < 0 || expiry%time.Second != 0 {
+		return fmt.Errorf("signature expiry %s must be a positive whole number of seconds", expiry)
+	}
+	return nil
+}
+
+// SigningKey finds the signing key with the alias in the configuration, returning its signer provider and the
+// annotations to add to its signatures.
+func SigningKey(ctx context.Context, cfg *v1alpha1.Configuration, keyAlias string) (sigcustom.SignerProvider, map[string]string, error) {
+	log := logger.FromContext(ctx)
+
+	var foundKey v1alpha1.SigningKey
+
+	// search for signing key in config.
+	for _, key := range cfg.SigningKeys {
+		if key.Alias == keyAlias {
+			foundKey = key
+			log.InfoContext(ctx, "key found", "alias", keyAlias)
+			break
+		}
+	}
+	// fail if key is not found or if incomplete metadata for key.
+	if foundKey.Alias == "" {
+		return nil, nil, fmt.Errorf("private key not found in configuration")
+	} else if (foundKey.KeyPath == "" && foundKey.Plugin == "") || foundKey.KeyAPI == "" || foundKey.UserIdentity == "" || foundKey.KeyID == "" {
+		return nil, nil, fmt.Errorf("private key metadata incomplete, please check ace-dt config: Alias: %s, KeyPath = %s, Plugin = %s, KeyAPI = %s, UserIdentity = %s, KeyID = %s", foundKey.Alias, foundKey.KeyPath, foundKey.Plugin, foundKey.KeyAPI, foundKey.UserIdentity, foundKey.KeyID)
+	}
+
+	// construct the map for the annotations to be signed.
+	unsignedAnnos := map[string]string{
+		sigcustom.AnnotationUserID:    foundKey.UserIdentity,
+		sigcustom.AnnotationVerifyAPI: foundKey.KeyAPI,
+		sigcustom.AnnotationKeyID:     foundKey.KeyID,
+	}
+
+	if foundKey.Plugin != "" {
+		// The key is held by a signer plugin, which is not run until the key is used during signing.
+		log.InfoContext(ctx, "Constructing plugin signer provider", "plugin", foundKey.Plugin, "keyID", foundKey.KeyID)
+		return sigcustom.NewPluginSignerProvider(foundKey.Plugin, foundKey.KeyID, foundKey.PluginConfig), unsignedAnnos, nil
+	}
+
+	// Create a file based private key provider. Note the key is not loaded until the key is used during signing.
+	log.InfoContext(ctx, "Constructing private key provider", "privateKeyPath", foundKey.KeyPath)
+	return sigcustom.NewFilePrivateKeyProvider(foundKey.KeyPath, CertPath(foundKey.KeyPath)), unsignedAnnos, nil
+}
+
+// CertPath returns the path of the certificate file of a private key file, the key path with a ".crt" extension.
+// HACK: We should come up with a more robust method for specifying cert files.
+func CertPath(keyPath string) string {
+	return strings.TrimSuffix(keyPath, filepath.Ext(keyPath)) + ".crt"
+}
+
+// RepositoryScope returns the repository of a reference for selecting a trust policy statement, or an empty string
+// for references without a registry, such as bottle IDs.
+func RepositoryScope(reference string) string {
+	r, err := ref.FromString(reference)
+	if err != nil || r.Reg == "" {
+		return ""
+	}
+	return r.RepoString()
+}
+
+// LoadTrustPolicy loads the trust policy file, fetching the revocation lists of the policy from their registries.
+func (action *DataTool) LoadTrustPolicy(ctx context.Context, policyFile string) (*sigcustom.TrustPolicy, error) {
+	log := logger.FromContext(ctx)
+
+	policy, err := sigcustom.LoadTrustPolicy(policyFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range policy.RevocationLists {
+		if src.Reference == "" {
+			continue // loaded with the trust policy
+		}
+		log.InfoContext(ctx, "fetching revocation list", "ref", src.Reference)
+		repo, err := action.Config.Repository(ctx, src.Reference)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := repo.Resolve(ctx, repo.Reference.ReferenceOrDefault())
+		if err != nil {
+			return nil, fmt.Errorf("resolving revocation list %s: %w", src.Reference, err)
+		}
+		list, err := sigcustom.FetchRevocationList(ctx, repo, desc, policy, RepositoryScope(src.Reference))
+		if err != nil {
+			return nil, err
+		}
+		policy.Revoke(list)
+	}
+	return policy, nil
+}
+
+// FormatPolicyResult formats the signatures of a trust policy result as a table.
+func FormatPolicyResult(result *sigcustom.PolicyResult) string {
+	t := format.NewTable()
+	t.AddRow("SIGNATURE", "KEY", "SIGNER", "POLICY", "RESULT")
+	for _, sig := range result.Signatures {
+		key := "-"
+		if sig.Fingerprint != "" {
+			key = sig.Fingerprint.String()
+		}
+		signer := sig.Signer
+		if signer == "" {
+			signer = "-"
+		}
+		status := "trusted"
+		if sig.Err != nil {
+			status = "failed: " + sig.Err.Error()
+		}
+		t.AddRow(sig.Digest, key, signer, result.Statement.Name, status)
+	}
+	return fmt.Sprintf("%s\n%d of %d required signers trusted", t.String(), result.Signers(), result.Required())
+}
@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+// ResolveTarget opens the remote repository, or the OCI image layout directory if ociLayout is set, of the reference
+// and resolves the reference in it.  The repository of remote references is returned for selecting a trust policy
+// statement, it is empty for OCI image layouts.
+func (action *DataTool) ResolveTarget(ctx context.Context, rawRef string, ociLayout bool) (oras.GraphTarget, ocispec.Descriptor, string, error) {
+	var target oras.GraphTarget
+	var ref, repository string
+
+	switch {
+	case ociLayout:
+		// local directory in oci layout
+		path, r, err := ParseOCILayoutReference(rawRef)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, "", err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, ocispec.Descriptor{}, "", fmt.Errorf("opening OCI image layout directory: %w", err)
+		}
+		store, err := oci.New(path)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, "", fmt.Errorf("opening OCI image layout directory: %w", err)
+		}
+		target = store
+		ref = r
+	default:
+		// remote reference
+		repo, err := action.Config.Repository(ctx, rawRef)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, "", err
+		}
+		target = repo
+		ref = repo.Reference.ReferenceOrDefault()
+		repository = RepositoryScope(rawRef)
+	}
+
+	desc, err := target.Resolve(ctx, ref)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, "", fmt.Errorf("resolving reference %s: %w", rawRef, err)
+	}
+	// signatures refer to the plain descriptor, without the annotations of an OCI image layout index
+	desc = ocispec.Descriptor{
+		MediaType: desc.MediaType,
+		Digest:    desc.Digest,
+		Size:      desc.Size,
+	}
+	return target, desc, repository, nil
+}
+
+// ParseOCILayoutReference parses the raw in format of <path>[:<tag>|@<digest>].
+func ParseOCILayoutReference(raw string) (string, string, error) {
+	if idx := strings.LastIndex(raw, "@"); idx != -1 {
+		// `digest` found
+		return raw[:idx], raw[idx+1:], nil
+	}
+	// find `tag`
+	if idx := strings.LastIndex(raw, ":"); idx != -1 {
+		return raw[:idx], raw[idx+1:], nil
+	}
+
+	return "", "", fmt.Errorf(`directory path and reference must be separated by "@" for digests and ":" for tags in %q`, raw)
+}
@@ -36,6 +36,10 @@ func TarToStream(ctx context.Context, fsys fs.FS, ostream io.Writer, options ...
 			return err
 		}
 
+		if ar.fidelity {
+			return ar.walkFidelity(fsys, path, d)
+		}
+
 		// We use fs.Stat (instead of d.Info()) so that the FileInfo is the info for the actual file (data) and not the symlink
 		info, err := fs.Stat(fsys, path)
 		if err != nil {
@@ -82,3 +86,26 @@ func TarToStream(ctx context.Context, fsys fs.FS, ostream io.Writer, options ...
 	// this walks in lexicographic order so it is deterministic
 	return fs.WalkDir(fsys, ".", walkFn)
 }
+
+// walkFidelity archives an entry in fidelity mode, where symbolic links are archived as links instead of being
+// followed.
+func (ar *TarArchiver) walkFidelity(fsys fs.FS, path string, d fs.DirEntry) error {
+	if path == "." {
+		// the top level directory is created by the bottle, as above
+		return nil
+	}
+
+	if strings.HasPrefix(d.Name(), ".") {
+		// ignore hidden files and directories
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	}
+
+	info, err := d.Info()
+	if err != nil {
+		return fmt.Errorf("unable to get the file info: %w", err)
+	}
+	return ar.WriteEntry(fsys, path, info)
+}
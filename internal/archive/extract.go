@@ -10,12 +10,38 @@ import (
 	"github.com/act3-ai/go-common/pkg/logger"
 )
 
+// ExtractOption is used for options for the extraction process.
+type ExtractOption func(ar *TarExtractor)
+
+// WithLinkRoot sets the directory that symbolic links in a fidelity mode archive must resolve within, such as the
+// bottle directory.  The default is the destination directory.
+func WithLinkRoot(root string) ExtractOption {
+	return func(ar *TarExtractor) {
+		ar.LinkRoot = root
+	}
+}
+
+// WithRestoreXattrs sets the extended attributes of the user namespace recorded in a fidelity mode archive on the
+// extracted files.
+func WithRestoreXattrs() ExtractOption {
+	return func(ar *TarExtractor) {
+		ar.RestoreXattrs = true
+	}
+}
+
+// WithSparseFiles writes blocks of zeros in extracted files as holes.
+func WithSparseFiles() ExtractOption {
+	return func(ar *TarExtractor) {
+		ar.SparseFiles = true
+	}
+}
+
 // ExtractTar unarchives a tar file.
 // Existing files are overwritten.
-func ExtractTar(ctx context.Context, rc io.ReadCloser, destPath string) error {
+func ExtractTar(ctx context.Context, rc io.ReadCloser, destPath string, options ...ExtractOption) error {
 	dec := &PipeIn{}
 	dec.ConnectIn(rc)
-	return extractWithPipeStream(ctx, dec, destPath, false)
+	return extractWithPipeStream(ctx, dec, destPath, false, options...)
 }
 
 // ExtractTarCompat unarchives a tar file.
@@ -28,10 +54,10 @@ func ExtractTarCompat(ctx context.Context, rc io.ReadCloser, destPath string) er
 
 // ExtractTarZstd decompresses and unarchives a tar+zst file.
 // Existing files are overwritten.
-func ExtractTarZstd(ctx context.Context, rc io.ReadCloser, destPath string) error {
+func ExtractTarZstd(ctx context.Context, rc io.ReadCloser, destPath string, options ...ExtractOption) error {
 	dec := &PipeZstdDec{}
 	dec.ConnectIn(rc)
-	return extractWithPipeStream(ctx, dec, destPath, false)
+	return extractWithPipeStream(ctx, dec, destPath, false, options...)
 }
 
 // ExtractTarZstdCompat decompresses and unarchives a tar+zst file.
@@ -78,7 +104,7 @@ func ExtractZstd(ctx context.Context, rc io.ReadCloser, destPath string) error {
 }
 
 // extractWithPipeStream unarchives from the provided reader.
-func extractWithPipeStream(ctx context.Context, in io.ReadCloser, destPath string, makeParents bool) error {
+func extractWithPipeStream(ctx context.Context, in io.ReadCloser, destPath string, makeParents bool, options ...ExtractOption) error {
 	log := logger.FromContext(ctx).With("dest", destPath)
 	log.DebugContext(ctx, "Extracting archive")
 
@@ -89,6 +115,10 @@ func extractWithPipeStream(ctx context.Context, in io.ReadCloser, destPath strin
 	tar := TarExtractor{
 		OverwriteExisting:     true,
 		MakeParentDirectories: makeParents,
+		Log:                   log,
+	}
+	for _, o := range options {
+		o(&tar)
 	}
 
 	err := tar.UnarchiveRead(in, destPath)
@@ -0,0 +1,402 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FidelityRecord is the PAX record in the global header of an archive created in fidelity mode.  Extractors that
+// predate fidelity mode reject the global header, so they refuse to extract the archive instead of silently
+// dropping links and permissions.
+const FidelityRecord = "ACE.archive.fidelity"
+
+// FidelityVersion is the value of the FidelityRecord written by this version.
+const FidelityVersion = "1"
+
+// xattrRecordPrefix is the PAX record prefix used by GNU and BSD tar for extended attributes.
+const xattrRecordPrefix = "SCHILY.xattr."
+
+// userXattrPrefix is the namespace of the extended attributes preserved.  Attributes of the other namespaces, such as
+// security.capability, security.selinux or trusted.*, grant privileges or are specific to the host.
+const userXattrPrefix = "user."
+
+// sparseBlockSize is the size of the blocks examined for holes when writing sparse files.
+const sparseBlockSize = 4096
+
+// ErrUnsupportedFidelity is the error when an archive requires an unknown fidelity mode.
+var ErrUnsupportedFidelity = errors.New("unsupported archive fidelity")
+
+// ErrLinkOutsideRoot is the error when a symbolic or hard link refers to a path outside the allowed root.
+var ErrLinkOutsideRoot = errors.New("link target outside of root")
+
+// WithFidelity preserves symbolic links, hard links (as a single copy of the data) and permission bits.  dir is the
+// local directory being archived and root is the directory that all symbolic links must resolve within, such as
+// the bottle directory.  The archive is marked so that only extractors supporting fidelity mode will extract it.
+func WithFidelity(dir, root string) TarArchiverOption {
+	return func(ar *TarArchiver) error {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("resolving archive directory: %w", err)
+		}
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("resolving link root: %w", err)
+		}
+		ar.fidelity = true
+		ar.dir = absDir
+		ar.linkRoot = absRoot
+		ar.hardlinks = make(map[fileID]string)
+		return nil
+	}
+}
+
+// WithXattrs preserves the extended attributes of the user namespace of files and directories.  Only applies in
+// fidelity mode.
+func WithXattrs() TarArchiverOption {
+	return func(ar *TarArchiver) error {
+		ar.xattrs = true
+		return nil
+	}
+}
+
+// writeFidelityHeader writes the global header that marks the archive as created in fidelity mode.
+func (ar *TarArchiver) writeFidelityHeader() error {
+	hdr := &tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		Name:       "pax_global_header",
+		PAXRecords: map[string]string{FidelityRecord: FidelityVersion},
+		Format:     tar.FormatPAX,
+	}
+	if err := ar.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing fidelity header: %w", err)
+	}
+	return nil
+}
+
+// fidelityHeader completes the header for the entry at path in fidelity mode.  It returns false if the contents of
+// the entry should not be written, which is the case for links.
+func (ar *TarArchiver) fidelityHeader(hdr *tar.Header, path string, finfo fs.FileInfo) (bool, error) {
+	fm := finfo.Mode()
+	localPath := filepath.Join(ar.dir, filepath.FromSlash(path))
+
+	if ar.xattrs {
+		attrs, err := readXattrs(localPath)
+		if err != nil {
+			return false, fmt.Errorf("%s: reading extended attributes: %w", path, err)
+		}
+		for name, value := range attrs {
+			if hdr.PAXRecords == nil {
+				hdr.PAXRecords = make(map[string]string, len(attrs))
+			}
+			hdr.PAXRecords[xattrRecordPrefix+name] = value
+		}
+	}
+
+	switch {
+	case fm&fs.ModeSymlink != 0:
+		target, err := os.Readlink(localPath)
+		if err != nil {
+			return false, fmt.Errorf("%s: reading symbolic link: %w", path, err)
+		}
+		if err := checkSymlink(ar.linkRoot, localPath, target); err != nil {
+			return false, fmt.Errorf("%s: %w", path, err)
+		}
+		hdr.Typeflag = tar.TypeSymlink
+		hdr.Linkname = filepath.ToSlash(target)
+		hdr.Mode = 0o777
+		return false, nil
+	case fm.IsRegular():
+		hdr.Mode = int64(fm.Perm())
+		if id, ok := getFileID(finfo); ok {
+			if first, seen := ar.hardlinks[id]; seen {
+				hdr.Typeflag = tar.TypeLink
+				hdr.Linkname = first
+				hdr.Size = 0
+				return false, nil
+			}
+			ar.hardlinks[id] = path
+		}
+		return true, nil
+	case fm.IsDir():
+		hdr.Mode = int64(fm.Perm())
+		return false, nil
+	default:
+		return false, fmt.Errorf("file mode %v not supported", fm)
+	}
+}
+
+// checkSymlink ensures that the symbolic link at linkPath with the given target resolves within root, both lexically
+// and, if the target exists, after evaluating any other links along the way.
+func checkSymlink(root, linkPath, target string) error {
+	if filepath.IsAbs(target) || path.IsAbs(filepath.ToSlash(target)) {
+		return fmt.Errorf("absolute symbolic link %q: %w", target, ErrLinkOutsideRoot)
+	}
+
+	if !isWithin(root, filepath.Join(filepath.Dir(linkPath), target)) {
+		return fmt.Errorf("symbolic link %q: %w", target, ErrLinkOutsideRoot)
+	}
+
+	resolved, err := filepath.EvalSymlinks(linkPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// dangling links are allowed as long as they are lexically within the root
+		return nil
+	case err != nil:
+		return fmt.Errorf("evaluating symbolic link %q: %w", target, err)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return fmt.Errorf("evaluating link root: %w", err)
+	}
+	if !isWithin(realRoot, resolved) {
+		return fmt.Errorf("symbolic link %q: %w", target, ErrLinkOutsideRoot)
+	}
+	return nil
+}
+
+// isWithin reports whether the absolute path p is root or is contained in root.
+func isWithin(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// pendingEntry is an entry whose creation is deferred until the rest of the archive has been extracted.
+type pendingEntry struct {
+	path   string
+	target string
+	mode   fs.FileMode
+}
+
+// startFidelity handles a global header, enabling fidelity mode if the header requires it.
+func (ar *TarExtractor) startFidelity(hdr *tar.Header) error {
+	version, ok := hdr.PAXRecords[FidelityRecord]
+	switch {
+	case !ok:
+		return fmt.Errorf("%s has an unknown TAR type %c", hdr.Name, hdr.Typeflag)
+	case version != FidelityVersion:
+		return fmt.Errorf("archive requires fidelity version %q: %w", version, ErrUnsupportedFidelity)
+	}
+	ar.fidelity = true
+	return nil
+}
+
+// untarFidelityEntry extracts an entry of an archive created in fidelity mode.  Symbolic links and directory
+// permissions are applied by finishFidelity, so that no entry is written through a link or blocked by a read-only
+// directory.
+func (ar *TarExtractor) untarFidelityEntry(f *TarFileData, destination string, hdr *tar.Header) error {
+	if !filepath.IsLocal(filepath.FromSlash(strings.TrimSuffix(hdr.Name, "/"))) {
+		return fmt.Errorf("%s: path is outside of the destination", hdr.Name)
+	}
+	if err := checkNoSymlinkParents(destination, hdr.Name); err != nil {
+		return err
+	}
+	to := filepath.Join(destination, filepath.FromSlash(hdr.Name))
+	mode := fs.FileMode(hdr.Mode).Perm()
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(to, 0o777); err != nil {
+			return fmt.Errorf("tar extraction: %w", err)
+		}
+		ar.pendingModes = append(ar.pendingModes, pendingEntry{path: to, mode: mode})
+	case tar.TypeReg:
+		if err := ar.removeSymlink(to); err != nil {
+			return err
+		}
+		if err := ar.writeNewFile(to, f); err != nil {
+			return err
+		}
+		if err := os.Chmod(to, mode); err != nil {
+			return fmt.Errorf("%s: setting permissions: %w", to, err)
+		}
+	case tar.TypeLink:
+		linkname := filepath.FromSlash(hdr.Linkname)
+		if !filepath.IsLocal(linkname) {
+			return fmt.Errorf("%s: hard link %q: %w", hdr.Name, hdr.Linkname, ErrLinkOutsideRoot)
+		}
+		if err := checkNoSymlinkParents(destination, hdr.Linkname); err != nil {
+			return err
+		}
+		src := filepath.Join(destination, linkname)
+		if info, err := os.Lstat(src); err != nil || !info.Mode().IsRegular() {
+			return fmt.Errorf("%s: hard link target %q is not a regular file in the archive", hdr.Name, hdr.Linkname)
+		}
+		if err := ar.removeExisting(to); err != nil {
+			return err
+		}
+		if err := os.Link(src, to); err != nil {
+			return fmt.Errorf("tar extraction: %w", err)
+		}
+		return nil
+	case tar.TypeSymlink:
+		ar.pendingLinks = append(ar.pendingLinks, pendingEntry{path: to, target: filepath.FromSlash(hdr.Linkname)})
+		return nil
+	default:
+		return fmt.Errorf("%s has an unknown TAR type %c", hdr.Name, hdr.Typeflag)
+	}
+
+	if ar.RestoreXattrs {
+		return ar.restoreXattrs(to, hdr)
+	}
+	return nil
+}
+
+// finishFidelity creates the symbolic links and applies the directory permissions deferred during extraction.
+func (ar *TarExtractor) finishFidelity(destination string) error {
+	root := ar.LinkRoot
+	if root == "" {
+		root = destination
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("resolving link root: %w", err)
+	}
+
+	for _, link := range ar.pendingLinks {
+		to, err := filepath.Abs(link.path)
+		if err != nil {
+			return fmt.Errorf("resolving symbolic link path: %w", err)
+		}
+		if err := ar.removeExisting(to); err != nil {
+			return err
+		}
+		if err := os.Symlink(link.target, to); err != nil {
+			return fmt.Errorf("tar extraction: %w", err)
+		}
+	}
+
+	// links are checked once they all exist, since a link may resolve through another link
+	for _, link := range ar.pendingLinks {
+		to, err := filepath.Abs(link.path)
+		if err != nil {
+			return fmt.Errorf("resolving symbolic link path: %w", err)
+		}
+		if err := checkSymlink(root, to, link.target); err != nil {
+			if rerr := os.Remove(to); rerr != nil {
+				err = errors.Join(err, rerr)
+			}
+			return fmt.Errorf("%s: %w", link.path, err)
+		}
+	}
+
+	// children are extracted after their parents, so apply the modes in reverse to keep the parents writable
+	for i := len(ar.pendingModes) - 1; i >= 0; i-- {
+		dir := ar.pendingModes[i]
+		if err := os.Chmod(dir.path, dir.mode); err != nil {
+			return fmt.Errorf("%s: setting permissions: %w", dir.path, err)
+		}
+	}
+
+	ar.pendingLinks = nil
+	ar.pendingModes = nil
+	return nil
+}
+
+// removeExisting removes the file at fpath, if overwriting is enabled, so that it can be replaced with a link.
+func (ar *TarExtractor) removeExisting(fpath string) error {
+	if _, err := os.Lstat(fpath); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if !ar.OverwriteExisting {
+		return fmt.Errorf("file %s already exists but we are not overwriting files", fpath)
+	}
+	if err := os.Remove(fpath); err != nil {
+		return fmt.Errorf("removing existing file: %w", err)
+	}
+	return nil
+}
+
+// removeSymlink removes a symbolic link at fpath, so that writing a file replaces the link instead of following it.
+func (ar *TarExtractor) removeSymlink(fpath string) error {
+	info, err := os.Lstat(fpath)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		return nil
+	}
+	return ar.removeExisting(fpath)
+}
+
+// checkNoSymlinkParents ensures that none of the parent directories of name within destination are symbolic links,
+// which could otherwise redirect the extraction outside of the destination.
+func checkNoSymlinkParents(destination, name string) error {
+	parent := path.Dir(strings.TrimSuffix(name, "/"))
+	for dir := parent; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		info, err := os.Lstat(filepath.Join(destination, filepath.FromSlash(dir)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			continue
+		case err != nil:
+			return fmt.Errorf("checking parent directory: %w", err)
+		case info.Mode()&fs.ModeSymlink != 0:
+			return fmt.Errorf("%s: parent directory %s is a symbolic link", name, dir)
+		}
+	}
+	return nil
+}
+
+// isUserXattr returns true if the extended attribute is of the user namespace, the only namespace preserved.
+func isUserXattr(name string) bool {
+	return strings.HasPrefix(name, userXattrPrefix) && len(name) > len(userXattrPrefix)
+}
+
+// restoreXattrs sets the extended attributes of the user namespace recorded in the header on the extracted file.
+// Attributes are skipped, and logged, if the filesystem does not support them or the user may not set them, since
+// they do not prevent using the file.
+func (ar *TarExtractor) restoreXattrs(fpath string, hdr *tar.Header) error {
+	for key, value := range hdr.PAXRecords {
+		name, ok := strings.CutPrefix(key, xattrRecordPrefix)
+		if !ok || !isUserXattr(name) {
+			continue
+		}
+		err := setXattr(fpath, name, value)
+		switch {
+		case err != nil && isXattrUnsupported(err):
+			if ar.Log != nil {
+				ar.Log.Warn("skipping extended attribute that cannot be restored", "path", fpath, "attribute", name, "error", err)
+			}
+		case err != nil:
+			return fmt.Errorf("%s: restoring extended attributes: %w", fpath, err)
+		}
+	}
+	return nil
+}
+
+// copySparse copies in to out, seeking over blocks of zeros so that they become holes in the output file.
+func copySparse(out *os.File, in io.Reader) (int64, error) {
+	buf := make([]byte, sparseBlockSize)
+	zeros := make([]byte, sparseBlockSize)
+	var total int64
+	for {
+		n, err := io.ReadFull(in, buf)
+		if n > 0 {
+			if bytes.Equal(buf[:n], zeros[:n]) {
+				if _, serr := out.Seek(int64(n), io.SeekCurrent); serr != nil {
+					return total, fmt.Errorf("seeking over hole: %w", serr)
+				}
+			} else if _, werr := out.Write(buf[:n]); werr != nil {
+				return total, fmt.Errorf("writing block: %w", werr)
+			}
+			total += int64(n)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return total, fmt.Errorf("reading block: %w", err)
+		}
+	}
+
+	// a trailing hole is only recorded by the file size
+	if err := out.Truncate(total); err != nil {
+		return total, fmt.Errorf("setting file size: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,234 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupFidelityDir creates a directory with links and assorted permissions within a bottle directory.
+func setupFidelityDir(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic and hard links require privileges on windows")
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "data")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o777))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "other.txt"), []byte("other"), 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("file a"), 0o640))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh"), 0o755))
+	require.NoError(t, os.Link(filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")))
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(dir, "link.txt")))
+	require.NoError(t, os.Symlink("../a.txt", filepath.Join(dir, "sub", "up.txt")))
+	require.NoError(t, os.Symlink("sub", filepath.Join(dir, "sublink")))
+	require.NoError(t, os.Symlink("../other.txt", filepath.Join(dir, "other.txt")))
+	require.NoError(t, os.Chmod(filepath.Join(dir, "sub"), 0o750))
+	return root, dir
+}
+
+func Test_FidelityArchive(t *testing.T) {
+	ctx := context.Background()
+	root, dir := setupFidelityDir(t)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, TarToStream(ctx, os.DirFS(dir), buf, WithFidelity(dir, root)))
+
+	headers := make(map[string]*tar.Header)
+	tr := tar.NewReader(bytes.NewReader(buf.Bytes()))
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, byte(tar.TypeXGlobalHeader), hdr.Typeflag)
+	assert.Equal(t, FidelityVersion, hdr.PAXRecords[FidelityRecord])
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		headers[hdr.Name] = hdr
+	}
+
+	assert.Equal(t, byte(tar.TypeReg), headers["a.txt"].Typeflag)
+	assert.Equal(t, int64(0o640), headers["a.txt"].Mode)
+	assert.Equal(t, int64(0o755), headers["run.sh"].Mode)
+	assert.Equal(t, byte(tar.TypeLink), headers["b.txt"].Typeflag)
+	assert.Equal(t, "a.txt", headers["b.txt"].Linkname)
+	assert.Equal(t, byte(tar.TypeSymlink), headers["link.txt"].Typeflag)
+	assert.Equal(t, "a.txt", headers["link.txt"].Linkname)
+	assert.Equal(t, byte(tar.TypeSymlink), headers["sublink"].Typeflag)
+	assert.Equal(t, int64(0o750), headers["sub/"].Mode)
+	assert.NotContains(t, headers, "sublink/up.txt", "symbolic links to directories are not followed")
+
+	t.Run("extract", func(t *testing.T) {
+		destRoot := t.TempDir()
+		dest := filepath.Join(destRoot, "data")
+		require.NoError(t, os.WriteFile(filepath.Join(destRoot, "other.txt"), []byte("other"), 0o666))
+		require.NoError(t, ExtractTar(ctx, io.NopCloser(bytes.NewReader(buf.Bytes())), dest, WithLinkRoot(destRoot)))
+
+		info, err := os.Stat(filepath.Join(dest, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o640), info.Mode().Perm())
+
+		linked, err := os.Stat(filepath.Join(dest, "b.txt"))
+		require.NoError(t, err)
+		assert.True(t, os.SameFile(info, linked), "hard links should share the file")
+
+		info, err = os.Stat(filepath.Join(dest, "run.sh"))
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o755), info.Mode().Perm())
+
+		info, err = os.Stat(filepath.Join(dest, "sub"))
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o750), info.Mode().Perm())
+
+		target, err := os.Readlink(filepath.Join(dest, "sub", "up.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "../a.txt", target)
+
+		data, err := os.ReadFile(filepath.Join(dest, "sublink", "up.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "file a", string(data))
+
+		data, err = os.ReadFile(filepath.Join(dest, "other.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "other", string(data))
+	})
+
+	t.Run("extract outside link root", func(t *testing.T) {
+		// without the bottle directory as the link root, the link to other.txt escapes the destination
+		dest := t.TempDir()
+		err := ExtractTar(ctx, io.NopCloser(bytes.NewReader(buf.Bytes())), dest)
+		require.ErrorIs(t, err, ErrLinkOutsideRoot)
+	})
+}
+
+func Test_FidelityArchiveRejectsEscapingLinks(t *testing.T) {
+	ctx := context.Background()
+	root, dir := setupFidelityDir(t)
+
+	require.NoError(t, os.Symlink("../../outside", filepath.Join(dir, "escape")))
+	err := TarToStream(ctx, os.DirFS(dir), io.Discard, WithFidelity(dir, root))
+	require.ErrorIs(t, err, ErrLinkOutsideRoot)
+
+	// a link that is lexically inside, but resolves outside through another link
+	require.NoError(t, os.Remove(filepath.Join(dir, "escape")))
+	require.NoError(t, os.Symlink("..", filepath.Join(dir, "sub", "parent")))
+	require.NoError(t, os.Symlink("sub/parent/..", filepath.Join(dir, "sneaky")))
+	err = TarToStream(ctx, os.DirFS(dir), io.Discard, WithFidelity(dir, dir))
+	require.ErrorIs(t, err, ErrLinkOutsideRoot)
+}
+
+// writeFidelityTar writes a fidelity mode archive with the given headers, bypassing the checks done by TarArchiver.
+func writeFidelityTar(t *testing.T, version string, hdrs ...*tar.Header) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		PAXRecords: map[string]string{FidelityRecord: version},
+		Format:     tar.FormatPAX,
+	}))
+	for _, hdr := range hdrs {
+		require.NoError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write(make([]byte, hdr.Size))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func Test_FidelityExtractSafety(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require privileges on windows")
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		version string
+		hdrs    []*tar.Header
+		wantErr error
+	}{
+		{
+			name:    "unknown version",
+			version: "99",
+			wantErr: ErrUnsupportedFidelity,
+		},
+		{
+			name:    "escaping symlink",
+			version: FidelityVersion,
+			hdrs:    []*tar.Header{{Typeflag: tar.TypeSymlink, Name: "evil", Linkname: "../../etc"}},
+			wantErr: ErrLinkOutsideRoot,
+		},
+		{
+			name:    "absolute symlink",
+			version: FidelityVersion,
+			hdrs:    []*tar.Header{{Typeflag: tar.TypeSymlink, Name: "evil", Linkname: "/etc"}},
+			wantErr: ErrLinkOutsideRoot,
+		},
+		{
+			name:    "escaping hard link",
+			version: FidelityVersion,
+			hdrs:    []*tar.Header{{Typeflag: tar.TypeLink, Name: "evil", Linkname: "../secret"}},
+			wantErr: ErrLinkOutsideRoot,
+		},
+		{
+			name:    "escaping path",
+			version: FidelityVersion,
+			hdrs:    []*tar.Header{{Typeflag: tar.TypeReg, Name: "../evil", Mode: 0o644}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "dest")
+			data := writeFidelityTar(t, tt.version, tt.hdrs...)
+			err := ExtractTar(ctx, io.NopCloser(bytes.NewReader(data)), dest)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+			_, err = os.Lstat(filepath.Join(parent, "evil"))
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+		})
+	}
+
+	t.Run("existing symlink parent", func(t *testing.T) {
+		parent := t.TempDir()
+		dest := filepath.Join(parent, "dest")
+		require.NoError(t, os.MkdirAll(dest, 0o777))
+		require.NoError(t, os.Symlink("..", filepath.Join(dest, "up")))
+		data := writeFidelityTar(t, FidelityVersion, &tar.Header{Typeflag: tar.TypeReg, Name: "up/evil", Mode: 0o644, Size: 1})
+		err := ExtractTar(ctx, io.NopCloser(bytes.NewReader(data)), dest)
+		require.Error(t, err)
+		_, err = os.Lstat(filepath.Join(parent, "evil"))
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("hard link through existing symlink parent", func(t *testing.T) {
+		parent := t.TempDir()
+		dest := filepath.Join(parent, "dest")
+		require.NoError(t, os.MkdirAll(dest, 0o777))
+		require.NoError(t, os.WriteFile(filepath.Join(parent, "secret"), []byte("secret"), 0o600))
+		require.NoError(t, os.Symlink("..", filepath.Join(dest, "up")))
+		data := writeFidelityTar(t, FidelityVersion, &tar.Header{Typeflag: tar.TypeLink, Name: "stolen", Linkname: "up/secret"})
+		err := ExtractTar(ctx, io.NopCloser(bytes.NewReader(data)), dest)
+		require.Error(t, err)
+		_, err = os.Lstat(filepath.Join(dest, "stolen"))
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
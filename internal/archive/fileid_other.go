@@ -0,0 +1,13 @@
+//go:build !unix
+
+package archive
+
+import "io/fs"
+
+// fileID identifies a file independent of its path, so that hard links to the same file can be detected.
+type fileID struct{}
+
+// getFileID returns false since hard links are not detected on this platform, so each link is archived as a copy.
+func getFileID(finfo fs.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
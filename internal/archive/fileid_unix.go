@@ -0,0 +1,23 @@
+//go:build unix
+
+package archive
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileID identifies a file independent of its path, so that hard links to the same file can be detected.
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+// getFileID returns the identity of a file that has more than one hard link.
+func getFileID(finfo fs.FileInfo) (fileID, bool) {
+	st, ok := finfo.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink < 2 {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: st.Ino}, true //nolint:unconvert
+}
@@ -0,0 +1,41 @@
+//go:build unix
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExtractSparseFiles(t *testing.T) {
+	ctx := context.Background()
+	dest := t.TempDir()
+
+	content := make([]byte, 3*sparseBlockSize+10)
+	copy(content[sparseBlockSize:], "data")
+	fsys := map[string][]byte{"sparse.bin": content}
+	dir, _ := setup(t, fsys)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, TarToStream(ctx, os.DirFS(dir), buf))
+	require.NoError(t, ExtractTar(ctx, io.NopCloser(buf), dest, WithSparseFiles()))
+
+	data, err := os.ReadFile(filepath.Join(dest, "sparse.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// only the block of data is allocated, the blocks of zeros are holes
+	info, err := os.Stat(filepath.Join(dest, "sparse.bin"))
+	require.NoError(t, err)
+	st, ok := info.Sys().(*syscall.Stat_t)
+	require.True(t, ok)
+	assert.Less(t, st.Blocks*512, info.Size(), "expected holes in the extracted file")
+}
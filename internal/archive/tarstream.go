@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -46,6 +47,21 @@ type TarArchiver struct {
 
 	// portablePaths ensures all paths are portable POSIX paths
 	portablePaths bool
+
+	// fidelity preserves symbolic links, hard links and permission bits.  See WithFidelity.
+	fidelity bool
+
+	// dir is the local directory being archived in fidelity mode
+	dir string
+
+	// linkRoot is the directory that symbolic links must resolve within in fidelity mode
+	linkRoot string
+
+	// hardlinks maps files with multiple links to the first path archived for them
+	hardlinks map[fileID]string
+
+	// xattrs preserves extended attributes in fidelity mode
+	xattrs bool
 }
 
 // Close closes the writer if present, and clears any existing stream objects.
@@ -71,11 +87,18 @@ func NewArchiver(ostream io.Writer, options ...TarArchiverOption) (*TarArchiver,
 		}
 	}
 
+	if ar.fidelity {
+		if err := ar.writeFidelityHeader(); err != nil {
+			return nil, err
+		}
+	}
+
 	return ar, nil
 }
 
 // WriteEntry adds file or directory data to the archive.
-// finfo is expected to be the FileInfo for the actual file or directory (not a symlink to a file or directory).
+// finfo is expected to be the FileInfo for the actual file or directory (not a symlink to a file or directory),
+// except in fidelity mode where finfo describes the entry itself and symbolic links are archived as links.
 func (ar *TarArchiver) WriteEntry(fsys fs.FS, path string, finfo fs.FileInfo) error {
 	if ar.tw == nil {
 		return fmt.Errorf("tar archive was not created for writing first")
@@ -95,6 +118,24 @@ func (ar *TarArchiver) WriteEntry(fsys fs.FS, path string, finfo fs.FileInfo) er
 	}
 
 	switch {
+	case ar.fidelity:
+		if fm.IsDir() {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+		} else {
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = finfo.Size()
+		}
+		withContent, err := ar.fidelityHeader(hdr, path, finfo)
+		if err != nil {
+			return err
+		}
+		if !withContent {
+			if err := ar.tw.WriteHeader(hdr); err != nil {
+				return fmt.Errorf("writing header for path %s: %w", hdr.Name, err)
+			}
+			return nil
+		}
 	case fm.IsRegular():
 		hdr.Typeflag = tar.TypeReg
 		hdr.Size = finfo.Size()
@@ -158,6 +199,26 @@ type TarExtractor struct {
 
 	// MakeParentDirectories will make all parents instead of just what should be necessary (set to true for backwards compatibility)
 	MakeParentDirectories bool
+
+	// LinkRoot is the directory that symbolic links in a fidelity mode archive must resolve within.  Defaults to the
+	// destination directory.
+	LinkRoot string
+
+	// RestoreXattrs sets the extended attributes of the user namespace recorded in a fidelity mode archive
+	RestoreXattrs bool
+
+	// Log reports the extended attributes that could not be restored, if set
+	Log *slog.Logger
+
+	// SparseFiles writes blocks of zeros in regular files as holes
+	SparseFiles bool
+
+	// fidelity is set once the archive is found to be created in fidelity mode
+	fidelity bool
+
+	// pendingLinks and pendingModes are the symbolic links and directory permissions deferred in fidelity mode
+	pendingLinks []pendingEntry
+	pendingModes []pendingEntry
 }
 
 // Open opens an archive stream for reading.
@@ -210,6 +271,10 @@ func (ar *TarExtractor) UnarchiveRead(sourceReader io.Reader, destPath string) e
 		}
 	}
 
+	if ar.fidelity {
+		return ar.finishFidelity(destPath)
+	}
+
 	return nil
 }
 
@@ -223,6 +288,13 @@ func (ar *TarExtractor) untarNext(destination string) error {
 }
 
 func (ar *TarExtractor) untarEntry(f *TarFileData, destination string, hdr *tar.Header) error {
+	if hdr.Typeflag == tar.TypeXGlobalHeader {
+		return ar.startFidelity(hdr)
+	}
+	if ar.fidelity {
+		return ar.untarFidelityEntry(f, destination, hdr)
+	}
+
 	to := filepath.Join(destination, hdr.Name)
 
 	switch hdr.Typeflag {
@@ -260,9 +332,13 @@ func (ar *TarExtractor) writeNewFile(fpath string, in io.Reader) error {
 	}
 	defer out.Close()
 
-	_, err = io.Copy(out, in)
+	if ar.SparseFiles {
+		_, err = copySparse(out, in)
+	} else {
+		_, err = io.Copy(out, in)
+	}
 	if err != nil {
 		return fmt.Errorf("%s: writing file: %w", fpath, err)
 	}
-	return nil
+	return out.Close() //nolint:wrapcheck
 }
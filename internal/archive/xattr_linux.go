@@ -0,0 +1,72 @@
+//go:build linux
+
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// readXattrs returns the extended attributes of the user namespace of the file at path, without following symbolic
+// links.
+func readXattrs(path string) (map[string]string, error) {
+	size, err := unix.Llistxattr(path, nil)
+	switch {
+	case errors.Is(err, unix.ENOTSUP):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("listing attributes: %w", err)
+	case size == 0:
+		return nil, nil
+	}
+
+	buf := make([]byte, size)
+	size, err = unix.Llistxattr(path, buf)
+	if err != nil {
+		return nil, fmt.Errorf("listing attributes: %w", err)
+	}
+
+	attrs := make(map[string]string)
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		if !isUserXattr(string(name)) {
+			continue
+		}
+		value, err := getXattr(path, string(name))
+		if err != nil {
+			return nil, err
+		}
+		attrs[string(name)] = value
+	}
+	return attrs, nil
+}
+
+// getXattr returns the value of a single extended attribute.
+func getXattr(path, name string) (string, error) {
+	size, err := unix.Lgetxattr(path, name, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting attribute %s: %w", name, err)
+	}
+	buf := make([]byte, size)
+	size, err = unix.Lgetxattr(path, name, buf)
+	if err != nil {
+		return "", fmt.Errorf("getting attribute %s: %w", name, err)
+	}
+	return string(buf[:size]), nil
+}
+
+// setXattr sets an extended attribute of the file at path, without following symbolic links.
+func setXattr(path, name, value string) error {
+	if err := unix.Lsetxattr(path, name, []byte(value), 0); err != nil {
+		return fmt.Errorf("setting attribute %s: %w", name, err)
+	}
+	return nil
+}
+
+// isXattrUnsupported returns true if the error setting an extended attribute is because the filesystem does not
+// support extended attributes, or the user may not set them.
+func isXattrUnsupported(err error) bool {
+	return errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM)
+}
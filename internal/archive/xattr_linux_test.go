@@ -0,0 +1,80 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func Test_FidelityXattrs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fpath := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(fpath, []byte("file a"), 0o644))
+	if err := unix.Lsetxattr(fpath, "user.ace.test", []byte("value"), 0); err != nil {
+		t.Skipf("extended attributes not supported: %v", err)
+	}
+
+	// only root may set trusted attributes, which are not archived
+	trusted := unix.Lsetxattr(fpath, "trusted.ace.test", []byte("value"), 0) == nil
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, TarToStream(ctx, os.DirFS(dir), buf, WithFidelity(dir, dir), WithXattrs()))
+	if trusted {
+		assert.NotContains(t, buf.String(), "trusted.ace.test")
+	}
+
+	dest := t.TempDir()
+	require.NoError(t, ExtractTar(ctx, io.NopCloser(buf), dest, WithRestoreXattrs()))
+
+	attrs, err := readXattrs(filepath.Join(dest, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "value", attrs["user.ace.test"])
+}
+
+func Test_RestoreXattrsUserNamespace(t *testing.T) {
+	ctx := context.Background()
+	probe := filepath.Join(t.TempDir(), "probe")
+	require.NoError(t, os.WriteFile(probe, nil, 0o644))
+	if err := unix.Lsetxattr(probe, "user.ace.test", []byte("value"), 0); err != nil {
+		t.Skipf("extended attributes not supported: %v", err)
+	}
+
+	// attributes granting privileges are not restored, even by root
+	data := writeFidelityTar(t, FidelityVersion, &tar.Header{
+		Name:     "tool",
+		Typeflag: tar.TypeReg,
+		Mode:     0o755,
+		Size:     4,
+		PAXRecords: map[string]string{
+			xattrRecordPrefix + "user.ace.test":       "value",
+			xattrRecordPrefix + "security.capability": "\x01\x00\x00\x02\x00\x04\x00\x00",
+			xattrRecordPrefix + "trusted.ace.test":    "value",
+		},
+		Format: tar.FormatPAX,
+	})
+	dest := t.TempDir()
+	require.NoError(t, ExtractTar(ctx, io.NopCloser(bytes.NewReader(data)), dest, WithRestoreXattrs()))
+
+	fpath := filepath.Join(dest, "tool")
+	value, err := getXattr(fpath, "user.ace.test")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+	for _, name := range []string{"security.capability", "trusted.ace.test"} {
+		_, err := getXattr(fpath, name)
+		assert.ErrorIs(t, err, unix.ENODATA, "expected %s not to be restored", name)
+	}
+
+	assert.True(t, isXattrUnsupported(fmt.Errorf("setting: %w", unix.ENOTSUP)))
+	assert.True(t, isXattrUnsupported(fmt.Errorf("setting: %w", unix.EPERM)))
+	assert.False(t, isXattrUnsupported(fmt.Errorf("setting: %w", unix.ENOSPC)))
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+)
+
+// readXattrs fails since extended attributes are not supported on this platform.
+func readXattrs(path string) (map[string]string, error) {
+	return nil, fmt.Errorf("extended attributes: %w", errors.ErrUnsupported)
+}
+
+// setXattr fails since extended attributes are not supported on this platform.
+func setXattr(path, name, value string) error {
+	return fmt.Errorf("extended attributes: %w", errors.ErrUnsupported)
+}
+
+// isXattrUnsupported returns true if the error setting an extended attribute is because extended attributes are not
+// supported.
+func isXattrUnsupported(err error) bool {
+	return errors.Is(err, errors.ErrUnsupported)
+}
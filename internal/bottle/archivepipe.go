@@ -169,7 +169,9 @@ func makeArchivePath(scratchPath string) (string, error) {
 // if necessary.  Items that have an archive/compression format specified
 // are archived if the matching archive file does not already exist, and only
 // files that do not have digests that appear in the cache are archived.
-func archiveParts(ctx context.Context, btl *Bottle, compressionLevel string, tmpFileMap *sync.Map) error {
+// Directory parts are archived with the fidelity level, or the level they were
+// previously archived with if fidelity is empty.
+func archiveParts(ctx context.Context, btl *Bottle, compressionLevel string, fidelity string, tmpFileMap *sync.Map) error {
 	log := logger.FromContext(ctx)
 	log.InfoContext(ctx, "Archiving files/directories in bottle")
 
@@ -194,7 +196,7 @@ func archiveParts(ctx context.Context, btl *Bottle, compressionLevel string, tmp
 
 		// Start a goroutine for each part. Compressing and archiving if necessary
 		errGroup.Go(func() error {
-			return archivePart(ctx, &btlPartMutex, progress, &btl.Parts[i], btl, compressionLevel, tmpFileMap)
+			return archivePart(ctx, &btlPartMutex, progress, &btl.Parts[i], btl, compressionLevel, fidelity, tmpFileMap)
 		})
 	}
 	progress.Update(0, total)
@@ -203,14 +205,19 @@ func archiveParts(ctx context.Context, btl *Bottle, compressionLevel string, tmp
 	return errGroup.Wait()
 }
 
-func archivePart(ctx context.Context, btlPartMutex sync.Locker, progress *ui.Progress, part PartInfo, btl *Bottle, compressionLevel string, tmpFileMap *sync.Map) error {
+func archivePart(ctx context.Context, btlPartMutex sync.Locker, progress *ui.Progress, part *PartTrack, btl *Bottle, compressionLevel string, fidelity string, tmpFileMap *sync.Map) error {
 	log := logger.FromContext(ctx)
 	log.InfoContext(ctx, "Active file", "filename", part.GetName())
 
 	defer progress.Infof("%v completed", part.GetName())
 
-	// Skip if the part has a digest (has been archived and digested before), AND the digest matches the cache
-	if part.GetLayerDigest() != "" {
+	mt := part.GetMediaType()
+	fidelity = partFidelity(fidelity, part.Fidelity)
+	refidelity := mediatype.IsArchived(mt) && fidelity != part.Fidelity
+
+	// Skip if the part has a digest (has been archived and digested before), AND the digest matches the cache,
+	// unless it needs to be archived again with a different fidelity level
+	if part.GetLayerDigest() != "" && !refidelity {
 		exists, err := btl.cache.Exists(ctx, ocispec.Descriptor{Digest: part.GetLayerDigest()})
 		if err != nil {
 			logger.V(log, 1).ErrorContext(ctx, "checking for part in cache", "error", err)
@@ -222,7 +229,6 @@ func archivePart(ctx context.Context, btlPartMutex sync.Locker, progress *ui.Pro
 	}
 
 	// Skip if the part is already archived / compressed or marked oci RAW
-	mt := part.GetMediaType()
 	if (!mediatype.IsArchived(mt) && !mediatype.IsCompressed(mt)) || mediatype.IsRaw(mt) {
 		logger.V(log, 1).InfoContext(ctx, "Skipping archive because of format", "mediaType", mt)
 		return nil
@@ -250,7 +256,7 @@ func archivePart(ctx context.Context, btlPartMutex sync.Locker, progress *ui.Pro
 	// Archive to the pipeline or copy to the pipeline if not an archive format
 	if mediatype.IsArchived(mt) {
 		log.InfoContext(ctx, "Desired format is archived", "mediaType", mt)
-		err = archive.TarToStream(ctx, os.DirFS(srcFile), output)
+		err = archive.TarToStream(ctx, os.DirFS(srcFile), output, fidelityArchiveOptions(fidelity, srcFile, btl.GetPath())...)
 		// Set uncompressed size to archive size
 		archpipe.ContentSize = archpipe.contentCount.Count
 	} else {
@@ -291,12 +297,28 @@ func archivePart(ctx context.Context, btlPartMutex sync.Locker, progress *ui.Pro
 		mt,
 		nil,
 	)
+	if mediatype.IsArchived(mt) {
+		part.Fidelity = fidelity
+	}
 	btlPartMutex.Unlock()
 
 	log.InfoContext(ctx, "Archive file created", "path", archFile)
 	return nil
 }
 
+// partFidelity returns the fidelity level to archive a directory part with, given the requested level and the level
+// recorded for the part.  The default level is recorded as empty.
+func partFidelity(requested, recorded string) string {
+	switch requested {
+	case "":
+		return recorded
+	case FidelityNone:
+		return ""
+	default:
+		return requested
+	}
+}
+
 // digestParts calculates digest values for any files in the bottle, and records them in the
 // bottle structure.  The process is skipped if a digest is already present in the bottle. For
 // most cases, the digest is calculated as part of the archival process using a stream pipeline.
@@ -0,0 +1,114 @@
+package bottle
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SaveUpdatesToSetFidelity(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require privileges on windows")
+	}
+	ctx := context.Background()
+	cachePath := t.TempDir()
+
+	btlDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(btlDir, "data"), 0o777))
+	require.NoError(t, os.WriteFile(filepath.Join(btlDir, "data", "a.txt"), []byte("file a"), 0o640))
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(btlDir, "data", "link.txt")))
+	require.NoError(t, CreateBottle(btlDir, false))
+
+	btl, err := NewBottle(WithLocalPath(btlDir), WithCachePath(cachePath), WithBlobInfoCache(""))
+	require.NoError(t, err)
+	_, _, err = InspectBottleFiles(ctx, btl, Options{Visitor: PrepareUpdatedParts(ctx, btl)})
+	require.NoError(t, err)
+	require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{Fidelity: FidelityLinks}))
+
+	part := btl.partByName("data/")
+	require.NotNil(t, part)
+	assert.Equal(t, FidelityLinks, part.Fidelity)
+
+	layers := btl.Manifest.GetLayerDescriptors()
+	require.Len(t, layers, 1)
+	assert.Equal(t, FidelityLinks, layers[0].Annotations[AnnotationPartFidelity])
+
+	// the status uses the recorded fidelity level, so the part is unchanged
+	_, changed, err := InspectBottleFiles(ctx, btl, Options{})
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	t.Run("extract", func(t *testing.T) {
+		cfgData, err := btl.GetConfiguration()
+		require.NoError(t, err)
+
+		pulled, err := NewBottle(WithLocalPath(t.TempDir()), WithCachePath(cachePath), WithBlobInfoCache(""))
+		require.NoError(t, err)
+		pulled.SetManifest(btl.Manifest)
+		require.NoError(t, pulled.Configure(cfgData))
+		assert.Equal(t, FidelityLinks, pulled.partByName("data/").Fidelity)
+
+		var mu sync.Mutex
+		handled, err := CopyFromCache(ctx, pulled, layers[0], "data/", &mu)
+		require.NoError(t, err)
+		assert.True(t, handled)
+
+		target, err := os.Readlink(pulled.NativePath("data/link.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "a.txt", target)
+
+		info, err := os.Stat(pulled.NativePath("data/a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o640), info.Mode().Perm())
+	})
+
+	t.Run("none", func(t *testing.T) {
+		require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{Fidelity: FidelityNone}))
+		assert.Empty(t, btl.partByName("data/").Fidelity)
+		assert.Empty(t, btl.Manifest.GetLayerDescriptors()[0].Annotations)
+	})
+}
+
+func Test_SaveUpdatesToSetChangedPart(t *testing.T) {
+	ctx := context.Background()
+	cachePath := t.TempDir()
+
+	btlDir := t.TempDir()
+	fpath := filepath.Join(btlDir, "a.txt")
+	require.NoError(t, os.WriteFile(fpath, []byte("file a"), 0o644))
+	require.NoError(t, CreateBottle(btlDir, false))
+
+	btl, err := NewBottle(WithLocalPath(btlDir), WithCachePath(cachePath), WithBlobInfoCache(""))
+	require.NoError(t, err)
+	_, _, err = InspectBottleFiles(ctx, btl, Options{Visitor: PrepareUpdatedParts(ctx, btl)})
+	require.NoError(t, err)
+	require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{}))
+	before := *btl.partByName("a.txt")
+	require.NotEmpty(t, before.Digest)
+
+	// change the content in place without changing its size
+	require.NoError(t, os.WriteFile(fpath, []byte("file b"), 0o644))
+	later := before.Modified.Add(time.Minute)
+	require.NoError(t, os.Chtimes(fpath, later, later))
+
+	_, changed, err := InspectBottleFiles(ctx, btl, Options{Visitor: PrepareUpdatedParts(ctx, btl)})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{}))
+
+	after := btl.partByName("a.txt")
+	require.NotNil(t, after)
+	assert.Equal(t, before.Size, after.Size)
+	assert.NotEqual(t, before.Digest, after.Digest)
+	assert.NotEqual(t, before.LayerDigest, after.LayerDigest)
+	assert.Equal(t, digest.FromString("file b"), after.Digest)
+}
@@ -9,6 +9,7 @@ package bottle
 
 import (
 	"context"
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -29,8 +30,10 @@ import (
 	"github.com/act3-ai/bottle-schema/pkg/mediatype"
 	sutil "github.com/act3-ai/bottle-schema/pkg/util"
 
+	"github.com/act3-ai/data-tool/internal/archive"
 	"github.com/act3-ai/data-tool/internal/bottle/label"
 	"github.com/act3-ai/data-tool/internal/cache"
+	"github.com/act3-ai/data-tool/internal/encrypt"
 	"github.com/act3-ai/data-tool/internal/oci"
 	"github.com/act3-ai/data-tool/internal/util"
 )
@@ -69,6 +72,12 @@ type Bottle struct {
 	DisableCreateDestDir bool
 	disableCache         bool
 
+	// extractOptions are used when extracting directory parts from the cache
+	extractOptions []archive.ExtractOption
+
+	// decryptionKeys are used when extracting encrypted parts from the cache
+	decryptionKeys []crypto.PrivateKey
+
 	bic cache.BIC
 }
 
@@ -244,6 +253,20 @@ func (btl *Bottle) UpdatePartMetadata(name string,
 	}
 }
 
+// resetPartDigests clears the content and layer digests of the part identified by name, so they are calculated again
+// by the next commit.
+func (btl *Bottle) resetPartDigests(name string) {
+	part := btl.partByName(name)
+	if part == nil {
+		return
+	}
+	part.Digest = ""
+	part.LayerSize = 0
+	part.LayerDigest = ""
+	part.Stats = nil
+	btl.invalidateConfiguration()
+}
+
 // ConfigDescriptor returns an oci descriptor for a bottle config, as it corresponds to the config in a manifest.
 func (btl *Bottle) ConfigDescriptor() (ocispec.Descriptor, error) {
 	configData, err := btl.GetConfiguration()
@@ -267,17 +290,22 @@ func (btl *Bottle) PartDescriptors() ([]ocispec.Descriptor, error) {
 	}
 
 	fileDescs := make([]ocispec.Descriptor, 0, len(finfos))
-	for _, f := range finfos {
-		fdesc := ocispec.Descriptor{
-			MediaType: f.GetMediaType(),
-			Digest:    f.GetLayerDigest(),
-			Size:      f.GetLayerSize(),
-		}
-		fileDescs = append(fileDescs, fdesc)
+	for i := range finfos {
+		fileDescs = append(fileDescs, btl.Parts[i].layerDescriptor())
 	}
 	return fileDescs, nil
 }
 
+// PartLayerDescriptor returns the descriptor of the layer of a part, as it appears in the manifest.  Encrypted parts
+// have the descriptor of the encrypted layer.
+func (btl *Bottle) PartLayerDescriptor(name string) (ocispec.Descriptor, error) {
+	part := btl.partByName(name)
+	if part == nil {
+		return ocispec.Descriptor{}, fmt.Errorf("part %q not found", name)
+	}
+	return part.layerDescriptor(), nil
+}
+
 // ConstructManifest creates a ManifestHandler that corresponds to the data within a bottle, including configuration,
 // part descriptors (layers), and annotations.  This is a representation from an oci level, so part descriptors are
 // at the oci layer level (archived/compressed) and not at the bottle level.  The manifest handler that is stored
@@ -336,40 +364,63 @@ func (btl *Bottle) writeEntryYAML() error {
 // cfgData should be the full bottle configuration as JSON.
 // if it is not then you ust call btl.invalidateConfiguration() after calling Configure().
 func (btl *Bottle) Configure(cfgData []byte) error {
+	var ociMan *ocispec.Manifest
+	if btl.Manifest != nil {
+		manifest := btl.Manifest.GetManifestData()
+		ociMan = &manifest
+	}
+	def, upgraded, err := decodeConfig(cfgData, ociMan)
+	if err != nil {
+		return err
+	}
+	btl.Definition = *def
+
+	if upgraded {
+		// we will not have any part data in the btl.Definition if this is a local bottle (e.g., no manifest)
+		btl.invalidateConfiguration()
+	} else {
+		btl.cfgData = cfgData
+	}
+
+	btl.applyDefinitionPartData()
+
+	return nil
+}
+
+// DefinitionFromConfig decodes a bottle config of any supported version, returning the definition converted to the
+// latest version.  Part data only known by the manifest of older versions is not available.
+func DefinitionFromConfig(cfgData []byte) (*cfgdef.Bottle, error) {
+	def, _, err := decodeConfig(cfgData, nil)
+	return def, err
+}
+
+// decodeConfig decodes a bottle config of any supported version, upgrading it to the latest version with the part data
+// of the manifest, if provided.  It returns true if the config was upgraded.
+func decodeConfig(cfgData []byte, ociMan *ocispec.Manifest) (*cfgdef.Bottle, bool, error) {
 	scheme := runtime.NewScheme()
 	err := bottle.AddToScheme(scheme)
 	if err != nil {
-		return fmt.Errorf("error adding type data to conversion scheme: %w", err)
+		return nil, false, fmt.Errorf("error adding type data to conversion scheme: %w", err)
 	}
 
 	codecs := serializer.NewCodecFactory(scheme, serializer.EnableStrict)
 
 	bottleOriginal, err := runtime.Decode(codecs.UniversalDeserializer(), cfgData)
 	if err != nil {
-		return fmt.Errorf("error decoding config data: %w", err)
+		return nil, false, fmt.Errorf("error decoding config data: %w", err)
 	}
 
+	def := cfgdef.NewBottle()
 	if bottleOriginal.GetObjectKind().GroupVersionKind().GroupVersion() == cfgdef.GroupVersion {
-		bottleOriginal.(*cfgdef.Bottle).DeepCopyInto(&btl.Definition)
-		btl.cfgData = cfgData
-	} else {
-		// Upgrade bottle config version
-		var ociMan *ocispec.Manifest
-		if btl.Manifest != nil {
-			manifest := btl.Manifest.GetManifestData()
-			ociMan = &manifest
-		}
-		err = scheme.Convert(bottleOriginal, &btl.Definition, ociMan)
-		if err != nil {
-			return fmt.Errorf("error converting config data: %w", err)
-		}
-		// we will not have any part data in the btl.Definition if this is a local bottle (e.g., no manifest)
-		btl.invalidateConfiguration()
+		bottleOriginal.(*cfgdef.Bottle).DeepCopyInto(&def)
+		return &def, false, nil
 	}
 
-	btl.applyDefinitionPartData()
-
-	return nil
+	// Upgrade bottle config version
+	if err := scheme.Convert(bottleOriginal, &def, ociMan); err != nil {
+		return nil, false, fmt.Errorf("error converting config data: %w", err)
+	}
+	return &def, true, nil
 }
 
 // SetManifest sets a bottle's manifest to the provided ManifestHandler.
@@ -386,6 +437,18 @@ func (btl *Bottle) SetManifest(manifest oci.ManifestHandler) {
 		btl.Parts[i].LayerDigest = desc.Digest
 		btl.Parts[i].LayerSize = desc.Size
 		btl.Parts[i].MediaType = desc.MediaType
+		btl.Parts[i].Fidelity = desc.Annotations[AnnotationPartFidelity]
+		btl.Parts[i].Stats = statsAnnotation(desc.Annotations)
+		btl.Parts[i].Encryption = nil
+		if encrypt.IsEncrypted(desc.MediaType) {
+			// the unencrypted layer digest is only known once the part is decrypted
+			btl.Parts[i].MediaType = encrypt.DecryptedMediaType(desc.MediaType)
+			btl.Parts[i].Encryption = &PartEncryption{
+				LayerDigest: desc.Digest,
+				LayerSize:   desc.Size,
+				Annotations: encryptionAnnotations(desc.Annotations),
+			}
+		}
 		// mod time is updated later as it must align with the
 		// mod time of the file itself, otherwise all future evaluations
 		// would be false positives.
@@ -483,7 +546,8 @@ func (btl *Bottle) GetParts() []PartInfo {
 // GetPartByLayerDescriptor returns a PartInfo for a part based on a descriptor from a manifest, matching by digest.
 func (btl *Bottle) GetPartByLayerDescriptor(descriptor ocispec.Descriptor) PartInfo {
 	for _, p := range btl.Parts {
-		if p.LayerDigest == descriptor.Digest {
+		if p.LayerDigest == descriptor.Digest ||
+			(p.Encryption != nil && p.Encryption.LayerDigest == descriptor.Digest) {
 			return &p
 		}
 	}
@@ -1055,6 +1119,15 @@ func WithVirtualParts(btl *Bottle) error {
 	return nil
 }
 
+// WithExtractOptions defines additional options for extracting directory parts, such as restoring extended attributes
+// recorded by fidelity mode archives.
+func WithExtractOptions(options ...archive.ExtractOption) BOption {
+	return func(btl *Bottle) error {
+		btl.extractOptions = append(btl.extractOptions, options...)
+		return nil
+	}
+}
+
 // DisableDestinationCreate sets or clears a flag that optionally
 // disables the creation of an output path before transfer. This
 // only pertains to Bottle downloads.
@@ -0,0 +1,285 @@
+// Package bottlefs presents the parts of a remote bottle as a read-only file system.  Part layers are fetched
+// into the bottle cache and extracted on first access, so only the parts that are actually read are transferred.
+// The file system implements webdav.FileSystem and can be served over plain HTTP or WebDAV with NewHandler.
+package bottlefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/webdav"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// writeFlags are the os.OpenFile flags that would modify the file system.
+const writeFlags = os.O_WRONLY | os.O_RDWR | os.O_APPEND | os.O_CREATE | os.O_TRUNC
+
+// FileSystem is a read-only webdav.FileSystem backed by a remote bottle.  The bottle must be configured with its
+// manifest and config, and its local path is used as the working directory for extracted parts.
+type FileSystem struct {
+	btl     *bottle.Bottle
+	src     content.Fetcher
+	modTime time.Time
+
+	// parts is keyed by the part name without the trailing slash used for directory parts
+	parts map[string]*partState
+
+	// dirs holds the children of directories that are implied by part names, but are not parts themselves
+	dirs map[string][]string
+
+	// btlPartMutex protects btl.Parts, which is updated when a part is extracted
+	btlPartMutex sync.Mutex
+}
+
+// partState tracks the on demand extraction of a single part.
+type partState struct {
+	part  bottle.PartInfo
+	isDir bool
+
+	mu    sync.Mutex
+	ready bool
+}
+
+// New creates a FileSystem for the bottle, fetching part layers from src as they are needed.
+func New(btl *bottle.Bottle, src content.Fetcher) *FileSystem {
+	fsys := &FileSystem{
+		btl:     btl,
+		src:     src,
+		modTime: time.Now(),
+		parts:   make(map[string]*partState, btl.NumParts()),
+		dirs:    map[string][]string{"": {}},
+	}
+
+	for _, part := range btl.GetParts() {
+		name := strings.TrimSuffix(part.GetName(), "/")
+		fsys.parts[name] = &partState{
+			part:  part,
+			isDir: mediatype.IsArchived(part.GetMediaType()),
+		}
+
+		// record the implied parent directories, stopping once we reach one that is already known
+		for child := name; child != ""; child = parentName(child) {
+			parent := parentName(child)
+			siblings, known := fsys.dirs[parent]
+			if !slices.Contains(siblings, path.Base(child)) {
+				fsys.dirs[parent] = append(siblings, path.Base(child))
+			}
+			if known {
+				break
+			}
+		}
+	}
+
+	for _, children := range fsys.dirs {
+		slices.Sort(children)
+	}
+
+	return fsys
+}
+
+// Mkdir implements webdav.FileSystem.  The file system is read-only so this always fails.
+func (fsys *FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrPermission}
+}
+
+// RemoveAll implements webdav.FileSystem.  The file system is read-only so this always fails.
+func (fsys *FileSystem) RemoveAll(ctx context.Context, name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrPermission}
+}
+
+// Rename implements webdav.FileSystem.  The file system is read-only so this always fails.
+func (fsys *FileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	return &fs.PathError{Op: "rename", Path: oldName, Err: fs.ErrPermission}
+}
+
+// Stat implements webdav.FileSystem.  Parts and the directories containing them are described from the bottle
+// metadata; only files within directory parts require the part to be fetched.
+func (fsys *FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	name = cleanName(name)
+
+	if info, ok := fsys.statEntry(name); ok {
+		return info, nil
+	}
+
+	ps, err := fsys.materializeContaining(ctx, name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	if ps == nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	info, err := os.Stat(fsys.localPath(name))
+	if err != nil {
+		return nil, fmt.Errorf("getting part file info: %w", err)
+	}
+	return info, nil
+}
+
+// OpenFile implements webdav.FileSystem.  Reading a part, or opening a file within a directory part, fetches and
+// extracts the part if it has not been already.  Only read access is permitted.
+func (fsys *FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	name = cleanName(name)
+
+	if flag&writeFlags != 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+
+	if children, ok := fsys.dirs[name]; ok {
+		infos := make([]fs.FileInfo, 0, len(children))
+		for _, child := range children {
+			info, _ := fsys.statEntry(path.Join(name, child))
+			infos = append(infos, info)
+		}
+		return &dirFile{info: fsys.dirInfo(name), children: infos}, nil
+	}
+
+	// defer fetching a part until its content is read, since WebDAV clients open every entry in a listing
+	if ps, ok := fsys.parts[name]; ok {
+		info, _ := fsys.statEntry(name)
+		return &lazyFile{ctx: ctx, fsys: fsys, ps: ps, name: name, info: info}, nil
+	}
+
+	ps, err := fsys.materializeContaining(ctx, name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if ps == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	f, err := os.Open(fsys.localPath(name))
+	if err != nil {
+		return nil, fmt.Errorf("opening part file: %w", err)
+	}
+	return &readOnlyFile{File: f}, nil
+}
+
+// statEntry describes the named entry if it can be done from the bottle metadata alone.
+func (fsys *FileSystem) statEntry(name string) (fs.FileInfo, bool) {
+	if _, ok := fsys.dirs[name]; ok {
+		return fsys.dirInfo(name), true
+	}
+
+	ps, ok := fsys.parts[name]
+	switch {
+	case !ok:
+		return nil, false
+	case ps.isDir:
+		return fsys.dirInfo(name), true
+	default:
+		return &entryInfo{
+			name:    path.Base(name),
+			size:    ps.part.GetContentSize(),
+			mode:    0o444,
+			modTime: fsys.modTime,
+		}, true
+	}
+}
+
+// dirInfo returns the info for a directory that is described only by bottle metadata.
+func (fsys *FileSystem) dirInfo(name string) fs.FileInfo {
+	base := path.Base(name)
+	if name == "" {
+		base = "/"
+	}
+	return &entryInfo{
+		name:    base,
+		mode:    fs.ModeDir | 0o555,
+		modTime: fsys.modTime,
+	}
+}
+
+// materializeContaining extracts the part that contains name, returning nil if no part contains it.
+func (fsys *FileSystem) materializeContaining(ctx context.Context, name string) (*partState, error) {
+	for partName, ps := range fsys.parts {
+		if name == partName || (ps.isDir && strings.HasPrefix(name, partName+"/")) {
+			return ps, fsys.materialize(ctx, ps)
+		}
+	}
+	return nil, nil
+}
+
+// materialize ensures the part's layer is in the cache and extracted into the working directory.
+// A failed attempt is not remembered, so the next access retries the transfer.
+func (fsys *FileSystem) materialize(ctx context.Context, ps *partState) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.ready {
+		return nil
+	}
+
+	log := logger.FromContext(ctx).With("part", ps.part.GetName())
+
+	// encrypted parts are fetched as the encrypted layer, and decrypted when copied from the cache
+	fsys.btlPartMutex.Lock()
+	desc, err := fsys.btl.PartLayerDescriptor(ps.part.GetName())
+	fsys.btlPartMutex.Unlock()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	storage := fsys.btl.GetCache()
+	exists, err := storage.Exists(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("checking part existence in cache: %w", err)
+	}
+	if !exists {
+		log.InfoContext(ctx, "fetching part", "layerDigest", desc.Digest, "size", desc.Size)
+		rc, err := fsys.src.Fetch(ctx, desc)
+		if err != nil {
+			return fmt.Errorf("fetching part from remote: %w", err)
+		}
+		defer rc.Close()
+
+		if err := storage.Push(ctx, desc, rc); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+			return fmt.Errorf("caching part: %w", err)
+		}
+	}
+
+	log.InfoContext(ctx, "extracting part")
+	handled, err := bottle.CopyFromCache(ctx, fsys.btl, desc, ps.part.GetName(), &fsys.btlPartMutex)
+	if err != nil {
+		return err
+	}
+	if !handled {
+		return fmt.Errorf("part not found in cache after copy %s", desc.Digest)
+	}
+
+	ps.ready = true
+	return nil
+}
+
+// localPath returns the path of the extracted entry in the working directory.
+func (fsys *FileSystem) localPath(name string) string {
+	return filepath.Join(fsys.btl.GetPath(), filepath.FromSlash(name))
+}
+
+// parentName returns the bottle relative path of the directory containing name.
+func parentName(name string) string {
+	parent := path.Dir(name)
+	if parent == "." {
+		return ""
+	}
+	return parent
+}
+
+// cleanName converts a slash separated name from a request into a bottle relative path, with "" as the root.
+func cleanName(name string) string {
+	return strings.Trim(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,204 @@
+package bottlefs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+	"github.com/act3-ai/data-tool/internal/archive"
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/oci"
+)
+
+// countingFetcher counts the number of fetches made from the remote.
+type countingFetcher struct {
+	content.Fetcher
+	fetches atomic.Int32
+}
+
+func (f *countingFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	f.fetches.Add(1)
+	return f.Fetcher.Fetch(ctx, desc) //nolint:wrapcheck
+}
+
+// setupFileSystem creates a bottle with a file part, a nested file part and a directory part in memory.
+func setupFileSystem(t *testing.T) (*FileSystem, *countingFetcher) {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.New()
+
+	var tarData bytes.Buffer
+	err := archive.TarToStream(ctx, fstest.MapFS{
+		"a.txt":     {Data: []byte("file a")},
+		"sub/b.txt": {Data: []byte("file b")},
+	}, &tarData)
+	require.NoError(t, err)
+
+	partData := []struct {
+		name      string
+		mediaType string
+		data      []byte
+	}{
+		{"hello.txt", mediatype.MediaTypeLayer, []byte("hello world")},
+		{"docs/readme.md", mediatype.MediaTypeLayer, []byte("# readme")},
+		{"data/", mediatype.MediaTypeLayerTar, tarData.Bytes()},
+	}
+
+	definition := cfgdef.NewBottle()
+	layers := make([]ocispec.Descriptor, 0, len(partData))
+	for _, p := range partData {
+		desc := ocispec.Descriptor{
+			MediaType: p.mediaType,
+			Digest:    digest.FromBytes(p.data),
+			Size:      int64(len(p.data)),
+		}
+		require.NoError(t, store.Push(ctx, desc, bytes.NewReader(p.data)))
+		layers = append(layers, desc)
+		definition.Parts = append(definition.Parts, cfgdef.Part{
+			Name:   p.name,
+			Size:   int64(len(p.data)),
+			Digest: desc.Digest,
+		})
+	}
+
+	cfgData, err := json.Marshal(definition)
+	require.NoError(t, err)
+	cfgDesc := ocispec.Descriptor{
+		MediaType: mediatype.MediaTypeBottleConfig,
+		Digest:    digest.FromBytes(cfgData),
+		Size:      int64(len(cfgData)),
+	}
+	manData, err := oci.MakeManifest(cfgDesc, layers, mediatype.MediaTypeBottle, nil)
+	require.NoError(t, err)
+
+	btl, err := bottle.NewBottle(
+		bottle.WithLocalPath(t.TempDir()),
+		bottle.WithCachePath(t.TempDir()),
+		bottle.WithBlobInfoCache(""),
+	)
+	require.NoError(t, err)
+	btl.SetManifest(oci.ManifestFromData(ocispec.MediaTypeImageManifest, manData))
+	require.NoError(t, btl.Configure(cfgData))
+
+	src := &countingFetcher{Fetcher: store}
+	return New(btl, src), src
+}
+
+func TestFileSystem(t *testing.T) {
+	ctx := context.Background()
+	fsys, src := setupFileSystem(t)
+
+	t.Run("metadata only", func(t *testing.T) {
+		info, err := fsys.Stat(ctx, "/hello.txt")
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), info.Size())
+		assert.False(t, info.IsDir())
+
+		info, err = fsys.Stat(ctx, "/data")
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+
+		root, err := fsys.OpenFile(ctx, "/", 0, 0)
+		require.NoError(t, err)
+		children, err := root.Readdir(-1)
+		require.NoError(t, err)
+		names := make([]string, 0, len(children))
+		for _, c := range children {
+			names = append(names, c.Name())
+		}
+		assert.Equal(t, []string{"data", "docs", "hello.txt"}, names)
+
+		assert.Equal(t, int32(0), src.fetches.Load(), "no parts should be fetched")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := fsys.Stat(ctx, "/missing.txt")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		_, err := fsys.OpenFile(ctx, "/hello.txt", os.O_WRONLY, 0)
+		assert.Error(t, err)
+		assert.Error(t, fsys.Mkdir(ctx, "/new", 0o777))
+		assert.Error(t, fsys.RemoveAll(ctx, "/hello.txt"))
+	})
+
+	t.Run("directory part", func(t *testing.T) {
+		f, err := fsys.OpenFile(ctx, "/data/sub/b.txt", 0, 0)
+		require.NoError(t, err)
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, "file b", string(data))
+
+		// a second access reuses the extracted part
+		_, err = fsys.Stat(ctx, "/data/a.txt")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), src.fetches.Load())
+	})
+}
+
+func TestHandler(t *testing.T) {
+	fsys, src := setupFileSystem(t)
+	srv := httptest.NewServer(NewHandler(fsys))
+	defer srv.Close()
+
+	do := func(method, target string, header http.Header) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+target, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := do("PROPFIND", "/", http.Header{"Depth": {"1"}})
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	assert.Contains(t, body, "/docs/")
+	assert.Contains(t, body, "<D:getcontentlength>11</D:getcontentlength>")
+	assert.Equal(t, int32(0), src.fetches.Load(), "listing should not fetch parts")
+
+	resp, body = do(http.MethodGet, "/", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "hello.txt")
+	assert.Contains(t, body, "docs/")
+
+	resp, body = do(http.MethodGet, "/hello.txt", http.Header{"Range": {"bytes=6-10"}})
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "world", body)
+
+	resp, body = do(http.MethodGet, "/docs/readme.md", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "# readme", body)
+
+	resp, _ = do(http.MethodPut, "/new.txt", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, _ = do(http.MethodGet, "/missing.txt", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	assert.Equal(t, int32(2), src.fetches.Load(), "only the requested parts should be fetched")
+}
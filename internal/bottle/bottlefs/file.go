@@ -0,0 +1,170 @@
+package bottlefs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// entryInfo is an fs.FileInfo for entries described by bottle metadata.
+type entryInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (fi *entryInfo) Name() string       { return fi.name }
+func (fi *entryInfo) Size() int64        { return fi.size }
+func (fi *entryInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi *entryInfo) ModTime() time.Time { return fi.modTime }
+func (fi *entryInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *entryInfo) Sys() any           { return nil }
+
+// ContentType implements webdav.ContentTyper so that WebDAV listings do not read (and fetch) the part to sniff the
+// content type.
+func (fi *entryInfo) ContentType(ctx context.Context) (string, error) {
+	if ct := mime.TypeByExtension(path.Ext(fi.name)); ct != "" {
+		return ct, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// dirFile is a webdav.File for a directory that is implied by part names.
+type dirFile struct {
+	info     fs.FileInfo
+	children []fs.FileInfo
+	offset   int
+}
+
+// Close implements webdav.File.
+func (d *dirFile) Close() error {
+	return nil
+}
+
+// Read implements webdav.File.
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: fs.ErrInvalid}
+}
+
+// Seek implements webdav.File.  Only rewinding the directory listing is supported.
+func (d *dirFile) Seek(offset int64, whence int) (int64, error) {
+	if offset == 0 && whence == io.SeekStart {
+		d.offset = 0
+		return 0, nil
+	}
+	return 0, &fs.PathError{Op: "seek", Path: d.info.Name(), Err: fs.ErrInvalid}
+}
+
+// Readdir implements webdav.File with the semantics of os.File.Readdir.
+func (d *dirFile) Readdir(count int) ([]fs.FileInfo, error) {
+	remaining := d.children[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.children)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n := min(count, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+// Stat implements webdav.File.
+func (d *dirFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Write implements webdav.File.  The file system is read-only so this always fails.
+func (d *dirFile) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: d.info.Name(), Err: fs.ErrPermission}
+}
+
+// readOnlyFile is a webdav.File for an extracted part file or directory.
+type readOnlyFile struct {
+	*os.File
+}
+
+// Write implements webdav.File.  The file system is read-only so this always fails.
+func (f *readOnlyFile) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: f.Name(), Err: fs.ErrPermission}
+}
+
+// lazyFile is a webdav.File for a part that is fetched and extracted on first read.
+type lazyFile struct {
+	ctx  context.Context
+	fsys *FileSystem
+	ps   *partState
+	name string
+	info fs.FileInfo
+
+	f webdav.File
+}
+
+// open materializes the part and opens the extracted file or directory.
+func (l *lazyFile) open() (webdav.File, error) {
+	if l.f != nil {
+		return l.f, nil
+	}
+	if err := l.fsys.materialize(l.ctx, l.ps); err != nil {
+		return nil, &fs.PathError{Op: "open", Path: l.name, Err: err}
+	}
+	f, err := os.Open(l.fsys.localPath(l.name))
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	l.f = &readOnlyFile{File: f}
+	return l.f, nil
+}
+
+// Close implements webdav.File.
+func (l *lazyFile) Close() error {
+	if l.f == nil {
+		return nil
+	}
+	return l.f.Close() //nolint:wrapcheck
+}
+
+// Read implements webdav.File.
+func (l *lazyFile) Read(p []byte) (int, error) {
+	f, err := l.open()
+	if err != nil {
+		return 0, err
+	}
+	return f.Read(p) //nolint:wrapcheck
+}
+
+// Seek implements webdav.File.
+func (l *lazyFile) Seek(offset int64, whence int) (int64, error) {
+	f, err := l.open()
+	if err != nil {
+		return 0, err
+	}
+	return f.Seek(offset, whence) //nolint:wrapcheck
+}
+
+// Readdir implements webdav.File.
+func (l *lazyFile) Readdir(count int) ([]fs.FileInfo, error) {
+	f, err := l.open()
+	if err != nil {
+		return nil, err
+	}
+	return f.Readdir(count) //nolint:wrapcheck
+}
+
+// Stat implements webdav.File.  The part is described from the bottle metadata without fetching it.
+func (l *lazyFile) Stat() (fs.FileInfo, error) {
+	return l.info, nil
+}
+
+// Write implements webdav.File.  The file system is read-only so this always fails.
+func (l *lazyFile) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: l.name, Err: fs.ErrPermission}
+}
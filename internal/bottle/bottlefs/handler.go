@@ -0,0 +1,60 @@
+package bottlefs
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// allowedMethods are the HTTP and WebDAV methods that do not modify content.
+var allowedMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	"PROPFIND",
+	"LOCK",
+	"UNLOCK",
+}
+
+// NewHandler returns an http.Handler that serves fsys.  GET and HEAD requests are handled by http.FileServer,
+// which provides directory listings and range requests.  Read-only WebDAV methods are handled by webdav.Handler.
+// All other methods are rejected.
+func NewHandler(fsys *FileSystem) http.Handler {
+	dav := &webdav.Handler{
+		FileSystem: fsys,
+		LockSystem: webdav.NewMemLS(),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				logger.FromContext(r.Context()).ErrorContext(r.Context(), "WebDAV request failed",
+					"method", r.Method, "path", r.URL.Path, "error", err)
+			}
+		},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			http.FileServer(&httpFileSystem{ctx: r.Context(), fsys: fsys}).ServeHTTP(w, r)
+		case http.MethodOptions, "PROPFIND", "LOCK", "UNLOCK":
+			dav.ServeHTTP(w, r)
+		default:
+			w.Header().Set("Allow", strings.Join(allowedMethods, ", "))
+			http.Error(w, "bottle is served read-only", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// httpFileSystem adapts a FileSystem to http.FileSystem for a single request.
+type httpFileSystem struct {
+	ctx  context.Context
+	fsys *FileSystem
+}
+
+// Open implements http.FileSystem.
+func (h *httpFileSystem) Open(name string) (http.File, error) {
+	return h.fsys.OpenFile(h.ctx, name, 0, 0)
+}
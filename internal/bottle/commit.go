@@ -0,0 +1,135 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/act3-ai/data-tool/internal/ui"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// CommitOptions are the options for committing a bottle.
+type CommitOptions struct {
+	// CompressLevel is the compression level for compressed parts
+	CompressLevel string
+
+	// Fidelity is the fidelity level for archiving directory parts, see SaveOptions
+	Fidelity string
+
+	// Encryption selects the parts to encrypt and their recipients, see SaveOptions
+	Encryption *EncryptOptions
+
+	// NoDeprecate disables deprecation of the previous version of the bottle
+	NoDeprecate bool
+
+	// Stats computes the statistics of the parts, see SaveOptions
+	Stats bool
+
+	// Message describes the commit in the local history of the bottle
+	Message string
+}
+
+// Commit processes the local changes to a bottle, applying the label rules file, archiving, digesting and caching its
+// parts, and saves the bottle metadata.  Unless disabled, the previously committed version of the bottle is deprecated
+// if the bottleID changed.  A changed bottle is recorded in the local history of the bottle, see RecordCommit.
+func Commit(ctx context.Context, btl *Bottle, options CommitOptions) error {
+	log := logger.FromContext(ctx)
+
+	rootUI := ui.FromContextOrNoop(ctx)
+
+	// for each public artifact in the bottle path ensure we have the digest calculated
+	if err := verifyArtifacts(btl, rootUI); err != nil {
+		return err
+	}
+
+	// Before we commit, check if there is a bottleID in the .dt directory,
+	// This would mean that the bottle has already been committed / pulled / pushed
+	// and ace-dt now infers this a deprecating a bottle.
+	// Get bottleID from bottleID file if it exists
+	bottlePath := btl.GetPath()
+	defaultBottleIDPath := filepath.Join(bottlePath, ".dt", "bottleid")
+	var oldDigest digest.Digest
+
+	// check the bottlePath for a bottleID file, if it exists, then ReadBottleIDFile
+	if _, err := os.Stat(defaultBottleIDPath); err == nil {
+		oldDigest, err = ReadBottleIDFile(bottlePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := ApplyLabelRules(ctx, btl); err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "Saving updated bottle")
+	if err := SaveUpdatesToSet(ctx, btl, SaveOptions{
+		CompressLevel: options.CompressLevel,
+		Fidelity:      options.Fidelity,
+		Encryption:    options.Encryption,
+		Stats:         options.Stats,
+	}); err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "Validating bottle data")
+	if err := btl.Definition.ValidateWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to validate bottle before push: %w", err)
+	}
+
+	// if noDeprecate flag isn't set, then we deprecate the previous bottleID
+	// If the bottleID changed after saving the updates to the set,
+	// then we deprecate the previous bottleID (from default bottleID file read earlier)
+	if !options.NoDeprecate && oldDigest.String() != "" && oldDigest != btl.GetBottleID() {
+		// save changes to bottle, adding the deprecated to the entry.yaml (bottle config)
+		if err := deprecate(log, rootUI, btl, oldDigest); err != nil {
+			return err
+		}
+		if err := btl.ConstructManifest(); err != nil { // reconstruct the updated information
+			return fmt.Errorf("resetting bottle configuration: %w", err)
+		}
+	}
+
+	if err := SaveExtraBottleInfo(ctx, btl); err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "bottle commit complete")
+
+	return RecordCommit(ctx, btl, options.Message)
+}
+
+// deprecate deprecates the previous bottleID, then saves the new bottle configuration.
+func deprecate(log *slog.Logger, u *ui.Task, btl *Bottle, dgst digest.Digest) error {
+	log.Info("BottleID changed, deprecating previous bottleID")
+	btl.DeprecateBottleID(dgst)
+
+	if err := btl.Save(); err != nil {
+		return err
+	}
+	u.Infof("Deprecating previous bottle %s", dgst)
+	return nil
+}
+
+// verifyArtifacts ensures that all public artifacts have a digest calculated.
+func verifyArtifacts(btl *Bottle, u *ui.Task) error {
+	for i, art := range btl.Definition.PublicArtifacts {
+		if art.Digest == "" {
+			if _, err := os.Stat(btl.NativePath(art.Path)); errors.Is(err, fs.ErrNotExist) {
+				u.Infof("PublicArtifact %q not found.\nPlease update bottle metadata to reference an existing file.", art.Path)
+				continue
+			}
+			if err := btl.CalculatePublicArtifactDigest(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package bottle
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+	"github.com/act3-ai/data-tool/internal/encrypt"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// PartEncryption records the encrypted layer of a part.  The part itself continues to describe the unencrypted layer,
+// so the bottle configuration, and the bottleID, do not depend on the encryption.
+type PartEncryption struct {
+	// Digest is the digest of the unencrypted layer, empty if it is not yet known (i.e., the part was not decrypted)
+	Digest digest.Digest `json:"digest,omitempty"`
+
+	// LayerDigest and LayerSize describe the encrypted layer
+	LayerDigest digest.Digest `json:"layerDigest"`
+	LayerSize   int64         `json:"layerSize"`
+
+	// Annotations are the encryption annotations of the encrypted layer, holding the wrapped keys
+	Annotations map[string]string `json:"annotations"`
+
+	// Recipients are the PEM encoded recipients the layer was encrypted for, empty if unknown (i.e., the part was pulled)
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+// EncryptOptions selects the parts to encrypt when committing, and who to encrypt them for.
+type EncryptOptions struct {
+	// Recipients are the recipients to encrypt for.  No recipients removes the encryption of all parts.
+	Recipients []encrypt.Recipient
+
+	// Selector selects the parts to encrypt, all parts are encrypted if nil
+	Selector PartSelectorFunc
+}
+
+// ErrNoRecipients is the error when an encrypted part must be encrypted again, but the recipients are unknown.
+var ErrNoRecipients = errors.New("encryption recipients are required")
+
+// IsLayer returns true if the media type is a bottle layer media type, which may be encrypted.
+func IsLayer(mediaType string) bool {
+	return mediatype.IsLayer(encrypt.DecryptedMediaType(mediaType))
+}
+
+// WithDecryptionKeys defines the private keys used for decrypting encrypted parts.
+func WithDecryptionKeys(keys ...crypto.PrivateKey) BOption {
+	return func(btl *Bottle) error {
+		btl.decryptionKeys = append(btl.decryptionKeys, keys...)
+		return nil
+	}
+}
+
+// layerDescriptor returns the descriptor of the layer of the part, as it appears in the manifest.
+func (p *PartTrack) layerDescriptor() ocispec.Descriptor {
+	desc := ocispec.Descriptor{
+		MediaType: p.GetMediaType(),
+		Digest:    p.GetLayerDigest(),
+		Size:      p.GetLayerSize(),
+	}
+	if p.Fidelity != "" {
+		desc.Annotations = map[string]string{AnnotationPartFidelity: p.Fidelity}
+	}
+	if p.Stats != nil {
+		if data, err := json.Marshal(p.Stats); err == nil {
+			if desc.Annotations == nil {
+				desc.Annotations = make(map[string]string, 1)
+			}
+			desc.Annotations[AnnotationPartStats] = string(data)
+		}
+	}
+	if enc := p.Encryption; enc != nil {
+		desc.MediaType = encrypt.EncryptedMediaType(desc.MediaType)
+		desc.Digest = enc.LayerDigest
+		desc.Size = enc.LayerSize
+		if desc.Annotations == nil {
+			desc.Annotations = make(map[string]string, len(enc.Annotations))
+		}
+		maps.Copy(desc.Annotations, enc.Annotations)
+	}
+	return desc
+}
+
+// encryptionAnnotations returns the encryption annotations of a layer.
+func encryptionAnnotations(annotations map[string]string) map[string]string {
+	enc := make(map[string]string)
+	for _, k := range []string{encrypt.AnnotationJWEKeys, encrypt.AnnotationPKCS7Keys, encrypt.AnnotationPublicOptions} {
+		if v, ok := annotations[k]; ok {
+			enc[k] = v
+		}
+	}
+	return enc
+}
+
+// encryptParts encrypts the parts selected by options, storing the encrypted layers in the cache.  If options is nil,
+// parts remain as they were, but encrypted parts whose content changed are encrypted again for the same recipients.
+// Parts previously encrypted for the same recipients are not encrypted again, so unchanged parts keep their encrypted
+// layer digest.
+func encryptParts(ctx context.Context, btl *Bottle, options *EncryptOptions) error {
+	var recipients []string
+	if options != nil {
+		recipients = make([]string, 0, len(options.Recipients))
+		for _, r := range options.Recipients {
+			recipients = append(recipients, r.String())
+		}
+	}
+
+	for i := range btl.Parts {
+		part := &btl.Parts[i]
+
+		// virtual parts are not available locally, they keep their encrypted layer as is
+		if btl.VirtualPartTracker != nil && btl.VirtualPartTracker.HasContent(part.GetContentDigest()) {
+			continue
+		}
+
+		want := recipients
+		switch {
+		case options == nil && part.Encryption == nil:
+			continue
+		case options == nil:
+			want = part.Encryption.Recipients
+		case len(recipients) == 0 || (options.Selector != nil && !options.Selector(part)):
+			part.Encryption = nil
+			continue
+		}
+
+		if err := encryptPart(ctx, btl, part, want); err != nil {
+			return fmt.Errorf("encrypting part %s: %w", part.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// encryptPart encrypts the layer of the part for the recipients, unless it is already.
+func encryptPart(ctx context.Context, btl *Bottle, part *PartTrack, recipients []string) error {
+	log := logger.FromContext(ctx).With("part", part.GetName())
+
+	if enc := part.Encryption; enc != nil && enc.Digest == part.GetLayerDigest() && slices.Equal(enc.Recipients, recipients) {
+		exists, err := btl.cache.Exists(ctx, ocispec.Descriptor{Digest: enc.LayerDigest, Size: enc.LayerSize})
+		if err != nil {
+			return fmt.Errorf("checking for encrypted layer in cache: %w", err)
+		}
+		if exists {
+			log.InfoContext(ctx, "part is already encrypted", "layerDigest", enc.LayerDigest)
+			return nil
+		}
+	}
+
+	if len(recipients) == 0 {
+		return ErrNoRecipients
+	}
+	rcpts := make([]encrypt.Recipient, 0, len(recipients))
+	for _, r := range recipients {
+		rcpt, err := encrypt.ParseRecipient([]byte(r))
+		if err != nil {
+			return fmt.Errorf("parsing recipient: %w", err)
+		}
+		rcpts = append(rcpts, rcpt)
+	}
+
+	log.InfoContext(ctx, "encrypting part", "recipients", len(rcpts))
+	layer := ocispec.Descriptor{Digest: part.GetLayerDigest(), Size: part.GetLayerSize()}
+	rc, err := btl.cache.Fetch(ctx, layer)
+	if err != nil {
+		return fmt.Errorf("fetching layer from cache: %w", err)
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp(btl.ScratchPath(), "*")
+	if err != nil {
+		return fmt.Errorf("creating encrypted layer file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	digester := digest.Canonical.Digester()
+	annotations, err := encrypt.Encrypt(io.MultiWriter(tmp, digester.Hash()), rc, layer.Digest, rcpts)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	encrypted := ocispec.Descriptor{Digest: digester.Digest(), Size: layer.Size}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding encrypted layer file: %w", err)
+	}
+	if err := btl.cache.Push(ctx, encrypted, tmp); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return fmt.Errorf("pushing encrypted layer to cache: %w", err)
+	}
+
+	part.Encryption = &PartEncryption{
+		Digest:      layer.Digest,
+		LayerDigest: encrypted.Digest,
+		LayerSize:   encrypted.Size,
+		Annotations: annotations,
+		Recipients:  recipients,
+	}
+	return nil
+}
+
+// decryptToCache decrypts an encrypted layer in the cache, storing the decrypted layer in the cache, and records the
+// decrypted layer for the part.  The descriptor of the decrypted layer is returned.
+func decryptToCache(ctx context.Context, btl *Bottle, desc ocispec.Descriptor, name string, btlPartMutex sync.Locker) (ocispec.Descriptor, error) {
+	rc, err := btl.cache.Fetch(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("fetching encrypted layer from cache: %w", err)
+	}
+	defer rc.Close()
+
+	r, dgst, err := encrypt.Decrypt(rc, desc.Annotations, btl.decryptionKeys)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("decrypting part %s: %w", name, err)
+	}
+
+	// the layer cipher does not change the size of the layer
+	decrypted := ocispec.Descriptor{
+		MediaType: encrypt.DecryptedMediaType(desc.MediaType),
+		Digest:    dgst,
+		Size:      desc.Size,
+	}
+
+	exists, err := btl.cache.Exists(ctx, decrypted)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("checking for decrypted layer in cache: %w", err)
+	}
+	if !exists {
+		// the cache verifies the digest, and the reader verifies the HMAC, before the layer is stored
+		if err := btl.cache.Push(ctx, decrypted, r); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+			return ocispec.Descriptor{}, fmt.Errorf("decrypting part %s: %w", name, err)
+		}
+	}
+
+	btlPartMutex.Lock()
+	defer btlPartMutex.Unlock()
+	part := btl.partByName(name)
+	part.LayerDigest = dgst
+	if part.Encryption != nil {
+		part.Encryption.Digest = dgst
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,114 @@
+package bottle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/act3-ai/data-tool/internal/encrypt"
+)
+
+func Test_SaveUpdatesToSetEncryption(t *testing.T) {
+	ctx := context.Background()
+	cachePath := t.TempDir()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	recipient, err := encrypt.ParseRecipient(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+
+	btlDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(btlDir, "secret.txt"), []byte("export controlled"), 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(btlDir, "public.txt"), []byte("public"), 0o666))
+	require.NoError(t, CreateBottle(btlDir, false))
+
+	btl, err := NewBottle(WithLocalPath(btlDir), WithCachePath(cachePath), WithBlobInfoCache(""))
+	require.NoError(t, err)
+	_, _, err = InspectBottleFiles(ctx, btl, Options{Visitor: PrepareUpdatedParts(ctx, btl)})
+	require.NoError(t, err)
+	require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{}))
+	plainID := btl.GetBottleID()
+
+	encryptOpts := &EncryptOptions{
+		Recipients: []encrypt.Recipient{recipient},
+		Selector:   func(p PartInfo) bool { return p.GetName() == "secret.txt" },
+	}
+	require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{Encryption: encryptOpts}))
+
+	// the configuration does not depend on the encryption
+	assert.Equal(t, plainID, btl.GetBottleID())
+
+	secret := btl.partByName("secret.txt")
+	require.NotNil(t, secret.Encryption)
+	assert.Nil(t, btl.partByName("public.txt").Encryption)
+
+	secretLayer, err := btl.PartLayerDescriptor("secret.txt")
+	require.NoError(t, err)
+	assert.True(t, encrypt.IsEncrypted(secretLayer.MediaType))
+	assert.True(t, IsLayer(secretLayer.MediaType))
+	assert.NotEqual(t, secret.LayerDigest, secretLayer.Digest)
+	assert.Contains(t, secretLayer.Annotations, encrypt.AnnotationJWEKeys)
+	assert.Contains(t, btl.Manifest.GetLayerDescriptors(), secretLayer)
+
+	// unchanged parts keep their encrypted layer
+	require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{}))
+	again, err := btl.PartLayerDescriptor("secret.txt")
+	require.NoError(t, err)
+	assert.Equal(t, secretLayer, again)
+
+	pull := func(t *testing.T, options ...BOption) (*Bottle, error) {
+		t.Helper()
+		cfgData, err := btl.GetConfiguration()
+		require.NoError(t, err)
+
+		options = append(options, WithLocalPath(t.TempDir()), WithCachePath(cachePath), WithBlobInfoCache(""))
+		pulled, err := NewBottle(options...)
+		require.NoError(t, err)
+		pulled.SetManifest(btl.Manifest)
+		require.NoError(t, pulled.Configure(cfgData))
+
+		// label selection only needs the unencrypted part metadata
+		part := pulled.GetPartByLayerDescriptor(secretLayer)
+		require.NotNil(t, part)
+		assert.Equal(t, "secret.txt", part.GetName())
+
+		var mu sync.Mutex
+		_, err = CopyFromCache(ctx, pulled, secretLayer, "secret.txt", &mu)
+		return pulled, err
+	}
+
+	t.Run("decrypt", func(t *testing.T) {
+		pulled, err := pull(t, WithDecryptionKeys(key))
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(pulled.NativePath("secret.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "export controlled", string(data))
+		assert.Equal(t, secret.LayerDigest, pulled.partByName("secret.txt").LayerDigest)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := pull(t)
+		require.ErrorIs(t, err, encrypt.ErrNoDecryptionKey)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, SaveUpdatesToSet(ctx, btl, SaveOptions{Encryption: &EncryptOptions{}}))
+		assert.Nil(t, btl.partByName("secret.txt").Encryption)
+		for _, layer := range btl.Manifest.GetLayerDescriptors() {
+			assert.False(t, encrypt.IsEncrypted(layer.MediaType))
+		}
+	})
+}
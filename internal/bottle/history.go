@@ -0,0 +1,302 @@
+package bottle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/act3-ai/data-tool/internal/oci"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// ErrCommitNotFound is the error when a commit is not in the local history of a bottle.
+var ErrCommitNotFound = errors.New("commit not found in bottle history")
+
+// CommitRecord is an entry of the local history of a bottle, recorded by each commit that changes the bottle ID.  The
+// manifest and config of the commit are kept next to the history, see HistoryBlob.
+type CommitRecord struct {
+	BottleID digest.Digest `json:"bottleID"`
+	Manifest digest.Digest `json:"manifest"`
+	Created  time.Time     `json:"created"`
+	Message  string        `json:"message,omitempty"`
+}
+
+// historyDir returns the directory of the local history of a bottle.
+func historyDir(path string) string {
+	return filepath.Join(path, ".dt", "history")
+}
+
+// historyFile returns the commit log file of the local history of a bottle.
+func historyFile(path string) string {
+	return filepath.Join(historyDir(path), "log.json")
+}
+
+// historyBlobFile returns the file holding a manifest or config of the local history of a bottle.
+func historyBlobFile(path string, dgst digest.Digest) string {
+	return filepath.Join(historyDir(path), "blobs", dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// ReadHistory returns the local history of the bottle at path, oldest commit first.  A bottle without history, such
+// as one committed before history was recorded, returns an empty history.  The digests of the records are validated,
+// since they locate the manifest and config of each commit in the history directory.
+func ReadHistory(path string) ([]CommitRecord, error) {
+	data, err := os.ReadFile(historyFile(path))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading bottle history: %w", err)
+	}
+
+	var records []CommitRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("decoding bottle history: %w", err)
+	}
+	for i, record := range records {
+		if err := record.BottleID.Validate(); err != nil {
+			return nil, fmt.Errorf("bottle history entry %d has an invalid bottle ID: %w", i, err)
+		}
+		if err := record.Manifest.Validate(); err != nil {
+			return nil, fmt.Errorf("bottle history entry %d has an invalid manifest digest: %w", i, err)
+		}
+	}
+	return records, nil
+}
+
+// HistoryBlob returns the manifest or config with the digest from the local history of the bottle at path.
+func HistoryBlob(path string, dgst digest.Digest) ([]byte, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid history digest: %w", err)
+	}
+	data, err := os.ReadFile(historyBlobFile(path, dgst))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s from bottle history: %w", dgst, err)
+	}
+	if digest.FromBytes(data) != dgst {
+		return nil, fmt.Errorf("bottle history content %s is corrupt", dgst)
+	}
+	return data, nil
+}
+
+// RecordCommit appends the committed state of the bottle to its local history, unless the bottle ID is the same as
+// the last recorded commit.
+func RecordCommit(ctx context.Context, btl *Bottle, message string) error {
+	log := logger.FromContext(ctx)
+
+	records, err := ReadHistory(btl.localPath)
+	if err != nil {
+		return err
+	}
+	bottleID := btl.GetBottleID()
+	if len(records) > 0 && records[len(records)-1].BottleID == bottleID {
+		logger.V(log, 1).InfoContext(ctx, "bottle unchanged since last recorded commit", "bottleID", bottleID)
+		return nil
+	}
+
+	cfgData, err := btl.GetConfiguration()
+	if err != nil {
+		return fmt.Errorf("getting bottle configuration: %w", err)
+	}
+	manData, err := btl.GetBottleManifest()
+	if err != nil {
+		return err
+	}
+	if manData == nil {
+		return errors.New("recording bottle history: bottle has no manifest")
+	}
+
+	manDigest := digest.FromBytes(manData)
+	for dgst, data := range map[digest.Digest][]byte{bottleID: cfgData, manDigest: manData} {
+		file := historyBlobFile(btl.localPath, dgst)
+		if err := os.MkdirAll(filepath.Dir(file), 0o777); err != nil {
+			return fmt.Errorf("creating bottle history directory: %w", err)
+		}
+		if err := os.WriteFile(file, data, 0o666); err != nil {
+			return fmt.Errorf("saving bottle history content: %w", err)
+		}
+	}
+
+	records = append(records, CommitRecord{
+		BottleID: bottleID,
+		Manifest: manDigest,
+		Created:  time.Now().UTC(),
+		Message:  message,
+	})
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding bottle history: %w", err)
+	}
+	if err := os.WriteFile(historyFile(btl.localPath), data, 0o666); err != nil {
+		return fmt.Errorf("saving bottle history: %w", err)
+	}
+
+	log.InfoContext(ctx, "recorded commit in bottle history", "bottleID", bottleID)
+	return nil
+}
+
+// FindCommit returns the commit of the history identified by spec.  The spec is either an index, where 0 is the
+// most recent commit, 1 the one before it and so on, or a bottle ID, which may be abbreviated to a unique prefix of
+// its encoded digest.  An index in range takes precedence over a prefix made of digits.
+func FindCommit(records []CommitRecord, spec string) (CommitRecord, error) {
+	index, indexErr := strconv.Atoi(spec)
+	if indexErr == nil && index >= 0 && index < len(records) {
+		return records[len(records)-1-index], nil
+	}
+
+	var found []CommitRecord
+	for _, r := range records {
+		if r.BottleID.String() == spec || strings.HasPrefix(r.BottleID.Encoded(), spec) {
+			if !slices.ContainsFunc(found, func(f CommitRecord) bool { return f.BottleID == r.BottleID }) {
+				found = append(found, r)
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		if indexErr == nil {
+			return CommitRecord{}, fmt.Errorf("%w: index %d is out of range, the history has %d commits",
+				ErrCommitNotFound, index, len(records))
+		}
+		return CommitRecord{}, fmt.Errorf("%w: %s", ErrCommitNotFound, spec)
+	case 1:
+		return found[0], nil
+	default:
+		return CommitRecord{}, fmt.Errorf("bottle ID %s is ambiguous, it matches %d commits", spec, len(found))
+	}
+}
+
+// cachedIntact returns true if the cache holds the layer with its original content.  Uncompressed parts are linked
+// into the cache, so changing the part file in place also changes the cached layer.
+func cachedIntact(ctx context.Context, btl *Bottle, desc ocispec.Descriptor) (bool, error) {
+	exists, err := btl.cache.Exists(ctx, desc)
+	if err != nil {
+		return false, fmt.Errorf("checking part existence in cache: %w", err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	rc, err := btl.cache.Fetch(ctx, desc)
+	if err != nil {
+		return false, fmt.Errorf("fetching part from cache: %w", err)
+	}
+	defer rc.Close()
+	verifier := desc.Digest.Verifier()
+	if _, err := io.Copy(verifier, rc); err != nil {
+		return false, fmt.Errorf("reading part from cache: %w", err)
+	}
+	if !verifier.Verified() {
+		logger.FromContext(ctx).InfoContext(ctx, "cached layer was modified", "layerDigest", desc.Digest)
+		return false, nil
+	}
+	return true, nil
+}
+
+// MissingLayersError is the error when the cache no longer holds the layers of the parts of a commit.
+type MissingLayersError struct {
+	BottleID digest.Digest
+	Parts    []string
+}
+
+func (e *MissingLayersError) Error() string {
+	return fmt.Sprintf("the cache no longer holds %d part(s) of bottle %s: %s", len(e.Parts), e.BottleID,
+		strings.Join(e.Parts, ", "))
+}
+
+// Checkout restores the parts of the current bottle to the recorded commit, extracting them from the cache, and
+// resets the bottle metadata to that of the commit.  Parts of the current bottle are removed, except for virtual
+// parts, which stay virtual.  Nothing is changed if the cache no longer holds every part of the commit unmodified, in
+// which case a *MissingLayersError is returned.
+func Checkout(ctx context.Context, current *Bottle, record CommitRecord, opts ...BOption) (*Bottle, error) {
+	log := logger.FromContext(ctx)
+
+	manData, err := HistoryBlob(current.localPath, record.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	cfgData, err := HistoryBlob(current.localPath, record.BottleID)
+	if err != nil {
+		return nil, err
+	}
+
+	btl, err := NewBottle(append([]BOption{
+		WithLocalPath(current.localPath),
+		WithCachePath(current.cachePath),
+		WithVirtualParts,
+	}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	manifest := oci.ManifestFromData(ocispec.MediaTypeImageManifest, manData)
+	if err := manifest.GetStatus().Error; err != nil {
+		return nil, fmt.Errorf("loading manifest from bottle history: %w", err)
+	}
+	btl.SetManifest(manifest)
+	if err := btl.Configure(cfgData); err != nil {
+		return nil, err
+	}
+
+	// every part must be available before the working directory is touched
+	var restore []ocispec.Descriptor
+	missing := &MissingLayersError{BottleID: record.BottleID}
+	for _, desc := range manifest.GetLayerDescriptors() {
+		part := btl.GetPartByLayerDescriptor(desc)
+		if part == nil {
+			return nil, fmt.Errorf("layer %s of bottle history is not a part", desc.Digest)
+		}
+		if btl.VirtualPartTracker != nil && btl.VirtualPartTracker.HasContent(part.GetContentDigest()) {
+			continue
+		}
+		intact, err := cachedIntact(ctx, btl, desc)
+		if err != nil {
+			return nil, err
+		}
+		if !intact {
+			missing.Parts = append(missing.Parts, part.GetName())
+			continue
+		}
+		restore = append(restore, desc)
+	}
+	if len(missing.Parts) > 0 {
+		return nil, missing
+	}
+
+	for _, part := range current.GetParts() {
+		if err := os.RemoveAll(current.NativePath(part.GetName())); err != nil {
+			return nil, fmt.Errorf("removing part %s: %w", part.GetName(), err)
+		}
+	}
+
+	var btlPartMutex sync.Mutex
+	for _, desc := range restore {
+		name := btl.GetPartByLayerDescriptor(desc).GetName()
+		log.InfoContext(ctx, "restoring part from cache", "part", name, "layerDigest", desc.Digest)
+		if err := os.RemoveAll(btl.NativePath(name)); err != nil {
+			return nil, fmt.Errorf("removing part %s: %w", name, err)
+		}
+		if _, err := CopyFromCache(ctx, btl, desc, name, &btlPartMutex); err != nil {
+			return nil, fmt.Errorf("restoring part %s: %w", name, err)
+		}
+	}
+
+	if err := btl.Save(); err != nil {
+		return nil, err
+	}
+	if err := SaveExtraBottleInfo(ctx, btl); err != nil {
+		return nil, err
+	}
+	return btl, nil
+}
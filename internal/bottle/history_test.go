@@ -0,0 +1,69 @@
+package bottle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindCommit(t *testing.T) {
+	records := []CommitRecord{
+		{BottleID: digest.FromString("first")},
+		{BottleID: digest.FromString("second")},
+		{BottleID: digest.FromString("third")},
+	}
+	tests := []struct {
+		name    string
+		spec    string
+		want    digest.Digest
+		wantErr string
+	}{
+		{"latest", "0", records[2].BottleID, ""},
+		{"previous", "2", records[0].BottleID, ""},
+		{"out of range", "3", "", "index 3 is out of range"},
+		{"bottle ID", records[1].BottleID.String(), records[1].BottleID, ""},
+		{"prefix", records[1].BottleID.Encoded()[:8], records[1].BottleID, ""},
+		{"unknown", "sha256:deadbeef", "", "commit not found"},
+		{"ambiguous", "", "", "ambiguous"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindCommit(records, tt.spec)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.BottleID)
+		})
+	}
+}
+
+func Test_ReadHistoryInvalidDigests(t *testing.T) {
+	valid := digest.FromString("valid")
+	tests := []struct {
+		name    string
+		log     string
+		wantErr string
+	}{
+		{"no algorithm", `[{"bottleID":"abc","manifest":"` + valid.String() + `"}]`, "invalid bottle ID"},
+		{"path traversal", `[{"bottleID":"` + valid.String() + `","manifest":"sha256:../../../secret"}]`, "invalid manifest digest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(historyDir(dir), 0o777))
+			require.NoError(t, os.WriteFile(historyFile(dir), []byte(tt.log), 0o666))
+
+			_, err := ReadHistory(dir)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := HistoryBlob(filepath.Join(t.TempDir(), "bottle"), "sha256:../../secret")
+	assert.ErrorContains(t, err, "invalid history digest")
+}
@@ -0,0 +1,269 @@
+package label
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// RulesFilename is the name of the label rules file at the root of a bottle.
+const RulesFilename = ".label-rules.yaml"
+
+// Attributes are the attributes of a part that label rules match and compute labels from.
+type Attributes struct {
+	// Name is the part name, directory parts end with a slash
+	Name string
+	// Extension is the lower case file extension without the dot, empty for directories
+	Extension string
+	// Size is the content size of the part
+	Size int64
+	// MediaType is the media type of the content of the part, without parameters
+	MediaType string
+}
+
+// templateData is the data label value templates are rendered with.
+type templateData struct {
+	Attributes
+
+	Base       string // last element of the part name
+	Dir        string // directory of the part, "." at the root of the bottle
+	SizeBucket string // name of the size bucket the part falls in
+}
+
+// SizeBucket names the parts up to a size.
+type SizeBucket struct {
+	// Name is the name of the bucket, used as label value
+	Name string `json:"name"`
+
+	// Max is the largest size of the parts in the bucket, the last bucket may leave it out to take all larger parts
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// Rule sets and removes labels of the parts matching all of its conditions.  A rule without conditions matches every
+// part.
+type Rule struct {
+	// Match is a glob the part name must match, "**" matches any number of directories.  Directory parts are matched
+	// without their trailing slash.
+	Match string `json:"match,omitempty"`
+
+	// Regex is a regular expression the part name must match
+	Regex string `json:"regex,omitempty"`
+
+	// Extensions are the file extensions, one of which the part must have
+	Extensions []string `json:"extensions,omitempty"`
+
+	// MediaTypes are the media type patterns, such as "text/*", one of which the part must match
+	MediaTypes []string `json:"mediaTypes,omitempty"`
+
+	// MinSize is the smallest size of a matching part
+	MinSize *resource.Quantity `json:"minSize,omitempty"`
+
+	// MaxSize is the largest size of a matching part
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// Labels are set on matching parts.  Values are Go templates rendered with the part attributes, and invalid
+	// characters in the result are replaced with "-".  A label rendered empty is removed.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Remove are the keys of labels removed from matching parts
+	Remove []string `json:"remove,omitempty"`
+
+	regex  *regexp.Regexp
+	values map[string]*template.Template
+}
+
+// Rules are the content of a label rules file.  Rules are applied in order, so a later rule overrides the labels set or
+// removed by an earlier one.
+type Rules struct {
+	// SizeBuckets are the size buckets, from small to large, available to label templates as .SizeBucket
+	SizeBuckets []SizeBucket `json:"sizeBuckets,omitempty"`
+
+	// Rules are the labelling rules
+	Rules []Rule `json:"rules"`
+}
+
+// DefaultSizeBuckets returns the size buckets used when a rules file does not define any.
+func DefaultSizeBuckets() []SizeBucket {
+	small, medium, large := resource.MustParse("1Mi"), resource.MustParse("100Mi"), resource.MustParse("10Gi")
+	return []SizeBucket{
+		{Name: "small", Max: &small},
+		{Name: "medium", Max: &medium},
+		{Name: "large", Max: &large},
+		{Name: "huge"},
+	}
+}
+
+// LoadRules loads the label rules file at the root of fsys, returning nil if there is none.
+func LoadRules(fsys fs.FS) (*Rules, error) {
+	data, err := fs.ReadFile(fsys, RulesFilename)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading label rules: %w", err)
+	}
+	return ParseRules(data)
+}
+
+// ParseRules parses and validates label rules from YAML.
+func ParseRules(data []byte) (*Rules, error) {
+	var rules Rules
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing label rules: %w", err)
+	}
+	if len(rules.SizeBuckets) == 0 {
+		rules.SizeBuckets = DefaultSizeBuckets()
+	}
+	if err := rules.compile(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// compile validates the rules and prepares their regular expressions and templates.
+func (r *Rules) compile() error {
+	for i, bucket := range r.SizeBuckets {
+		if errs := validation.IsValidLabelValue(bucket.Name); bucket.Name == "" || len(errs) > 0 {
+			return fmt.Errorf("size bucket %d: invalid name %q: %s", i+1, bucket.Name, strings.Join(errs, "; "))
+		}
+		if bucket.Max == nil && i != len(r.SizeBuckets)-1 {
+			return fmt.Errorf("size bucket %s: only the last bucket can leave out max", bucket.Name)
+		}
+	}
+
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if len(rule.Labels) == 0 && len(rule.Remove) == 0 {
+			return fmt.Errorf("label rule %d: must set or remove labels", i+1)
+		}
+		if rule.Match != "" && !doublestar.ValidatePattern(rule.Match) {
+			return fmt.Errorf("label rule %d: invalid match pattern %q", i+1, rule.Match)
+		}
+		for _, mt := range rule.MediaTypes {
+			if _, err := path.Match(mt, ""); err != nil {
+				return fmt.Errorf("label rule %d: invalid media type pattern %q: %w", i+1, mt, err)
+			}
+		}
+		if rule.Regex != "" {
+			re, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				return fmt.Errorf("label rule %d: invalid regex: %w", i+1, err)
+			}
+			rule.regex = re
+		}
+		for _, key := range append(slices.Collect(maps.Keys(rule.Labels)), rule.Remove...) {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return fmt.Errorf("label rule %d: invalid label key %q: %s", i+1, key, strings.Join(errs, "; "))
+			}
+		}
+		rule.values = make(map[string]*template.Template, len(rule.Labels))
+		for key, value := range rule.Labels {
+			tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+			if err != nil {
+				return fmt.Errorf("label rule %d: invalid value of label %s: %w", i+1, key, err)
+			}
+			rule.values[key] = tmpl
+		}
+	}
+	return nil
+}
+
+// Apply returns the labels of the part with the attributes after applying the rules to its current labels.
+func (r *Rules) Apply(attrs Attributes, current map[string]string) (map[string]string, error) {
+	data := templateData{
+		Attributes: attrs,
+		Base:       path.Base(strings.TrimSuffix(attrs.Name, "/")),
+		Dir:        path.Dir(strings.TrimSuffix(attrs.Name, "/")),
+		SizeBucket: r.sizeBucket(attrs.Size),
+	}
+
+	result := maps.Clone(current)
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if !rule.matches(attrs) {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		for _, key := range rule.Remove {
+			delete(result, key)
+		}
+		for key, tmpl := range rule.values {
+			var value strings.Builder
+			if err := tmpl.Execute(&value, data); err != nil {
+				return nil, fmt.Errorf("label rule %d: computing label %s of part %s: %w", i+1, key, attrs.Name, err)
+			}
+			if v := sanitizeValue(value.String()); v != "" {
+				result[key] = v
+			} else {
+				delete(result, key)
+			}
+		}
+	}
+	return result, nil
+}
+
+// matches returns true if the part with the attributes satisfies every condition of the rule.
+func (rule *Rule) matches(attrs Attributes) bool {
+	name := strings.TrimSuffix(attrs.Name, "/")
+	if rule.Match != "" {
+		if ok, _ := doublestar.Match(rule.Match, name); !ok {
+			return false
+		}
+	}
+	if rule.regex != nil && !rule.regex.MatchString(attrs.Name) {
+		return false
+	}
+	if len(rule.Extensions) > 0 && !slices.ContainsFunc(rule.Extensions, func(ext string) bool {
+		return strings.EqualFold(strings.TrimPrefix(ext, "."), attrs.Extension)
+	}) {
+		return false
+	}
+	if len(rule.MediaTypes) > 0 && !slices.ContainsFunc(rule.MediaTypes, func(pattern string) bool {
+		ok, _ := path.Match(pattern, attrs.MediaType)
+		return ok
+	}) {
+		return false
+	}
+	if rule.MinSize != nil && attrs.Size < rule.MinSize.Value() {
+		return false
+	}
+	if rule.MaxSize != nil && attrs.Size > rule.MaxSize.Value() {
+		return false
+	}
+	return true
+}
+
+// sizeBucket returns the name of the first bucket holding the size, or an empty string if none does.
+func (r *Rules) sizeBucket(size int64) string {
+	for _, bucket := range r.SizeBuckets {
+		if bucket.Max == nil || size <= bucket.Max.Value() {
+			return bucket.Name
+		}
+	}
+	return ""
+}
+
+// invalidValueChars are the characters not allowed in label values.
+var invalidValueChars = regexp.MustCompile(`[^-A-Za-z0-9_.]+`)
+
+// sanitizeValue turns a computed string into a valid label value, replacing invalid characters with "-".
+func sanitizeValue(s string) string {
+	s = invalidValueChars.ReplaceAllString(s, "-")
+	if len(s) > validation.LabelValueMaxLength {
+		s = s[:validation.LabelValueMaxLength]
+	}
+	return strings.Trim(s, "-_.")
+}
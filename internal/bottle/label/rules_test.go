@@ -0,0 +1,141 @@
+package label
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown field", "rules:\n- labels: {a: b}\n  glob: '*'\n"},
+		{"no labels", "rules:\n- match: '*'\n"},
+		{"bad glob", "rules:\n- match: '[a'\n  labels: {a: b}\n"},
+		{"bad regex", "rules:\n- regex: '(a'\n  labels: {a: b}\n"},
+		{"bad media type", "rules:\n- mediaTypes: ['[text']\n  labels: {a: b}\n"},
+		{"bad key", "rules:\n- labels: {'a b': c}\n"},
+		{"bad removal", "rules:\n- remove: ['-a']\n"},
+		{"bad template", "rules:\n- labels: {a: '{{ .Name'}\n"},
+		{"bad bucket name", "sizeBuckets:\n- name: 'a b'\nrules: []\n"},
+		{"open bucket not last", "sizeBuckets:\n- name: a\n- name: b\n  max: 1Ki\nrules: []\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules, err := ParseRules([]byte(`
+rules:
+  - labels:
+      ext: "{{ .Extension }}"
+      size: "{{ .SizeBucket }}"
+      type: "{{ .MediaType }}"
+  - match: "train/**"
+    labels: {split: train}
+  - regex: "^test/"
+    labels: {split: test, dir: "{{ .Dir }}"}
+  - extensions: [".TXT"]
+    remove: [split, manual]
+  - mediaTypes: ["image/*"]
+    labels: {kind: image}
+  - minSize: 1Mi
+    maxSize: 2Mi
+    labels: {large: "true"}
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		attrs   Attributes
+		current map[string]string
+		want    map[string]string
+	}{
+		{
+			name:  "computed",
+			attrs: Attributes{Name: "data.csv", Extension: "csv", Size: 10, MediaType: "text/csv"},
+			want:  map[string]string{"ext": "csv", "size": "small", "type": "text-csv"},
+		},
+		{
+			name:    "glob keeps manual labels",
+			attrs:   Attributes{Name: "train/a/b.png", Extension: "png", Size: 10, MediaType: "image/png"},
+			current: map[string]string{"manual": "yes"},
+			want: map[string]string{"ext": "png", "size": "small", "type": "image-png", "split": "train",
+				"kind": "image", "manual": "yes"},
+		},
+		{
+			name:  "directory part",
+			attrs: Attributes{Name: "train/", Size: 10, MediaType: "inode/directory"},
+			want:  map[string]string{"size": "small", "type": "inode-directory", "split": "train"},
+		},
+		{
+			name:  "regex and template",
+			attrs: Attributes{Name: "test/x/y.bin", Extension: "bin", Size: 10, MediaType: "application/octet-stream"},
+			want: map[string]string{"ext": "bin", "size": "small", "type": "application-octet-stream",
+				"split": "test", "dir": "test-x"},
+		},
+		{
+			name:    "later rule removes",
+			attrs:   Attributes{Name: "test/notes.txt", Extension: "txt", Size: 10, MediaType: "text/plain"},
+			current: map[string]string{"manual": "yes", "other": "kept"},
+			want:    map[string]string{"ext": "txt", "size": "small", "type": "text-plain", "dir": "test", "other": "kept"},
+		},
+		{
+			name:  "size range",
+			attrs: Attributes{Name: "big.bin", Extension: "bin", Size: 1 << 20, MediaType: "application/octet-stream"},
+			want: map[string]string{"ext": "bin", "size": "small", "type": "application-octet-stream",
+				"large": "true"},
+		},
+		{
+			name:  "above size range",
+			attrs: Attributes{Name: "huge.bin", Extension: "bin", Size: 3 << 20, MediaType: "application/octet-stream"},
+			want:  map[string]string{"ext": "bin", "size": "medium", "type": "application-octet-stream"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rules.Apply(tt.attrs, tt.current)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRules_Apply_Unmatched(t *testing.T) {
+	rules, err := ParseRules([]byte("rules:\n- match: '*.csv'\n  labels: {a: b}\n"))
+	require.NoError(t, err)
+
+	got, err := rules.Apply(Attributes{Name: "x.txt"}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules(fstest.MapFS{})
+	require.NoError(t, err)
+	assert.Nil(t, rules)
+
+	rules, err = LoadRules(fstest.MapFS{
+		RulesFilename: &fstest.MapFile{Data: []byte("sizeBuckets:\n- name: tiny\n  max: 1Ki\n- name: rest\nrules: []\n")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tiny", rules.sizeBucket(1024))
+	assert.Equal(t, "rest", rules.sizeBucket(1025))
+}
+
+func Test_sanitizeValue(t *testing.T) {
+	assert.Equal(t, "text-plain", sanitizeValue("text/plain"))
+	assert.Equal(t, "a-b", sanitizeValue("/a b/"))
+	assert.Empty(t, sanitizeValue("///"))
+	assert.Len(t, sanitizeValue(strings.Repeat("a", 100)), validation.LabelValueMaxLength)
+}
@@ -0,0 +1,71 @@
+package bottle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/act3-ai/data-tool/internal/bottle/label"
+	"github.com/act3-ai/data-tool/internal/bottle/stats"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// mediaTypeDirectory is the media type of directory parts.
+const mediaTypeDirectory = "inode/directory"
+
+// ApplyLabelRules applies the label rules file at the root of the bottle, if there is one, to the labels of the local
+// parts.  Virtual parts keep their labels, since their content is not available.  Returns true if the bottle has a
+// rules file.
+func ApplyLabelRules(ctx context.Context, btl *Bottle) (bool, error) {
+	log := logger.FromContext(ctx)
+
+	rules, err := label.LoadRules(os.DirFS(btl.localPath))
+	if err != nil {
+		return false, err
+	}
+	if rules == nil {
+		return false, nil
+	}
+
+	for _, part := range btl.GetParts() {
+		if btl.VirtualPartTracker != nil && btl.VirtualPartTracker.HasContent(part.GetContentDigest()) {
+			continue
+		}
+		attrs, err := btl.partAttributes(part)
+		if err != nil {
+			return true, err
+		}
+		lbls, err := rules.Apply(attrs, part.GetLabels())
+		if err != nil {
+			return true, err
+		}
+		if !equalLabels(part.GetLabels(), lbls) {
+			logger.V(log, 1).InfoContext(ctx, "labelling part from rules", "part", part.GetName(), "labels", lbls)
+			btl.UpdatePartMetadata(part.GetName(), -1, "", lbls, -1, "", "", nil)
+		}
+	}
+	return true, nil
+}
+
+// partAttributes returns the attributes of a local part used by label rules.
+func (btl *Bottle) partAttributes(part PartInfo) (label.Attributes, error) {
+	name := part.GetName()
+	attrs := label.Attributes{
+		Name: name,
+		Size: part.GetContentSize(),
+	}
+	if strings.HasSuffix(name, "/") {
+		attrs.MediaType = mediaTypeDirectory
+		return attrs, nil
+	}
+
+	attrs.Extension = strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+	mt, err := stats.DetectMediaType(btl.NativePath(name))
+	if err != nil {
+		return attrs, fmt.Errorf("detecting media type of part %s: %w", name, err)
+	}
+	attrs.MediaType = mt
+	return attrs, nil
+}
@@ -0,0 +1,326 @@
+// Package lineage builds the lineage graph of bottles from their sources and deprecations.
+package lineage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/opencontainers/go-digest"
+	"oras.land/oras-go/v2/errdef"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+
+	"github.com/act3-ai/data-tool/internal/ref"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Bottle is a bottle in the lineage graph.
+type Bottle struct {
+	// ID is the bottle ID
+	ID digest.Digest `json:"id"`
+
+	// Location is where the bottle was found, empty if unknown
+	Location string `json:"location,omitempty"`
+
+	// Description is the description of the bottle
+	Description string `json:"description,omitempty"`
+
+	// Unresolved is true if the bottle could not be found, so its own relations are unknown
+	Unresolved bool `json:"unresolved,omitempty"`
+
+	definition *cfgdef.Bottle
+}
+
+// Relation is the kind of edge between two bottles.
+type Relation string
+
+const (
+	// RelationSource is an edge from a bottle to one of its sources.
+	RelationSource Relation = "source"
+	// RelationDeprecates is an edge from a bottle to a bottle it deprecates.
+	RelationDeprecates Relation = "deprecates"
+)
+
+// Edge is a directed edge from a bottle to an older bottle, either one of its sources or a bottle it deprecates.
+type Edge struct {
+	From     digest.Digest `json:"from"`
+	To       digest.Digest `json:"to"`
+	Relation Relation      `json:"relation"`
+
+	// Name is the name of the source, empty for deprecations
+	Name string `json:"name,omitempty"`
+}
+
+// Graph is the lineage graph around a root bottle.
+type Graph struct {
+	// Root is the bottle ID of the bottle the graph was built for
+	Root digest.Digest `json:"root"`
+
+	// Bottles are the bottles in the graph, in the order they were discovered
+	Bottles []*Bottle `json:"bottles"`
+
+	// Edges are the relations between the bottles
+	Edges []Edge `json:"edges"`
+
+	// Cycles are the cycles found in the graph, each listing the bottle IDs along the cycle
+	Cycles [][]digest.Digest `json:"cycles,omitempty"`
+
+	// Truncated are the bottles whose relations were not followed because of the depth limit
+	Truncated []digest.Digest `json:"truncated,omitempty"`
+
+	index map[digest.Digest]*Bottle
+}
+
+// Bottle returns the bottle in the graph with the bottle ID, nil if there is none.
+func (g *Graph) Bottle(id digest.Digest) *Bottle {
+	return g.index[id]
+}
+
+// Resolver finds bottles by their bottle ID.
+type Resolver interface {
+	// Resolve returns the definition of the bottle with the bottle ID, and where it was found.  If the bottle is not
+	// known, an error wrapping errdef.ErrNotFound is returned.
+	Resolve(ctx context.Context, id digest.Digest) (*cfgdef.Bottle, string, error)
+
+	// Dependents returns the IDs of the known bottles that have the bottle as a source, or deprecate it.
+	Dependents(ctx context.Context, id digest.Digest) ([]digest.Digest, error)
+}
+
+// Direction selects the relations that are followed when building a graph.
+type Direction string
+
+const (
+	// Ancestors follows sources and deprecated bottles.
+	Ancestors Direction = "ancestors"
+	// Descendants follows bottles derived from, or deprecating, a bottle.
+	Descendants Direction = "descendants"
+	// Both follows ancestors and descendants.
+	Both Direction = "both"
+)
+
+// Options are the options for building a lineage graph.
+type Options struct {
+	// Direction of the relations that are followed, defaults to Both
+	Direction Direction
+
+	// Depth is the maximum number of relations followed from the root, unlimited if zero or less
+	Depth int
+}
+
+// Build builds the lineage graph of the root bottle, resolving related bottles with the resolver.
+func Build(ctx context.Context, rootID digest.Digest, root *cfgdef.Bottle, location string, resolver Resolver,
+	options Options,
+) (*Graph, error) {
+	if options.Direction == "" {
+		options.Direction = Both
+	}
+	ancestors := options.Direction == Ancestors || options.Direction == Both
+	descendants := options.Direction == Descendants || options.Direction == Both
+
+	g := &Graph{Root: rootID, index: make(map[digest.Digest]*Bottle)}
+	g.add(&Bottle{ID: rootID, Location: location, Description: root.Description, definition: root})
+
+	b := builder{graph: g, resolver: resolver, edges: make(map[Edge]bool)}
+	if ancestors {
+		if err := b.walk(ctx, rootID, options.Depth, b.ancestors); err != nil {
+			return nil, err
+		}
+	}
+	if descendants {
+		if err := b.walk(ctx, rootID, options.Depth, b.descendants); err != nil {
+			return nil, err
+		}
+	}
+
+	g.Cycles = findCycles(g)
+	return g, nil
+}
+
+type builder struct {
+	graph    *Graph
+	resolver Resolver
+	edges    map[Edge]bool
+}
+
+// walk visits the bottles related to the root breadth first, next returns the related bottles of a bottle.
+func (b *builder) walk(ctx context.Context, rootID digest.Digest, depth int,
+	next func(context.Context, *Bottle) ([]digest.Digest, error),
+) error {
+	visited := map[digest.Digest]bool{rootID: true}
+	queue := []digest.Digest{rootID}
+	for level := 0; len(queue) > 0; level++ {
+		if depth > 0 && level >= depth {
+			for _, id := range queue {
+				if !slices.Contains(b.graph.Truncated, id) {
+					b.graph.Truncated = append(b.graph.Truncated, id)
+				}
+			}
+			return nil
+		}
+
+		var nextQueue []digest.Digest
+		for _, id := range queue {
+			btl := b.graph.Bottle(id)
+			if btl.Unresolved {
+				continue
+			}
+			related, err := next(ctx, btl)
+			if err != nil {
+				return err
+			}
+			for _, r := range related {
+				if !visited[r] {
+					visited[r] = true
+					nextQueue = append(nextQueue, r)
+				}
+			}
+		}
+		queue = nextQueue
+	}
+	return nil
+}
+
+// ancestors adds the sources and deprecated bottles of a bottle to the graph.
+func (b *builder) ancestors(ctx context.Context, btl *Bottle) ([]digest.Digest, error) {
+	edges := Outgoing(btl.ID, btl.definition)
+	related := make([]digest.Digest, 0, len(edges))
+	for _, e := range edges {
+		if err := b.ensure(ctx, e.To); err != nil {
+			return nil, err
+		}
+		b.addEdge(e)
+		related = append(related, e.To)
+	}
+	return related, nil
+}
+
+// descendants adds the bottles that have a bottle as a source, or deprecate it, to the graph.
+func (b *builder) descendants(ctx context.Context, btl *Bottle) ([]digest.Digest, error) {
+	dependents, err := b.resolver.Dependents(ctx, btl.ID)
+	if err != nil {
+		return nil, fmt.Errorf("finding bottles derived from %s: %w", btl.ID, err)
+	}
+
+	related := make([]digest.Digest, 0, len(dependents))
+	for _, id := range dependents {
+		if err := b.ensure(ctx, id); err != nil {
+			return nil, err
+		}
+		dependent := b.graph.Bottle(id)
+		if dependent.Unresolved {
+			continue
+		}
+		for _, e := range Outgoing(id, dependent.definition) {
+			if e.To == btl.ID {
+				b.addEdge(e)
+			}
+		}
+		related = append(related, id)
+	}
+	return related, nil
+}
+
+// ensure adds the bottle to the graph, resolving it if it is not already known.
+func (b *builder) ensure(ctx context.Context, id digest.Digest) error {
+	if b.graph.Bottle(id) != nil {
+		return nil
+	}
+
+	def, location, err := b.resolver.Resolve(ctx, id)
+	switch {
+	case errors.Is(err, errdef.ErrNotFound):
+		logger.FromContext(ctx).InfoContext(ctx, "bottle not found", "bottleID", id)
+		b.graph.add(&Bottle{ID: id, Unresolved: true})
+	case err != nil:
+		return fmt.Errorf("resolving bottle %s: %w", id, err)
+	default:
+		b.graph.add(&Bottle{ID: id, Location: location, Description: def.Description, definition: def})
+	}
+	return nil
+}
+
+func (b *builder) addEdge(e Edge) {
+	if !b.edges[e] {
+		b.edges[e] = true
+		b.graph.Edges = append(b.graph.Edges, e)
+	}
+}
+
+func (g *Graph) add(btl *Bottle) {
+	g.index[btl.ID] = btl
+	g.Bottles = append(g.Bottles, btl)
+}
+
+// Outgoing returns the edges from a bottle to its sources and the bottles it deprecates.
+func Outgoing(id digest.Digest, def *cfgdef.Bottle) []Edge {
+	var edges []Edge
+	for _, src := range def.Sources {
+		if srcID, ok := SourceBottleID(src); ok {
+			edges = append(edges, Edge{From: id, To: srcID, Relation: RelationSource, Name: src.Name})
+		}
+	}
+	for _, dep := range def.Deprecates {
+		edges = append(edges, Edge{From: id, To: dep, Relation: RelationDeprecates})
+	}
+	return edges
+}
+
+// SourceBottleID returns the bottle ID of a source referring to a bottle, with either the bottle or hash scheme.
+func SourceBottleID(src cfgdef.Source) (digest.Digest, bool) {
+	r, err := ref.FromString(src.URI)
+	if err != nil || (r.Scheme != ref.SchemeBottle && r.Scheme != ref.SchemeHash) {
+		return "", false
+	}
+	id, err := digest.Parse(r.Digest)
+	if err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+// Refers returns true if the bottle has the bottle ID as a source, or deprecates it.
+func Refers(def *cfgdef.Bottle, id digest.Digest) bool {
+	return slices.ContainsFunc(Outgoing("", def), func(e Edge) bool { return e.To == id })
+}
+
+// findCycles returns the cycles of the graph, each starting at its first discovered bottle.
+func findCycles(g *Graph) [][]digest.Digest {
+	adjacent := make(map[digest.Digest][]digest.Digest)
+	for _, e := range g.Edges {
+		adjacent[e.From] = append(adjacent[e.From], e.To)
+	}
+
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[digest.Digest]int, len(g.Bottles))
+	var path []digest.Digest
+	var cycles [][]digest.Digest
+
+	var visit func(id digest.Digest)
+	visit = func(id digest.Digest) {
+		state[id] = onPath
+		path = append(path, id)
+		for _, next := range adjacent[id] {
+			switch state[next] {
+			case onPath:
+				start := slices.Index(path, next)
+				cycles = append(cycles, slices.Clone(path[start:]))
+			case unvisited:
+				visit(next)
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
+	for _, btl := range g.Bottles {
+		if state[btl.ID] == unvisited {
+			visit(btl.ID)
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,174 @@
+package lineage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+)
+
+// fakeResolver is an in memory resolver, bottles are named by their ID.
+type fakeResolver map[digest.Digest]*cfgdef.Bottle
+
+func (f fakeResolver) Resolve(ctx context.Context, id digest.Digest) (*cfgdef.Bottle, string, error) {
+	def, ok := f[id]
+	if !ok {
+		return nil, "", fmt.Errorf("bottle %s: %w", id, errdef.ErrNotFound)
+	}
+	return def, "reg.example/" + id.Encoded()[:4], nil
+}
+
+func (f fakeResolver) Dependents(ctx context.Context, id digest.Digest) ([]digest.Digest, error) {
+	var ids []digest.Digest
+	for _, name := range []string{"a", "b", "c", "d", "e", "x"} {
+		other := bottleID(name)
+		if def, ok := f[other]; ok && Refers(def, id) {
+			ids = append(ids, other)
+		}
+	}
+	return ids, nil
+}
+
+func bottleID(name string) digest.Digest {
+	return digest.FromString(name)
+}
+
+func sources(names ...string) []cfgdef.Source {
+	srcs := make([]cfgdef.Source, 0, len(names))
+	for _, name := range names {
+		srcs = append(srcs, cfgdef.Source{Name: name, URI: "bottle:" + bottleID(name).String()})
+	}
+	return srcs
+}
+
+// testBottles returns bottles where c has the sources a and b, d deprecates c, and a has the missing source x.
+func testBottles() fakeResolver {
+	return fakeResolver{
+		bottleID("a"): {Description: "a", Sources: sources("x")},
+		bottleID("b"): {Description: "b"},
+		bottleID("c"): {Description: "c", Sources: sources("a", "b")},
+		bottleID("d"): {Description: "d", Deprecates: []digest.Digest{bottleID("c")}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	bottles := testBottles()
+
+	t.Run("both", func(t *testing.T) {
+		g, err := Build(ctx, bottleID("c"), bottles[bottleID("c")], "", bottles, Options{})
+		require.NoError(t, err)
+		assert.Len(t, g.Bottles, 5)
+		assert.Contains(t, g.Edges, Edge{From: bottleID("c"), To: bottleID("a"), Relation: RelationSource, Name: "a"})
+		assert.Contains(t, g.Edges, Edge{From: bottleID("d"), To: bottleID("c"), Relation: RelationDeprecates})
+		assert.True(t, g.Bottle(bottleID("x")).Unresolved)
+		assert.Empty(t, g.Cycles)
+		assert.Empty(t, g.Truncated)
+	})
+
+	t.Run("ancestors with depth", func(t *testing.T) {
+		g, err := Build(ctx, bottleID("c"), bottles[bottleID("c")], "", bottles, Options{Direction: Ancestors, Depth: 1})
+		require.NoError(t, err)
+		assert.Len(t, g.Bottles, 3)
+		assert.Nil(t, g.Bottle(bottleID("d")))
+		assert.Nil(t, g.Bottle(bottleID("x")))
+		assert.ElementsMatch(t, []digest.Digest{bottleID("a"), bottleID("b")}, g.Truncated)
+	})
+
+	t.Run("descendants", func(t *testing.T) {
+		g, err := Build(ctx, bottleID("a"), bottles[bottleID("a")], "", bottles, Options{Direction: Descendants})
+		require.NoError(t, err)
+		assert.Equal(t, []Edge{
+			{From: bottleID("c"), To: bottleID("a"), Relation: RelationSource, Name: "a"},
+			{From: bottleID("d"), To: bottleID("c"), Relation: RelationDeprecates},
+		}, g.Edges)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		cyclic := testBottles()
+		cyclic[bottleID("b")] = &cfgdef.Bottle{Description: "b", Sources: sources("c")}
+		g, err := Build(ctx, bottleID("c"), cyclic[bottleID("c")], "", cyclic, Options{Direction: Ancestors})
+		require.NoError(t, err)
+		require.Len(t, g.Cycles, 1)
+		assert.ElementsMatch(t, []digest.Digest{bottleID("c"), bottleID("b")}, g.Cycles[0])
+	})
+}
+
+func TestRender(t *testing.T) {
+	ctx := context.Background()
+	bottles := testBottles()
+	g, err := Build(ctx, bottleID("c"), bottles[bottleID("c")], "here", bottles, Options{})
+	require.NoError(t, err)
+
+	var tree bytes.Buffer
+	require.NoError(t, Render(&tree, g, FormatTree))
+	assert.Contains(t, tree.String(), "ancestors:\n├── source \"a\": ")
+	assert.Contains(t, tree.String(), "│   └── source \"x\": "+bottleID("x").String()[:19]+" (not found)\n")
+	assert.Contains(t, tree.String(), "descendants:\n└── deprecated by: ")
+
+	var dot bytes.Buffer
+	require.NoError(t, Render(&dot, g, FormatDOT))
+	assert.Contains(t, dot.String(), fmt.Sprintf("%q -> %q [label=\"deprecates\", style=dashed];",
+		bottleID("d").String(), bottleID("c").String()))
+
+	var mermaid bytes.Buffer
+	require.NoError(t, Render(&mermaid, g, FormatMermaid))
+	assert.Contains(t, mermaid.String(), "b0 -->|\"source a\"| b1\n")
+
+	var js bytes.Buffer
+	require.NoError(t, Render(&js, g, FormatJSON))
+	var decoded Graph
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, g.Edges, decoded.Edges)
+
+	require.Error(t, Render(&js, g, "svg"))
+}
+
+func TestRegistryResolver(t *testing.T) {
+	ctx := context.Background()
+	store, err := oci.New(t.TempDir())
+	require.NoError(t, err)
+
+	push := func(tag string, def *cfgdef.Bottle) digest.Digest {
+		def.TypeMeta.APIVersion = cfgdef.GroupVersion.String()
+		def.TypeMeta.Kind = "Bottle"
+		cfgBytes, err := json.Marshal(def)
+		require.NoError(t, err)
+		cfgDesc, err := oras.PushBytes(ctx, store, mediatype.MediaTypeBottleConfig, cfgBytes)
+		require.NoError(t, err)
+		manDesc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "",
+			oras.PackManifestOptions{ConfigDescriptor: &cfgDesc, Layers: []ocispec.Descriptor{}})
+		require.NoError(t, err)
+		require.NoError(t, store.Tag(ctx, manDesc, tag))
+		return cfgDesc.Digest
+	}
+	parent := push("v1", &cfgdef.Bottle{Description: "parent"})
+	child := push("v2", &cfgdef.Bottle{Description: "child", Sources: []cfgdef.Source{
+		{Name: "parent", URI: "hash://" + parent.Algorithm().String() + "/" + parent.Encoded()},
+	}})
+
+	r := NewRegistryResolver(map[string]Repository{"reg.example/repo": store})
+	def, location, err := r.Resolve(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, "parent", def.Description)
+	assert.Equal(t, "reg.example/repo:v1", location)
+
+	dependents, err := r.Dependents(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, []digest.Digest{child}, dependents)
+
+	_, _, err = r.Resolve(ctx, bottleID("missing"))
+	require.ErrorIs(t, err, errdef.ErrNotFound)
+}
@@ -0,0 +1,186 @@
+package lineage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Repository is a repository that can be scanned for bottles.
+type Repository interface {
+	registry.TagLister
+	content.Resolver
+	content.Fetcher
+}
+
+// RegistryResolver resolves bottles by scanning the tags of a set of repositories.  The repositories are scanned once,
+// on first use.
+type RegistryResolver struct {
+	repos map[string]Repository
+
+	indexed    bool
+	bottles    map[digest.Digest]*cfgdef.Bottle
+	locations  map[digest.Digest]string
+	dependents map[digest.Digest][]digest.Digest
+}
+
+// NewRegistryResolver returns a resolver scanning the repositories, keyed by their reference without a tag.
+func NewRegistryResolver(repos map[string]Repository) *RegistryResolver {
+	return &RegistryResolver{repos: repos}
+}
+
+// Resolve implements Resolver.
+func (r *RegistryResolver) Resolve(ctx context.Context, id digest.Digest) (*cfgdef.Bottle, string, error) {
+	if err := r.index(ctx); err != nil {
+		return nil, "", err
+	}
+	def, ok := r.bottles[id]
+	if !ok {
+		return nil, "", fmt.Errorf("bottle %s: %w", id, errdef.ErrNotFound)
+	}
+	return def, r.locations[id], nil
+}
+
+// Dependents implements Resolver.
+func (r *RegistryResolver) Dependents(ctx context.Context, id digest.Digest) ([]digest.Digest, error) {
+	if err := r.index(ctx); err != nil {
+		return nil, err
+	}
+	return r.dependents[id], nil
+}
+
+// index reads the bottle config of every tag in the repositories.
+func (r *RegistryResolver) index(ctx context.Context) error {
+	if r.indexed {
+		return nil
+	}
+	r.bottles = make(map[digest.Digest]*cfgdef.Bottle)
+	r.locations = make(map[digest.Digest]string)
+	r.dependents = make(map[digest.Digest][]digest.Digest)
+
+	names := make([]string, 0, len(r.repos))
+	for name := range r.repos {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if err := r.indexRepository(ctx, name, r.repos[name]); err != nil {
+			return err
+		}
+	}
+	r.indexed = true
+	return nil
+}
+
+func (r *RegistryResolver) indexRepository(ctx context.Context, name string, repo Repository) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "scanning repository for bottles", "repository", name)
+
+	var tags []string
+	if err := repo.Tags(ctx, "", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("listing tags of %s: %w", name, err)
+	}
+
+	for _, tag := range tags {
+		location := name + ":" + tag
+		id, def, err := fetchBottle(ctx, repo, tag)
+		if err != nil {
+			logger.V(log, 1).InfoContext(ctx, "skipping tag", "ref", location, "reason", err.Error())
+			continue
+		}
+		if _, ok := r.bottles[id]; ok {
+			continue
+		}
+		r.bottles[id] = def
+		r.locations[id] = location
+		for _, e := range Outgoing(id, def) {
+			if !slices.Contains(r.dependents[e.To], id) {
+				r.dependents[e.To] = append(r.dependents[e.To], id)
+			}
+		}
+	}
+	return nil
+}
+
+// fetchBottle fetches the bottle config of a tagged bottle manifest, returning the bottle ID and definition.
+func fetchBottle(ctx context.Context, repo Repository, tag string) (digest.Digest, *cfgdef.Bottle, error) {
+	desc, err := repo.Resolve(ctx, tag)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolving tag: %w", err)
+	}
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		return "", nil, fmt.Errorf("unsupported media type %s", desc.MediaType)
+	}
+	manBytes, err := content.FetchAll(ctx, repo, desc)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetching manifest: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manBytes, &manifest); err != nil {
+		return "", nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+	if !mediatype.IsBottleConfig(manifest.Config.MediaType) {
+		return "", nil, fmt.Errorf("not a bottle, config media type is %s", manifest.Config.MediaType)
+	}
+	cfgBytes, err := content.FetchAll(ctx, repo, manifest.Config)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetching bottle config: %w", err)
+	}
+	def, err := bottle.DefinitionFromConfig(cfgBytes)
+	if err != nil {
+		return "", nil, err
+	}
+	return manifest.Config.Digest, def, nil
+}
+
+// MultiResolver resolves bottles with each resolver in turn.
+type MultiResolver []Resolver
+
+// Resolve implements Resolver, returning the first bottle found.
+func (m MultiResolver) Resolve(ctx context.Context, id digest.Digest) (*cfgdef.Bottle, string, error) {
+	for _, r := range m {
+		def, location, err := r.Resolve(ctx, id)
+		switch {
+		case err == nil:
+			return def, location, nil
+		case !errors.Is(err, errdef.ErrNotFound):
+			return nil, "", err
+		}
+	}
+	return nil, "", fmt.Errorf("bottle %s: %w", id, errdef.ErrNotFound)
+}
+
+// Dependents implements Resolver, returning the dependents found by all resolvers.
+func (m MultiResolver) Dependents(ctx context.Context, id digest.Digest) ([]digest.Digest, error) {
+	var all []digest.Digest
+	for _, r := range m {
+		dependents, err := r.Dependents(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range dependents {
+			if !slices.Contains(all, d) {
+				all = append(all, d)
+			}
+		}
+	}
+	return all, nil
+}
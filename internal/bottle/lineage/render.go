@@ -0,0 +1,207 @@
+package lineage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Formats for rendering a lineage graph.
+const (
+	FormatTree    = "tree"
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatJSON    = "json"
+)
+
+// Formats are the supported formats for rendering a lineage graph.
+var Formats = []string{FormatTree, FormatDOT, FormatMermaid, FormatJSON}
+
+// Render writes the graph in the format.
+func Render(w io.Writer, g *Graph, format string) error {
+	switch format {
+	case FormatTree:
+		return writeTree(w, g)
+	case FormatDOT:
+		return writeDOT(w, g)
+	case FormatMermaid:
+		return writeMermaid(w, g)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(g); err != nil {
+			return fmt.Errorf("encoding lineage graph: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown lineage format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// label returns a short, human readable, label for a bottle.
+func (g *Graph) label(id digest.Digest) string {
+	short := id.String()
+	if len(id.Encoded()) > 12 {
+		short = id.Algorithm().String() + ":" + id.Encoded()[:12]
+	}
+	btl := g.Bottle(id)
+	switch {
+	case btl == nil:
+		return short
+	case btl.Unresolved:
+		return short + " (not found)"
+	case btl.Location != "":
+		short += " " + btl.Location
+	}
+	if slices.Contains(g.Truncated, id) {
+		short += " (depth limit)"
+	}
+	return short
+}
+
+// writeTree writes the ancestors and descendants of the root as indented trees.
+func writeTree(w io.Writer, g *Graph) error {
+	var sb strings.Builder
+	sb.WriteString(g.label(g.Root) + "\n")
+
+	var ancestors, descendants []Edge
+	for _, e := range g.Edges {
+		if e.From == g.Root {
+			ancestors = append(ancestors, e)
+		}
+		if e.To == g.Root {
+			descendants = append(descendants, e)
+		}
+	}
+
+	if len(ancestors) > 0 {
+		sb.WriteString("ancestors:\n")
+		writeBranch(&sb, g, g.Root, "", []digest.Digest{g.Root}, map[digest.Digest]bool{}, true)
+	}
+	if len(descendants) > 0 {
+		sb.WriteString("descendants:\n")
+		writeBranch(&sb, g, g.Root, "", []digest.Digest{g.Root}, map[digest.Digest]bool{}, false)
+	}
+
+	for _, cycle := range g.Cycles {
+		ids := make([]string, 0, len(cycle)+1)
+		for _, id := range append(cycle, cycle[0]) {
+			ids = append(ids, id.String())
+		}
+		sb.WriteString("cycle: " + strings.Join(ids, " -> ") + "\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err //nolint:wrapcheck
+}
+
+// writeBranch writes the children of a bottle, following edges towards the ancestors or descendants.  Bottles on the
+// current path are marked as cycles, and bottles shown before are not expanded again.
+func writeBranch(sb *strings.Builder, g *Graph, id digest.Digest, indent string, path []digest.Digest,
+	shown map[digest.Digest]bool, ancestors bool,
+) {
+	var edges []Edge
+	for _, e := range g.Edges {
+		if (ancestors && e.From == id) || (!ancestors && e.To == id) {
+			edges = append(edges, e)
+		}
+	}
+
+	for i, e := range edges {
+		branch, childIndent := "├── ", indent+"│   "
+		if i == len(edges)-1 {
+			branch, childIndent = "└── ", indent+"    "
+		}
+
+		child, relation := e.To, string(e.Relation)
+		if !ancestors {
+			child = e.From
+			relation = map[Relation]string{RelationSource: "used as source by", RelationDeprecates: "deprecated by"}[e.Relation]
+		}
+		if e.Name != "" {
+			relation += fmt.Sprintf(" %q", e.Name)
+		}
+
+		line := indent + branch + relation + ": " + g.label(child)
+		switch {
+		case slices.Contains(path, child):
+			sb.WriteString(line + " (cycle)\n")
+		case shown[child]:
+			sb.WriteString(line + " (shown above)\n")
+		default:
+			shown[child] = true
+			sb.WriteString(line + "\n")
+			writeBranch(sb, g, child, childIndent, append(path, child), shown, ancestors)
+		}
+	}
+}
+
+// writeDOT writes the graph in the Graphviz DOT language, with edges pointing from a bottle to its ancestors.
+func writeDOT(w io.Writer, g *Graph) error {
+	var sb strings.Builder
+	sb.WriteString("digraph lineage {\n")
+	sb.WriteString("  rankdir=BT;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, btl := range g.Bottles {
+		attrs := fmt.Sprintf("label=%q", g.label(btl.ID))
+		switch {
+		case btl.ID == g.Root:
+			attrs += ", style=bold"
+		case btl.Unresolved:
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&sb, "  %q [%s];\n", btl.ID.String(), attrs)
+	}
+	for _, e := range g.Edges {
+		label := string(e.Relation)
+		if e.Name != "" {
+			label += " " + e.Name
+		}
+		attrs := fmt.Sprintf("label=%q", label)
+		if e.Relation == RelationDeprecates {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&sb, "  %q -> %q [%s];\n", e.From.String(), e.To.String(), attrs)
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err //nolint:wrapcheck
+}
+
+// writeMermaid writes the graph as a Mermaid flowchart, with edges pointing from a bottle to its ancestors.
+func writeMermaid(w io.Writer, g *Graph) error {
+	ids := make(map[digest.Digest]string, len(g.Bottles))
+	var sb strings.Builder
+	sb.WriteString("flowchart BT\n")
+	for i, btl := range g.Bottles {
+		ids[btl.ID] = fmt.Sprintf("b%d", i)
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[btl.ID], mermaidEscape(g.label(btl.ID)))
+	}
+	for _, e := range g.Edges {
+		label := string(e.Relation)
+		if e.Name != "" {
+			label += " " + e.Name
+		}
+		arrow := "-->"
+		if e.Relation == RelationDeprecates {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s|\"%s\"| %s\n", ids[e.From], arrow, mermaidEscape(label), ids[e.To])
+	}
+	if root, ok := ids[g.Root]; ok {
+		fmt.Fprintf(&sb, "  style %s stroke-width:3px\n", root)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err //nolint:wrapcheck
+}
+
+// mermaidEscape escapes quotes, which end a Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,349 @@
+// Package lint evaluates bottles against a configurable set of quality rules.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	// SeverityError findings fail the lint.
+	SeverityError Severity = "error"
+	// SeverityWarning findings are reported, but only fail the lint if requested.
+	SeverityWarning Severity = "warning"
+	// SeverityOff disables a rule.
+	SeverityOff Severity = "off"
+)
+
+// ParseSeverity parses a severity name.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarning, SeverityOff:
+		return sev, nil
+	default:
+		return "", fmt.Errorf("invalid severity %q, must be one of %q, %q or %q", s, SeverityError, SeverityWarning, SeverityOff)
+	}
+}
+
+// AtLeast returns true if the severity is at least as severe as other.
+func (s Severity) AtLeast(other Severity) bool {
+	rank := func(s Severity) int {
+		switch s {
+		case SeverityError:
+			return 2
+		case SeverityWarning:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return rank(s) >= rank(other)
+}
+
+// Finding is a single rule violation.
+type Finding struct {
+	// Rule is the name of the rule that produced the finding
+	Rule string `json:"rule"`
+
+	// Severity is the configured severity of the rule
+	Severity Severity `json:"severity"`
+
+	// Part is the name of the part the finding applies to, empty for the bottle itself
+	Part string `json:"part,omitempty"`
+
+	// Message describes the violation
+	Message string `json:"message"`
+}
+
+// Rule names.
+const (
+	RuleDescription          = "description"
+	RuleAuthors              = "authors"
+	RuleLicense              = "license"
+	RuleSources              = "sources"
+	RuleSourceReferences     = "sourceReferences"
+	RuleMetrics              = "metrics"
+	RuleLabelKeys            = "labelKeys"
+	RulePartLabels           = "partLabels"
+	RulePartSize             = "partSize"
+	RuleDeprecatedMediaTypes = "deprecatedMediaTypes"
+)
+
+// RuleSettings are the settings common to all rules.
+type RuleSettings struct {
+	// Severity of the findings of the rule, "off" disables the rule
+	Severity Severity `json:"severity,omitempty"`
+}
+
+// DescriptionRule requires a bottle description.
+type DescriptionRule struct {
+	RuleSettings `json:",inline"`
+
+	// MinLength is the minimum length of the description
+	MinLength int `json:"minLength,omitempty"`
+}
+
+// LicenseRule requires a license label or annotation.
+type LicenseRule struct {
+	RuleSettings `json:",inline"`
+
+	// Keys are the label or annotation keys that hold the license, any one of them is sufficient
+	Keys []string `json:"keys,omitempty"`
+}
+
+// LabelKeysRule requires bottle and part label keys to follow a naming convention.
+type LabelKeysRule struct {
+	RuleSettings `json:",inline"`
+
+	// Pattern is the regular expression label keys must match
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// PartSizeRule limits the size of parts.
+type PartSizeRule struct {
+	RuleSettings `json:",inline"`
+
+	// Max is the maximum size of a part
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// Config is the rule set used when linting a bottle.
+type Config struct {
+	Description          DescriptionRule `json:"description"`
+	Authors              RuleSettings    `json:"authors"`
+	License              LicenseRule     `json:"license"`
+	Sources              RuleSettings    `json:"sources"`
+	SourceReferences     RuleSettings    `json:"sourceReferences"`
+	Metrics              RuleSettings    `json:"metrics"`
+	LabelKeys            LabelKeysRule   `json:"labelKeys"`
+	PartLabels           RuleSettings    `json:"partLabels"`
+	PartSize             PartSizeRule    `json:"partSize"`
+	DeprecatedMediaTypes RuleSettings    `json:"deprecatedMediaTypes"`
+}
+
+// DefaultConfig returns the default rule set, following the Bottle Creator Guide.
+func DefaultConfig() Config {
+	maxPartSize := resource.MustParse("10Gi")
+	return Config{
+		Description:          DescriptionRule{RuleSettings: RuleSettings{SeverityError}, MinLength: 20},
+		Authors:              RuleSettings{SeverityError},
+		License:              LicenseRule{RuleSettings: RuleSettings{SeverityWarning}, Keys: []string{"license"}},
+		Sources:              RuleSettings{SeverityWarning},
+		SourceReferences:     RuleSettings{SeverityError},
+		Metrics:              RuleSettings{SeverityWarning},
+		LabelKeys:            LabelKeysRule{RuleSettings: RuleSettings{SeverityWarning}, Pattern: `^([a-z0-9]([-a-z0-9.]*[a-z0-9])?/)?[a-z0-9]([-a-z0-9]*[a-z0-9])?$`},
+		PartLabels:           RuleSettings{SeverityWarning},
+		PartSize:             PartSizeRule{RuleSettings: RuleSettings{SeverityWarning}, Max: &maxPartSize},
+		DeprecatedMediaTypes: RuleSettings{SeverityWarning},
+	}
+}
+
+// LoadConfig loads a rule set from a YAML file.  Rules and settings missing from the file keep their defaults.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading lint rules: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing lint rules %s: %w", path, err)
+	}
+	return cfg, cfg.validate()
+}
+
+// settings returns the settings of every rule by name.
+func (cfg *Config) settings() map[string]*RuleSettings {
+	return map[string]*RuleSettings{
+		RuleDescription:          &cfg.Description.RuleSettings,
+		RuleAuthors:              &cfg.Authors,
+		RuleLicense:              &cfg.License.RuleSettings,
+		RuleSources:              &cfg.Sources,
+		RuleSourceReferences:     &cfg.SourceReferences,
+		RuleMetrics:              &cfg.Metrics,
+		RuleLabelKeys:            &cfg.LabelKeys.RuleSettings,
+		RulePartLabels:           &cfg.PartLabels,
+		RulePartSize:             &cfg.PartSize.RuleSettings,
+		RuleDeprecatedMediaTypes: &cfg.DeprecatedMediaTypes,
+	}
+}
+
+// Rules returns the names of all rules.
+func Rules() []string {
+	cfg := DefaultConfig()
+	names := make([]string, 0, len(cfg.settings()))
+	for name := range cfg.settings() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetSeverity changes the severity of the named rule.
+func (cfg *Config) SetSeverity(rule string, severity Severity) error {
+	settings, ok := cfg.settings()[rule]
+	if !ok {
+		return fmt.Errorf("unknown lint rule %q, must be one of %s", rule, strings.Join(Rules(), ", "))
+	}
+	settings.Severity = severity
+	return nil
+}
+
+func (cfg *Config) validate() error {
+	for name, settings := range cfg.settings() {
+		if settings.Severity == "" {
+			continue
+		}
+		if _, err := ParseSeverity(string(settings.Severity)); err != nil {
+			return fmt.Errorf("rule %s: %w", name, err)
+		}
+	}
+	if _, err := regexp.Compile(cfg.LabelKeys.Pattern); err != nil {
+		return fmt.Errorf("rule %s: invalid pattern: %w", RuleLabelKeys, err)
+	}
+	return nil
+}
+
+// Lint evaluates the bottle definition and parts against the rule set, returning the findings of the enabled rules.
+func Lint(def *cfgdef.Bottle, parts []bottle.PartInfo, cfg Config) ([]Finding, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	report := func(rule string, settings RuleSettings, part, format string, args ...any) {
+		if settings.Severity == "" || settings.Severity == SeverityOff {
+			return
+		}
+		findings = append(findings, Finding{
+			Rule:     rule,
+			Severity: settings.Severity,
+			Part:     part,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// bottle metadata
+	switch description := strings.TrimSpace(def.Description); {
+	case description == "":
+		report(RuleDescription, cfg.Description.RuleSettings, "", "bottle has no description")
+	case len(description) < cfg.Description.MinLength:
+		report(RuleDescription, cfg.Description.RuleSettings, "",
+			"description is shorter than %d characters", cfg.Description.MinLength)
+	}
+
+	if len(def.Authors) == 0 {
+		report(RuleAuthors, cfg.Authors, "", "bottle has no authors")
+	}
+	for _, author := range def.Authors {
+		if author.Name == "" || author.Email == "" {
+			report(RuleAuthors, cfg.Authors, "", "author %q must have a name and email", author.Name+author.Email)
+		}
+	}
+
+	if len(cfg.License.Keys) > 0 && !slices.ContainsFunc(cfg.License.Keys, func(key string) bool {
+		return def.Labels[key] != "" || def.Annotations[key] != ""
+	}) {
+		report(RuleLicense, cfg.License.RuleSettings, "", "bottle has no license, add a label or annotation with one of the keys %s",
+			strings.Join(cfg.License.Keys, ", "))
+	}
+
+	if len(def.Sources) == 0 {
+		report(RuleSources, cfg.Sources, "", "bottle has no sources")
+	}
+	for _, source := range def.Sources {
+		if err := checkSourceURI(source.URI); err != nil {
+			report(RuleSourceReferences, cfg.SourceReferences, "", "source %q: %v", source.Name, err)
+		}
+	}
+
+	if len(def.Metrics) == 0 {
+		report(RuleMetrics, cfg.Metrics, "", "bottle has no metrics")
+	}
+
+	keyPattern := regexp.MustCompile(cfg.LabelKeys.Pattern)
+	for _, key := range sortedKeys(def.Labels) {
+		if !keyPattern.MatchString(key) {
+			report(RuleLabelKeys, cfg.LabelKeys.RuleSettings, "", "label key %q does not match %s", key, cfg.LabelKeys.Pattern)
+		}
+	}
+
+	// parts
+	for _, part := range parts {
+		name := part.GetName()
+		lbls := part.GetLabels()
+		if len(lbls) == 0 {
+			report(RulePartLabels, cfg.PartLabels, name, "part has no labels")
+		}
+		for _, key := range sortedKeys(lbls) {
+			if !keyPattern.MatchString(key) {
+				report(RuleLabelKeys, cfg.LabelKeys.RuleSettings, name, "label key %q does not match %s", key, cfg.LabelKeys.Pattern)
+			}
+		}
+
+		if maxSize := cfg.PartSize.Max; maxSize != nil && part.GetContentSize() > maxSize.Value() {
+			report(RulePartSize, cfg.PartSize.RuleSettings, name, "part size %s exceeds %s",
+				resource.NewQuantity(part.GetContentSize(), resource.BinarySI), maxSize)
+		}
+
+		if mt := part.GetMediaType(); mt != "" && isDeprecatedMediaType(mt) {
+			report(RuleDeprecatedMediaTypes, cfg.DeprecatedMediaTypes, name,
+				"part uses the deprecated media type %s, commit the bottle again to upgrade it", mt)
+		}
+	}
+
+	return findings, nil
+}
+
+// checkSourceURI checks that a source refers to something.
+func checkSourceURI(uri string) error {
+	scheme, rest, ok := strings.Cut(uri, ":")
+	switch {
+	case uri == "":
+		return errors.New("has no URI")
+	case !ok || scheme == "" || rest == "":
+		return fmt.Errorf("URI %q is not a reference", uri)
+	case scheme == "bottle":
+		if _, err := digest.Parse(rest); err != nil {
+			return fmt.Errorf("URI %q has an invalid bottle ID: %w", uri, err)
+		}
+	}
+	return nil
+}
+
+// isDeprecatedMediaType returns true for the legacy part media types.
+func isDeprecatedMediaType(mt string) bool {
+	switch mt {
+	case mediatype.MediaTypeLayerTarZstdOld, mediatype.MediaTypeLayerTarGzipOld, mediatype.MediaTypeLayerTarOld,
+		mediatype.MediaTypeLayerRawOld,
+		mediatype.MediaTypeLayerTarZstdLegacy, mediatype.MediaTypeLayerTarGzipLegacy, mediatype.MediaTypeLayerTarLegacy,
+		mediatype.MediaTypeLayerZstdLegacy, mediatype.MediaTypeLayerRawLegacy:
+		return true
+	default:
+		return false
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
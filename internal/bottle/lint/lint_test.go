@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+)
+
+func goodBottle() (*cfgdef.Bottle, []bottle.PartInfo) {
+	def := &cfgdef.Bottle{
+		Description: "A well documented bottle of test data",
+		Authors:     []cfgdef.Author{{Name: "Ada", Email: "ada@example.com"}},
+		Labels:      map[string]string{"license": "MIT", "example.com/team": "data"},
+		Sources:     []cfgdef.Source{{Name: "training", URI: "bottle:sha256:" + sha256Hex}},
+		Metrics:     []cfgdef.Metric{{Name: "accuracy", Value: "0.9"}},
+	}
+	parts := []bottle.PartInfo{
+		&bottle.PartTrack{
+			Part:      cfgdef.Part{Name: "data/", Size: 1024, Labels: map[string]string{"split": "train"}},
+			MediaType: mediatype.MediaTypeLayerTarZstd,
+		},
+	}
+	return def, parts
+}
+
+const sha256Hex = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func rules(findings []Finding) []string {
+	names := make([]string, 0, len(findings))
+	for _, f := range findings {
+		names = append(names, f.Rule)
+	}
+	return names
+}
+
+func TestLint(t *testing.T) {
+	t.Run("clean", func(t *testing.T) {
+		def, parts := goodBottle()
+		findings, err := Lint(def, parts, DefaultConfig())
+		require.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		findings, err := Lint(&cfgdef.Bottle{}, nil, DefaultConfig())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{RuleDescription, RuleAuthors, RuleLicense, RuleSources, RuleMetrics}, rules(findings))
+		assert.Equal(t, SeverityError, findings[0].Severity)
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		def, _ := goodBottle()
+		def.Description = "short"
+		def.Authors = append(def.Authors, cfgdef.Author{Name: "Anonymous"})
+		def.Labels["Bad_Key"] = "x"
+		def.Sources = append(def.Sources, cfgdef.Source{Name: "missing"}, cfgdef.Source{Name: "bad", URI: "bottle:nope"})
+		parts := []bottle.PartInfo{
+			&bottle.PartTrack{Part: cfgdef.Part{Name: "huge.bin", Size: 11 << 30}, MediaType: mediatype.MediaTypeLayerRawLegacy},
+		}
+
+		findings, err := Lint(def, parts, DefaultConfig())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			RuleDescription, RuleAuthors, RuleLabelKeys, RuleSourceReferences, RuleSourceReferences,
+			RulePartLabels, RulePartSize, RuleDeprecatedMediaTypes,
+		}, rules(findings))
+		for _, f := range findings {
+			if f.Rule == RulePartSize {
+				assert.Equal(t, "huge.bin", f.Part)
+			}
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "lint.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+authors:
+  severity: warning
+license:
+  keys: [spdx]
+metrics:
+  severity: "off"
+partSize:
+  max: 1k
+`), 0o666))
+		cfg, err := LoadConfig(path)
+		require.NoError(t, err)
+		require.NoError(t, cfg.SetSeverity(RuleSources, SeverityOff))
+		assert.Equal(t, SeverityError, cfg.Description.Severity, "unspecified rules keep their defaults")
+
+		def, parts := goodBottle()
+		def.Authors = nil
+		def.Sources = nil
+		def.Metrics = nil
+		findings, err := Lint(def, parts, cfg)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{RuleAuthors, RuleLicense, RulePartSize}, rules(findings))
+		assert.Equal(t, SeverityWarning, findings[0].Severity)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "lint.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("authors:\n  severity: fatal\n"), 0o666))
+		_, err := LoadConfig(path)
+		require.Error(t, err)
+
+		cfg := DefaultConfig()
+		require.Error(t, cfg.SetSeverity("unknown", SeverityOff))
+	})
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	assert.True(t, SeverityError.AtLeast(SeverityWarning))
+	assert.True(t, SeverityWarning.AtLeast(SeverityWarning))
+	assert.False(t, SeverityWarning.AtLeast(SeverityError))
+}
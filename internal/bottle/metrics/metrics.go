@@ -0,0 +1,197 @@
+// Package metrics reads metrics from the output files of training runs and experiment trackers, and reduces the
+// recorded values of each metric to the single value stored in a bottle.
+package metrics
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Format is the format of a metrics file.
+type Format string
+
+// Supported metrics formats.
+const (
+	// FormatJSON is a JSON object of metrics, or an array of such objects, one per step
+	FormatJSON Format = "json"
+	// FormatYAML is FormatJSON as YAML
+	FormatYAML Format = "yaml"
+	// FormatCSV is a CSV file with a column per metric and a row per step, or with name and value columns
+	FormatCSV Format = "csv"
+	// FormatTensorBoard is a TensorBoard event file holding scalar summaries, or a log directory of event files
+	FormatTensorBoard Format = "tensorboard"
+	// FormatMLflow is an MLflow run directory, or its metrics directory
+	FormatMLflow Format = "mlflow"
+)
+
+// Formats are the supported formats.
+var Formats = []Format{FormatJSON, FormatYAML, FormatCSV, FormatTensorBoard, FormatMLflow}
+
+// Point is a recorded value of a metric.
+type Point struct {
+	// Step is the training step or epoch the value was recorded at, or the position of the value if unknown
+	Step int64
+	// Value is the value of the metric
+	Value float64
+}
+
+// Series are the recorded values of metrics by metric name, each in the order they were recorded.
+type Series map[string][]Point
+
+// add appends a value to the series of a metric.  Values that are not finite are dropped, since bottle metrics must
+// be numbers.
+func (s Series) add(name string, step int64, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+	s[name] = append(s[name], Point{Step: step, Value: value})
+}
+
+// DetectFormat returns the format of the metrics file or directory at path, from its name and type.
+func DetectFormat(path string) (Format, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("reading metrics file information: %w", err)
+	}
+	if info.IsDir() {
+		events, err := eventFiles(path)
+		if err != nil {
+			return "", err
+		}
+		if len(events) > 0 {
+			return FormatTensorBoard, nil
+		}
+		return FormatMLflow, nil
+	}
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.Contains(name, "tfevents"):
+		return FormatTensorBoard, nil
+	case strings.HasSuffix(name, ".json"):
+		return FormatJSON, nil
+	case strings.HasSuffix(name, ".yaml"), strings.HasSuffix(name, ".yml"):
+		return FormatYAML, nil
+	case strings.HasSuffix(name, ".csv"):
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unable to detect the metrics format of %s, specify it as one of %s", path,
+			joinFormats())
+	}
+}
+
+// Read reads the metrics from the file or directory at path in the format, detecting the format if empty.
+func Read(path string, format Format) (Series, error) {
+	if format == "" {
+		var err error
+		if format, err = DetectFormat(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var read func(string) (Series, error)
+	switch format {
+	case FormatJSON, FormatYAML:
+		read = readDict
+	case FormatCSV:
+		read = readCSV
+	case FormatTensorBoard:
+		read = readTensorBoard
+	case FormatMLflow:
+		read = readMLflow
+	default:
+		return nil, fmt.Errorf("unknown metrics format %q, expected one of %s", format, joinFormats())
+	}
+
+	series, err := read(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s metrics from %s: %w", format, path, err)
+	}
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no numeric metrics found in %s", path)
+	}
+	return series, nil
+}
+
+// joinFormats returns the supported formats as a list.
+func joinFormats() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Aggregation reduces the recorded values of a metric to a single value.
+type Aggregation string
+
+// Supported aggregations.
+const (
+	AggregateLast  Aggregation = "last"
+	AggregateFirst Aggregation = "first"
+	AggregateMin   Aggregation = "min"
+	AggregateMax   Aggregation = "max"
+	AggregateMean  Aggregation = "mean"
+)
+
+// ErrUnknownAggregation is the error for an unsupported aggregation.
+var ErrUnknownAggregation = errors.New("unknown aggregation, expected one of last, first, min, max, mean")
+
+// ParseAggregation returns the aggregation with the name.
+func ParseAggregation(s string) (Aggregation, error) {
+	a := Aggregation(strings.ToLower(strings.TrimSpace(s)))
+	switch a {
+	case AggregateLast, AggregateFirst, AggregateMin, AggregateMax, AggregateMean:
+		return a, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownAggregation, s)
+	}
+}
+
+// Apply reduces the values to a single value.  The last and first values are those of the highest and lowest step,
+// ties going to the value recorded last and first respectively.
+func (a Aggregation) Apply(points []Point) (float64, error) {
+	if len(points) == 0 {
+		return 0, errors.New("no values to aggregate")
+	}
+	switch a {
+	case AggregateLast, "":
+		last := points[0]
+		for _, p := range points[1:] {
+			if p.Step >= last.Step {
+				last = p
+			}
+		}
+		return last.Value, nil
+	case AggregateFirst:
+		first := points[len(points)-1]
+		for _, p := range slices.Backward(points[:len(points)-1]) {
+			if p.Step <= first.Step {
+				first = p
+			}
+		}
+		return first.Value, nil
+	case AggregateMin:
+		return slices.MinFunc(points, comparePoints).Value, nil
+	case AggregateMax:
+		return slices.MaxFunc(points, comparePoints).Value, nil
+	case AggregateMean:
+		var sum float64
+		for _, p := range points {
+			sum += p.Value
+		}
+		return sum / float64(len(points)), nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownAggregation, string(a))
+	}
+}
+
+// comparePoints compares points by value.
+func comparePoints(a, b Point) int {
+	return cmp.Compare(a.Value, b.Value)
+}
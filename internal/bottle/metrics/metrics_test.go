@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func writeFile(t *testing.T, file string, data []byte) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o777))
+	require.NoError(t, os.WriteFile(file, data, 0o666))
+	return file
+}
+
+// scalarEvent returns an Event with a summary value for each tag, the first as simple value and the others as float
+// tensors like TensorFlow 2 writes them.
+func scalarEvent(step int64, values map[string]float32) []byte {
+	var summary []byte
+	for tag, v := range values {
+		var value []byte
+		value = protowire.AppendTag(value, valueTag, protowire.BytesType)
+		value = protowire.AppendString(value, tag)
+		if tag == "simple" {
+			value = protowire.AppendTag(value, valueSimple, protowire.Fixed32Type)
+			value = protowire.AppendFixed32(value, math.Float32bits(v))
+		} else {
+			var tensor []byte
+			tensor = protowire.AppendTag(tensor, tensorDtype, protowire.VarintType)
+			tensor = protowire.AppendVarint(tensor, dtFloat)
+			tensor = protowire.AppendTag(tensor, tensorFloat, protowire.BytesType)
+			tensor = protowire.AppendBytes(tensor, binary.LittleEndian.AppendUint32(nil, math.Float32bits(v)))
+			value = protowire.AppendTag(value, valueTensor, protowire.BytesType)
+			value = protowire.AppendBytes(value, tensor)
+		}
+		summary = protowire.AppendTag(summary, summaryValue, protowire.BytesType)
+		summary = protowire.AppendBytes(summary, value)
+	}
+
+	var event []byte
+	event = protowire.AppendTag(event, 1, protowire.Fixed64Type) // wall_time
+	event = protowire.AppendFixed64(event, math.Float64bits(1.7e9))
+	event = protowire.AppendTag(event, eventStep, protowire.VarintType)
+	event = protowire.AppendVarint(event, uint64(step))
+	event = protowire.AppendTag(event, eventSummary, protowire.BytesType)
+	return protowire.AppendBytes(event, summary)
+}
+
+// tfRecords returns the records in TFRecord format.
+func tfRecords(records ...[]byte) []byte {
+	var out []byte
+	for _, r := range records {
+		length := binary.LittleEndian.AppendUint64(nil, uint64(len(r)))
+		out = append(out, length...)
+		out = binary.LittleEndian.AppendUint32(out, maskedCRC(length))
+		out = append(out, r...)
+		out = binary.LittleEndian.AppendUint32(out, maskedCRC(r))
+	}
+	return out
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	var fileVersion []byte
+	fileVersion = protowire.AppendTag(fileVersion, 3, protowire.BytesType)
+	fileVersion = protowire.AppendString(fileVersion, "brain.Event:2")
+
+	tests := []struct {
+		name   string
+		path   string
+		format Format
+		want   Series
+	}{
+		{
+			name:   "json",
+			path:   writeFile(t, filepath.Join(dir, "m.json"), []byte(`{"acc": 0.9, "eval": {"f1": "0.8", "name": "x"}, "loss": [3, 2.5]}`)),
+			format: FormatJSON,
+			want: Series{
+				"acc":     {{0, 0.9}},
+				"eval/f1": {{0, 0.8}},
+				"loss":    {{0, 3}, {1, 2.5}},
+			},
+		},
+		{
+			name:   "yaml records",
+			path:   writeFile(t, filepath.Join(dir, "m.yml"), []byte("- {epoch: 1, loss: 2}\n- {epoch: 2, loss: 1, acc: 0.5}\n")),
+			format: FormatYAML,
+			want: Series{
+				"loss": {{1, 2}, {2, 1}},
+				"acc":  {{2, 0.5}},
+			},
+		},
+		{
+			name:   "wide csv",
+			path:   writeFile(t, filepath.Join(dir, "wide.csv"), []byte("Epoch,loss,acc,note\n3,0.5,,a\n4,0.25,0.75,b\n")),
+			format: FormatCSV,
+			want: Series{
+				"loss": {{3, 0.5}, {4, 0.25}},
+				"acc":  {{4, 0.75}},
+			},
+		},
+		{
+			name:   "long csv",
+			path:   writeFile(t, filepath.Join(dir, "long.csv"), []byte("metric,value\nloss,2\nloss,1\nacc,NaN\nacc,0.5\n")),
+			format: FormatCSV,
+			want: Series{
+				"loss": {{0, 2}, {1, 1}},
+				"acc":  {{0, 0.5}},
+			},
+		},
+		{
+			name: "mlflow run",
+			path: func() string {
+				writeFile(t, filepath.Join(dir, "run", "metrics", "loss"), []byte("1700000000 0.5 0\n1700000001 0.25 5\n"))
+				writeFile(t, filepath.Join(dir, "run", "metrics", "eval", "acc"), []byte("1700000000 0.75\n"))
+				writeFile(t, filepath.Join(dir, "run", "params", "lr"), []byte("0.1"))
+				return filepath.Join(dir, "run")
+			}(),
+			format: FormatMLflow,
+			want: Series{
+				"loss":     {{0, 0.5}, {5, 0.25}},
+				"eval/acc": {{0, 0.75}},
+			},
+		},
+		{
+			name: "tensorboard log directory",
+			path: func() string {
+				writeFile(t, filepath.Join(dir, "logs", "train", "events.out.tfevents.1.host"), tfRecords(
+					fileVersion,
+					scalarEvent(1, map[string]float32{"simple": 0.1}),
+					scalarEvent(2, map[string]float32{"simple": 0.2, "epoch_loss": 1.5}),
+				))
+				writeFile(t, filepath.Join(dir, "logs", "events.out.tfevents.2.host"), tfRecords(
+					scalarEvent(7, map[string]float32{"acc": 0.3}),
+				))
+				return filepath.Join(dir, "logs")
+			}(),
+			format: FormatTensorBoard,
+			want: Series{
+				"acc":              {{7, 0.3}},
+				"train/simple":     {{1, 0.1}, {2, 0.2}},
+				"train/epoch_loss": {{2, 1.5}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := DetectFormat(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.format, format)
+
+			got, err := Read(tt.path, "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Read(writeFile(t, filepath.Join(dir, "m.txt"), []byte("acc 1")), "")
+	assert.ErrorContains(t, err, "unable to detect")
+
+	_, err = Read(writeFile(t, filepath.Join(dir, "m.json"), []byte(`{"name": "x"}`)), "")
+	assert.ErrorContains(t, err, "no numeric metrics")
+
+	_, err = Read(writeFile(t, filepath.Join(dir, "m.json"), []byte(`{"acc": 1}`)), "xml")
+	assert.ErrorContains(t, err, "unknown metrics format")
+
+	corrupt := tfRecords(scalarEvent(1, map[string]float32{"simple": 1}))
+	corrupt[len(corrupt)-1] ^= 0xff
+	_, err = Read(writeFile(t, filepath.Join(dir, "events.out.tfevents.1"), corrupt), "")
+	assert.ErrorContains(t, err, "corrupt record")
+}
+
+func TestAggregation_Apply(t *testing.T) {
+	points := []Point{{2, 0.5}, {0, 3}, {2, 0.25}, {1, 1}, {0, 4}}
+	tests := []struct {
+		agg  string
+		want float64
+	}{
+		{"last", 0.25},
+		{"FIRST", 3},
+		{"min", 0.25},
+		{"max", 4},
+		{" mean ", 1.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.agg, func(t *testing.T) {
+			agg, err := ParseAggregation(tt.agg)
+			require.NoError(t, err)
+			got, err := agg.Apply(points)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-12)
+		})
+	}
+
+	_, err := ParseAggregation("median")
+	assert.ErrorIs(t, err, ErrUnknownAggregation)
+
+	_, err = AggregateLast.Apply(nil)
+	assert.Error(t, err)
+}
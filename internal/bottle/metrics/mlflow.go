@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readMLflow reads the metrics of an MLflow run from its metrics directory, which holds a file per metric, named by
+// the metric, with a "timestamp value step" line per value.  The path is the run directory or the metrics directory.
+func readMLflow(path string) (Series, error) {
+	dir := filepath.Join(path, "metrics")
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dir = path
+	}
+
+	series := make(Series)
+	err := filepath.WalkDir(dir, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, pth)
+		if err != nil {
+			return fmt.Errorf("naming metric: %w", err)
+		}
+		return readMLflowMetric(series, filepath.ToSlash(rel), pth)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading MLflow metrics: %w", err)
+	}
+	return series, nil
+}
+
+// readMLflowMetric adds the values of the MLflow metric file to the series.  Old versions of MLflow leave out the step.
+func readMLflowMetric(series Series, name, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("opening metric file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("metric %s line %d: expected timestamp and value", name, line)
+		}
+		value, ok := parseNumber(fields[1])
+		if !ok {
+			return fmt.Errorf("metric %s line %d: invalid value %q", name, line, fields[1])
+		}
+		step := int64(len(series[name]))
+		if len(fields) > 2 {
+			if step, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+				return fmt.Errorf("metric %s line %d: invalid step %q", name, line, fields[2])
+			}
+		}
+		series.add(name, step, value)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading metric file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// stepKeys are the keys and column names holding the step of the values in a record, rather than a metric.
+var stepKeys = []string{"step", "_step", "global_step", "epoch", "iteration"}
+
+// nameKeys are the column names holding the metric name in CSV files with a row per value.
+var nameKeys = []string{"name", "metric", "key", "tag"}
+
+// isKey returns true if the name is one of the keys, compared without case.
+func isKey(keys []string, name string) bool {
+	return slices.ContainsFunc(keys, func(k string) bool { return strings.EqualFold(k, strings.TrimSpace(name)) })
+}
+
+// parseNumber parses a number from a metrics file.
+func parseNumber(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v, err == nil
+}
+
+// readDict reads metrics from a JSON or YAML document.  Nested objects are flattened, joining keys with "/".  Arrays
+// of numbers are the values of a metric by step, and arrays of objects are records of metrics by step, where a step
+// key such as "epoch" gives the step.  Values that are not numbers are ignored.
+func readDict(path string) (Series, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+
+	series := make(Series)
+	flatten(series, "", doc, 0)
+	return series, nil
+}
+
+// flatten adds the numbers in v to the series, named by their path below prefix.
+func flatten(series Series, prefix string, v any, step int64) {
+	switch v := v.(type) {
+	case float64:
+		if prefix != "" {
+			series.add(prefix, step, v)
+		}
+	case string:
+		if f, ok := parseNumber(v); ok && prefix != "" {
+			series.add(prefix, step, f)
+		}
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if isKey(stepKeys, k) {
+				if f, ok := v[k].(float64); ok {
+					step = int64(f)
+				}
+			}
+		}
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if isKey(stepKeys, k) {
+				continue
+			}
+			name := k
+			if prefix != "" {
+				name = prefix + "/" + k
+			}
+			flatten(series, name, v[k], step)
+		}
+	case []any:
+		for i, e := range v {
+			flatten(series, prefix, e, int64(i))
+		}
+	}
+}
+
+// readCSV reads metrics from a CSV file with a header.  Either the file has name and value columns and a row per
+// value, or a column per metric and a row per step.  A step column, such as "epoch", gives the step of a row, else
+// rows are numbered.  Cells that are not numbers are ignored.
+func readCSV(path string) (Series, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	stepCol, nameCol, valueCol := -1, -1, -1
+	for i, col := range header {
+		switch {
+		case stepCol < 0 && isKey(stepKeys, col):
+			stepCol = i
+		case nameCol < 0 && isKey(nameKeys, col):
+			nameCol = i
+		case valueCol < 0 && isKey([]string{"value"}, col):
+			valueCol = i
+		}
+	}
+	long := nameCol >= 0 && valueCol >= 0
+
+	series := make(Series)
+	for row := int64(0); ; row++ {
+		record, err := r.Read()
+		switch {
+		case errors.Is(err, io.EOF):
+			return series, nil
+		case err != nil:
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+
+		step := row
+		if stepCol >= 0 && stepCol < len(record) {
+			if f, ok := parseNumber(record[stepCol]); ok {
+				step = int64(math.Round(f))
+			}
+		}
+		if long {
+			if nameCol >= len(record) || valueCol >= len(record) {
+				continue
+			}
+			name := strings.TrimSpace(record[nameCol])
+			if f, ok := parseNumber(record[valueCol]); ok && name != "" {
+				if stepCol < 0 {
+					step = int64(len(series[name]))
+				}
+				series.add(name, step, f)
+			}
+			continue
+		}
+		for i, cell := range record {
+			if i == stepCol || i >= len(header) {
+				continue
+			}
+			if f, ok := parseNumber(cell); ok {
+				series.add(strings.TrimSpace(header[i]), step, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// eventFiles returns the TensorBoard event files under the log directory, sorted by path.
+func eventFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.Contains(d.Name(), "tfevents") {
+			files = append(files, pth)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding TensorBoard event files: %w", err)
+	}
+	return files, nil
+}
+
+// readTensorBoard reads the scalar summaries of a TensorBoard event file, or of the event files of a log directory.
+// The tags of event files in subdirectories of the log directory, which TensorBoard shows as separate runs, are
+// prefixed with the subdirectory.
+func readTensorBoard(path string) (Series, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading file information: %w", err)
+	}
+	if !info.IsDir() {
+		series := make(Series)
+		return series, readEventFile(series, "", path)
+	}
+
+	files, err := eventFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	series := make(Series)
+	for _, file := range files {
+		prefix, err := filepath.Rel(path, filepath.Dir(file))
+		if err != nil {
+			return nil, fmt.Errorf("naming run: %w", err)
+		}
+		if prefix == "." {
+			prefix = ""
+		}
+		if err := readEventFile(series, filepath.ToSlash(prefix), file); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return series, nil
+}
+
+// crcTable is the CRC-32C table of TFRecord checksums.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// maskedCRC returns the masked CRC-32C checksum of TFRecord files.
+func maskedCRC(data []byte) uint32 {
+	crc := crc32.Checksum(data, crcTable)
+	return (crc>>15 | crc<<17) + 0xa282ead8
+}
+
+// readEventFile adds the scalars of the TFRecord file of Event protocol buffers to the series.
+func readEventFile(series Series, prefix, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("opening event file: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header := make([]byte, 12) // length and its checksum
+	footer := make([]byte, 4)  // checksum of the data
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("reading record header: %w", err)
+		}
+		if binary.LittleEndian.Uint32(header[8:]) != maskedCRC(header[:8]) {
+			return errors.New("corrupt record length")
+		}
+		length := binary.LittleEndian.Uint64(header)
+		if length > math.MaxInt32 {
+			return fmt.Errorf("record too large: %d bytes", length)
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("reading record: %w", err)
+		}
+		if _, err := io.ReadFull(r, footer); err != nil {
+			return fmt.Errorf("reading record checksum: %w", err)
+		}
+		if binary.LittleEndian.Uint32(footer) != maskedCRC(data) {
+			return errors.New("corrupt record")
+		}
+		if err := readEvent(series, prefix, data); err != nil {
+			return err
+		}
+	}
+}
+
+// Field numbers of the TensorFlow protocol buffers that hold scalars.
+const (
+	eventStep     = 2 // Event.step
+	eventSummary  = 5 // Event.summary
+	summaryValue  = 1 // Summary.value
+	valueTag      = 1 // Summary.Value.tag
+	valueSimple   = 2 // Summary.Value.simple_value
+	valueTensor   = 8 // Summary.Value.tensor
+	tensorDtype   = 1 // TensorProto.dtype
+	tensorContent = 4 // TensorProto.tensor_content
+	tensorFloat   = 5 // TensorProto.float_val
+	tensorDouble  = 6 // TensorProto.double_val
+
+	dtFloat  = 1 // DataType.DT_FLOAT
+	dtDouble = 2 // DataType.DT_DOUBLE
+)
+
+// readEvent adds the scalar summary values of an Event to the series.
+func readEvent(series Series, prefix string, data []byte) error {
+	var step int64
+	var summaries [][]byte
+	err := eachField(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) {
+		switch {
+		case num == eventStep && typ == protowire.VarintType:
+			step = int64(v)
+		case num == eventSummary && typ == protowire.BytesType:
+			summaries = append(summaries, b)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("decoding event: %w", err)
+	}
+
+	for _, summary := range summaries {
+		err := eachField(summary, func(num protowire.Number, typ protowire.Type, _ uint64, b []byte) {
+			if num != summaryValue || typ != protowire.BytesType {
+				return
+			}
+			tag, value, ok := scalarValue(b)
+			if !ok {
+				return
+			}
+			if prefix != "" {
+				tag = prefix + "/" + tag
+			}
+			series.add(tag, step, value)
+		})
+		if err != nil {
+			return fmt.Errorf("decoding summary: %w", err)
+		}
+	}
+	return nil
+}
+
+// scalarValue returns the tag and value of a summary value holding a scalar, either as simple value or as scalar
+// float tensor.  Returns false for other summaries, such as images and histograms.
+func scalarValue(data []byte) (string, float64, bool) {
+	var tag string
+	var value float64
+	var ok bool
+	_ = eachField(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) {
+		switch {
+		case num == valueTag && typ == protowire.BytesType:
+			tag = string(b)
+		case num == valueSimple && typ == protowire.Fixed32Type:
+			value, ok = float32Value(uint32(v)), true
+		case num == valueTensor && typ == protowire.BytesType:
+			value, ok = scalarTensor(b)
+		}
+	})
+	return tag, value, ok && tag != ""
+}
+
+// scalarTensor returns the value of a TensorProto holding a single float or double.
+func scalarTensor(data []byte) (float64, bool) {
+	var dtype uint64
+	var values []float64
+	var content []byte
+	err := eachField(data, func(num protowire.Number, typ protowire.Type, v uint64, b []byte) {
+		switch num {
+		case tensorDtype:
+			dtype = v
+		case tensorContent:
+			content = b
+		case tensorFloat:
+			values = append(values, packedFloats(typ, v, b, 4)...)
+		case tensorDouble:
+			values = append(values, packedFloats(typ, v, b, 8)...)
+		}
+	})
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case len(values) == 1:
+		return values[0], true
+	case dtype == dtFloat && len(content) == 4:
+		return float32Value(binary.LittleEndian.Uint32(content)), true
+	case dtype == dtDouble && len(content) == 8:
+		return math.Float64frombits(binary.LittleEndian.Uint64(content)), true
+	default:
+		return 0, false
+	}
+}
+
+// float32Value returns the float with the bits as the float64 with the same shortest decimal form, so 0.1 stays 0.1.
+func float32Value(bits uint32) float64 {
+	f := math.Float32frombits(bits)
+	v, err := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	if err != nil {
+		return float64(f)
+	}
+	return v
+}
+
+// packedFloats returns the floats of a repeated float (size 4) or double (size 8) field, packed or not.
+func packedFloats(typ protowire.Type, v uint64, b []byte, size int) []float64 {
+	switch {
+	case typ == protowire.Fixed32Type && size == 4:
+		return []float64{float32Value(uint32(v))}
+	case typ == protowire.Fixed64Type && size == 8:
+		return []float64{math.Float64frombits(v)}
+	case typ != protowire.BytesType:
+		return nil
+	}
+	values := make([]float64, 0, len(b)/size)
+	for ; len(b) >= size; b = b[size:] {
+		if size == 4 {
+			values = append(values, float32Value(binary.LittleEndian.Uint32(b)))
+		} else {
+			values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+	}
+	return values
+}
+
+// eachField calls fn with each field of the protocol buffer message, with the value of numeric fields in v and of
+// length delimited fields in b.  Groups are skipped.
+func eachField(data []byte, fn func(num protowire.Number, typ protowire.Type, v uint64, b []byte)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var v uint64
+		var b []byte
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var v32 uint32
+			v32, n = protowire.ConsumeFixed32(data)
+			v = uint64(v32)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		fn(num, typ, v, b)
+	}
+	return nil
+}
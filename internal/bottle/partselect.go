@@ -6,6 +6,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/act3-ai/data-tool/internal/bottle/stats"
 	"github.com/act3-ai/go-common/pkg/logger"
 
 	"github.com/act3-ai/bottle-schema/pkg/selectors"
@@ -60,7 +61,7 @@ func (opts *PartSelectorOptions) New(ctx context.Context) (PartSelectorFunc, err
 				}
 			}
 
-			if sels.Matches(labels.Set(part.GetLabels())) {
+			if sels.Matches(selectorLabels(part)) {
 				log.Info("Selecting part because it matches a selector")
 				return true
 			}
@@ -70,3 +71,13 @@ func (opts *PartSelectorOptions) New(ctx context.Context) (PartSelectorFunc, err
 		}, nil
 	}
 }
+
+// selectorLabels returns the labels of the part that selectors match, which include the part statistics, if known,
+// under keys starting with stats.LabelPrefix.
+func selectorLabels(part PartInfo) labels.Set {
+	lbls := labels.Set(part.GetLabels())
+	if p, ok := part.(interface{ GetStats() *stats.Stats }); ok && p.GetStats() != nil {
+		lbls = labels.Merge(lbls, p.GetStats().Labels())
+	}
+	return lbls
+}
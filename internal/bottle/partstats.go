@@ -0,0 +1,118 @@
+package bottle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/act3-ai/data-tool/internal/bottle/stats"
+	"github.com/act3-ai/data-tool/internal/cache"
+	"github.com/act3-ai/data-tool/internal/ui"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// statsAnnotation returns the part statistics recorded in the layer annotations, nil if there are none or they are
+// not valid.
+func statsAnnotation(annotations map[string]string) *stats.Stats {
+	data, ok := annotations[AnnotationPartStats]
+	if !ok {
+		return nil
+	}
+	var s stats.Stats
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return nil
+	}
+	return &s
+}
+
+// statsCacheFile returns the file caching the statistics of part content with the digest.
+func (btl *Bottle) statsCacheFile(dgst digest.Digest) string {
+	return filepath.Join(btl.cachePath, cache.StatsDir, dgst.Algorithm().String(), dgst.Encoded()+".json")
+}
+
+// computeStats computes the statistics of the local parts that do not have them yet.  Statistics are cached by the
+// content digest of the part, so parts whose content was seen before are not scanned again.  Parts must be digested
+// first.  Virtual parts are skipped, since their content is not available.
+func computeStats(ctx context.Context, btl *Bottle) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "Computing part statistics")
+	defer log.InfoContext(ctx, "Computing part statistics completed")
+
+	// protects btl.Parts
+	var btlPartMutex sync.Mutex
+
+	errGroup, ctx := errgroup.WithContext(ctx)
+	errGroup.SetLimit(5)
+
+	progress := ui.FromContextOrNoop(ctx).SubTaskWithProgress("Computing Part Statistics")
+	defer progress.Complete()
+
+	for i := range btl.Parts {
+		part := &btl.Parts[i]
+		if part.Stats != nil || part.Digest == "" {
+			continue
+		}
+		if btl.VirtualPartTracker != nil && btl.VirtualPartTracker.HasContent(part.Digest) {
+			continue
+		}
+		progress.Update(0, part.GetContentSize())
+
+		errGroup.Go(func() error {
+			s, err := btl.partStats(ctx, part.Name, part.Digest)
+			if err != nil {
+				return err
+			}
+			btlPartMutex.Lock()
+			part.Stats = s
+			btlPartMutex.Unlock()
+			progress.Update(part.GetContentSize(), 0)
+			return nil
+		})
+	}
+	return errGroup.Wait()
+}
+
+// partStats returns the statistics of the part content with the digest, from the cache if possible.
+func (btl *Bottle) partStats(ctx context.Context, name string, dgst digest.Digest) (*stats.Stats, error) {
+	log := logger.FromContext(ctx).With("part", name)
+
+	file := btl.statsCacheFile(dgst)
+	data, err := os.ReadFile(file)
+	switch {
+	case err == nil:
+		var s stats.Stats
+		if err := json.Unmarshal(data, &s); err == nil {
+			logger.V(log, 1).InfoContext(ctx, "using cached part statistics", "digest", dgst)
+			return &s, nil
+		}
+		log.InfoContext(ctx, "ignoring invalid cached part statistics", "file", file)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("reading cached part statistics: %w", err)
+	}
+
+	log.InfoContext(ctx, "scanning part for statistics")
+	s, err := stats.Compute(btl.NativePath(name))
+	if err != nil {
+		return nil, fmt.Errorf("part %s: %w", name, err)
+	}
+
+	data, err = json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("encoding part statistics: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o777); err != nil {
+		return nil, fmt.Errorf("creating part statistics cache: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0o666); err != nil {
+		return nil, fmt.Errorf("caching part statistics: %w", err)
+	}
+	return s, nil
+}
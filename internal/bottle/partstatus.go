@@ -188,12 +188,12 @@ func (fss *partStatuses) ChangesDetected() bool {
 }
 
 // getDirArchiveSize gets the uncompressed size of a directory by archiving it, without
-// compression. The archived data is ignored.
-func getDirArchiveSize(ctx context.Context, btl *Bottle, path string) (int64, error) {
+// compression, with the fidelity level of the part. The archived data is ignored.
+func getDirArchiveSize(ctx context.Context, btl *Bottle, path string, fidelity string) (int64, error) {
 	counter := archive.NewPipeCounter()
 	counter.ConnectOut(&archive.PipeTerm{})
 	defer counter.Close()
-	err := archive.TarToStream(ctx, os.DirFS(path), counter)
+	err := archive.TarToStream(ctx, os.DirFS(path), counter, fidelityArchiveOptions(fidelity, path, btl.GetPath())...)
 	if err != nil {
 		return 0, err
 	}
@@ -238,7 +238,11 @@ func statusDirVisitor(ctx context.Context, btl *Bottle, fS *partStatuses, opts O
 	}
 	// TODO this is inefficient.  We walk the directory above for find the latest modtime and then we walk it again to find the archived size.  This could be one traversal of the directory tree.
 	// TODO this should also use fs.FS since it is read only
-	archSize, err := getDirArchiveSize(ctx, btl, dirPath)
+	var fidelity string
+	if existing := btl.partByName(path + "/"); existing != nil {
+		fidelity = existing.Fidelity
+	}
+	archSize, err := getDirArchiveSize(ctx, btl, dirPath, fidelity)
 	if err != nil {
 		return err
 	}
@@ -1,14 +1,34 @@
 package bottle
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/opencontainers/go-digest"
 	"k8s.io/apimachinery/pkg/labels"
 
 	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/data-tool/internal/archive"
+	"github.com/act3-ai/data-tool/internal/bottle/stats"
 )
 
+// Fidelity levels for archiving directory parts.
+const (
+	// FidelityNone archives only regular files and directories, following symbolic links.
+	FidelityNone = "none"
+	// FidelityLinks preserves symbolic links, hard links and permission bits.
+	FidelityLinks = "links"
+	// FidelityXattrs preserves extended attributes in addition to FidelityLinks.
+	FidelityXattrs = "xattrs"
+)
+
+// AnnotationPartFidelity is the layer annotation recording the fidelity level of a directory part.  The layer itself is
+// marked so that clients without fidelity support refuse to extract it.
+const AnnotationPartFidelity = "vnd.act3-ace.bottle.part.fidelity"
+
+// AnnotationPartStats is the layer annotation recording the statistics of the content of a part, as JSON.
+const AnnotationPartStats = "vnd.act3-ace.bottle.part.stats"
+
 // PartInfo is an interface for oci file entry data retrieval.
 type PartInfo interface {
 	// GetName returns a file name
@@ -42,6 +62,15 @@ type PartTrack struct {
 	LayerDigest digest.Digest `json:"layerDigest"`
 	MediaType   string        `json:"mediaType"`
 
+	// Fidelity is the fidelity level the part was archived with, empty for the default
+	Fidelity string `json:"fidelity,omitempty"`
+
+	// Encryption describes the encrypted layer of the part, nil if the part is not encrypted
+	Encryption *PartEncryption `json:"encryption,omitempty"`
+
+	// Stats are the statistics of the content of the part, nil if they were not computed
+	Stats *stats.Stats `json:"stats,omitempty"`
+
 	Modified time.Time `json:"modified"`
 }
 
@@ -89,6 +118,11 @@ func (p *PartTrack) GetModTime() time.Time {
 	return p.Modified
 }
 
+// GetStats returns the statistics of the content of the part, nil if they were not computed.
+func (p *PartTrack) GetStats() *stats.Stats {
+	return p.Stats
+}
+
 // SetMediaType sets the media type string for a part.
 func (p *PartTrack) SetMediaType(mt string) {
 	if mt == "" {
@@ -119,3 +153,27 @@ func (p *PartTrack) SetLayerSize(sz int64) {
 func (p *PartTrack) SetModTime(mtime time.Time) {
 	p.Modified = mtime
 }
+
+// ValidateFidelity returns an error if level is not a known fidelity level.  The empty string is allowed and keeps the
+// level a part was previously archived with.
+func ValidateFidelity(level string) error {
+	switch level {
+	case "", FidelityNone, FidelityLinks, FidelityXattrs:
+		return nil
+	default:
+		return fmt.Errorf("unknown fidelity level %q, must be one of %s, %s or %s", level, FidelityNone, FidelityLinks, FidelityXattrs)
+	}
+}
+
+// fidelityArchiveOptions returns the archiver options for archiving the directory dir in the bottle at root with the
+// fidelity level.
+func fidelityArchiveOptions(level, dir, root string) []archive.TarArchiverOption {
+	switch level {
+	case FidelityLinks:
+		return []archive.TarArchiverOption{archive.WithFidelity(dir, root)}
+	case FidelityXattrs:
+		return []archive.TarArchiverOption{archive.WithFidelity(dir, root), archive.WithXattrs()}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,188 @@
+// Package report renders human readable reports, such as datasheets and model cards, of bottles through Go templates.
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/opencontainers/go-digest"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/print"
+)
+
+// Formats of the built-in report templates.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats are the formats of the built-in report templates.
+var Formats = []string{FormatMarkdown, FormatHTML}
+
+//go:embed templates
+var templateFS embed.FS
+
+// Report is the data available to report templates.
+type Report struct {
+	// BottleID is the bottle ID of the reported bottle, empty if it is not known
+	BottleID digest.Digest
+
+	// Bottle is the bottle definition, with the description, authors, sources, metrics, labels and public artifacts
+	Bottle *cfgdef.Bottle
+
+	// Parts are the parts of the bottle, ordered by name
+	Parts []Part
+
+	// Signatures are the signatures of the bottle
+	Signatures []Signature
+}
+
+// Part is a part of the reported bottle.
+type Part struct {
+	Name      string
+	Size      int64
+	Digest    digest.Digest
+	MediaType string
+	Labels    map[string]string
+}
+
+// Signature is a signature of the reported bottle.
+type Signature struct {
+	Digest   digest.Digest
+	Identity string
+	KeyID    string
+}
+
+// New returns the report data of a bottle.
+func New(btl *bottle.Bottle) *Report {
+	parts := btl.GetParts()
+	r := &Report{
+		BottleID: btl.GetBottleID(),
+		Bottle:   &btl.Definition,
+		Parts:    make([]Part, 0, len(parts)),
+	}
+	for _, p := range parts {
+		r.Parts = append(r.Parts, Part{
+			Name:      p.GetName(),
+			Size:      p.GetContentSize(),
+			Digest:    p.GetContentDigest(),
+			MediaType: p.GetMediaType(),
+			Labels:    p.GetLabels(),
+		})
+	}
+	slices.SortFunc(r.Parts, func(a, b Part) int { return strings.Compare(a.Name, b.Name) })
+	return r
+}
+
+// TotalSize returns the total size of the parts.
+func (r *Report) TotalSize() int64 {
+	var total int64
+	for _, p := range r.Parts {
+		total += p.Size
+	}
+	return total
+}
+
+// MediaType returns the media type of a report file, determined by its extension.
+func MediaType(path string) string {
+	switch filepath.Ext(path) {
+	case ".html", ".htm":
+		return "text/html"
+	case ".md", ".markdown":
+		return "text/markdown"
+	}
+	if mt := mediatype.DetermineType(path); mt != "" {
+		return mt
+	}
+	return "text/plain"
+}
+
+// Extension returns the file extension of reports in the format.
+func Extension(format string) string {
+	if format == FormatHTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+var funcs = map[string]any{
+	"bytes": func(n int64) string {
+		if n < 0 {
+			return ""
+		}
+		return print.Bytes(n)
+	},
+	"join": strings.Join,
+	"short": func(d digest.Digest) string {
+		if d.Validate() != nil {
+			return d.String()
+		}
+		return print.ShortDigest(d)
+	},
+	// cell escapes text for a Markdown table cell
+	"cell": func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	},
+	"labels": func(m map[string]string) string {
+		pairs := make([]string, 0, len(m))
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			pairs = append(pairs, k+"="+m[k])
+		}
+		return strings.Join(pairs, ", ")
+	},
+}
+
+// Render renders the report with a built-in template of the format, or with the user supplied template file if
+// templatePath is not empty.  Templates ending in .html are HTML templates, others are text templates.
+func Render(w io.Writer, r *Report, format, templatePath string) error {
+	var name, text string
+	switch {
+	case templatePath != "":
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return fmt.Errorf("reading report template: %w", err)
+		}
+		name, text = filepath.Base(templatePath), string(data)
+	case format == FormatMarkdown || format == FormatHTML:
+		name = format + Extension(format) + ".tmpl"
+		data, err := templateFS.ReadFile("templates/" + name)
+		if err != nil {
+			return fmt.Errorf("reading built-in report template: %w", err)
+		}
+		text = string(data)
+	default:
+		return fmt.Errorf("unknown report format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+
+	if strings.HasSuffix(strings.TrimSuffix(name, ".tmpl"), ".html") {
+		tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(text)
+		if err != nil {
+			return fmt.Errorf("parsing report template: %w", err)
+		}
+		if err := tmpl.Execute(w, r); err != nil {
+			return fmt.Errorf("rendering report: %w", err)
+		}
+		return nil
+	}
+
+	tmpl, err := texttemplate.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parsing report template: %w", err)
+	}
+	if err := tmpl.Execute(w, r); err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+)
+
+func testReport() *Report {
+	return &Report{
+		BottleID: digest.FromString("bottle"),
+		Bottle: &cfgdef.Bottle{
+			Description: "Images of <cats> & dogs",
+			Authors:     []cfgdef.Author{{Name: "Ada", Email: "ada@example.com"}},
+			Metrics:     []cfgdef.Metric{{Name: "accuracy", Value: "0.9", Description: "top-1 | validation"}},
+			Labels:      map[string]string{"license": "MIT"},
+		},
+		Parts: []Part{
+			{Name: "train/", Size: 2048, Digest: digest.FromString("train"), Labels: map[string]string{"split": "train"}},
+			{Name: "test.csv", Size: 512, Digest: digest.FromString("test")},
+		},
+		Signatures: []Signature{{Digest: digest.FromString("sig"), Identity: "ada", KeyID: "key1"}},
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := testReport()
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, r, FormatMarkdown, ""))
+		md := buf.String()
+		assert.Contains(t, md, "**Bottle ID:** `"+r.BottleID.String()+"`")
+		assert.Contains(t, md, "Images of <cats> & dogs")
+		assert.Contains(t, md, "- Ada <ada@example.com>\n")
+		assert.Contains(t, md, "| accuracy | 0.9 | top-1 \\| validation |\n")
+		assert.Contains(t, md, "2 part(s), 2.6 kB in total.")
+		assert.Contains(t, md, "| train/ | 2.0 kB | `"+digest.FromString("train").Encoded()[:12]+"` | split=train |\n")
+		assert.Contains(t, md, "| ada | key1 |")
+		assert.NotContains(t, md, "## Public Artifacts")
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, r, FormatHTML, ""))
+		html := buf.String()
+		assert.Contains(t, html, "<p>Images of &lt;cats&gt; &amp; dogs</p>")
+		assert.Contains(t, html, "<tr><td>license</td><td>MIT</td></tr>")
+	})
+
+	t.Run("template", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "card.md")
+		require.NoError(t, os.WriteFile(path, []byte(`{{ .Bottle.Description }} ({{ bytes .TotalSize }})`), 0o666))
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, r, FormatHTML, path))
+		assert.Equal(t, "Images of <cats> & dogs (2.6 kB)", buf.String())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		require.Error(t, Render(&bytes.Buffer{}, r, "pdf", ""))
+	})
+}
+
+func TestMediaType(t *testing.T) {
+	assert.Equal(t, "text/markdown", MediaType("report.md"))
+	assert.Equal(t, "text/html", MediaType("report.html"))
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/act3-ai/data-tool/internal/archive"
 	"github.com/act3-ai/data-tool/internal/bottle/label"
+	"github.com/act3-ai/data-tool/internal/encrypt"
 	"github.com/act3-ai/data-tool/internal/util"
 )
 
@@ -30,6 +31,17 @@ type SaveOptions struct {
 	NoDigest      bool
 	NoCommit      bool
 	CompressLevel string
+
+	// Fidelity is the fidelity level for archiving directory parts.  Parts archived with a different level are
+	// archived again.  The default keeps the level each part was previously archived with.
+	Fidelity string
+
+	// Encryption selects the parts to encrypt and their recipients.  If nil, parts keep their current encryption.
+	Encryption *EncryptOptions
+
+	// Stats computes the statistics of parts that do not have them, see computeStats.  Parts keep their statistics
+	// until their content changes.
+	Stats bool
 }
 
 // SaveUpdatesToSet performs archival, digest, and cache commission to bottle components, and saves bottle metadata.
@@ -40,7 +52,7 @@ func SaveUpdatesToSet(ctx context.Context, btl *Bottle, options SaveOptions) err
 
 	if !options.NoArchive {
 		log.InfoContext(ctx, "Checking if files need to be archived")
-		if err := archiveParts(ctx, btl, options.CompressLevel, &tmpFileMap); err != nil {
+		if err := archiveParts(ctx, btl, options.CompressLevel, options.Fidelity, &tmpFileMap); err != nil {
 			return err
 		}
 	}
@@ -50,12 +62,21 @@ func SaveUpdatesToSet(ctx context.Context, btl *Bottle, options SaveOptions) err
 			return err
 		}
 	}
+	if options.Stats {
+		if err := computeStats(ctx, btl); err != nil {
+			return err
+		}
+	}
 	if !options.NoCommit {
 		log.InfoContext(ctx, "Committing new files to cache")
 		if err := commitParts(ctx, btl, &tmpFileMap); err != nil {
 			return err
 		}
 
+		if err := encryptParts(ctx, btl, options.Encryption); err != nil {
+			return err
+		}
+
 		// build the latest manifest for the bottle based on any updated information generated above. We don't need the
 		// manifest handler here, but note that it is saved within the bottle.
 		err := btl.ConstructManifest()
@@ -102,6 +123,8 @@ func PrepareUpdatedParts(ctx context.Context, btl *Bottle) Visitor {
 				"",
 				&modTime,
 			)
+			// the content may have changed without changing size, so the digests must be calculated again
+			btl.resetPartDigests(name)
 		case StatusNew:
 			log.InfoContext(ctx, "New part flagged for processing")
 			fullPath := btl.NativePath(name)
@@ -137,7 +160,14 @@ func CopyFromCache(ctx context.Context, btl *Bottle, desc ocispec.Descriptor, na
 	case !exists:
 		return false, nil
 	default:
-		if err := handlePartMedia(ctx, btl.localPath, btl.cache, desc, name); err != nil {
+		if encrypt.IsEncrypted(desc.MediaType) {
+			// the decrypted layer is cached, so later commits can tell whether the part changed
+			desc, err = decryptToCache(ctx, btl, desc, name, btlPartMutex)
+			if err != nil {
+				return false, err
+			}
+		}
+		if err := handlePartMedia(ctx, btl.localPath, btl.cache, desc, name, btl.extractOptions...); err != nil {
 			return false, fmt.Errorf("copying part from cache: %w", err)
 		}
 
@@ -159,10 +189,12 @@ func CopyFromCache(ctx context.Context, btl *Bottle, desc ocispec.Descriptor, na
 // ErrUnknownLayerMediaType is the error if the layer media type is unknown.
 var ErrUnknownLayerMediaType = errors.New("unknown layer media type")
 
-// handlePartMedia copies the layer into the part file/directory given by partName.
-func handlePartMedia(ctx context.Context, localPath string, fetcher content.Fetcher, desc ocispec.Descriptor, partName string) error {
+// handlePartMedia copies the layer into the part file/directory given by partName.  Symbolic links in directory parts
+// must resolve within localPath.
+func handlePartMedia(ctx context.Context, localPath string, fetcher content.Fetcher, desc ocispec.Descriptor, partName string, options ...archive.ExtractOption) error {
 
 	destPath := filepath.Join(localPath, filepath.FromSlash(partName))
+	options = append([]archive.ExtractOption{archive.WithLinkRoot(localPath)}, options...)
 
 	rc, err := fetcher.Fetch(ctx, desc)
 	if err != nil {
@@ -172,11 +204,11 @@ func handlePartMedia(ctx context.Context, localPath string, fetcher content.Fetc
 
 	switch desc.MediaType {
 	case mediatype.MediaTypeLayerTar:
-		return archive.ExtractTar(ctx, rc, destPath)
+		return archive.ExtractTar(ctx, rc, destPath, options...)
 	case mediatype.MediaTypeLayerTarOld, mediatype.MediaTypeLayerTarLegacy:
 		return archive.ExtractTarCompat(ctx, rc, localPath)
 	case mediatype.MediaTypeLayerTarZstd:
-		return archive.ExtractTarZstd(ctx, rc, destPath)
+		return archive.ExtractTarZstd(ctx, rc, destPath, options...)
 	case mediatype.MediaTypeLayerTarZstdOld, mediatype.MediaTypeLayerTarZstdLegacy:
 		return archive.ExtractTarZstdCompat(ctx, rc, localPath)
 	case mediatype.MediaTypeLayerZstd:
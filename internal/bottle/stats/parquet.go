@@ -0,0 +1,247 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// parquetMagic starts and ends every Parquet file.
+const parquetMagic = "PAR1"
+
+// maxParquetFooter bounds the size of the Parquet footer that is read.
+const maxParquetFooter = 64 << 20
+
+// maxThriftDepth bounds the nesting of Thrift values in the Parquet footer, so that a crafted footer cannot exhaust the
+// stack.
+const maxThriftDepth = 64
+
+// countParquet reads the row count and the number of top level columns from the footer of a Parquet file.
+func countParquet(f *os.File) (int64, int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading file information: %w", err)
+	}
+	if info.Size() < 2*int64(len(parquetMagic))+4 {
+		return 0, 0, errors.New("not a Parquet file: too short")
+	}
+
+	tail := make([]byte, 8)
+	if _, err := f.ReadAt(tail, info.Size()-8); err != nil {
+		return 0, 0, fmt.Errorf("reading Parquet footer: %w", err)
+	}
+	if string(tail[4:]) != parquetMagic {
+		return 0, 0, errors.New("not a Parquet file: missing magic")
+	}
+	size := int64(binary.LittleEndian.Uint32(tail))
+	if size > maxParquetFooter || size > info.Size()-8-int64(len(parquetMagic)) {
+		return 0, 0, fmt.Errorf("invalid Parquet footer size %d", size)
+	}
+	footer := make([]byte, size)
+	if _, err := f.ReadAt(footer, info.Size()-8-size); err != nil {
+		return 0, 0, fmt.Errorf("reading Parquet footer: %w", err)
+	}
+
+	md, err := readFileMetaData(bufio.NewReader(bytes.NewReader(footer)))
+	if err != nil {
+		return 0, 0, fmt.Errorf("decoding Parquet footer: %w", err)
+	}
+	return md.numRows, md.columns, nil
+}
+
+// fileMetaData holds the fields of the Parquet FileMetaData structure that are used.
+type fileMetaData struct {
+	numRows int64
+	columns int64 // children of the root schema element
+}
+
+// Thrift compact protocol types.
+const (
+	compactBoolTrue  = 1
+	compactBoolFalse = 2
+	compactByte      = 3
+	compactI16       = 4
+	compactI32       = 5
+	compactI64       = 6
+	compactDouble    = 7
+	compactBinary    = 8
+	compactList      = 9
+	compactSet       = 10
+	compactMap       = 11
+	compactStruct    = 12
+)
+
+// readFileMetaData decodes the Thrift compact encoded FileMetaData of a Parquet file, keeping the row count (field 3)
+// and the number of children of the root element of the schema (field 2).
+func readFileMetaData(r *bufio.Reader) (fileMetaData, error) {
+	var md fileMetaData
+	err := readStruct(r, 1, func(id int16, typ byte) error {
+		switch {
+		case id == 2 && typ == compactList:
+			n, elem, err := readListHeader(r)
+			if err != nil {
+				return err
+			}
+			for i := range n {
+				if i > 0 || elem != compactStruct {
+					if err := skip(r, elem, 3); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := readStruct(r, 3, func(id int16, typ byte) error {
+					if id == 5 && typ == compactI32 {
+						v, err := readVarint(r)
+						md.columns = v
+						return err
+					}
+					return skip(r, typ, 4)
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		case id == 3 && typ == compactI64:
+			v, err := readVarint(r)
+			md.numRows = v
+			return err
+		default:
+			return skip(r, typ, 2)
+		}
+	})
+	return md, err
+}
+
+// readStruct reads the fields of a struct nested at the depth up to its stop field, calling field for each to consume
+// its value.
+func readStruct(r *bufio.Reader, depth int, field func(id int16, typ byte) error) error {
+	if depth > maxThriftDepth {
+		return errThriftDepth
+	}
+	var id int16
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return fmt.Errorf("reading field header: %w", err)
+		}
+		if b == 0 {
+			return nil
+		}
+		typ := b & 0x0f
+		if delta := int16(b >> 4); delta != 0 {
+			id += delta
+		} else {
+			v, err := readVarint(r)
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		if err := field(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// readListHeader reads the size and element type of a list or set.
+func readListHeader(r *bufio.Reader) (int64, byte, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading list header: %w", err)
+	}
+	n := int64(b >> 4)
+	if n == 15 {
+		u, err := binary.ReadUvarint(r)
+		if err != nil {
+			return 0, 0, fmt.Errorf("reading list size: %w", err)
+		}
+		n = int64(u)
+	}
+	return n, b & 0x0f, nil
+}
+
+// readVarint reads a zigzag encoded integer.
+func readVarint(r *bufio.Reader) (int64, error) {
+	v, err := binary.ReadVarint(r)
+	if err != nil {
+		return 0, fmt.Errorf("reading integer: %w", err)
+	}
+	return v, nil
+}
+
+// errThriftDepth is returned for Thrift values nested deeper than maxThriftDepth.
+var errThriftDepth = fmt.Errorf("values nested deeper than %d", maxThriftDepth)
+
+// skip consumes a value of the type, nested at the depth.
+func skip(r *bufio.Reader, typ byte, depth int) error {
+	if depth > maxThriftDepth {
+		return errThriftDepth
+	}
+	switch typ {
+	case compactBoolTrue, compactBoolFalse:
+		return nil // the value is in the type of a field
+	case compactByte:
+		_, err := r.ReadByte()
+		return err //nolint:wrapcheck
+	case compactI16, compactI32, compactI64:
+		_, err := readVarint(r)
+		return err
+	case compactDouble:
+		_, err := r.Discard(8)
+		return err //nolint:wrapcheck
+	case compactBinary:
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("reading binary length: %w", err)
+		}
+		if _, err := io.CopyN(io.Discard, r, int64(n)); err != nil {
+			return fmt.Errorf("reading binary: %w", err)
+		}
+		return nil
+	case compactList, compactSet:
+		n, elem, err := readListHeader(r)
+		if err != nil {
+			return err
+		}
+		for range n {
+			if elem == compactBoolTrue || elem == compactBoolFalse {
+				elem = compactByte // list booleans take a byte each
+			}
+			if err := skip(r, elem, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case compactMap:
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("reading map size: %w", err)
+		}
+		if n == 0 {
+			return nil
+		}
+		kv, err := r.ReadByte()
+		if err != nil {
+			return fmt.Errorf("reading map types: %w", err)
+		}
+		for range n {
+			for _, t := range []byte{kv >> 4, kv & 0x0f} {
+				if t == compactBoolTrue || t == compactBoolFalse {
+					t = compactByte
+				}
+				if err := skip(r, t, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case compactStruct:
+		return readStruct(r, depth, func(_ int16, typ byte) error { return skip(r, typ, depth+1) })
+	default:
+		return fmt.Errorf("unknown Thrift type %d", typ)
+	}
+}
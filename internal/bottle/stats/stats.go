@@ -0,0 +1,174 @@
+// Package stats computes statistics of the content of bottle parts.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// LabelPrefix is the prefix of the label keys under which part statistics are available to part selectors.
+const LabelPrefix = "stats."
+
+// Stats are the statistics of the content of a part.  For a file part the statistics describe the file, for a
+// directory part the files in the directory.
+type Stats struct {
+	// Files is the number of regular files
+	Files int64 `json:"files"`
+
+	// Bytes is the total size of the files, uncompressed
+	Bytes int64 `json:"bytes"`
+
+	// Extensions counts the files by lower case extension, without the dot.  Files without an extension are left out.
+	Extensions map[string]int64 `json:"extensions,omitempty"`
+
+	// MediaTypes counts the files by media type, detected from the extension or else by content sniffing
+	MediaTypes map[string]int64 `json:"mediaTypes,omitempty"`
+
+	// TabularFiles is the number of CSV, JSON Lines and Parquet files the rows and columns are counted in
+	TabularFiles int64 `json:"tabularFiles,omitempty"`
+
+	// Rows is the total number of data rows of the tabular files.  The first record of a CSV file is taken to be its
+	// header.
+	Rows int64 `json:"rows,omitempty"`
+
+	// Columns is the number of columns of the tabular files, left out if they do not all have the same number
+	Columns int64 `json:"columns,omitempty"`
+}
+
+// Compute returns the statistics of the file or directory at path.  Symbolic links to files are followed, symbolic
+// links to directories are not.
+func Compute(path string) (*Stats, error) {
+	s := &Stats{}
+	columns := int64(-1) // unknown until the first tabular file
+	err := filepath.WalkDir(path, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(pth)
+		if err != nil {
+			return fmt.Errorf("reading file information: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		s.Files++
+		s.Bytes += info.Size()
+		if ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(pth), ".")); ext != "" {
+			increment(&s.Extensions, ext)
+		}
+		mt, err := DetectMediaType(pth)
+		if err != nil {
+			return err
+		}
+		increment(&s.MediaTypes, mt)
+
+		rows, cols, ok, err := countTable(pth)
+		if err != nil {
+			return fmt.Errorf("counting rows of %s: %w", pth, err)
+		}
+		if ok {
+			s.TabularFiles++
+			s.Rows += rows
+			switch columns {
+			case -1:
+				columns = cols
+			case cols:
+			default:
+				columns = 0
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("computing statistics: %w", err)
+	}
+	s.Columns = max(columns, 0)
+	return s, nil
+}
+
+// increment adds one to the count of key, allocating the map if needed.
+func increment(m *map[string]int64, key string) {
+	if *m == nil {
+		*m = make(map[string]int64)
+	}
+	(*m)[key]++
+}
+
+// MediaType returns the most common media type of the files, or an empty string if there are none.  Ties are broken
+// alphabetically.
+func (s *Stats) MediaType() string {
+	var best string
+	for _, mt := range slices.Sorted(maps.Keys(s.MediaTypes)) {
+		if best == "" || s.MediaTypes[mt] > s.MediaTypes[best] {
+			best = mt
+		}
+	}
+	return best
+}
+
+// Labels returns the statistics as labels for part selectors, with keys starting with LabelPrefix.  Media types are
+// turned into valid label values by replacing "/" and "+" with "-".
+func (s *Stats) Labels() labels.Set {
+	lbls := labels.Set{
+		LabelPrefix + "files": strconv.FormatInt(s.Files, 10),
+		LabelPrefix + "bytes": strconv.FormatInt(s.Bytes, 10),
+	}
+	if mt := s.MediaType(); mt != "" {
+		lbls[LabelPrefix+"type"] = strings.NewReplacer("/", "-", "+", "-").Replace(mt)
+	}
+	if s.TabularFiles > 0 {
+		lbls[LabelPrefix+"rows"] = strconv.FormatInt(s.Rows, 10)
+		if s.Columns > 0 {
+			lbls[LabelPrefix+"columns"] = strconv.FormatInt(s.Columns, 10)
+		}
+	}
+	return lbls
+}
+
+// extensionTypes are the media types of data file extensions missing from the system media types.
+var extensionTypes = map[string]string{
+	".jsonl":   "application/jsonl",
+	".ndjson":  "application/jsonl",
+	".parquet": "application/vnd.apache.parquet",
+	".yaml":    "application/yaml",
+	".yml":     "application/yaml",
+}
+
+// DetectMediaType returns the media type of a file, without parameters, from its extension or else its content.
+func DetectMediaType(file string) (string, error) {
+	mt, ok := extensionTypes[strings.ToLower(filepath.Ext(file))]
+	if !ok {
+		mt = mime.TypeByExtension(filepath.Ext(file))
+	}
+	if mt == "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", fmt.Errorf("opening file: %w", err)
+		}
+		defer f.Close()
+
+		head := make([]byte, 512) // all that content sniffing considers
+		n, err := io.ReadFull(f, head)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("reading file: %w", err)
+		}
+		mt = http.DetectContentType(head[:n])
+	}
+	if base, _, err := mime.ParseMediaType(mt); err == nil {
+		return base, nil
+	}
+	return mt, nil
+}
@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// parquetFile returns a Parquet file without data whose footer has a schema with two columns and 42 rows, in Thrift
+// compact encoding.
+func parquetFile() []byte {
+	footer := []byte{
+		0x15, 0x02, // version: 1
+		0x19, 0x3c, // schema: list of 3 structs
+		0x48, 6, 's', 'c', 'h', 'e', 'm', 'a', 0x15, 0x04, 0x00, // root, num_children: 2
+		0x15, 0x02, 0x38, 1, 'a', 0x00, // column a
+		0x15, 0x0c, 0x38, 1, 'b', 0x00, // column b
+		0x16, 0x54, // num_rows: 42
+		0x1c, 0x15, 0x02, 0x00, // row_groups is skipped: struct with version
+		0x00,
+	}
+	data := append([]byte(parquetMagic), footer...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(footer)))
+	return append(data, parquetMagic...)
+}
+
+func writeFiles(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+	for name, data := range files {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o777))
+		require.NoError(t, os.WriteFile(p, data, 0o666))
+	}
+}
+
+func TestCompute(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{
+		"a.csv":         []byte("x,y\n1,2\n3,4\n"),
+		"sub/b.csv":     []byte("x,y\n5,6\n"),
+		"sub/c.jsonl":   []byte("{\"x\":1,\"y\":2}\n\n{\"x\":3,\"y\":4}"),
+		"d.parquet":     parquetFile(),
+		"notes":         []byte("plain text"),
+		"sub/image.PNG": []byte("\x89PNG\r\n\x1a\n"),
+	})
+
+	s, err := Compute(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), s.Files)
+	assert.Equal(t, map[string]int64{"csv": 2, "jsonl": 1, "parquet": 1, "png": 1}, s.Extensions)
+	assert.Equal(t, map[string]int64{
+		"text/csv":                       2,
+		"application/jsonl":              1,
+		"application/vnd.apache.parquet": 1,
+		"text/plain":                     1,
+		"image/png":                      1,
+	}, s.MediaTypes)
+	assert.Equal(t, int64(4), s.TabularFiles)
+	assert.Equal(t, int64(2+1+2+42), s.Rows)
+	assert.Equal(t, int64(2), s.Columns)
+	assert.Equal(t, "text/csv", s.MediaType())
+
+	var size int64
+	for _, name := range []string{"a.csv", "sub/b.csv", "sub/c.jsonl", "d.parquet", "notes", "sub/image.PNG"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		size += info.Size()
+	}
+	assert.Equal(t, size, s.Bytes)
+}
+
+func TestCompute_File(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"a.csv": []byte("x,y,z\n1,2,3\n")})
+
+	s, err := Compute(filepath.Join(dir, "a.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, &Stats{
+		Files:        1,
+		Bytes:        12,
+		Extensions:   map[string]int64{"csv": 1},
+		MediaTypes:   map[string]int64{"text/csv": 1},
+		TabularFiles: 1,
+		Rows:         1,
+		Columns:      3,
+	}, s)
+}
+
+func TestCompute_MixedColumns(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{
+		"a.csv": []byte("x,y\n1,2\n"),
+		"b.csv": []byte("x,y,z\n1,2,3\n"),
+	})
+
+	s, err := Compute(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), s.Rows)
+	assert.Zero(t, s.Columns)
+}
+
+func TestCompute_InvalidParquet(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"a.parquet": []byte("not parquet")})
+
+	_, err := Compute(dir)
+	assert.Error(t, err)
+}
+
+func TestReadFileMetaData_Nesting(t *testing.T) {
+	// fields of nested structs, each closed by a stop field
+	nested := func(depth int) []byte {
+		footer := bytes.Repeat([]byte{0x1c}, depth)
+		return append(footer, make([]byte, depth+1)...)
+	}
+
+	_, err := readFileMetaData(bufio.NewReader(bytes.NewReader(nested(maxThriftDepth - 1))))
+	require.NoError(t, err)
+
+	_, err = readFileMetaData(bufio.NewReader(bytes.NewReader(nested(100000))))
+	assert.ErrorIs(t, err, errThriftDepth)
+}
+
+func TestStats_Labels(t *testing.T) {
+	s := &Stats{
+		Files:        3,
+		Bytes:        100,
+		MediaTypes:   map[string]int64{"image/svg+xml": 2, "text/plain": 1},
+		TabularFiles: 1,
+		Rows:         10,
+	}
+	assert.Equal(t, labels.Set{
+		"stats.files": "3",
+		"stats.bytes": "100",
+		"stats.type":  "image-svg-xml",
+		"stats.rows":  "10",
+	}, s.Labels())
+
+	sel, err := labels.Parse("stats.rows>5,stats.type=image-svg-xml")
+	require.NoError(t, err)
+	assert.True(t, sel.Matches(s.Labels()))
+}
@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// countTable returns the number of data rows and columns of a CSV, JSON Lines or Parquet file, selected by extension.
+// Returns false if the file is not one of them.
+func countTable(file string) (rows, columns int64, ok bool, err error) {
+	var count func(*os.File) (int64, int64, error)
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		count = countCSV
+	case ".jsonl", ".ndjson":
+		count = countJSONLines
+	case ".parquet":
+		count = countParquet
+	default:
+		return 0, 0, false, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	rows, columns, err = count(f)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return rows, columns, true, nil
+}
+
+// countCSV counts the records of a CSV file, less the header, and the fields of the header.
+func countCSV(f *os.File) (int64, int64, error) {
+	r := csv.NewReader(bufio.NewReader(f))
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+	r.LazyQuotes = true
+
+	var rows, columns int64
+	for {
+		record, err := r.Read()
+		switch {
+		case errors.Is(err, io.EOF):
+			return max(rows-1, 0), columns, nil
+		case err != nil:
+			return 0, 0, fmt.Errorf("reading CSV: %w", err)
+		}
+		if rows == 0 {
+			columns = int64(len(record))
+		}
+		rows++
+	}
+}
+
+// countJSONLines counts the non-blank lines of a JSON Lines file, and the keys of the first object.
+func countJSONLines(f *os.File) (int64, int64, error) {
+	r := bufio.NewReader(f)
+	var rows, columns int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if rows == 0 {
+				var obj map[string]json.RawMessage
+				if json.Unmarshal(line, &obj) == nil {
+					columns = int64(len(obj))
+				}
+			}
+			rows++
+		}
+		switch {
+		case errors.Is(err, io.EOF):
+			return rows, columns, nil
+		case err != nil:
+			return 0, 0, fmt.Errorf("reading JSON lines: %w", err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+//go:build linux
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask is the set of inotify events watched on each directory.
+const inotifyMask = unix.IN_CREATE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE | unix.IN_ATTRIB | unix.IN_DELETE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_ONLYDIR
+
+// inotifyNotifier reports changes with inotify, watching every directory of the tree.
+type inotifyNotifier struct {
+	root    string
+	f       *os.File
+	fd      int
+	dirs    map[int]string // relative directory paths by watch descriptor
+	changes chan change
+	errs    chan error
+	done    chan struct{}
+}
+
+func newNotifier(ctx context.Context, root string) (notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("initializing inotify: %w", err)
+	}
+
+	n := &inotifyNotifier{
+		root:    root,
+		f:       os.NewFile(uintptr(fd), "inotify"),
+		fd:      fd,
+		dirs:    make(map[int]string),
+		changes: make(chan change),
+		errs:    make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	if _, err := n.addTree("."); err != nil {
+		n.f.Close()
+		return nil, err
+	}
+
+	go n.run(ctx)
+	return n, nil
+}
+
+func (n *inotifyNotifier) Changes() <-chan change {
+	return n.changes
+}
+
+func (n *inotifyNotifier) Errors() <-chan error {
+	return n.errs
+}
+
+func (n *inotifyNotifier) Close() error {
+	close(n.done)
+	return n.f.Close() //nolint:wrapcheck
+}
+
+// addTree watches the directory at the relative path and every directory below it, returning the relative paths of
+// the files found, which may have been created before the directories were watched.
+func (n *inotifyNotifier) addTree(rel string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(filepath.Join(n.root, rel), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil // removed in the meantime
+			}
+			return err
+		}
+		r, err := filepath.Rel(n.root, path)
+		if err != nil {
+			return fmt.Errorf("resolving watched path: %w", err)
+		}
+		if r != "." && ignored(d.Name()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			files = append(files, r)
+			return nil
+		}
+		wd, err := unix.InotifyAddWatch(n.fd, path, inotifyMask)
+		switch {
+		case errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ENOTDIR):
+			return nil // removed or replaced in the meantime
+		case err != nil:
+			return fmt.Errorf("watching directory %s: %w", path, err)
+		}
+		n.dirs[wd] = r
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("watching directory tree: %w", err)
+	}
+	return files, nil
+}
+
+// run reads inotify events until the notifier is closed.
+func (n *inotifyNotifier) run(ctx context.Context) {
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		size, err := n.f.Read(buf)
+		if err != nil {
+			select {
+			case <-n.done:
+			default:
+				n.errs <- fmt.Errorf("reading inotify events: %w", err)
+			}
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= size; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(event.Len)]
+			offset += unix.SizeofInotifyEvent + int(event.Len)
+
+			if event.Mask&unix.IN_Q_OVERFLOW != 0 {
+				// events were lost, report the whole tree as updated
+				if !n.send(ctx, change{path: ".", kind: changeUpdated}) {
+					return
+				}
+				continue
+			}
+			changes, err := n.translate(event, string(bytes.TrimRight(nameBytes, "\x00")))
+			if err != nil {
+				n.errs <- err
+				return
+			}
+			for _, c := range changes {
+				if !n.send(ctx, c) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// translate converts an inotify event to the changes it reports, watching newly created directories.
+func (n *inotifyNotifier) translate(event *unix.InotifyEvent, name string) ([]change, error) {
+	dir, ok := n.dirs[int(event.Wd)]
+	if !ok {
+		return nil, nil
+	}
+	if event.Mask&(unix.IN_DELETE_SELF|unix.IN_IGNORED) != 0 {
+		delete(n.dirs, int(event.Wd))
+		return nil, nil
+	}
+	if name == "" || ignored(name) {
+		return nil, nil
+	}
+	rel := filepath.Join(dir, name)
+
+	switch {
+	case event.Mask&unix.IN_ISDIR != 0 && event.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		files, err := n.addTree(rel)
+		if err != nil {
+			return nil, err
+		}
+		changes := []change{{path: rel, kind: changeUpdated}}
+		for _, f := range files {
+			changes = append(changes, change{path: f, kind: changeUpdated})
+		}
+		return changes, nil
+	case event.Mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+		return []change{{path: rel, kind: changeRemoved}}, nil
+	case event.Mask&unix.IN_CLOSE_WRITE != 0:
+		return []change{{path: rel, kind: changeClosed}}, nil
+	case event.Mask&unix.IN_MODIFY != 0, event.Mask&unix.IN_CREATE != 0 && n.isRegular(rel):
+		// regular files are created open, other files, such as symbolic links, are never closed
+		return []change{{path: rel, kind: changeWritten}}, nil
+	default:
+		return []change{{path: rel, kind: changeUpdated}}, nil
+	}
+}
+
+// isRegular returns true if the relative path is a regular file.
+func (n *inotifyNotifier) isRegular(rel string) bool {
+	info, err := os.Lstat(filepath.Join(n.root, rel))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// send reports the change, returning false if the notifier is closed or the context is done.
+func (n *inotifyNotifier) send(ctx context.Context, c change) bool {
+	select {
+	case n.changes <- c:
+		return true
+	case <-n.done:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
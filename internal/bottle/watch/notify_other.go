@@ -0,0 +1,147 @@
+//go:build !linux
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pollInterval is how often the directory tree is scanned for changes.
+const pollInterval = time.Second
+
+// fileState is the state of a file compared between scans.
+type fileState struct {
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+}
+
+// pollNotifier reports changes by scanning the directory tree.  Files still being written can not be told apart from
+// other changes, so only the quiet interval protects them.
+type pollNotifier struct {
+	root    string
+	changes chan change
+	errs    chan error
+	done    chan struct{}
+}
+
+func newNotifier(ctx context.Context, root string) (notifier, error) {
+	n := &pollNotifier{
+		root:    root,
+		changes: make(chan change),
+		errs:    make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	state, err := n.scan()
+	if err != nil {
+		return nil, err
+	}
+	go n.run(ctx, state)
+	return n, nil
+}
+
+func (n *pollNotifier) Changes() <-chan change {
+	return n.changes
+}
+
+func (n *pollNotifier) Errors() <-chan error {
+	return n.errs
+}
+
+func (n *pollNotifier) Close() error {
+	close(n.done)
+	return nil
+}
+
+// run scans the tree until the notifier is closed, reporting the differences between scans.
+func (n *pollNotifier) run(ctx context.Context, previous map[string]fileState) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := n.scan()
+		if err != nil {
+			n.errs <- err
+			return
+		}
+		for path, state := range current {
+			if old, ok := previous[path]; !ok || old != state {
+				if !n.send(ctx, change{path: path, kind: changeUpdated}) {
+					return
+				}
+			}
+		}
+		for path := range previous {
+			if _, ok := current[path]; !ok {
+				if !n.send(ctx, change{path: path, kind: changeRemoved}) {
+					return
+				}
+			}
+		}
+		previous = current
+	}
+}
+
+// scan returns the state of every file and directory of the tree, by relative path.
+func (n *pollNotifier) scan() (map[string]fileState, error) {
+	state := make(map[string]fileState)
+	err := filepath.WalkDir(n.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil // removed in the meantime
+			}
+			return err
+		}
+		rel, err := filepath.Rel(n.root, path)
+		if err != nil {
+			return fmt.Errorf("resolving watched path: %w", err)
+		}
+		if rel == "." {
+			return nil
+		}
+		if ignored(d.Name()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return fmt.Errorf("reading file information: %w", err)
+		}
+		state[rel] = fileState{size: info.Size(), modTime: info.ModTime(), mode: info.Mode()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning directory tree: %w", err)
+	}
+	return state, nil
+}
+
+// send reports the change, returning false if the notifier is closed or the context is done.
+func (n *pollNotifier) send(ctx context.Context, c change) bool {
+	select {
+	case n.changes <- c:
+		return true
+	case <-n.done:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
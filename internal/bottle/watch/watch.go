@@ -0,0 +1,181 @@
+// Package watch waits for the files of a bottle directory to settle after they change.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/act3-ai/data-tool/internal/bottle/label"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// changeKind is the kind of a change to a file.
+type changeKind int
+
+const (
+	// changeWritten is a file written to, which is still open until it is closed.
+	changeWritten changeKind = iota
+	// changeClosed is a file closed after writing.
+	changeClosed
+	// changeUpdated is a file or directory created, moved or changed in any other way.
+	changeUpdated
+	// changeRemoved is a file or directory removed or moved away.
+	changeRemoved
+)
+
+// change is a change to a file, with a path relative to the watched directory.
+type change struct {
+	path string
+	kind changeKind
+}
+
+// notifier reports changes to the files under a directory, ignoring hidden files and directories.
+type notifier interface {
+	// Changes returns the channel of changes.
+	Changes() <-chan change
+	// Errors returns the channel of errors, after which no more changes are reported.
+	Errors() <-chan error
+	// Close stops reporting changes.
+	Close() error
+}
+
+// Options are the options of Watch.
+type Options struct {
+	// Quiet is how long the files must be unchanged before calling the function.
+	Quiet time.Duration
+
+	// Settled is called when the files have been quiet for Options.Quiet after a change, and none is still being
+	// written.  It is also called after the first quiet interval, to process changes made before watching started.
+	Settled func(ctx context.Context) error
+
+	// Changed is called with the path of each change, if not nil.
+	Changed func(path string)
+}
+
+// Watch monitors the files under dir, calling opts.Settled each time they settle after a change.  Hidden files and
+// directories are ignored, like when committing a bottle, except for part label files and the label rules file.
+// Files written to are considered in use until they are closed, where the platform reports it.  Watch returns when
+// ctx is done, or when opts.Settled fails.
+func Watch(ctx context.Context, dir string, opts Options) error {
+	log := logger.FromContext(ctx)
+
+	n, err := newNotifier(ctx, dir)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+
+	labels, err := labelDigests(dir)
+	if err != nil {
+		return err
+	}
+	writing := make(map[string]bool)
+	timer := time.NewTimer(opts.Quiet)
+	defer timer.Stop()
+	settle := timer.C
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-n.Errors():
+			return err
+		case c := <-n.Changes():
+			if isLabelFile(c.path) && !labels.changed(dir, c.path) {
+				// committing rewrites the label files, which must not trigger another commit
+				delete(writing, c.path)
+				continue
+			}
+			switch c.kind {
+			case changeWritten:
+				writing[c.path] = true
+			case changeClosed, changeRemoved:
+				delete(writing, c.path)
+			}
+			if opts.Changed != nil {
+				opts.Changed(c.path)
+			}
+			timer.Reset(opts.Quiet)
+			settle = timer.C
+		case <-settle:
+			settle = nil
+			if len(writing) > 0 {
+				// closing the last file restarts the quiet interval
+				logger.V(log, 1).InfoContext(ctx, "waiting for files being written", "files", len(writing))
+				continue
+			}
+			if err := opts.Settled(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// labelSums are the digests of the part label files, by relative path.
+type labelSums map[string]digest.Digest
+
+// labelDigests returns the digests of the label files under dir.
+func labelDigests(dir string) (labelSums, error) {
+	sums := make(labelSums)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return fmt.Errorf("resolving watched path: %w", err)
+		}
+		switch {
+		case rel == ".":
+		case ignored(d.Name()) && d.IsDir():
+			return fs.SkipDir
+		case isLabelFile(rel):
+			sums.changed(dir, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading label files: %w", err)
+	}
+	return sums, nil
+}
+
+// changed records the digest of the label file at the relative path, returning true if it differs from the one
+// recorded before.
+func (s labelSums) changed(dir, rel string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, rel))
+	if errors.Is(err, fs.ErrNotExist) {
+		_, ok := s[rel]
+		delete(s, rel)
+		return ok
+	}
+	if err != nil {
+		return true
+	}
+	sum := digest.FromBytes(data)
+	if s[rel] == sum {
+		return false
+	}
+	s[rel] = sum
+	return true
+}
+
+// isLabelFile returns true if the relative path is a part label file.
+func isLabelFile(rel string) bool {
+	name := filepath.Base(rel)
+	return name == label.LabelsFilename || name == label.LabelsFilenameLegacy
+}
+
+// ignored returns true if changes to the file or directory with the name are not reported.
+func ignored(name string) bool {
+	return strings.HasPrefix(name, ".") && name != label.LabelsFilename && name != label.LabelsFilenameLegacy &&
+		name != label.RulesFilename
+}
@@ -0,0 +1,80 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+// startWatch watches dir in the background, counting the calls to Settled.
+func startWatch(t *testing.T, dir string, quiet time.Duration) *atomic.Int32 {
+	t.Helper()
+	ctx, cancel := context.WithCancel(logger.NewContext(context.Background(), tlog.Logger(t, 0)))
+	done := make(chan error)
+	settled := &atomic.Int32{}
+	go func() {
+		done <- Watch(ctx, dir, Options{
+			Quiet: quiet,
+			Settled: func(ctx context.Context) error {
+				settled.Add(1)
+				return nil
+			},
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return settled
+}
+
+func TestWatch(t *testing.T) {
+	quiet := 300 * time.Millisecond
+	if runtime.GOOS != "linux" {
+		quiet = 3 * time.Second // changes are found by polling
+	}
+
+	t.Run("settles after changes", func(t *testing.T) {
+		dir := t.TempDir()
+		settled := startWatch(t, dir, quiet)
+		require.Eventually(t, func() bool { return settled.Load() == 1 }, 4*quiet, quiet/10, "initial settle")
+
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "data", "sub"), 0o777))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "data", "sub", "file.txt"), []byte("data"), 0o666))
+		require.Eventually(t, func() bool { return settled.Load() == 2 }, 4*quiet, quiet/10, "settle after change")
+
+		// hidden files are ignored
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), []byte("data"), 0o666))
+		time.Sleep(2 * quiet)
+		assert.Equal(t, int32(2), settled.Load())
+	})
+
+	t.Run("waits for files being written", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("open files are only detected with inotify")
+		}
+		dir := t.TempDir()
+		settled := startWatch(t, dir, quiet)
+		require.Eventually(t, func() bool { return settled.Load() == 1 }, 4*quiet, quiet/10, "initial settle")
+
+		f, err := os.Create(filepath.Join(dir, "log.csv"))
+		require.NoError(t, err)
+		_, err = f.WriteString("a,b\n")
+		require.NoError(t, err)
+		time.Sleep(3 * quiet)
+		assert.Equal(t, int32(1), settled.Load(), "settled while a file is open")
+
+		require.NoError(t, f.Close())
+		require.Eventually(t, func() bool { return settled.Load() == 2 }, 4*quiet, quiet/10, "settle after close")
+	})
+}
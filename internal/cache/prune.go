@@ -18,8 +18,11 @@ import (
 	"github.com/act3-ai/go-common/pkg/fsutil"
 )
 
+// StatsDir is the directory of the cache holding the statistics of part content, by content digest.
+const StatsDir = "stats"
+
 // Prune removes files until the total size of the cache is less than or
-// equal to maxSize.
+// equal to maxSize.  Cached part statistics are removed along with the blobs.
 func Prune(ctx context.Context, root string, maxSize int64) error {
 	// sanity
 	if root == "" {
@@ -33,6 +36,9 @@ func Prune(ctx context.Context, root string, maxSize int64) error {
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
 			return fmt.Errorf("removing entire cache: %w", err)
 		}
+		if err := os.RemoveAll(filepath.Join(root, StatsDir)); err != nil {
+			return fmt.Errorf("removing entire cache: %w", err)
+		}
 		return nil
 	}
 
@@ -43,6 +49,13 @@ func Prune(ctx context.Context, root string, maxSize int64) error {
 	if err != nil {
 		return fmt.Errorf("evaluating cache directory: %w", err)
 	}
+	statsPath := filepath.Join(root, StatsDir)
+	statsSize, statsInfos, err := evalDir(filepath.ToSlash(statsPath))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("evaluating cache directory: %w", err)
+	}
+	curSize += statsSize
+	finfos = append(finfos, statsInfos...)
 	// short circuit
 	if curSize <= maxSize {
 		return nil
@@ -71,7 +84,11 @@ Pruning:
 		}
 		sz := info.Size()
 
-		err = deleteBlob(root, digest.Digest(filepath.Base(filepath.Dir(info.path))+":"+info.Name()))
+		if strings.HasPrefix(info.path, statsPath+string(filepath.Separator)) {
+			err = os.Remove(info.path)
+		} else {
+			err = deleteBlob(root, digest.Digest(filepath.Base(filepath.Dir(info.path))+":"+info.Name()))
+		}
 		switch {
 		case errors.Is(err, fs.ErrPermission):
 			// permission denied occurs if file is locked, just skip to the next file
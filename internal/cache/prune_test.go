@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneStats(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (string, string) {
+		t.Helper()
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "blobs", "sha256"), 0o777))
+		statsFile := filepath.Join(root, StatsDir, "sha256", "0123.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(statsFile), 0o777))
+		require.NoError(t, os.WriteFile(statsFile, make([]byte, 1024), 0o666))
+		return root, statsFile
+	}
+
+	t.Run("entire cache", func(t *testing.T) {
+		root, _ := setup(t)
+		require.NoError(t, Prune(ctx, root, 0))
+		assert.NoDirExists(t, filepath.Join(root, StatsDir))
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		root, statsFile := setup(t)
+		require.NoError(t, Prune(ctx, root, 2048))
+		assert.FileExists(t, statsFile)
+
+		require.NoError(t, Prune(ctx, root, 512))
+		assert.NoFileExists(t, statsFile)
+	})
+}
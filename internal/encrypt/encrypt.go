@@ -0,0 +1,251 @@
+// Package encrypt implements encryption of OCI layers following the OCI image encryption conventions of ocicrypt.
+// Layers are encrypted with AES-256-CTR and authenticated with HMAC-SHA256.  The symmetric key is wrapped for each
+// recipient, with JWE for public keys and PKCS#7 for X.509 certificates, and recorded in the layer annotations.
+package encrypt
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Layer annotations and media types defined by the OCI image encryption conventions.
+const (
+	// MediaTypeSuffix is appended to the media type of an encrypted layer.
+	MediaTypeSuffix = "+encrypted"
+
+	// AnnotationJWEKeys holds the comma separated, base64 encoded JWE wrapped keys.
+	AnnotationJWEKeys = "org.opencontainers.image.enc.keys.jwe"
+	// AnnotationPKCS7Keys holds the comma separated, base64 encoded PKCS#7 wrapped keys.
+	AnnotationPKCS7Keys = "org.opencontainers.image.enc.keys.pkcs7"
+	// AnnotationPublicOptions holds the base64 encoded public cipher options, including the HMAC.
+	AnnotationPublicOptions = "org.opencontainers.image.enc.pubopts"
+
+	// CipherAES256CTR is the layer cipher.
+	CipherAES256CTR = "AES_256_CTR_HMAC_SHA256"
+)
+
+var (
+	// ErrNoRecipients is returned when encrypting without recipients.
+	ErrNoRecipients = errors.New("no encryption recipients")
+	// ErrNoDecryptionKey is returned when none of the keys can decrypt a layer.
+	ErrNoDecryptionKey = errors.New("no decryption key for the layer recipients")
+	// ErrAuthentication is returned when the HMAC of an encrypted layer does not match.
+	ErrAuthentication = errors.New("encrypted layer failed authentication")
+)
+
+// publicOptions are the cipher options that are stored in the clear.
+type publicOptions struct {
+	Cipher        string            `json:"cipher"`
+	HMAC          []byte            `json:"hmac"`
+	CipherOptions map[string][]byte `json:"cipheroptions"`
+}
+
+// privateOptions are the cipher options that are wrapped for each recipient.
+type privateOptions struct {
+	SymmetricKey  []byte            `json:"symkey"`
+	Digest        digest.Digest     `json:"digest"`
+	CipherOptions map[string][]byte `json:"cipheroptions"`
+}
+
+// IsEncrypted returns true if the media type is an encrypted layer media type.
+func IsEncrypted(mediaType string) bool {
+	return strings.HasSuffix(mediaType, MediaTypeSuffix)
+}
+
+// EncryptedMediaType returns the media type of the encrypted form of a layer.
+func EncryptedMediaType(mediaType string) string {
+	if IsEncrypted(mediaType) {
+		return mediaType
+	}
+	return mediaType + MediaTypeSuffix
+}
+
+// DecryptedMediaType returns the media type of the decrypted form of a layer.
+func DecryptedMediaType(mediaType string) string {
+	return strings.TrimSuffix(mediaType, MediaTypeSuffix)
+}
+
+// Encrypt encrypts the layer read from r, with the digest dgst, writing the ciphertext to w.  The returned
+// annotations must be added to the encrypted layer descriptor.
+func Encrypt(w io.Writer, r io.Reader, dgst digest.Digest, recipients []Recipient) (map[string]string, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	symKey := make([]byte, 32)
+	if _, err := rand.Read(symKey); err != nil {
+		return nil, fmt.Errorf("generating layer key: %w", err)
+	}
+	nonce := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating layer nonce: %w", err)
+	}
+
+	annotations, err := wrapKeys(privateOptions{
+		SymmetricKey:  symKey,
+		Digest:        dgst,
+		CipherOptions: map[string][]byte{"nonce": nonce},
+	}, recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(symKey)
+	if err != nil {
+		return nil, fmt.Errorf("initializing layer cipher: %w", err)
+	}
+	mac := hmac.New(sha256.New, symKey)
+	out := &cipher.StreamWriter{S: cipher.NewCTR(block, nonce), W: io.MultiWriter(w, mac)}
+	if _, err := io.Copy(out, r); err != nil {
+		return nil, fmt.Errorf("encrypting layer: %w", err)
+	}
+
+	pubOpts, err := json.Marshal(publicOptions{
+		Cipher:        CipherAES256CTR,
+		HMAC:          mac.Sum(nil),
+		CipherOptions: map[string][]byte{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding public cipher options: %w", err)
+	}
+	annotations[AnnotationPublicOptions] = base64.StdEncoding.EncodeToString(pubOpts)
+	return annotations, nil
+}
+
+// Decrypt returns a reader for the decrypted layer read from r, and the digest of the decrypted layer.  The annotations
+// are those of the encrypted layer descriptor.  The HMAC is verified when the returned reader reaches the end of the
+// layer, so the content must not be trusted before then.
+func Decrypt(r io.Reader, annotations map[string]string, keys []crypto.PrivateKey) (io.Reader, digest.Digest, error) {
+	pubData, err := base64.StdEncoding.DecodeString(annotations[AnnotationPublicOptions])
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding public cipher options: %w", err)
+	}
+	var pubOpts publicOptions
+	if err := json.Unmarshal(pubData, &pubOpts); err != nil {
+		return nil, "", fmt.Errorf("parsing public cipher options: %w", err)
+	}
+	if pubOpts.Cipher != CipherAES256CTR {
+		return nil, "", fmt.Errorf("unsupported layer cipher %q", pubOpts.Cipher)
+	}
+
+	privOpts, err := unwrapKeys(annotations, keys)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := privOpts.Digest.Validate(); err != nil {
+		return nil, "", fmt.Errorf("invalid decrypted layer digest: %w", err)
+	}
+
+	block, err := aes.NewCipher(privOpts.SymmetricKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("initializing layer cipher: %w", err)
+	}
+	nonce := privOpts.CipherOptions["nonce"]
+	if len(nonce) != aes.BlockSize {
+		return nil, "", fmt.Errorf("invalid layer nonce length %d", len(nonce))
+	}
+
+	mac := hmac.New(sha256.New, privOpts.SymmetricKey)
+	authenticated := &macReader{r: io.TeeReader(r, mac), mac: mac, expected: pubOpts.HMAC}
+	return &cipher.StreamReader{S: cipher.NewCTR(block, nonce), R: authenticated}, privOpts.Digest, nil
+}
+
+// macReader verifies the HMAC of the data read once the end of the data is reached.
+type macReader struct {
+	r        io.Reader
+	mac      hash.Hash
+	expected []byte
+}
+
+func (m *macReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	if errors.Is(err, io.EOF) && !hmac.Equal(m.mac.Sum(nil), m.expected) {
+		return n, ErrAuthentication
+	}
+	return n, err //nolint:wrapcheck
+}
+
+// wrapKeys wraps the private options for the recipients, returning the key annotations.
+func wrapKeys(opts privateOptions, recipients []Recipient) (map[string]string, error) {
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("encoding private cipher options: %w", err)
+	}
+
+	var publicKeys []crypto.PublicKey
+	var certs []*x509.Certificate
+	for _, r := range recipients {
+		if r.certificate != nil {
+			certs = append(certs, r.certificate)
+		} else {
+			publicKeys = append(publicKeys, r.publicKey)
+		}
+	}
+
+	annotations := make(map[string]string, 3)
+	if len(publicKeys) > 0 {
+		jwe, err := wrapJWE(data, publicKeys)
+		if err != nil {
+			return nil, err
+		}
+		annotations[AnnotationJWEKeys] = base64.StdEncoding.EncodeToString(jwe)
+	}
+	if len(certs) > 0 {
+		envelope, err := wrapPKCS7(data, certs)
+		if err != nil {
+			return nil, err
+		}
+		annotations[AnnotationPKCS7Keys] = base64.StdEncoding.EncodeToString(envelope)
+	}
+	return annotations, nil
+}
+
+// unwrapKeys unwraps the private options with the first key that matches a recipient.
+func unwrapKeys(annotations map[string]string, keys []crypto.PrivateKey) (privateOptions, error) {
+	unwrappers := []struct {
+		annotation string
+		unwrap     func(data []byte, key crypto.PrivateKey) ([]byte, error)
+	}{
+		{AnnotationJWEKeys, unwrapJWE},
+		{AnnotationPKCS7Keys, unwrapPKCS7},
+	}
+
+	for _, u := range unwrappers {
+		value := annotations[u.annotation]
+		if value == "" {
+			continue
+		}
+		for _, wrapped := range strings.Split(value, ",") {
+			data, err := base64.StdEncoding.DecodeString(wrapped)
+			if err != nil {
+				return privateOptions{}, fmt.Errorf("decoding wrapped keys: %w", err)
+			}
+			for _, key := range keys {
+				optsData, err := u.unwrap(data, key)
+				if err != nil {
+					continue // not a recipient
+				}
+				var opts privateOptions
+				if err := json.Unmarshal(optsData, &opts); err != nil {
+					return privateOptions{}, fmt.Errorf("parsing private cipher options: %w", err)
+				}
+				return opts, nil
+			}
+		}
+	}
+	return privateOptions{}, ErrNoDecryptionKey
+}
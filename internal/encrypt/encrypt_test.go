@@ -0,0 +1,129 @@
+package encrypt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaRecipient(t *testing.T) (Recipient, crypto.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	r, err := ParseRecipient(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	return r, key
+}
+
+func ecRecipient(t *testing.T) (Recipient, crypto.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	// a private key file may be used as a recipient
+	r, err := ParseRecipient(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	return r, key
+}
+
+func certRecipient(t *testing.T) (Recipient, crypto.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "recipient"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	r, err := ParseRecipient(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	require.NoError(t, err)
+	return r, key
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	rsaRcpt, rsaKey := rsaRecipient(t)
+	ecRcpt, ecKey := ecRecipient(t)
+	certRcpt, certKey := certRecipient(t)
+	_, otherKey := rsaRecipient(t)
+
+	layer := bytes.Repeat([]byte("layer data "), 1000)
+	dgst := digest.FromBytes(layer)
+
+	ciphertext := new(bytes.Buffer)
+	annotations, err := Encrypt(ciphertext, bytes.NewReader(layer), dgst, []Recipient{rsaRcpt, ecRcpt, certRcpt})
+	require.NoError(t, err)
+	assert.Contains(t, annotations, AnnotationJWEKeys)
+	assert.Contains(t, annotations, AnnotationPKCS7Keys)
+	assert.Contains(t, annotations, AnnotationPublicOptions)
+	assert.Len(t, ciphertext.Bytes(), len(layer))
+	assert.NotEqual(t, layer, ciphertext.Bytes())
+
+	for name, key := range map[string]crypto.PrivateKey{"jwe rsa": rsaKey, "jwe ec": ecKey, "pkcs7": certKey} {
+		t.Run(name, func(t *testing.T) {
+			r, decDigest, err := Decrypt(bytes.NewReader(ciphertext.Bytes()), annotations, []crypto.PrivateKey{otherKey, key})
+			require.NoError(t, err)
+			assert.Equal(t, dgst, decDigest)
+			plaintext, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, layer, plaintext)
+		})
+	}
+
+	t.Run("no key", func(t *testing.T) {
+		_, _, err := Decrypt(bytes.NewReader(ciphertext.Bytes()), annotations, []crypto.PrivateKey{otherKey})
+		require.ErrorIs(t, err, ErrNoDecryptionKey)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Clone(ciphertext.Bytes())
+		tampered[10] ^= 0xff
+		r, _, err := Decrypt(bytes.NewReader(tampered), annotations, []crypto.PrivateKey{rsaKey})
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrAuthentication)
+	})
+
+	t.Run("no recipients", func(t *testing.T) {
+		_, err := Encrypt(io.Discard, bytes.NewReader(layer), dgst, nil)
+		require.ErrorIs(t, err, ErrNoRecipients)
+	})
+}
+
+func TestRecipientString(t *testing.T) {
+	for _, setup := range []func(*testing.T) (Recipient, crypto.PrivateKey){rsaRecipient, ecRecipient, certRecipient} {
+		r, _ := setup(t)
+		parsed, err := ParseRecipient([]byte(r.String()))
+		require.NoError(t, err)
+		assert.Equal(t, r.String(), parsed.String())
+	}
+}
+
+func TestMediaTypes(t *testing.T) {
+	mt := "application/vnd.act3-ace.bottle.layer.v1.tar+zstd"
+	enc := EncryptedMediaType(mt)
+	assert.Equal(t, mt+MediaTypeSuffix, enc)
+	assert.True(t, IsEncrypted(enc))
+	assert.False(t, IsEncrypted(mt))
+	assert.Equal(t, enc, EncryptedMediaType(enc))
+	assert.Equal(t, mt, DecryptedMediaType(enc))
+}
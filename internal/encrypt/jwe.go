@@ -0,0 +1,59 @@
+package encrypt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// jweKeyAlgorithms are the key management algorithms accepted when unwrapping keys.
+var jweKeyAlgorithms = []jose.KeyAlgorithm{
+	jose.RSA_OAEP, jose.RSA_OAEP_256,
+	jose.ECDH_ES_A128KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A256KW,
+}
+
+// jweContentEncryption are the content encryption algorithms accepted when unwrapping keys.
+var jweContentEncryption = []jose.ContentEncryption{jose.A128GCM, jose.A192GCM, jose.A256GCM}
+
+// wrapJWE encrypts data for the public keys as a JWE in the JSON serialization.
+func wrapJWE(data []byte, keys []crypto.PublicKey) ([]byte, error) {
+	recipients := make([]jose.Recipient, 0, len(keys))
+	for _, key := range keys {
+		var alg jose.KeyAlgorithm
+		switch key.(type) {
+		case *rsa.PublicKey:
+			alg = jose.RSA_OAEP
+		case *ecdsa.PublicKey:
+			alg = jose.ECDH_ES_A256KW
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", key)
+		}
+		recipients = append(recipients, jose.Recipient{Algorithm: alg, Key: key})
+	}
+
+	encrypter, err := jose.NewMultiEncrypter(jose.A256GCM, recipients, nil)
+	if err != nil {
+		return nil, fmt.Errorf("initializing JWE encrypter: %w", err)
+	}
+	obj, err := encrypter.Encrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping keys with JWE: %w", err)
+	}
+	return []byte(obj.FullSerialize()), nil
+}
+
+// unwrapJWE decrypts a JWE with the private key.
+func unwrapJWE(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	obj, err := jose.ParseEncrypted(string(data), jweKeyAlgorithms, jweContentEncryption)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JWE: %w", err)
+	}
+	_, _, plaintext, err := obj.DecryptMulti(key)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping keys with JWE: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,142 @@
+package encrypt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Recipient is a recipient of encrypted layers, either a public key or an X.509 certificate.
+type Recipient struct {
+	publicKey   crypto.PublicKey
+	certificate *x509.Certificate
+
+	// encoded is the PEM encoding of the recipient
+	encoded string
+}
+
+// String returns the PEM encoding of the recipient, suitable for ParseRecipient.
+func (r Recipient) String() string {
+	return r.encoded
+}
+
+// LoadRecipient loads a recipient from a PEM encoded public key, certificate, or private key file.
+func LoadRecipient(path string) (Recipient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Recipient{}, fmt.Errorf("reading recipient: %w", err)
+	}
+	r, err := ParseRecipient(data)
+	if err != nil {
+		return Recipient{}, fmt.Errorf("recipient %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ParseRecipient parses a PEM encoded public key, certificate, or private key as a recipient.  Keys are wrapped with
+// JWE for public and private keys, and with PKCS#7 for certificates.
+func ParseRecipient(data []byte) (Recipient, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Recipient{}, errors.New("no PEM data found")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return Recipient{}, fmt.Errorf("parsing certificate: %w", err)
+		}
+		if _, ok := cert.PublicKey.(*rsa.PublicKey); !ok {
+			return Recipient{}, fmt.Errorf("unsupported certificate key type %T, PKCS#7 recipients must use RSA", cert.PublicKey)
+		}
+		return Recipient{certificate: cert, encoded: string(pem.EncodeToMemory(block))}, nil
+	case "PUBLIC KEY", "RSA PUBLIC KEY":
+		var key crypto.PublicKey
+		var err error
+		if block.Type == "RSA PUBLIC KEY" {
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		} else {
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		}
+		if err != nil {
+			return Recipient{}, fmt.Errorf("parsing public key: %w", err)
+		}
+		return publicKeyRecipient(key)
+	default:
+		key, err := parsePrivateKeyBlock(block)
+		if err != nil {
+			return Recipient{}, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return Recipient{}, fmt.Errorf("unsupported key type %T", key)
+		}
+		return publicKeyRecipient(signer.Public())
+	}
+}
+
+// publicKeyRecipient returns a JWE recipient for the public key.
+func publicKeyRecipient(key crypto.PublicKey) (Recipient, error) {
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return Recipient{}, fmt.Errorf("unsupported public key type %T", key)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return Recipient{}, fmt.Errorf("encoding public key: %w", err)
+	}
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return Recipient{publicKey: key, encoded: string(encoded)}, nil
+}
+
+// LoadPrivateKey loads a PEM encoded private key used for decrypting layers.
+func LoadPrivateKey(path string) (crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading decryption key: %w", err)
+	}
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("decryption key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS#8, PKCS#1, or EC private key.
+func ParsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	return parsePrivateKeyBlock(block)
+}
+
+func parsePrivateKeyBlock(block *pem.Block) (crypto.PrivateKey, error) {
+	var key crypto.PrivateKey
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		return nil, errors.New("unsupported key type ed25519")
+	}
+	return key, nil
+}
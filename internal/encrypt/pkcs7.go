@@ -0,0 +1,211 @@
+package encrypt
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The subset of PKCS#7 (RFC 2315) enveloped data used for wrapping keys for certificate recipients.  Content is
+// encrypted with AES-GCM (RFC 5084) and the content key is transported with RSA PKCS#1 v1.5.
+
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+	oidRSAEncryption = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidAES128GCM     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 6}
+	oidAES256GCM     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 46}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type envelopedData struct {
+	Version              int
+	RecipientInfos       []recipientInfo `asn1:"set"`
+	EncryptedContentInfo encryptedContentInfo
+}
+
+type recipientInfo struct {
+	Version                int
+	IssuerAndSerialNumber  issuerAndSerial
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+type issuerAndSerial struct {
+	IssuerName   asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           asn1.RawValue `asn1:"tag:0,optional"`
+}
+
+type gcmParameters struct {
+	Nonce  []byte
+	ICVLen int
+}
+
+// wrapPKCS7 encrypts data for the certificates as PKCS#7 enveloped data.
+func wrapPKCS7(data []byte, certs []*x509.Certificate) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating content key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating content nonce: %w", err)
+	}
+	params, err := asn1.Marshal(gcmParameters{Nonce: nonce, ICVLen: gcm.Overhead()})
+	if err != nil {
+		return nil, fmt.Errorf("encoding content encryption parameters: %w", err)
+	}
+
+	recipients := make([]recipientInfo, 0, len(certs))
+	for _, cert := range certs {
+		pub, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported certificate key type %T", cert.PublicKey)
+		}
+		encryptedKey, err := rsa.EncryptPKCS1v15(rand.Reader, pub, key)
+		if err != nil {
+			return nil, fmt.Errorf("encrypting content key: %w", err)
+		}
+		recipients = append(recipients, recipientInfo{
+			IssuerAndSerialNumber: issuerAndSerial{
+				IssuerName:   asn1.RawValue{FullBytes: cert.RawIssuer},
+				SerialNumber: cert.SerialNumber,
+			},
+			KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue},
+			EncryptedKey:           encryptedKey,
+		})
+	}
+
+	envelope, err := asn1.Marshal(envelopedData{
+		RecipientInfos: recipients,
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType: oidData,
+			ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+				Algorithm:  oidAES256GCM,
+				Parameters: asn1.RawValue{FullBytes: params},
+			},
+			EncryptedContent: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: gcm.Seal(nil, nonce, data, nil)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding enveloped data: %w", err)
+	}
+
+	out, err := asn1.Marshal(contentInfo{
+		ContentType: oidEnvelopedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: envelope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding content info: %w", err)
+	}
+	return out, nil
+}
+
+// unwrapPKCS7 decrypts PKCS#7 enveloped data with the private key, which must be an RSA key.
+func unwrapPKCS7(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported PKCS#7 key type %T", key)
+	}
+
+	var info contentInfo
+	if _, err := asn1.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing content info: %w", err)
+	}
+	if !info.ContentType.Equal(oidEnvelopedData) {
+		return nil, fmt.Errorf("unsupported PKCS#7 content type %s", info.ContentType)
+	}
+	var env envelopedData
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &env); err != nil {
+		return nil, fmt.Errorf("parsing enveloped data: %w", err)
+	}
+
+	eci := env.EncryptedContentInfo
+	alg := eci.ContentEncryptionAlgorithm.Algorithm
+	if !alg.Equal(oidAES128GCM) && !alg.Equal(oidAES256GCM) {
+		return nil, fmt.Errorf("unsupported PKCS#7 content encryption algorithm %s", alg)
+	}
+	var params gcmParameters
+	if _, err := asn1.Unmarshal(eci.ContentEncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("parsing content encryption parameters: %w", err)
+	}
+	ciphertext, err := encryptedContent(eci.EncryptedContent)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range env.RecipientInfos {
+		if !r.KeyEncryptionAlgorithm.Algorithm.Equal(oidRSAEncryption) {
+			continue
+		}
+		contentKey, err := rsa.DecryptPKCS1v15(nil, rsaKey, r.EncryptedKey)
+		if err != nil {
+			continue // not this recipient
+		}
+		gcm, err := newGCM(contentKey)
+		if err != nil {
+			continue
+		}
+		plaintext, err := gcm.Open(nil, params.Nonce, ciphertext, nil)
+		if err != nil {
+			continue
+		}
+		return plaintext, nil
+	}
+	return nil, ErrNoDecryptionKey
+}
+
+// encryptedContent returns the encrypted content, which may be encoded as a primitive or constructed octet string.
+func encryptedContent(v asn1.RawValue) ([]byte, error) {
+	if !v.IsCompound {
+		return v.Bytes, nil
+	}
+	var content []byte
+	for rest := v.Bytes; len(rest) > 0; {
+		var segment []byte
+		var err error
+		rest, err = asn1.Unmarshal(rest, &segment)
+		if err != nil {
+			return nil, fmt.Errorf("parsing encrypted content: %w", err)
+		}
+		content = append(content, segment...)
+	}
+	return content, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, errors.New("invalid content key length")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("initializing content cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("initializing content cipher: %w", err)
+	}
+	return gcm, nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"slices"
 	"strings"
 	"sync"
@@ -43,6 +44,11 @@ type GatherOptions struct {
 	DestReference  registry.Reference
 	Recursive      bool
 	Targeter       reg.GraphTargeter
+
+	// Screen, if set, is called for each source before it is copied, with the source name and its reference pinned
+	// to the resolved digest.  Sources it rejects are not gathered, the annotations it returns are added to the
+	// gathered manifests of the source.
+	Screen func(ctx context.Context, name, reference string) (bool, map[string]string, error)
 }
 
 // Gather will take the references defined in a SourceFile and consolidate them to a destination target.
@@ -99,6 +105,21 @@ func Gather(ctx context.Context, dataToolVersion string, opts GatherOptions) (oc
 				return err
 			}
 
+			var screenAnnotations map[string]string
+			if opts.Screen != nil {
+				pinned := srcRef
+				pinned.Reference = desc.Digest.String()
+				keep, annotations, err := opts.Screen(gctx, src.Name, pinned.String())
+				if err != nil {
+					return fmt.Errorf("screening %s: %w", src.Name, err)
+				}
+				if !keep {
+					task.Infof("Dropped %s", src.Name)
+					return nil
+				}
+				screenAnnotations = annotations
+			}
+
 			copyOpts := oras.CopyGraphOptions{
 				MountFrom: mountFrom(srcRef, opts.DestReference),
 				OnMounted: onMounted(opts.Log),
@@ -126,6 +147,7 @@ func Gather(ctx context.Context, dataToolVersion string, opts GatherOptions) (oc
 				if err != nil {
 					return err
 				}
+				maps.Copy(desc.Annotations, screenAnnotations)
 				// count bytes
 				if err := extractBlobs(ctx, bt.AddDescriptor, opts.DestStorage, desc); err != nil {
 					return fmt.Errorf("counting bytes: %w", err)
@@ -141,6 +163,7 @@ func Gather(ctx context.Context, dataToolVersion string, opts GatherOptions) (oc
 					if err != nil {
 						return err
 					}
+					maps.Copy(d.Annotations, screenAnnotations)
 					// count bytes
 					if err := extractBlobs(ctx, bt.AddDescriptor, opts.DestStorage, d); err != nil {
 						return fmt.Errorf("counting bytes: %w", err)
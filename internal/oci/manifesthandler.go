@@ -16,6 +16,7 @@ import (
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/act3-ai/bottle-schema/pkg/validation"
+	"github.com/act3-ai/data-tool/internal/encrypt"
 	"github.com/act3-ai/data-tool/internal/ref"
 )
 
@@ -209,7 +210,14 @@ func MakeManifest(config ocispec.Descriptor, layers []ocispec.Descriptor, artifa
 	if artifactType != "" {
 		manifest.ArtifactType = artifactType
 	}
-	if err := validation.ValidateManifest(manifest); err != nil {
+	// encrypted layers are validated as the layers they decrypt to
+	validated := manifest
+	validated.Layers = make([]ocispec.Descriptor, len(layers))
+	for i, layer := range layers {
+		layer.MediaType = encrypt.DecryptedMediaType(layer.MediaType)
+		validated.Layers[i] = layer
+	}
+	if err := validation.ValidateManifest(validated); err != nil {
 		return nil, fmt.Errorf("validating manifest: %w", err)
 	}
 	return json.Marshal(manifest)
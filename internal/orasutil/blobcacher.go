@@ -67,6 +67,11 @@ func (c *CachedGraphTarget) Push(ctx context.Context, expected ocispec.Descripto
 	return push(ctx, c.Cache, expected, content, c.GraphTarget.Push)
 }
 
+// Mount mounts blobs in the remote from another repository, without caching them.
+func (c *CachedGraphTarget) Mount(ctx context.Context, desc ocispec.Descriptor, fromRepo string, getContent func() (io.ReadCloser, error)) error {
+	return Mount(ctx, c.GraphTarget, desc, fromRepo, getContent)
+}
+
 // fetch prefers to read from a local cache (storage), falling back to the
 // remoteFetcherFn on misses.
 func fetch(ctx context.Context, storage orascontent.Storage, target ocispec.Descriptor, remoteFetcherFn func(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error)) (io.ReadCloser, error) {
@@ -0,0 +1,30 @@
+package orasutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
+)
+
+// Mount mounts a blob into the target from another repository of the same registry if the target supports
+// mounting.  Otherwise the content is copied from getContent, as registries do when they are unable to mount.
+// Wrappers of repositories use it to keep supporting registry.Mounter.
+func Mount(ctx context.Context, target content.Pusher, desc ocispec.Descriptor, fromRepo string, getContent func() (io.ReadCloser, error)) error {
+	if mounter, ok := target.(registry.Mounter); ok {
+		return mounter.Mount(ctx, desc, fromRepo, getContent) //nolint:wrapcheck
+	}
+	if getContent == nil {
+		return fmt.Errorf("mounting blob from %s: %w", fromRepo, errdef.ErrUnsupported)
+	}
+	rc, err := getContent()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return target.Push(ctx, desc, rc) //nolint:wrapcheck
+}
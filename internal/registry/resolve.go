@@ -3,12 +3,14 @@ package registry
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/url"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/registry"
 
+	"github.com/act3-ai/data-tool/internal/orasutil"
 	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
 	reg "github.com/act3-ai/data-tool/pkg/registry"
 )
@@ -85,6 +87,11 @@ func (er *endpointResolver) Resolve(ctx context.Context, reference string) (ocis
 	return er.GraphTarget.Resolve(ctx, ref.String()) //nolint
 }
 
+// Mount passes cross-repository mounts through to the wrapped target.
+func (er *endpointResolver) Mount(ctx context.Context, desc ocispec.Descriptor, fromRepo string, getContent func() (io.ReadCloser, error)) error {
+	return orasutil.Mount(ctx, er.GraphTarget, desc, fromRepo, getContent)
+}
+
 // ResolveEndpoint checks for alternative registry endpoints in an RegistryConfig.
 // It returns the original endpoint if one was not found.
 // Currently only supports handling the first endpoint in the config.
@@ -0,0 +1,53 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// DeleteTag removes a tag from the repository, leaving the tagged manifest in place, with the
+// DELETE /v2/<name>/manifests/<tag> endpoint of the OCI distribution specification v1.1.  Registries may not support
+// deleting tags, errdef.ErrUnsupported is returned by those that do not.  They remove the tags of a manifest when the
+// manifest is deleted.
+func DeleteTag(ctx context.Context, repo *remote.Repository, tag string) error {
+	ref := repo.Reference
+	ref.Reference = tag
+	if err := ref.ValidateReferenceAsTag(); err != nil {
+		return fmt.Errorf("invalid tag %q: %w", tag, err)
+	}
+
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, ref.Host(), ref.Repository, tag)
+
+	ctx = auth.AppendRepositoryScope(ctx, ref, auth.ActionDelete)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating tag delete request: %w", err)
+	}
+	client := repo.Client
+	if client == nil {
+		client = auth.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("deleting tag %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		return nil
+	case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusNotFound:
+		return fmt.Errorf("deleting tag %s: %s: %w", ref, resp.Status, errdef.ErrUnsupported)
+	default:
+		return fmt.Errorf("deleting tag %s: unexpected response %s", ref, resp.Status)
+	}
+}
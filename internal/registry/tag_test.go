@@ -0,0 +1,48 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+func TestDeleteTag(t *testing.T) {
+	ctx := context.Background()
+	status := http.StatusAccepted
+	var path string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "unexpected method", http.StatusBadRequest)
+			return
+		}
+		path = r.URL.Path
+		w.WriteHeader(status)
+	}))
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+
+	repo, err := remote.NewRepository(u.Host + "/library/app")
+	require.NoError(t, err)
+	repo.PlainHTTP = true
+
+	require.NoError(t, DeleteTag(ctx, repo, "v1"))
+	assert.Equal(t, "/v2/library/app/manifests/v1", path)
+
+	status = http.StatusMethodNotAllowed
+	assert.ErrorIs(t, DeleteTag(ctx, repo, "v1"), errdef.ErrUnsupported)
+
+	status = http.StatusConflict
+	err = DeleteTag(ctx, repo, "v1")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errdef.ErrUnsupported)
+
+	assert.Error(t, DeleteTag(ctx, repo, "sha256:0123"), "expected a digest to be rejected as a tag")
+}
@@ -0,0 +1,242 @@
+// Package catalog generates SBOMs of OCI images in-process, cataloging the packages installed in the image filesystem
+// by reading its layers from any OCI storage.
+package catalog
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// ErrNotImage is returned when cataloging a manifest that is not a container image, such as a signature, helm chart
+// or bottle.
+var ErrNotImage = errors.New("not a container image")
+
+const (
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerConfig   = "application/vnd.docker.container.image.v1+json"
+	mediaTypeDockerLayer    = "application/vnd.docker.image.rootfs.diff.tar"
+
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// Catalog is the inventory of packages installed in an image.
+type Catalog struct {
+	// Name of the image, typically its reference
+	Name string
+
+	// Digest of the image manifest
+	Digest digest.Digest
+
+	// Platform of the image, from its config
+	Platform ocispec.Platform
+
+	// Distro is the Linux distribution of the image, nil if it has no os-release file
+	Distro *Distro
+
+	// Packages are the packages installed in the image
+	Packages []Package
+
+	// Uncataloged are the ecosystems of the package manager files found in the image whose packages are not
+	// cataloged, such as ruby or rust
+	Uncataloged []string
+}
+
+// entry is what was cataloged from a file of the image filesystem.
+type entry struct {
+	layer    int
+	distro   *Distro
+	packages []Package
+
+	// uncataloged is the ecosystem of a package manager file that is not cataloged
+	uncataloged string
+}
+
+// Image catalogs the packages installed in the image with the manifest desc, reading the manifest, config and layers
+// from storage.  ErrNotImage is returned if the manifest is not a container image.
+func Image(ctx context.Context, storage content.Fetcher, desc ocispec.Descriptor, name string) (*Catalog, error) {
+	log := logger.FromContext(ctx)
+
+	if desc.MediaType != ocispec.MediaTypeImageManifest && desc.MediaType != mediaTypeDockerManifest {
+		return nil, fmt.Errorf("%s has media type %s: %w", name, desc.MediaType, ErrNotImage)
+	}
+	data, err := content.FetchAll(ctx, storage, desc)
+	if err != nil {
+		return nil, fmt.Errorf("fetching image manifest: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decoding image manifest: %w", err)
+	}
+	if manifest.Config.MediaType != ocispec.MediaTypeImageConfig && manifest.Config.MediaType != mediaTypeDockerConfig {
+		return nil, fmt.Errorf("%s has config media type %s: %w", name, manifest.Config.MediaType, ErrNotImage)
+	}
+	for _, layer := range manifest.Layers {
+		if !isTarLayer(layer.MediaType) {
+			return nil, fmt.Errorf("%s has layer media type %s: %w", name, layer.MediaType, ErrNotImage)
+		}
+	}
+
+	cat := &Catalog{Name: name, Digest: desc.Digest}
+	configData, err := content.FetchAll(ctx, storage, manifest.Config)
+	if err != nil {
+		return nil, fmt.Errorf("fetching image config: %w", err)
+	}
+	if err := json.Unmarshal(configData, &cat.Platform); err != nil {
+		return nil, fmt.Errorf("decoding image config: %w", err)
+	}
+
+	files := map[string]entry{}
+	for i, layer := range manifest.Layers {
+		log.InfoContext(ctx, "cataloging layer", "image", name, "layer", layer.Digest)
+		if err := catalogLayer(ctx, storage, layer, i, files); err != nil {
+			return nil, fmt.Errorf("cataloging layer %s: %w", layer.Digest, err)
+		}
+	}
+
+	// /etc/os-release takes precedence, /usr/lib/os-release is its fallback
+	for _, p := range []string{"/usr/lib/os-release", "/etc/os-release"} {
+		if e, ok := files[p]; ok && e.distro != nil {
+			cat.Distro = e.distro
+		}
+	}
+	for _, e := range files {
+		cat.Packages = append(cat.Packages, e.packages...)
+		if e.uncataloged != "" {
+			cat.Uncataloged = append(cat.Uncataloged, e.uncataloged)
+		}
+	}
+	slices.SortFunc(cat.Packages, comparePackages)
+	cat.Packages = slices.CompactFunc(cat.Packages, func(a, b Package) bool { return comparePackages(a, b) == 0 })
+	slices.Sort(cat.Uncataloged)
+	cat.Uncataloged = slices.Compact(cat.Uncataloged)
+
+	if len(cat.Uncataloged) > 0 {
+		// vulnerabilities of these packages are not found, syft catalogs them
+		log.WarnContext(ctx, "packages of some ecosystems in the image are not cataloged", "image", name, "ecosystems", cat.Uncataloged)
+	}
+	if cat.Distro != nil && !slices.ContainsFunc(cat.Packages, Package.IsOS) {
+		// vulnerabilities of OS packages are not found without them, such as with an unsupported package database
+		log.WarnContext(ctx, "no OS packages cataloged in the image of a Linux distribution", "image", name, "distro", cat.Distro.ID)
+	}
+	log.InfoContext(ctx, "cataloged image", "image", name, "packages", len(cat.Packages))
+	return cat, nil
+}
+
+// isTarLayer returns true if layers with the media type are tar archives, compressed or not.
+func isTarLayer(mediaType string) bool {
+	base, _, _ := strings.Cut(mediaType, "+")
+	switch base {
+	case ocispec.MediaTypeImageLayer, ocispec.MediaTypeImageLayerNonDistributable: //nolint:staticcheck
+		return true
+	}
+	return strings.HasPrefix(mediaType, mediaTypeDockerLayer) ||
+		strings.HasPrefix(mediaType, "application/vnd.docker.image.rootfs.foreign.diff.tar")
+}
+
+// catalogLayer applies the layer to the cataloged files, removing the files it deletes and cataloging the files it
+// adds.
+func catalogLayer(ctx context.Context, storage content.Fetcher, desc ocispec.Descriptor, layer int, files map[string]entry) error {
+	rc, err := storage.Fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("fetching layer: %w", err)
+	}
+	defer rc.Close()
+
+	r, err := decompress(rc)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading layer: %w", err)
+		}
+		name := path.Clean("/" + hdr.Name)
+		dir, base := path.Split(name)
+
+		// whiteouts only delete files of lower layers
+		switch {
+		case base == whiteoutOpaque:
+			removeFiles(files, strings.TrimSuffix(dir, "/"), layer, false)
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			removeFiles(files, dir+strings.TrimPrefix(base, whiteoutPrefix), layer, true)
+			continue
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		delete(files, name)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		e, err := catalogFile(ctx, name, hdr, tr)
+		if err != nil {
+			return err
+		}
+		if e.distro != nil || len(e.packages) > 0 || e.uncataloged != "" {
+			e.layer = layer
+			files[name] = e
+		}
+	}
+}
+
+// removeFiles removes the files under dir of layers below layer, and dir itself if self is set.
+func removeFiles(files map[string]entry, dir string, layer int, self bool) {
+	for name, e := range files {
+		if e.layer < layer && (strings.HasPrefix(name, dir+"/") || (self && name == dir)) {
+			delete(files, name)
+		}
+	}
+}
+
+// decompress returns a reader of the uncompressed layer, detecting the compression from its magic number, since
+// layer media types are not always accurate.
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading layer: %w", err)
+	}
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing layer: %w", err)
+		}
+		return gr, nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing layer: %w", err)
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return io.NopCloser(br), nil
+	}
+}
@@ -0,0 +1,348 @@
+package catalog
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+const dpkgStatus = `Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.2.15-2+b2
+Description: GNU Bourne Again SHell
+ Bash is an sh-compatible command language interpreter.
+
+Package: libc6
+Status: install ok installed
+Architecture: amd64
+Source: glibc (2.36-9+deb12u4)
+Version: 2.36-9+deb12u4
+
+Package: removed
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0
+`
+
+// testFile is a file of a test image layer.
+type testFile struct {
+	name string
+	mode int64
+	data []byte
+}
+
+// tarLayer returns a tar archive of the files, compressed with compress.
+func tarLayer(t *testing.T, compress string, files ...testFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		mode := f.mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: mode, Size: int64(len(f.data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	switch compress {
+	case "gzip":
+		gw := gzip.NewWriter(&out)
+		if _, err := gw.Write(buf.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err := gw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	case "zstd":
+		zw, err := zstd.NewWriter(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := zw.Write(buf.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	default:
+		return buf.Bytes()
+	}
+	return out.Bytes()
+}
+
+// jarFile returns a java archive with the maven metadata of a package.
+func jarFile(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("META-INF/maven/org.apache.commons/commons-text/pom.properties")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("#Generated by Maven\ngroupId=org.apache.commons\nartifactId=commons-text\nversion=1.9\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// fatJarFile returns a Spring Boot jar with the jar of jarFile as a library.
+func fatJarFile(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("BOOT-INF/lib/commons-text-1.9.jar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(jarFile(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pushImage pushes an image of the layers, with the media types, to the storage.
+func pushImage(ctx context.Context, t *testing.T, storage oras.Target, configMediaType string, layers map[string][]byte, order []string) ocispec.Descriptor {
+	t.Helper()
+	configData := []byte(`{"architecture":"amd64","os":"linux"}`)
+	configDesc, err := oras.PushBytes(ctx, storage, configMediaType, configData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var descs []ocispec.Descriptor
+	for _, mediaType := range order {
+		desc, err := oras.PushBytes(ctx, storage, mediaType, layers[mediaType])
+		if err != nil {
+			t.Fatal(err)
+		}
+		descs = append(descs, desc)
+	}
+	desc, err := oras.PackManifest(ctx, storage, oras.PackManifestVersion1_1, "", oras.PackManifestOptions{
+		ConfigDescriptor: &configDesc,
+		Layers:           descs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return desc
+}
+
+func TestImage(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	storage := memory.New()
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	goBinary, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layers := map[string][]byte{
+		ocispec.MediaTypeImageLayer: tarLayer(t, "",
+			testFile{name: "etc/os-release", data: []byte("PRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nNAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\nID=debian\n")},
+			testFile{name: "var/lib/dpkg/status", data: []byte(dpkgStatus)},
+			testFile{name: "usr/lib/python3/dist-packages/Flask_Cors-4.0.0.dist-info/METADATA", data: []byte("Metadata-Version: 2.1\nName: Flask_Cors\nVersion: 4.0.0\nLicense: MIT\n\nA Flask extension.\n")},
+			testFile{name: "app/node_modules/@babel/core/package.json", data: []byte(`{"name":"@babel/core","version":"7.23.0","license":"MIT"}`)},
+			testFile{name: "app/node_modules/left-pad/package.json", data: []byte(`{"name":"left-pad","version":"1.3.0","license":{"type":"WTFPL"}}`)},
+			testFile{name: "opt/cache/node_modules/stale/package.json", data: []byte(`{"name":"stale","version":"0.1.0"}`)},
+			testFile{name: "app/lib/commons-text.jar", data: jarFile(t)},
+			testFile{name: "srv/app.jar", data: fatJarFile(t)},
+			testFile{name: "usr/lib/ruby/gems/3.1.0/specifications/rake-13.0.6.gemspec", data: []byte("Gem::Specification.new do |s|\nend\n")},
+			testFile{name: "usr/local/bin/tool", mode: 0o755, data: goBinary},
+			testFile{name: "usr/local/bin/script.sh", mode: 0o755, data: []byte("#!/bin/sh\necho hello\n")},
+		),
+		// deletes left-pad and everything in /opt/cache
+		ocispec.MediaTypeImageLayerGzip: tarLayer(t, "gzip",
+			testFile{name: "app/node_modules/.wh.left-pad"},
+			testFile{name: "opt/cache/.wh..wh..opq"},
+			testFile{name: "opt/cache/node_modules/fresh/package.json", data: []byte(`{"name":"fresh","version":"2.0.0"}`)},
+		),
+		ocispec.MediaTypeImageLayerZstd: tarLayer(t, "zstd",
+			testFile{name: "usr/lib/python3/dist-packages/requests-2.31.0.dist-info/METADATA", data: []byte("Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\nLicense: Apache 2.0\n")},
+		),
+	}
+	desc := pushImage(ctx, t, storage, ocispec.MediaTypeImageConfig, layers,
+		[]string{ocispec.MediaTypeImageLayer, ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerZstd})
+
+	cat, err := Image(ctx, storage, desc, "reg.example.com/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cat.Distro == nil || cat.Distro.ID != "debian" || cat.Distro.VersionID != "12" {
+		t.Errorf("unexpected distro %+v", cat.Distro)
+	}
+	if cat.Platform.OS != "linux" || cat.Platform.Architecture != "amd64" {
+		t.Errorf("unexpected platform %+v", cat.Platform)
+	}
+
+	purls := map[string]bool{}
+	for _, p := range cat.Packages {
+		purls[p.PURL(cat.Distro)] = true
+	}
+	if !slices.ContainsFunc(cat.Packages, func(p Package) bool {
+		return p.Name == "commons-text" && p.Location == "/srv/app.jar:BOOT-INF/lib/commons-text-1.9.jar"
+	}) {
+		t.Errorf("expected the package of the jar nested in /srv/app.jar in %+v", cat.Packages)
+	}
+	if !slices.Equal(cat.Uncataloged, []string{"ruby"}) {
+		t.Errorf("expected the ruby packages to be reported as uncataloged, got %v", cat.Uncataloged)
+	}
+	for _, purl := range []string{
+		"pkg:deb/debian/bash@5.2.15-2+b2?arch=amd64&distro=debian-12",
+		"pkg:deb/debian/libc6@2.36-9+deb12u4?arch=amd64&distro=debian-12&upstream=glibc%402.36-9%2Bdeb12u4",
+		"pkg:pypi/flask-cors@4.0.0",
+		"pkg:pypi/requests@2.31.0",
+		"pkg:npm/%40babel/core@7.23.0",
+		"pkg:npm/fresh@2.0.0",
+		"pkg:maven/org.apache.commons/commons-text@1.9",
+		"pkg:golang/stdlib@" + runtime.Version()[2:],
+	} {
+		if !purls[purl] {
+			t.Errorf("expected package %s in %v", purl, purls)
+		}
+	}
+	for _, purl := range []string{
+		"pkg:deb/debian/removed@1.0?arch=amd64&distro=debian-12",
+		"pkg:npm/left-pad@1.3.0",
+		"pkg:npm/stale@0.1.0",
+	} {
+		if purls[purl] {
+			t.Errorf("unexpected package %s", purl)
+		}
+	}
+
+	t.Run("spdx", func(t *testing.T) {
+		data, err := cat.Encode(FormatSPDX, "ace-dt-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc spdxDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.SPDXVersion != "SPDX-2.3" || doc.CreationInfo.Creators[0] != "Tool: ace-dt-test" {
+			t.Errorf("unexpected document header %s %v", doc.SPDXVersion, doc.CreationInfo)
+		}
+		// the image and the operating system are packages too
+		if len(doc.Packages) != len(cat.Packages)+2 || len(doc.Relationships) != len(doc.Packages) {
+			t.Errorf("expected %d packages and relationships, got %d and %d", len(cat.Packages)+2, len(doc.Packages), len(doc.Relationships))
+		}
+		i := slices.IndexFunc(doc.Packages, func(p spdxPackage) bool { return p.Name == "requests" })
+		if i < 0 || doc.Packages[i].LicenseDeclared != "NOASSERTION" {
+			t.Error("expected the license of requests, which is not an SPDX identifier, to be NOASSERTION")
+		}
+	})
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		data, err := cat.Encode(FormatCycloneDX, "ace-dt-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc cdxDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.BOMFormat != "CycloneDX" || doc.Metadata.Component.Version != desc.Digest.String() {
+			t.Errorf("unexpected document metadata %+v", doc.Metadata)
+		}
+		if !slices.ContainsFunc(doc.Components, func(c cdxComponent) bool {
+			return c.Type == "operating-system" && c.Name == "debian" && c.Version == "12"
+		}) {
+			t.Error("expected an operating system component")
+		}
+		if !slices.ContainsFunc(doc.Components, func(c cdxComponent) bool {
+			return c.PURL == "pkg:maven/org.apache.commons/commons-text@1.9" && c.Group == "org.apache.commons"
+		}) {
+			t.Error("expected the maven component with its group")
+		}
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		desc := pushImage(ctx, t, storage, "application/vnd.cncf.helm.config.v1+json",
+			map[string][]byte{"application/vnd.cncf.helm.chart.content.v1.tar+gzip": []byte("chart")},
+			[]string{"application/vnd.cncf.helm.chart.content.v1.tar+gzip"})
+		if _, err := Image(ctx, storage, desc, "reg.example.com/chart:v1"); !errors.Is(err, ErrNotImage) {
+			t.Errorf("expected ErrNotImage, got %v", err)
+		}
+	})
+}
+
+func TestImageRPM(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	storage := memory.New()
+
+	// the Berkeley DB rpm database of a RHEL 8 image with libuuid installed
+	rpmDB, err := os.ReadFile(filepath.Join("testdata", "rhel8", "Packages"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers := map[string][]byte{
+		ocispec.MediaTypeImageLayerGzip: tarLayer(t, "gzip",
+			testFile{name: "etc/os-release", data: []byte("NAME=\"Red Hat Enterprise Linux\"\nVERSION_ID=\"8.8\"\nID=\"rhel\"\nID_LIKE=\"fedora\"\n")},
+			testFile{name: "var/lib/rpm/Packages", data: rpmDB},
+		),
+	}
+	desc := pushImage(ctx, t, storage, ocispec.MediaTypeImageConfig, layers, []string{ocispec.MediaTypeImageLayerGzip})
+
+	cat, err := Image(ctx, storage, desc, "reg.example.com/ubi8:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cat.Distro == nil || cat.Distro.ID != "rhel" || cat.Distro.VersionID != "8.8" {
+		t.Errorf("unexpected distro %+v", cat.Distro)
+	}
+	i := slices.IndexFunc(cat.Packages, func(p Package) bool { return p.Name == "libuuid" })
+	if i < 0 {
+		t.Fatalf("expected the libuuid package in %+v", cat.Packages)
+	}
+	p := cat.Packages[i]
+	if !p.IsOS() || !slices.Equal(p.Licenses, []string{"BSD"}) || p.Location != "/var/lib/rpm/Packages" {
+		t.Errorf("unexpected package %+v", p)
+	}
+	want := "pkg:rpm/rhel/libuuid@2.32.1-42.el8_8?arch=x86_64&distro=rhel-8.8&upstream=util-linux-2.32.1-42.el8_8.src.rpm"
+	if purl := p.PURL(cat.Distro); purl != want {
+		t.Errorf("expected package URL %s, got %s", want, purl)
+	}
+
+	// the epoch of the version is a qualifier
+	p.Version = "1:" + p.Version
+	want = "pkg:rpm/rhel/libuuid@2.32.1-42.el8_8?arch=x86_64&distro=rhel-8.8&epoch=1&upstream=util-linux-2.32.1-42.el8_8.src.rpm"
+	if purl := p.PURL(cat.Distro); purl != want {
+		t.Errorf("expected package URL %s, got %s", want, purl)
+	}
+}
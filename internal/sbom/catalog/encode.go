@@ -0,0 +1,277 @@
+package catalog
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Format is an SBOM document format.
+type Format string
+
+// SBOM formats supported.
+const (
+	FormatSPDX      Format = "spdx-json"
+	FormatCycloneDX Format = "cyclonedx-json"
+)
+
+// Formats are the supported SBOM formats.
+var Formats = []Format{FormatSPDX, FormatCycloneDX}
+
+const (
+	// ArtifactTypeSPDX is the artifact type of SPDX SBOMs.
+	ArtifactTypeSPDX = "application/spdx+json"
+	// ArtifactTypeCycloneDX is the artifact type of CycloneDX SBOMs.
+	ArtifactTypeCycloneDX = "application/vnd.cyclonedx+json"
+)
+
+// ArtifactType returns the artifact type of SBOMs in the format.
+func (f Format) ArtifactType() string {
+	if f == FormatCycloneDX {
+		return ArtifactTypeCycloneDX
+	}
+	return ArtifactTypeSPDX
+}
+
+// spdxLicense matches license expressions made of SPDX license identifiers, the only values allowed in SPDX license
+// fields.
+var spdxLicense = regexp.MustCompile(`^[A-Za-z0-9.+-]+( (AND|OR|WITH) [A-Za-z0-9.+-]+)*$`)
+
+// Encode encodes the catalog as an SBOM document in the format, created by the tool, such as "ace-dt-v1.2.3".
+func (c *Catalog) Encode(format Format, tool string) ([]byte, error) {
+	var doc any
+	switch format {
+	case FormatSPDX:
+		doc = c.spdx(tool)
+	case FormatCycloneDX:
+		doc = c.cycloneDX(tool)
+	default:
+		return nil, fmt.Errorf("unknown SBOM format %q, expected one of %v", format, Formats)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding SBOM: %w", err)
+	}
+	return data, nil
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	SourceInfo            string            `json:"sourceInfo,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdx returns the catalog as an SPDX 2.3 document.
+func (c *Catalog) spdx(tool string) spdxDocument {
+	const noAssertion = "NOASSERTION"
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              c.Name,
+		DocumentNamespace: "https://github.com/act3-ai/data-tool/spdx/" + strings.NewReplacer("/", "-", ":", "-", "@", "-").Replace(c.Name) + "-" + newUUID(),
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + tool},
+		},
+	}
+
+	image := spdxPackage{
+		Name:                  c.Name,
+		SPDXID:                "SPDXRef-Image",
+		VersionInfo:           c.Digest.String(),
+		DownloadLocation:      noAssertion,
+		LicenseConcluded:      noAssertion,
+		LicenseDeclared:       noAssertion,
+		CopyrightText:         noAssertion,
+		PrimaryPackagePurpose: "CONTAINER",
+	}
+	doc.Packages = append(doc.Packages, image)
+	doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", image.SPDXID})
+
+	if c.Distro != nil {
+		doc.Packages = append(doc.Packages, spdxPackage{
+			Name:                  c.Distro.ID,
+			SPDXID:                "SPDXRef-OperatingSystem",
+			VersionInfo:           c.Distro.VersionID,
+			DownloadLocation:      noAssertion,
+			LicenseConcluded:      noAssertion,
+			LicenseDeclared:       noAssertion,
+			CopyrightText:         noAssertion,
+			PrimaryPackagePurpose: "OPERATING-SYSTEM",
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{image.SPDXID, "CONTAINS", "SPDXRef-OperatingSystem"})
+	}
+
+	for i, p := range c.Packages {
+		license := strings.Join(p.Licenses, " AND ")
+		if !spdxLicense.MatchString(license) {
+			license = noAssertion
+		}
+		pkg := spdxPackage{
+			Name:                  p.Name,
+			SPDXID:                fmt.Sprintf("SPDXRef-Package-%d", i),
+			VersionInfo:           p.Version,
+			DownloadLocation:      noAssertion,
+			LicenseConcluded:      noAssertion,
+			LicenseDeclared:       license,
+			CopyrightText:         noAssertion,
+			SourceInfo:            "found in " + p.Location,
+			PrimaryPackagePurpose: "LIBRARY",
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  p.PURL(c.Distro),
+			}},
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{image.SPDXID, "CONTAINS", pkg.SPDXID})
+	}
+	return doc
+}
+
+type cdxDocument struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	BOMRef   string       `json:"bom-ref,omitempty"`
+	Type     string       `json:"type"`
+	Name     string       `json:"name"`
+	Group    string       `json:"group,omitempty"`
+	Version  string       `json:"version,omitempty"`
+	PURL     string       `json:"purl,omitempty"`
+	Licenses []cdxLicense `json:"licenses,omitempty"`
+	Evidence *cdxEvidence `json:"evidence,omitempty"`
+}
+
+type cdxLicense struct {
+	License cdxLicenseName `json:"license"`
+}
+
+type cdxLicenseName struct {
+	Name string `json:"name"`
+}
+
+type cdxEvidence struct {
+	Occurrences []cdxOccurrence `json:"occurrences"`
+}
+
+type cdxOccurrence struct {
+	Location string `json:"location"`
+}
+
+// cycloneDX returns the catalog as a CycloneDX 1.5 document.
+func (c *Catalog) cycloneDX(tool string) cdxDocument {
+	doc := cdxDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{{Type: "application", Name: tool}}},
+			Component: cdxComponent{
+				BOMRef:  c.Digest.String(),
+				Type:    "container",
+				Name:    c.Name,
+				Version: c.Digest.String(),
+			},
+		},
+		Components: []cdxComponent{},
+	}
+
+	if c.Distro != nil {
+		doc.Components = append(doc.Components, cdxComponent{
+			BOMRef:  "os:" + c.Distro.ID,
+			Type:    "operating-system",
+			Name:    c.Distro.ID,
+			Version: c.Distro.VersionID,
+		})
+	}
+	for _, p := range c.Packages {
+		purl := p.PURL(c.Distro)
+		comp := cdxComponent{
+			// the same package may be found in several files
+			BOMRef:   purl + "#" + p.Location,
+			Type:     "library",
+			Name:     p.Name,
+			Group:    p.Namespace,
+			Version:  p.Version,
+			PURL:     purl,
+			Evidence: &cdxEvidence{Occurrences: []cdxOccurrence{{Location: p.Location}}},
+		}
+		if p.IsOS() {
+			// the namespace of OS packages is the distro, not a group
+			comp.Group = ""
+		}
+		for _, l := range p.Licenses {
+			comp.Licenses = append(comp.Licenses, cdxLicense{License: cdxLicenseName{Name: l}})
+		}
+		doc.Components = append(doc.Components, comp)
+	}
+	return doc
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
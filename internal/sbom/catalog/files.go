@@ -0,0 +1,469 @@
+package catalog
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	_ "github.com/glebarez/go-sqlite" // sqlite driver of rpm databases
+	rpmdb "github.com/knqyf263/go-rpmdb/pkg"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+const (
+	// maxMetadataSize is the largest package metadata file read, such as a dpkg status file
+	maxMetadataSize = 64 << 20
+	// maxArchiveSize is the largest executable or java archive read
+	maxArchiveSize = 512 << 20
+	// maxNestedArchives is the depth of java archives nested in java archives read, such as the libraries of a
+	// Spring Boot jar
+	maxNestedArchives = 3
+)
+
+// executableMagic are the magic numbers of ELF, Mach-O and PE executables, the formats Go binaries are built in.
+var executableMagic = [][]byte{
+	{0x7f, 'E', 'L', 'F'},
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf}, {0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+	{'M', 'Z'},
+}
+
+// pomProperties matches the maven metadata of java archives.
+var pomProperties = regexp.MustCompile(`^META-INF/maven/[^/]+/[^/]+/pom\.properties$`)
+
+// uncatalogedFile returns the ecosystem of a package manager file that is not cataloged, or an empty string.
+func uncatalogedFile(name string) string {
+	dir, base := path.Split(name)
+	switch {
+	case strings.HasSuffix(dir, "/specifications/") && strings.HasSuffix(base, ".gemspec"), base == "Gemfile.lock":
+		return "ruby"
+	case base == "Cargo.lock":
+		return "rust"
+	case base == "composer.lock", strings.HasSuffix(name, "/vendor/composer/installed.json"):
+		return "php"
+	case strings.HasSuffix(base, ".deps.json"):
+		return "dotnet"
+	case strings.HasSuffix(dir, "/conda-meta/") && strings.HasSuffix(base, ".json"):
+		return "conda"
+	default:
+		return ""
+	}
+}
+
+// catalogFile catalogs the packages of a regular file of the image.  Files that cannot be parsed are logged and
+// skipped, so that one malformed file does not prevent cataloging the rest of the image.
+func catalogFile(ctx context.Context, name string, hdr *tar.Header, r io.Reader) (entry, error) {
+	log := logger.V(logger.FromContext(ctx), 1)
+
+	var parse func(name string, data []byte) (entry, error)
+	limit := int64(maxMetadataSize)
+	executable := false
+	dir, base := path.Split(name)
+	switch {
+	case name == "/etc/os-release" || name == "/usr/lib/os-release":
+		parse = parseOSRelease
+	case name == "/var/lib/dpkg/status" ||
+		(dir == "/var/lib/dpkg/status.d/" && !strings.HasSuffix(base, ".md5sums")):
+		parse = parseDpkgStatus
+	case name == "/lib/apk/db/installed":
+		parse = parseApkInstalled
+	case (dir == "/var/lib/rpm/" || dir == "/usr/lib/sysimage/rpm/") &&
+		(base == "rpmdb.sqlite" || base == "Packages" || base == "Packages.db"):
+		// sqlite, Berkeley DB and ndb databases of rpm
+		parse = parseRpmDB
+		limit = maxArchiveSize
+	case strings.HasSuffix(name, ".dist-info/METADATA") || strings.HasSuffix(name, ".egg-info/PKG-INFO"):
+		parse = parsePythonMetadata
+	case base == "package.json" && strings.Contains(dir, "/node_modules/"):
+		parse = parsePackageJSON
+	case isJavaArchive(base):
+		parse = parseJavaArchive
+		limit = maxArchiveSize
+	case hdr.Mode&0o111 != 0:
+		parse = parseGoBinary
+		limit = maxArchiveSize
+		executable = true
+	default:
+		return entry{uncataloged: uncatalogedFile(name)}, nil
+	}
+	if hdr.Size > limit {
+		log.InfoContext(ctx, "skipping large file", "path", name, "size", hdr.Size)
+		return entry{}, nil
+	}
+
+	br := bufio.NewReader(r)
+	if executable {
+		// other files with the executable bit set, such as scripts, are not read in full
+		magic, _ := br.Peek(4)
+		if !isExecutable(magic) {
+			return entry{}, nil
+		}
+	}
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return entry{}, fmt.Errorf("reading %s: %w", name, err)
+	}
+	e, err := parse(name, data)
+	if err != nil {
+		log.InfoContext(ctx, "skipping file that could not be cataloged", "path", name, "error", err)
+		return entry{}, nil
+	}
+	return e, nil
+}
+
+// isExecutable returns true if the magic number is of an executable format.
+func isExecutable(magic []byte) bool {
+	for _, m := range executableMagic {
+		if bytes.HasPrefix(magic, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFields parses RFC 822 style "Key: value" fields, as used by dpkg, apk and python metadata, calling fn with each
+// paragraph of fields.  Continuation lines are appended to the previous field.
+func parseFields(data []byte, sep string, fn func(fields map[string]string)) {
+	fields := map[string]string{}
+	var last string
+	for line := range strings.SplitSeq(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case line == "":
+			if len(fields) > 0 {
+				fn(fields)
+			}
+			fields = map[string]string{}
+			last = ""
+		case (line[0] == ' ' || line[0] == '\t') && last != "":
+			fields[last] += "\n" + strings.TrimSpace(line)
+		default:
+			key, value, ok := strings.Cut(line, sep)
+			if !ok {
+				continue
+			}
+			last = strings.TrimSpace(key)
+			if _, exists := fields[last]; exists {
+				// repeated fields, such as python classifiers, keep their first value
+				continue
+			}
+			fields[last] = strings.TrimSpace(value)
+		}
+	}
+	if len(fields) > 0 {
+		fn(fields)
+	}
+}
+
+// parseOSRelease parses an os-release file.
+func parseOSRelease(_ string, data []byte) (entry, error) {
+	values := map[string]string{}
+	for line := range strings.SplitSeq(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	if values["ID"] == "" {
+		return entry{}, fmt.Errorf("os-release has no ID")
+	}
+	return entry{distro: &Distro{
+		ID:         values["ID"],
+		VersionID:  values["VERSION_ID"],
+		Name:       values["NAME"],
+		PrettyName: values["PRETTY_NAME"],
+	}}, nil
+}
+
+// parseDpkgStatus parses the installed packages of a dpkg status file.
+func parseDpkgStatus(name string, data []byte) (entry, error) {
+	var e entry
+	parseFields(data, ":", func(fields map[string]string) {
+		// status.d files of distroless images have no status, all their packages are installed
+		if status, ok := fields["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			return
+		}
+		if fields["Package"] == "" {
+			return
+		}
+		p := Package{
+			Type:      PackageTypeDeb,
+			Namespace: "debian",
+			Name:      fields["Package"],
+			Version:   fields["Version"],
+			Arch:      fields["Architecture"],
+			Location:  name,
+		}
+		// the source may have its own version, as "name (version)"
+		if source, version, ok := strings.Cut(fields["Source"], " "); ok {
+			p.Source = source + "@" + strings.Trim(version, "()")
+		} else if source != "" && source != p.Name {
+			p.Source = source
+		}
+		e.packages = append(e.packages, p)
+	})
+	return e, nil
+}
+
+// parseApkInstalled parses the installed packages of the apk database.
+func parseApkInstalled(name string, data []byte) (entry, error) {
+	var e entry
+	parseFields(data, ":", func(fields map[string]string) {
+		if fields["P"] == "" {
+			return
+		}
+		p := Package{
+			Type:      PackageTypeApk,
+			Namespace: "alpine",
+			Name:      fields["P"],
+			Version:   fields["V"],
+			Arch:      fields["A"],
+			Location:  name,
+		}
+		if origin := fields["o"]; origin != "" && origin != p.Name {
+			p.Source = origin
+		}
+		if license := fields["L"]; license != "" {
+			p.Licenses = []string{license}
+		}
+		e.packages = append(e.packages, p)
+	})
+	return e, nil
+}
+
+// parseRpmDB parses the installed packages of an rpm database.  The database is read from a temporary file, since
+// rpm databases are not read from memory.
+func parseRpmDB(name string, data []byte) (entry, error) {
+	f, err := os.CreateTemp("", "rpmdb-*")
+	if err != nil {
+		return entry{}, fmt.Errorf("creating temporary rpm database: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return entry{}, fmt.Errorf("writing temporary rpm database: %w", err)
+	}
+
+	db, err := rpmdb.Open(f.Name())
+	if err != nil {
+		return entry{}, fmt.Errorf("opening rpm database: %w", err)
+	}
+	defer db.Close()
+	pkgs, err := db.ListPackages()
+	if err != nil {
+		return entry{}, fmt.Errorf("listing rpm packages: %w", err)
+	}
+
+	var e entry
+	for _, pkg := range pkgs {
+		// the public keys rpm trusts are not packages
+		if pkg.Name == "" || pkg.Name == "gpg-pubkey" {
+			continue
+		}
+		p := Package{
+			Type:      PackageTypeRPM,
+			Namespace: "redhat",
+			Name:      pkg.Name,
+			Version:   pkg.Version + "-" + pkg.Release,
+			Arch:      pkg.Arch,
+			Source:    pkg.SourceRpm,
+			Location:  name,
+		}
+		// the epoch prefixes the version, as in rpm version comparisons
+		if pkg.Epoch != nil {
+			p.Version = fmt.Sprintf("%d:%s", *pkg.Epoch, p.Version)
+		}
+		if pkg.License != "" {
+			p.Licenses = []string{pkg.License}
+		}
+		e.packages = append(e.packages, p)
+	}
+	return e, nil
+}
+
+// parsePythonMetadata parses the core metadata of an installed python distribution.
+func parsePythonMetadata(name string, data []byte) (entry, error) {
+	// the description follows the headers, after a blank line
+	headers, _, _ := bytes.Cut(data, []byte("\n\n"))
+	var e entry
+	parseFields(headers, ":", func(fields map[string]string) {
+		if fields["Name"] == "" || fields["Version"] == "" {
+			return
+		}
+		p := Package{
+			Type: PackageTypePyPI,
+			// normalized as in package URLs
+			Name:     strings.ReplaceAll(strings.ToLower(fields["Name"]), "_", "-"),
+			Version:  fields["Version"],
+			Location: name,
+		}
+		switch {
+		case fields["License-Expression"] != "":
+			p.Licenses = []string{fields["License-Expression"]}
+		case fields["License"] != "" && fields["License"] != "UNKNOWN" && !strings.Contains(fields["License"], "\n"):
+			// full license texts are not license names
+			p.Licenses = []string{fields["License"]}
+		}
+		e.packages = append(e.packages, p)
+	})
+	if len(e.packages) == 0 {
+		return entry{}, fmt.Errorf("python metadata has no name or version")
+	}
+	return e, nil
+}
+
+// parsePackageJSON parses the manifest of an installed npm package.
+func parsePackageJSON(name string, data []byte) (entry, error) {
+	var manifest struct {
+		Name    string          `json:"name"`
+		Version string          `json:"version"`
+		License json.RawMessage `json:"license"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return entry{}, fmt.Errorf("decoding package.json: %w", err)
+	}
+	if manifest.Name == "" || manifest.Version == "" {
+		// package.json files of tests and examples within packages are not packages
+		return entry{}, nil
+	}
+	p := Package{
+		Type:     PackageTypeNpm,
+		Name:     manifest.Name,
+		Version:  manifest.Version,
+		Location: name,
+	}
+	// the license is an SPDX expression, or an object with the type in old packages
+	var license string
+	var licenseObject struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(manifest.License, &license); err != nil && json.Unmarshal(manifest.License, &licenseObject) == nil {
+		license = licenseObject.Type
+	}
+	if license != "" {
+		p.Licenses = []string{license}
+	}
+	return entry{packages: []Package{p}}, nil
+}
+
+// parseJavaArchive parses the maven metadata of the packages in a java archive, and of the archives nested in it, such
+// as the libraries of Spring Boot jars and web archives.
+func parseJavaArchive(name string, data []byte) (entry, error) {
+	return parseNestedJavaArchive(name, data, 0)
+}
+
+// parseNestedJavaArchive parses the java archive at the depth of nesting.  The packages of nested archives are located
+// at the path of the archive, then the path of the nested archive in it, separated by a colon.
+func parseNestedJavaArchive(name string, data []byte, depth int) (entry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return entry{}, fmt.Errorf("opening java archive: %w", err)
+	}
+	var e entry
+	for _, f := range zr.File {
+		if isJavaArchive(f.Name) && depth < maxNestedArchives && f.UncompressedSize64 <= maxArchiveSize {
+			// a malformed nested archive does not prevent cataloging the rest of the archive
+			if nested, err := readZipFile(f, maxArchiveSize); err == nil {
+				if ne, err := parseNestedJavaArchive(name+":"+f.Name, nested, depth+1); err == nil {
+					e.packages = append(e.packages, ne.packages...)
+				}
+			}
+			continue
+		}
+		if !pomProperties.MatchString(f.Name) {
+			continue
+		}
+		props, err := readZipFile(f, maxMetadataSize)
+		if err != nil {
+			return entry{}, err
+		}
+		values := map[string]string{}
+		for line := range strings.SplitSeq(string(props), "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+			if ok && !strings.HasPrefix(key, "#") {
+				values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+		if values["artifactId"] == "" || values["version"] == "" {
+			continue
+		}
+		e.packages = append(e.packages, Package{
+			Type:      PackageTypeMaven,
+			Namespace: values["groupId"],
+			Name:      values["artifactId"],
+			Version:   values["version"],
+			Location:  name,
+		})
+	}
+	return e, nil
+}
+
+// isJavaArchive returns true if the file name is of a java, web or enterprise archive.
+func isJavaArchive(name string) bool {
+	return strings.HasSuffix(name, ".jar") || strings.HasSuffix(name, ".war") || strings.HasSuffix(name, ".ear")
+}
+
+// readZipFile reads the file of a zip archive, up to limit bytes.
+func readZipFile(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", f.Name, err)
+	}
+	return data, nil
+}
+
+// parseGoBinary parses the build information of a Go binary, cataloging its main module, dependencies and the
+// standard library it was built with.
+func parseGoBinary(name string, data []byte) (entry, error) {
+	info, err := buildinfo.Read(bytes.NewReader(data))
+	if err != nil {
+		// most executables are not Go binaries
+		return entry{}, nil
+	}
+	e := entry{packages: []Package{{
+		Type:     PackageTypeGolang,
+		Name:     "stdlib",
+		Version:  info.GoVersion,
+		Licenses: []string{"BSD-3-Clause"},
+		Location: name,
+	}}}
+	if info.Main.Path != "" {
+		e.packages = append(e.packages, Package{
+			Type:     PackageTypeGolang,
+			Name:     info.Main.Path,
+			Version:  info.Main.Version,
+			Location: name,
+		})
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		e.packages = append(e.packages, Package{
+			Type:     PackageTypeGolang,
+			Name:     dep.Path,
+			Version:  dep.Version,
+			Location: name,
+		})
+	}
+	return e, nil
+}
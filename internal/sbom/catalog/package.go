@@ -0,0 +1,140 @@
+package catalog
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PackageType is the ecosystem of a package, the type of its package URL.
+type PackageType string
+
+// Package types cataloged.
+const (
+	PackageTypeDeb    PackageType = "deb"
+	PackageTypeApk    PackageType = "apk"
+	PackageTypeRPM    PackageType = "rpm"
+	PackageTypeGolang PackageType = "golang"
+	PackageTypePyPI   PackageType = "pypi"
+	PackageTypeNpm    PackageType = "npm"
+	PackageTypeMaven  PackageType = "maven"
+)
+
+// Package is a package installed in an image.
+type Package struct {
+	Type    PackageType
+	Name    string
+	Version string
+
+	// Namespace of the package name, such as the group of a maven package
+	Namespace string
+
+	// Arch is the architecture the package was built for, if known
+	Arch string
+
+	// Source is the source package of an OS package, if it differs from the package name
+	Source string
+
+	// Licenses are the declared licenses of the package, as given by the package metadata
+	Licenses []string
+
+	// Location is the path of the file the package was found in
+	Location string
+}
+
+// Distro is the Linux distribution of an image, from its os-release file.
+type Distro struct {
+	ID         string
+	VersionID  string
+	Name       string
+	PrettyName string
+}
+
+// IsOS returns true if the package is installed by the package manager of the distro.
+func (p Package) IsOS() bool {
+	return p.Type == PackageTypeDeb || p.Type == PackageTypeApk || p.Type == PackageTypeRPM
+}
+
+// PURL returns the package URL of the package.  OS packages are qualified with the distro, which vulnerability
+// scanners use to select the distro's security advisories.
+func (p Package) PURL(distro *Distro) string {
+	var name string
+	switch p.Type {
+	case PackageTypeDeb, PackageTypeApk, PackageTypeRPM:
+		namespace := p.Namespace
+		if distro != nil && distro.ID != "" {
+			namespace = distro.ID
+		}
+		name = escapeSegment(namespace) + "/" + escapeSegment(p.Name)
+	case PackageTypeGolang:
+		// Go module paths are the namespace and name
+		segments := strings.Split(p.Name, "/")
+		for i, s := range segments {
+			segments[i] = escapeSegment(s)
+		}
+		name = strings.Join(segments, "/")
+	case PackageTypeNpm:
+		// the scope of scoped packages is the namespace
+		if scope, n, ok := strings.Cut(p.Name, "/"); ok {
+			name = escapeSegment(scope) + "/" + escapeSegment(n)
+		} else {
+			name = escapeSegment(p.Name)
+		}
+	case PackageTypeMaven:
+		name = escapeSegment(p.Namespace) + "/" + escapeSegment(p.Name)
+	default:
+		name = escapeSegment(p.Name)
+	}
+
+	version := p.Version
+	var epoch string
+	switch {
+	case p.Type == PackageTypeGolang && p.Name == "stdlib":
+		version = strings.TrimPrefix(version, "go")
+	case p.Type == PackageTypeRPM:
+		// the epoch of rpm packages is a qualifier
+		if e, v, ok := strings.Cut(version, ":"); ok {
+			epoch, version = e, v
+		}
+	}
+	purl := fmt.Sprintf("pkg:%s/%s", p.Type, name)
+	if version != "" {
+		purl += "@" + escapeSegment(version)
+	}
+
+	// qualifiers are sorted by key
+	var qualifiers []string
+	if p.Arch != "" {
+		qualifiers = append(qualifiers, "arch="+url.QueryEscape(p.Arch))
+	}
+	if p.IsOS() && distro != nil && distro.ID != "" {
+		qualifiers = append(qualifiers, "distro="+url.QueryEscape(distro.ID+"-"+distro.VersionID))
+	}
+	if epoch != "" {
+		qualifiers = append(qualifiers, "epoch="+url.QueryEscape(epoch))
+	}
+	if p.Source != "" {
+		qualifiers = append(qualifiers, "upstream="+url.QueryEscape(p.Source))
+	}
+	if len(qualifiers) > 0 {
+		purl += "?" + strings.Join(qualifiers, "&")
+	}
+	return purl
+}
+
+// escapeSegment escapes a segment of a package URL, where "@" separates the version so must be escaped too.
+func escapeSegment(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "@", "%40")
+}
+
+// comparePackages orders packages by type, name, version and location.
+func comparePackages(a, b Package) int {
+	return cmp.Or(
+		cmp.Compare(a.Type, b.Type),
+		cmp.Compare(a.Namespace, b.Namespace),
+		cmp.Compare(a.Name, b.Name),
+		cmp.Compare(a.Version, b.Version),
+		cmp.Compare(a.Location, b.Location),
+	)
+}
@@ -51,6 +51,26 @@ func (cr *ArtifactScanReport) GetVulnerabilityCount(severity string) int {
 	}
 }
 
+// GetVulnerabilityMatches returns the matches for the given vulnerability severity level.
+func (cr *ArtifactScanReport) GetVulnerabilityMatches(severity string) []Matches {
+	switch severity {
+	case "critical":
+		return cr.CriticalVulnerabilities
+	case "high":
+		return cr.HighVulnerabilities
+	case "medium":
+		return cr.MediumVulnerabilities
+	case "low":
+		return cr.LowVulnerabilities
+	case "negligible":
+		return cr.NegligibleVulnerabilities
+	case "unknown":
+		return cr.UnknownVulnerabilities
+	default:
+		return nil
+	}
+}
+
 // GetVulnerabilityCVEs parses the results json and returns a slice of CVE IDs for the given severity level.
 func (cr *ArtifactScanReport) GetVulnerabilityCVEs(severity string) []string {
 	var vulnerabilities []string
@@ -4,34 +4,63 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/act3-ai/data-tool/internal/sbom/catalog"
 	"github.com/act3-ai/data-tool/internal/ui"
 )
 
-func syftReference(ctx context.Context, reference string) ([]byte, error) {
-	// log := logger.FromContext(ctx)
-	// exec out to syft to generate the SBOM
-	// log.InfoContext(ctx, "creating sbom", "reference", reference)
-	cmd := exec.CommandContext(ctx, "syft", "scan", reference, "-o", "spdx-json")
-	res, err := cmd.CombinedOutput()
+// SyftSBOM generates the SBOM of the image source with syft, in the format.  The source is a syft source, such as
+// "registry:reg.example.com/image:v1" or "oci-dir:path".  The platform selects the image of a multi-platform image,
+// the platform of the host if empty.
+func SyftSBOM(ctx context.Context, source string, format catalog.Format, platform string) ([]byte, error) {
+	args := []string{"scan", source, "-o", string(format)}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+	cmd := exec.CommandContext(ctx, "syft", args...)
+	// the SBOM is written to stdout, the progress to stderr
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	res, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("syft is required to generate SBOMs, install it or select the %s cataloger: %w", CatalogerBuiltin, err)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error executing command: %s\n %w\n output: %s", cmd, err, string(res))
+		return nil, fmt.Errorf("error executing command: %s\n %w\n output: %s", cmd, err, stderr.String())
 	}
-	// log.InfoContext(ctx, "created SBOM", "reference", reference)
 	return res, nil
 }
 
-func grypeReference(ctx context.Context, reference string) (*Results, error) {
+// ScanReference scans the image with the reference with grype, returning the vulnerabilities of all severities.
+func ScanReference(ctx context.Context, reference string) (*ArtifactScanReport, error) {
+	res, err := grypeReference(ctx, reference, 0)
+	if err != nil {
+		return nil, err
+	}
+	return calculateResults(res)
+}
+
+// grypeCommand returns the grype command with the arguments, which neither updates the database nor, when the
+// database age is checked against maxDBAge instead, validates its age.  A maxDBAge of 0 keeps grype's own age check.
+func grypeCommand(ctx context.Context, maxDBAge time.Duration, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "grype", args...)
+	cmd.Env = append(os.Environ(), "GRYPE_DB_AUTO_UPDATE=false")
+	if maxDBAge > 0 {
+		cmd.Env = append(cmd.Env, "GRYPE_DB_VALIDATE_AGE=false")
+	}
+	return cmd
+}
+
+func grypeReference(ctx context.Context, reference string, maxDBAge time.Duration) (*Results, error) {
 	vulnerabilities := Results{}
-	cmd := exec.CommandContext(ctx, "grype", reference, "-o", "json")
-	cmd.Env = append(os.Environ(),
-		"GRYPE_DB_AUTO_UPDATE=false",
-	)
+	cmd := grypeCommand(ctx, maxDBAge, reference, "-o", "json")
 	res, err := cmd.CombinedOutput()
 
 	if err != nil {
@@ -56,12 +85,9 @@ func grypeReference(ctx context.Context, reference string) (*Results, error) {
 	return &vulnerabilities, nil
 }
 
-func grypeSBOM(ctx context.Context, sbom io.ReadCloser) (*Results, error) {
+func grypeSBOM(ctx context.Context, sbom io.ReadCloser, maxDBAge time.Duration) (*Results, error) {
 	vulnerabilities := Results{}
-	cmd := exec.CommandContext(ctx, "grype", "-o", "json")
-	cmd.Env = append(os.Environ(),
-		"GRYPE_DB_AUTO_UPDATE=false",
-	)
+	cmd := grypeCommand(ctx, maxDBAge, "-o", "json")
 	cmd.Stdin = sbom
 	res, err := cmd.CombinedOutput()
 	if err != nil {
@@ -83,21 +109,6 @@ func grypeSBOM(ctx context.Context, sbom io.ReadCloser) (*Results, error) {
 	return &vulnerabilities, nil
 }
 
-func getGrypeDBChecksum(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "grype", "db", "status", "--output", "json")
-	res, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("getting the grype db checksum: %w", err)
-	}
-
-	var grypeStatus grypeDBChecksum
-	if err := json.Unmarshal(res, &grypeStatus); err != nil {
-		return "", fmt.Errorf("decoding grype db checksum output: %w", err)
-	}
-
-	return grypeStatus.value()
-}
-
 // The following is for documenting changes to the output of grype db status
 // due to frequent changes.
 // v0.87.0 and prior:
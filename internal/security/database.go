@@ -0,0 +1,366 @@
+package security
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+const (
+	// ArtifactTypeGrypeDatabase is the artifact type of grype vulnerability databases packaged by ace-dt.
+	ArtifactTypeGrypeDatabase = "application/vnd.act3-ace.grype.db.v1"
+	// MediaTypeGrypeDatabaseLayer is the media type of the layer holding the grype database directory.
+	MediaTypeGrypeDatabaseLayer = "application/vnd.act3-ace.grype.db.layer.v1.tar+gzip"
+	// AnnotationGrypeDatabaseBuilt is the time the packaged grype database was built.
+	AnnotationGrypeDatabaseBuilt = "vnd.act3-ace.scan.database.built"
+	// AnnotationGrypeDatabaseSchema is the schema version of the packaged grype database.
+	AnnotationGrypeDatabaseSchema = "vnd.act3-ace.scan.database.schema"
+)
+
+// DatabaseStatus describes the grype vulnerability database used for scanning.
+type DatabaseStatus struct {
+	SchemaVersion string    `json:"schemaVersion"`
+	Built         time.Time `json:"built"`
+	Checksum      string    `json:"checksum"`
+
+	// Path is the directory of the database
+	Path string `json:"-"`
+}
+
+// Age returns the age of the database.
+func (s *DatabaseStatus) Age() time.Duration {
+	return time.Since(s.Built)
+}
+
+// CheckAge returns an error if the database is older than maxAge.  A maxAge of 0 accepts databases of any age.
+func (s *DatabaseStatus) CheckAge(maxAge time.Duration) error {
+	if maxAge > 0 && s.Age() > maxAge {
+		return fmt.Errorf("the vulnerability database was built %s ago at %s, older than the maximum age %s; update or import a newer database",
+			s.Age().Round(time.Hour), s.Built.Format(time.RFC3339), maxAge)
+	}
+	return nil
+}
+
+// String describes the database for reports.
+func (s *DatabaseStatus) String() string {
+	return fmt.Sprintf("Vulnerability database: schema %s, built %s (%d days old), checksum %s",
+		s.SchemaVersion, s.Built.Format(time.RFC3339), int(s.Age().Hours()/24), s.Checksum)
+}
+
+// grypeDBStatus contains all possible keys of the output of 'grype db status', see grypeDBChecksum.
+type grypeDBStatus struct {
+	grypeDBChecksum
+	SchemaVersion json.RawMessage `json:"schemaVersion"` // a number in v0.87.0 and prior, a string after
+	Built         string          `json:"built"`
+	Location      string          `json:"location,omitempty"` // v0.87.0 and prior
+	Path          string          `json:"path,omitempty"`
+}
+
+// GetDatabaseStatus returns the status of the grype database, as reported by 'grype db status'.
+func GetDatabaseStatus(ctx context.Context) (*DatabaseStatus, error) {
+	cmd := exec.CommandContext(ctx, "grype", "db", "status", "--output", "json")
+	res, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("getting the grype db status: %w\n output: %s", err, res)
+	}
+	return parseDatabaseStatus(res)
+}
+
+// parseDatabaseStatus parses the JSON output of 'grype db status'.
+func parseDatabaseStatus(res []byte) (*DatabaseStatus, error) {
+	var grypeStatus grypeDBStatus
+	if err := json.Unmarshal(res, &grypeStatus); err != nil {
+		return nil, fmt.Errorf("decoding grype db status output: %w", err)
+	}
+	checksum, err := grypeStatus.value()
+	if err != nil {
+		return nil, err
+	}
+	built, err := time.Parse(time.RFC3339, grypeStatus.Built)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the grype db build time: %w", err)
+	}
+
+	// the path is of the database file since v0.88.0, and of its directory before
+	dir := grypeStatus.Path
+	if dir == "" {
+		dir = grypeStatus.Location
+	}
+	if fi, err := os.Stat(dir); err == nil && !fi.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	return &DatabaseStatus{
+		SchemaVersion: strings.Trim(string(grypeStatus.SchemaVersion), `"`),
+		Built:         built.UTC(),
+		Checksum:      checksum,
+		Path:          dir,
+	}, nil
+}
+
+// UpdateDatabase downloads the latest grype database with 'grype db update'.
+func UpdateDatabase(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "grype", "db", "update")
+	if res, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("updating the grype db: %w\n output: %s", err, res)
+	}
+	return nil
+}
+
+// DatabaseCacheDir returns the directory grype loads databases from, as set by GRYPE_DB_CACHE_DIR.
+func DatabaseCacheDir() string {
+	if dir := os.Getenv("GRYPE_DB_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(xdg.CacheHome, "grype", "db")
+}
+
+// PackageDatabase pushes the grype database directory to the target as a database artifact, so that it can be
+// mirrored to hosts without access to the grype database servers.  The database files are archived under the name
+// of their directory, the schema directory of the grype cache.
+func PackageDatabase(ctx context.Context, target oras.Target, status *DatabaseStatus) (ocispec.Descriptor, error) {
+	if !schemaDirPattern.MatchString(filepath.Base(status.Path)) {
+		return ocispec.Descriptor{}, fmt.Errorf("the grype db directory %s is not a schema directory of the grype cache", status.Path)
+	}
+	layer, err := pushDatabaseLayer(ctx, target, status.Path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	desc, err := oras.PackManifest(ctx, target, oras.PackManifestVersion1_1, ArtifactTypeGrypeDatabase, oras.PackManifestOptions{
+		Layers: []ocispec.Descriptor{layer},
+		ManifestAnnotations: map[string]string{
+			ocispec.AnnotationCreated:       status.Built.Format(time.RFC3339),
+			AnnotationGrypeDatabaseBuilt:    status.Built.Format(time.RFC3339),
+			AnnotationGrypeDatabaseSchema:   status.SchemaVersion,
+			AnnotationGrypeDatabaseChecksum: status.Checksum,
+		},
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("pushing the grype db manifest: %w", err)
+	}
+	return desc, nil
+}
+
+// pushDatabaseLayer archives the database directory to a temporary file, to learn its digest without holding the
+// database in memory, and pushes it to the target.
+func pushDatabaseLayer(ctx context.Context, target oras.Target, dir string) (ocispec.Descriptor, error) {
+	log := logger.FromContext(ctx)
+
+	f, err := os.CreateTemp("", "grype-db-*.tar.gz")
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("creating the grype db archive: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	digester := digest.Canonical.Digester()
+	if err := archiveDatabase(io.MultiWriter(f, digester.Hash()), dir); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("archiving the grype db %s: %w", dir, err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("reading the grype db archive: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("reading the grype db archive: %w", err)
+	}
+
+	layer := ocispec.Descriptor{
+		MediaType: MediaTypeGrypeDatabaseLayer,
+		Digest:    digester.Digest(),
+		Size:      size,
+	}
+	log.InfoContext(ctx, "pushing grype db", "path", dir, "size", layer.Size)
+	if err := target.Push(ctx, layer, f); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return ocispec.Descriptor{}, fmt.Errorf("pushing the grype db: %w", err)
+	}
+	return layer, nil
+}
+
+// archiveDatabase writes a gzipped tar archive of the regular files of the database directory.
+func archiveDatabase(w io.Writer, dir string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading the grype db directory: %w", err)
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if err := archiveFile(tw, filepath.Join(dir, e.Name()), filepath.Base(dir)+"/"+e.Name()); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing the grype db archive: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("closing the grype db archive: %w", err)
+	}
+	return nil
+}
+
+// archiveFile adds the file at path to the archive with the name.
+func archiveFile(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	return nil
+}
+
+// ImportDatabase extracts the grype database artifact with the manifest desc into the grype cache directory,
+// replacing the database of the same schema.  It returns the status of the imported database, from the annotations
+// of the artifact.
+func ImportDatabase(ctx context.Context, storage content.Fetcher, desc ocispec.Descriptor, cacheDir string) (*DatabaseStatus, error) {
+	log := logger.FromContext(ctx)
+
+	data, err := content.FetchAll(ctx, storage, desc)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the grype db manifest: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decoding the grype db manifest: %w", err)
+	}
+	if manifest.ArtifactType != ArtifactTypeGrypeDatabase || len(manifest.Layers) != 1 {
+		return nil, fmt.Errorf("artifact type %q is not a grype db packaged by ace-dt", manifest.ArtifactType)
+	}
+	built, err := time.Parse(time.RFC3339, manifest.Annotations[AnnotationGrypeDatabaseBuilt])
+	if err != nil {
+		return nil, fmt.Errorf("parsing the grype db build time: %w", err)
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o775); err != nil {
+		return nil, fmt.Errorf("creating the grype db cache directory: %w", err)
+	}
+	// extracted next to the database it replaces, so that it is replaced by renaming
+	tmp, err := os.MkdirTemp(cacheDir, ".import-")
+	if err != nil {
+		return nil, fmt.Errorf("creating the grype db import directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	rc, err := storage.Fetch(ctx, manifest.Layers[0])
+	if err != nil {
+		return nil, fmt.Errorf("fetching the grype db: %w", err)
+	}
+	defer rc.Close()
+	vr := content.NewVerifyReader(rc, manifest.Layers[0])
+	schemaDir, err := extractDatabase(vr, tmp)
+	if err != nil {
+		return nil, err
+	}
+	// the database is only installed once the whole layer matches its digest
+	if _, err := io.Copy(io.Discard, vr); err != nil {
+		return nil, fmt.Errorf("reading the grype db: %w", err)
+	}
+	if err := vr.Verify(); err != nil {
+		return nil, fmt.Errorf("verifying the grype db: %w", err)
+	}
+
+	dest := filepath.Join(cacheDir, schemaDir)
+	log.InfoContext(ctx, "replacing grype db", "path", dest)
+	if err := os.RemoveAll(dest); err != nil {
+		return nil, fmt.Errorf("removing the previous grype db: %w", err)
+	}
+	if err := os.Rename(filepath.Join(tmp, schemaDir), dest); err != nil {
+		return nil, fmt.Errorf("installing the grype db: %w", err)
+	}
+
+	return &DatabaseStatus{
+		SchemaVersion: manifest.Annotations[AnnotationGrypeDatabaseSchema],
+		Built:         built.UTC(),
+		Checksum:      manifest.Annotations[AnnotationGrypeDatabaseChecksum],
+		Path:          dest,
+	}, nil
+}
+
+// schemaDirPattern matches the names of the schema directories of the grype cache, such as 5 or v6.
+var schemaDirPattern = regexp.MustCompile(`^v?[0-9]+$`)
+
+// extractDatabase extracts the database archive into dir, returning the schema directory the files are in.
+func extractDatabase(r io.Reader, dir string) (string, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return "", fmt.Errorf("decompressing the grype db: %w", err)
+	}
+	tr := tar.NewReader(gr)
+	var schemaDir string
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading the grype db archive: %w", err)
+		}
+		// all files are in a single schema directory
+		d, name, ok := strings.Cut(hdr.Name, "/")
+		switch {
+		case hdr.Typeflag != tar.TypeReg:
+			continue
+		case !ok || !schemaDirPattern.MatchString(d) || !filepath.IsLocal(name) || strings.Contains(name, "/") ||
+			(schemaDir != "" && d != schemaDir):
+			return "", fmt.Errorf("unexpected file %q in the grype db archive", hdr.Name)
+		}
+		schemaDir = d
+
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o775); err != nil {
+			return "", fmt.Errorf("creating the grype db directory: %w", err)
+		}
+		f, err := os.OpenFile(filepath.Join(dir, d, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return "", fmt.Errorf("extracting %s: %w", hdr.Name, err)
+		}
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", fmt.Errorf("extracting %s: %w", hdr.Name, err)
+		}
+	}
+	if schemaDir == "" {
+		return "", errors.New("the grype db archive is empty")
+	}
+	return schemaDir, nil
+}
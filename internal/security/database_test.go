@@ -0,0 +1,195 @@
+package security
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestDatabaseStatus(t *testing.T) {
+	t.Run("v0.87.0", func(t *testing.T) {
+		status, err := parseDatabaseStatus([]byte(`{"schemaVersion":5,"built":"2024-05-01T01:31:25Z","location":"/cache/grype/db/5","checksum":"sha256:0123","error":null}`))
+		require.NoError(t, err)
+		assert.Equal(t, "5", status.SchemaVersion)
+		assert.Equal(t, "/cache/grype/db/5", status.Path)
+		assert.Equal(t, "sha256:0123", status.Checksum)
+		assert.Equal(t, time.Date(2024, 5, 1, 1, 31, 25, 0, time.UTC), status.Built)
+	})
+
+	t.Run("v0.90.0", func(t *testing.T) {
+		dir := t.TempDir()
+		db := filepath.Join(dir, "vulnerability.db")
+		require.NoError(t, os.WriteFile(db, []byte("db"), 0o644))
+		status, err := parseDatabaseStatus([]byte(`{"schemaVersion":"v6.0.2","built":"2025-04-01T04:12:42Z","path":"` + db + `","from":"https://grype.anchore.io/databases/v6/vulnerability-db.tar.zst?checksum=sha256%3A0123","valid":true}`))
+		require.NoError(t, err)
+		assert.Equal(t, "v6.0.2", status.SchemaVersion)
+		assert.Equal(t, dir, status.Path, "expected the directory of the database file")
+		assert.Contains(t, status.Checksum, "checksum=sha256")
+	})
+
+	t.Run("age", func(t *testing.T) {
+		status := &DatabaseStatus{Built: time.Now().Add(-72 * time.Hour)}
+		assert.NoError(t, status.CheckAge(0))
+		assert.NoError(t, status.CheckAge(96*time.Hour))
+		assert.ErrorContains(t, status.CheckAge(48*time.Hour), "older than the maximum age")
+	})
+}
+
+func TestPackageImportDatabase(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+
+	// a grype cache with a database of schema 6
+	src := filepath.Join(t.TempDir(), "6")
+	require.NoError(t, os.Mkdir(src, 0o775))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "vulnerability.db"), []byte("vulnerabilities"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "import.json"), []byte(`{"digest":"xxh64:0123"}`), 0o644))
+	status := &DatabaseStatus{
+		SchemaVersion: "v6.0.2",
+		Built:         time.Date(2025, 4, 1, 4, 12, 42, 0, time.UTC),
+		Checksum:      "xxh64:0123",
+		Path:          src,
+	}
+
+	desc, err := PackageDatabase(ctx, storage, status)
+	require.NoError(t, err)
+
+	// the previous database is replaced
+	cacheDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(cacheDir, "6"), 0o775))
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "6", "stale.db"), []byte("stale"), 0o644))
+
+	imported, err := ImportDatabase(ctx, storage, desc, cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cacheDir, "6"), imported.Path)
+	assert.Equal(t, status.Built, imported.Built)
+	assert.Equal(t, status.SchemaVersion, imported.SchemaVersion)
+	assert.Equal(t, status.Checksum, imported.Checksum)
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, "6", "vulnerability.db"))
+	require.NoError(t, err)
+	assert.Equal(t, "vulnerabilities", string(data))
+	assert.FileExists(t, filepath.Join(cacheDir, "6", "import.json"))
+	assert.NoFileExists(t, filepath.Join(cacheDir, "6", "stale.db"))
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "expected the import directory to be removed")
+}
+
+// tamperedFetcher fetches other data for the blob with the digest.
+type tamperedFetcher struct {
+	content.Fetcher
+	digest digest.Digest
+	data   []byte
+}
+
+func (f *tamperedFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if desc.Digest == f.digest {
+		return io.NopCloser(bytes.NewReader(f.data)), nil
+	}
+	return f.Fetcher.Fetch(ctx, desc)
+}
+
+func TestImportDatabaseMismatchedDigest(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+
+	src := filepath.Join(t.TempDir(), "6")
+	require.NoError(t, os.Mkdir(src, 0o775))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "vulnerability.db"), []byte("vulnerabilities"), 0o644))
+	status := &DatabaseStatus{SchemaVersion: "v6.0.2", Built: time.Now(), Checksum: "xxh64:0123", Path: src}
+	desc, err := PackageDatabase(ctx, storage, status)
+	require.NoError(t, err)
+
+	var manifest ocispec.Manifest
+	data, err := content.FetchAll(ctx, storage, desc)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &manifest))
+
+	// a valid database archive, but not the one of the layer digest
+	require.NoError(t, os.WriteFile(filepath.Join(src, "vulnerability.db"), []byte("tampered"), 0o644))
+	var tampered bytes.Buffer
+	require.NoError(t, archiveDatabase(&tampered, src))
+	fetcher := &tamperedFetcher{Fetcher: storage, digest: manifest.Layers[0].Digest, data: tampered.Bytes()}
+
+	// the previous database is kept
+	cacheDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(cacheDir, "6"), 0o775))
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "6", "vulnerability.db"), []byte("previous"), 0o644))
+
+	_, err = ImportDatabase(ctx, fetcher, desc, cacheDir)
+	require.Error(t, err)
+	data, err = os.ReadFile(filepath.Join(cacheDir, "6", "vulnerability.db"))
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(data))
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "expected the import directory to be removed")
+}
+
+func TestGrypeCommand(t *testing.T) {
+	ctx := context.Background()
+	assert.NotContains(t, grypeCommand(ctx, 0, "db", "status").Env, "GRYPE_DB_VALIDATE_AGE=false",
+		"expected grype to check the database age without a maximum age")
+	assert.Contains(t, grypeCommand(ctx, 24*time.Hour, "db", "status").Env, "GRYPE_DB_VALIDATE_AGE=false")
+}
+
+func TestExtractDatabaseSchemaDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "5/vulnerability.db"},
+		{name: "v6/vulnerability.db"},
+		{name: "./vulnerability.db", wantErr: true},
+		{name: "../vulnerability.db", wantErr: true},
+		{name: "vulnerability.db", wantErr: true},
+		{name: "db/vulnerability.db", wantErr: true},
+		{name: "6/sub/vulnerability.db", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gw)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: tt.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: 2}))
+			_, err := tw.Write([]byte("db"))
+			require.NoError(t, err)
+			require.NoError(t, tw.Close())
+			require.NoError(t, gw.Close())
+
+			schemaDir, err := extractDatabase(&buf, t.TempDir())
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unexpected file")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Dir(tt.name), schemaDir)
+		})
+	}
+}
+
+func TestPackageDatabaseSchemaDir(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "db")
+	require.NoError(t, os.Mkdir(src, 0o775))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "vulnerability.db"), []byte("vulnerabilities"), 0o644))
+	status := &DatabaseStatus{SchemaVersion: "v6.0.2", Built: time.Now(), Checksum: "xxh64:0123", Path: src}
+	_, err := PackageDatabase(context.Background(), memory.New(), status)
+	assert.ErrorContains(t, err, "not a schema directory")
+}
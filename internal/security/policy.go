@@ -0,0 +1,289 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Policy is a vulnerability policy that scanned images must pass.
+type Policy struct {
+	PolicyRules `json:",inline"`
+
+	// Allowlist are vulnerabilities that are accepted in all images
+	Allowlist []AllowedVulnerability `json:"allowlist,omitempty"`
+
+	// Exceptions override the rules of the policy for images.  The first exception matching an image applies.
+	Exceptions []PolicyException `json:"exceptions,omitempty"`
+}
+
+// PolicyRules are the limits on the vulnerabilities of an image.
+type PolicyRules struct {
+	// MaxSeverity is the highest severity of vulnerabilities allowed, unset allows all severities.  Vulnerabilities of
+	// unknown severity exceed it, unless MaxCounts limits them.
+	MaxSeverity string `json:"maxSeverity,omitempty"`
+
+	// MaxCounts is the maximum number of vulnerabilities allowed for each severity
+	MaxCounts map[string]int `json:"maxCounts,omitempty"`
+
+	// FixedOnly only applies the rules to vulnerabilities that have a fix available
+	FixedOnly *bool `json:"fixedOnly,omitempty"`
+}
+
+// AllowedVulnerability is a vulnerability that does not count against the policy until it expires.
+type AllowedVulnerability struct {
+	// ID is the vulnerability identifier, such as CVE-2024-1234
+	ID string `json:"id"`
+
+	// Justification explains why the vulnerability is accepted
+	Justification string `json:"justification"`
+
+	// Expires is the date, as YYYY-MM-DD or RFC 3339, after which the vulnerability is no longer accepted
+	Expires string `json:"expires,omitempty"`
+
+	// Images are the image references the vulnerability is accepted in, as path.Match patterns.  Empty accepts the
+	// vulnerability in all images.
+	Images []string `json:"images,omitempty"`
+
+	expires time.Time
+}
+
+// PolicyException overrides the rules of the policy for images.
+type PolicyException struct {
+	// Image is the image reference the exception applies to, as a path.Match pattern
+	Image string `json:"image"`
+
+	// Justification explains why the image is excepted
+	Justification string `json:"justification"`
+
+	// PolicyRules override the rules of the policy that are set
+	PolicyRules `json:",inline"`
+
+	// Allowlist are vulnerabilities that are accepted in the image in addition to the policy allowlist
+	Allowlist []AllowedVulnerability `json:"allowlist,omitempty"`
+}
+
+// PolicyResult is the evaluation of the vulnerability policy for an image.
+type PolicyResult struct {
+	Reference string `json:"reference"`
+	Pass      bool   `json:"pass"`
+
+	// Violations describe the rules the image does not satisfy
+	Violations []string `json:"violations,omitempty"`
+
+	// Allowed are the allowlisted vulnerabilities found in the image
+	Allowed []string `json:"allowed,omitempty"`
+
+	// Expired are the vulnerabilities found in the image with expired allowlist entries
+	Expired []string `json:"expired,omitempty"`
+}
+
+// LoadPolicy reads and validates the vulnerability policy file.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading vulnerability policy: %w", err)
+	}
+	var policy Policy
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf("decoding vulnerability policy %s: %w", file, err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid vulnerability policy %s: %w", file, err)
+	}
+	return &policy, nil
+}
+
+// validate checks the policy, parsing the expiry dates of the allowlists.
+func (p *Policy) validate() error {
+	var errs []error
+	errs = append(errs, p.PolicyRules.validate())
+	errs = append(errs, validateAllowlist(p.Allowlist))
+	for i := range p.Exceptions {
+		e := &p.Exceptions[i]
+		if e.Image == "" {
+			errs = append(errs, fmt.Errorf("exception %d has no image", i))
+		} else if _, err := path.Match(e.Image, ""); err != nil {
+			errs = append(errs, fmt.Errorf("exception image pattern %q: %w", e.Image, err))
+		}
+		if e.Justification == "" {
+			errs = append(errs, fmt.Errorf("exception for %s has no justification", e.Image))
+		}
+		errs = append(errs, e.PolicyRules.validate(), validateAllowlist(e.Allowlist))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *PolicyRules) validate() error {
+	var errs []error
+	if _, ok := SeverityLevels[r.MaxSeverity]; r.MaxSeverity != "" && !ok {
+		errs = append(errs, fmt.Errorf("invalid maxSeverity %q, expected one of %v", r.MaxSeverity, orderedSeverities))
+	}
+	for sev, n := range r.MaxCounts {
+		if _, ok := SeverityLevels[sev]; !ok {
+			errs = append(errs, fmt.Errorf("invalid maxCounts severity %q, expected one of %v", sev, orderedSeverities))
+		}
+		if n < 0 {
+			errs = append(errs, fmt.Errorf("maxCounts of %s is negative", sev))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateAllowlist(allowlist []AllowedVulnerability) error {
+	var errs []error
+	for i := range allowlist {
+		a := &allowlist[i]
+		if a.ID == "" {
+			errs = append(errs, fmt.Errorf("allowlist entry %d has no id", i))
+		}
+		if a.Justification == "" {
+			errs = append(errs, fmt.Errorf("allowlist entry %s has no justification", a.ID))
+		}
+		for _, image := range a.Images {
+			if _, err := path.Match(image, ""); err != nil {
+				errs = append(errs, fmt.Errorf("allowlist entry %s image pattern %q: %w", a.ID, image, err))
+			}
+		}
+		if a.Expires == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, a.Expires)
+		if err != nil {
+			t, err = time.Parse(time.RFC3339, a.Expires)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("allowlist entry %s expires %q, expected YYYY-MM-DD or RFC 3339", a.ID, a.Expires))
+		}
+		a.expires = t
+	}
+	return errors.Join(errs...)
+}
+
+// matchImage reports whether the reference matches the path.Match pattern.
+func matchImage(pattern, reference string) bool {
+	ok, _ := path.Match(pattern, reference)
+	return ok
+}
+
+// rules returns the rules and the allowlist that apply to the image.
+func (p *Policy) rules(reference string) (PolicyRules, []AllowedVulnerability) {
+	rules := PolicyRules{
+		MaxSeverity: p.MaxSeverity,
+		MaxCounts:   map[string]int{},
+		FixedOnly:   p.FixedOnly,
+	}
+	for sev, n := range p.MaxCounts {
+		rules.MaxCounts[sev] = n
+	}
+	var allowlist []AllowedVulnerability
+	for _, a := range p.Allowlist {
+		if len(a.Images) == 0 || slices.ContainsFunc(a.Images, func(image string) bool { return matchImage(image, reference) }) {
+			allowlist = append(allowlist, a)
+		}
+	}
+
+	i := slices.IndexFunc(p.Exceptions, func(e PolicyException) bool { return matchImage(e.Image, reference) })
+	if i < 0 {
+		return rules, allowlist
+	}
+	e := p.Exceptions[i]
+	if e.MaxSeverity != "" {
+		rules.MaxSeverity = e.MaxSeverity
+	}
+	for sev, n := range e.MaxCounts {
+		rules.MaxCounts[sev] = n
+	}
+	if e.FixedOnly != nil {
+		rules.FixedOnly = e.FixedOnly
+	}
+	return rules, append(allowlist, e.Allowlist...)
+}
+
+// Evaluate evaluates the policy for the vulnerabilities found in the image with the reference.  Allowlist entries
+// expire at the time now.
+func (p *Policy) Evaluate(reference string, report *ArtifactScanReport, now time.Time) PolicyResult {
+	rules, allowlist := p.rules(reference)
+	fixedOnly := rules.FixedOnly != nil && *rules.FixedOnly
+	result := PolicyResult{Reference: reference}
+
+	// vulnerabilities counted against the rules, by severity
+	counted := map[string][]string{}
+	for _, sev := range orderedSeverities {
+		for _, m := range report.GetVulnerabilityMatches(sev) {
+			id := m.Vulnerabilities.ID
+			if slices.Contains(counted[sev], id) || slices.Contains(result.Allowed, id) || slices.Contains(result.Expired, id) {
+				continue
+			}
+			if i := slices.IndexFunc(allowlist, func(a AllowedVulnerability) bool { return a.ID == id }); i >= 0 {
+				if allowlist[i].expires.IsZero() || now.Before(allowlist[i].expires) {
+					result.Allowed = append(result.Allowed, id)
+					continue
+				}
+				result.Expired = append(result.Expired, id)
+			}
+			if fixedOnly && !m.Vulnerabilities.Fix.Available() {
+				continue
+			}
+			counted[sev] = append(counted[sev], id)
+		}
+	}
+
+	for _, sev := range orderedSeverities {
+		ids := counted[sev]
+		if len(ids) == 0 {
+			continue
+		}
+		n, limited := rules.MaxCounts[sev]
+		switch {
+		case rules.MaxSeverity != "" && sev == "unknown" && !limited:
+			// vulnerabilities of unknown severity may be of any severity
+			result.Violations = append(result.Violations,
+				fmt.Sprintf("%d vulnerabilities of unknown severity may exceed the maximum severity %s: %s", len(ids), rules.MaxSeverity, strings.Join(ids, ", ")))
+		case rules.MaxSeverity != "" && SeverityLevels[sev] > SeverityLevels[rules.MaxSeverity]:
+			result.Violations = append(result.Violations,
+				fmt.Sprintf("%d %s vulnerabilities exceed the maximum severity %s: %s", len(ids), sev, rules.MaxSeverity, strings.Join(ids, ", ")))
+		case limited && len(ids) > n:
+			result.Violations = append(result.Violations,
+				fmt.Sprintf("%d %s vulnerabilities exceed the maximum of %d", len(ids), sev, n))
+		}
+	}
+	result.Pass = len(result.Violations) == 0
+	return result
+}
+
+// PrintPolicySummary prints the pass or fail result of the vulnerability policy for each image, with the violations
+// of failed images.
+func PrintPolicySummary(out io.Writer, results []PolicyResult) error {
+	table := [][]string{{}, {"reference", "policy", "violations"}}
+	var failed int
+	for _, res := range results {
+		if res.Pass {
+			table = append(table, []string{res.Reference, "pass", ""})
+			continue
+		}
+		failed++
+		for i, v := range res.Violations {
+			status := ""
+			if i == 0 {
+				status = "fail"
+			}
+			table = append(table, []string{res.Reference, status, v})
+		}
+	}
+	table = append(table, []string{})
+	if err := PrintCustomTable(out, table); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "%d of %d images passed the vulnerability policy\n\n", len(results)-failed, len(results)); err != nil {
+		return fmt.Errorf("printing the policy summary: %w", err)
+	}
+	return nil
+}
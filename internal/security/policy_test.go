@@ -0,0 +1,152 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+maxSeverity: high
+maxCounts:
+  high: 1
+  medium: 2
+fixedOnly: true
+allowlist:
+- id: CVE-2024-0001
+  justification: the vulnerable function is not called
+  expires: 2025-06-01
+- id: CVE-2024-0002
+  justification: mitigated by the network policy
+  images: ["reg.example.com/app/*"]
+exceptions:
+- image: reg.example.com/legacy/*
+  justification: scheduled for removal
+  maxSeverity: critical
+  fixedOnly: false
+`
+
+func match(id, severity, fixState string) Matches {
+	return Matches{Vulnerabilities: Vulnerability{ID: id, Severity: severity, Fix: Fix{State: fixState}}}
+}
+
+func TestPolicy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testPolicy), 0o644))
+	policy, err := LoadPolicy(file)
+	require.NoError(t, err)
+
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("pass", func(t *testing.T) {
+		report := &ArtifactScanReport{
+			HighVulnerabilities:   []Matches{match("CVE-2024-1000", "High", "fixed")},
+			MediumVulnerabilities: []Matches{match("CVE-2024-1001", "Medium", "fixed"), match("CVE-2024-1002", "Medium", "fixed")},
+		}
+		res := policy.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.True(t, res.Pass, res.Violations)
+	})
+
+	t.Run("max severity", func(t *testing.T) {
+		report := &ArtifactScanReport{CriticalVulnerabilities: []Matches{match("CVE-2024-1000", "Critical", "fixed")}}
+		res := policy.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.False(t, res.Pass)
+		assert.Len(t, res.Violations, 1)
+		assert.Contains(t, res.Violations[0], "maximum severity high")
+	})
+
+	t.Run("max counts", func(t *testing.T) {
+		report := &ArtifactScanReport{HighVulnerabilities: []Matches{
+			match("CVE-2024-1000", "High", "fixed"),
+			match("CVE-2024-1001", "High", "fixed"),
+			match("CVE-2024-1001", "High", "fixed"), // the same vulnerability in another package
+		}}
+		res := policy.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.False(t, res.Pass)
+		assert.Equal(t, []string{"2 high vulnerabilities exceed the maximum of 1"}, res.Violations)
+	})
+
+	t.Run("fixed only", func(t *testing.T) {
+		report := &ArtifactScanReport{CriticalVulnerabilities: []Matches{match("CVE-2024-1000", "Critical", "not-fixed")}}
+		res := policy.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.True(t, res.Pass, res.Violations)
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		report := &ArtifactScanReport{CriticalVulnerabilities: []Matches{
+			match("CVE-2024-0001", "Critical", "fixed"),
+			match("CVE-2024-0002", "Critical", "fixed"),
+		}}
+		res := policy.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.True(t, res.Pass, res.Violations)
+		assert.ElementsMatch(t, []string{"CVE-2024-0001", "CVE-2024-0002"}, res.Allowed)
+
+		// the allowlist entry expired
+		res = policy.Evaluate("reg.example.com/app/web:v1", report, after)
+		assert.False(t, res.Pass)
+		assert.Equal(t, []string{"CVE-2024-0001"}, res.Expired)
+
+		// the allowlist entry is for other images
+		res = policy.Evaluate("reg.example.com/other/web:v1", report, before)
+		assert.False(t, res.Pass)
+		assert.Equal(t, []string{"CVE-2024-0001"}, res.Allowed)
+	})
+
+	t.Run("exception", func(t *testing.T) {
+		report := &ArtifactScanReport{
+			CriticalVulnerabilities: []Matches{match("CVE-2024-1000", "Critical", "fixed")},
+			HighVulnerabilities:     []Matches{match("CVE-2024-1001", "High", "not-fixed"), match("CVE-2024-1002", "High", "wont-fix")},
+		}
+		res := policy.Evaluate("reg.example.com/legacy/db:v1", report, before)
+		assert.False(t, res.Pass)
+		// the maximum count of the policy still applies, to vulnerabilities without fixes too
+		assert.Equal(t, []string{"2 high vulnerabilities exceed the maximum of 1"}, res.Violations)
+	})
+
+	t.Run("unknown severity", func(t *testing.T) {
+		report := &ArtifactScanReport{UnknownVulnerabilities: []Matches{match("CVE-2024-1000", "Unknown", "fixed")}}
+		res := policy.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.False(t, res.Pass)
+		assert.Equal(t, []string{"1 vulnerabilities of unknown severity may exceed the maximum severity high: CVE-2024-1000"}, res.Violations)
+
+		// allowlisted
+		report.UnknownVulnerabilities = []Matches{match("CVE-2024-0002", "Unknown", "fixed")}
+		res = policy.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.True(t, res.Pass, res.Violations)
+
+		// limited by a maximum count instead
+		limited := &Policy{PolicyRules: PolicyRules{MaxSeverity: "high", MaxCounts: map[string]int{"unknown": 1}}}
+		report.UnknownVulnerabilities = []Matches{match("CVE-2024-1000", "Unknown", "fixed")}
+		res = limited.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.True(t, res.Pass, res.Violations)
+		report.UnknownVulnerabilities = append(report.UnknownVulnerabilities, match("CVE-2024-1001", "Unknown", "fixed"))
+		res = limited.Evaluate("reg.example.com/app/web:v1", report, before)
+		assert.Equal(t, []string{"2 unknown vulnerabilities exceed the maximum of 1"}, res.Violations)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+		require.NoError(t, os.WriteFile(invalid, []byte(`
+maxSeverity: severe
+allowlist:
+- id: CVE-2024-0001
+  expires: tomorrow
+exceptions:
+- image: "reg.example.com/["
+`), 0o644))
+		_, err := LoadPolicy(invalid)
+		require.Error(t, err)
+		for _, msg := range []string{`invalid maxSeverity "severe"`, "CVE-2024-0001 has no justification", `expires "tomorrow"`, "exception image pattern", "has no justification"} {
+			assert.ErrorContains(t, err, msg)
+		}
+
+		require.NoError(t, os.WriteFile(invalid, []byte("maxSeverity: high\nmaxCount:\n  high: 1\n"), 0o644))
+		_, err = LoadPolicy(invalid)
+		assert.ErrorContains(t, err, "maxCount", "expected unknown fields to be rejected")
+	})
+}
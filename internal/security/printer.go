@@ -96,6 +96,11 @@ func PrintMarkdown(out io.Writer, results []*ArtifactDetails, vulnerabilityLevel
 	table := `| Reference | Critical Vulnerabilities | High Vulnerabilities | Medium Vulnerabilities | Platforms | OCI Compliance | SBOM exists | Signed |
 |-----|-----|-----|-----|-----|-----|-----|-----|`
 
+	if db := scannedDatabase(results); db != nil {
+		if _, err := fmt.Fprintf(out, "%s\n\n", db); err != nil {
+			return fmt.Errorf("printing the vulnerability database to markdown: %w", err)
+		}
+	}
 	_, err := fmt.Fprintln(out, table)
 	if err != nil {
 		return fmt.Errorf("printing the vulnerability table to markdown: %w", err)
@@ -153,9 +158,18 @@ func PrintMarkdown(out io.Writer, results []*ArtifactDetails, vulnerabilityLevel
 	return nil
 }
 
+// scannedDatabase returns the vulnerability database the results were scanned with, the same for all results.
+func scannedDatabase(results []*ArtifactDetails) *DatabaseStatus {
+	for _, res := range results {
+		if res.Database != nil {
+			return res.Database
+		}
+	}
+	return nil
+}
+
 // PrintCSV prints out the ArtifactDetails in CSV format to the io.Writer defined.
 func PrintCSV(out io.Writer, results []*ArtifactDetails, vulnerabilityLevel string) error {
-	orderedSeverities := []string{"critical", "high", "medium", "low", "negligible", "unknown"}
 	table := make([][]string, len(results)+1)
 	minLevel := SeverityLevels[strings.ToLower(vulnerabilityLevel)]
 	header := []string{"reference"}
@@ -183,7 +197,6 @@ func PrintCSV(out io.Writer, results []*ArtifactDetails, vulnerabilityLevel stri
 // PrintTable prints out the ArtifactDetails in a printed table format to the io.Writer defined.
 func PrintTable(out io.Writer, results []*ArtifactDetails, vulnerabilityLevel string, displayCVEs, displayPlatforms bool) error {
 	table := [][]string{}
-	orderedSeverities := []string{"critical", "high", "medium", "low", "negligible", "unknown"}
 	minLevel := SeverityLevels[strings.ToLower(vulnerabilityLevel)]
 	columns := 2
 	tableHeader := []string{"reference", "size"}
@@ -208,6 +221,11 @@ func PrintTable(out io.Writer, results []*ArtifactDetails, vulnerabilityLevel st
 	if err := PrintCustomTable(out, table); err != nil {
 		return err
 	}
+	if db := scannedDatabase(results); db != nil {
+		if _, err := fmt.Fprintf(out, "%s\n\n", db); err != nil {
+			return fmt.Errorf("printing the vulnerability database: %w", err)
+		}
+	}
 	if displayCVEs {
 		if err := printCVETable(out, results, vulnerabilityLevel); err != nil {
 			return err
@@ -230,7 +248,6 @@ func printCVETable(out io.Writer, results []*ArtifactDetails, vulnerabilityLevel
 	}
 
 	table := [][]string{{}}
-	orderedSeverities := []string{"critical", "high", "medium", "low", "negligible", "unknown"}
 	minLevel := SeverityLevels[strings.ToLower(vulnerabilityLevel)]
 	// create the table header
 	tableHeader := []string{"reference", "CVE", "severity"}
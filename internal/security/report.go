@@ -0,0 +1,144 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Standard report formats of the vulnerability results, for tools that ingest them.
+const (
+	ReportFormatSARIF = "sarif"
+	ReportFormatVEX   = "cyclonedx-vex"
+)
+
+// ReportFormats are the standard report formats.
+var ReportFormats = []string{ReportFormatSARIF, ReportFormatVEX}
+
+// imageFinding is a vulnerability found in a package of a scanned image.
+type imageFinding struct {
+	image    *ArtifactDetails
+	severity string // lowercase severity
+	Matches
+}
+
+// imageDigest returns the digest of the scanned image.
+func (ad *ArtifactDetails) imageDigest() digest.Digest {
+	return ad.desc.Digest
+}
+
+// packageRef identifies the package of the finding in the image.
+func (f *imageFinding) packageRef() string {
+	pkg := f.Artifact.PURL
+	if pkg == "" {
+		pkg = f.Artifact.Name + "@" + f.Artifact.Version
+	}
+	return f.image.imageDigest().String() + "/" + pkg
+}
+
+// findings returns the vulnerabilities of vulnerabilityLevel and higher found in the images, by image from the
+// highest to the lowest severity, once for each package.
+func findings(results []*ArtifactDetails, vulnerabilityLevel string) ([]imageFinding, error) {
+	minLevel, ok := SeverityLevels[strings.ToLower(vulnerabilityLevel)]
+	if !ok {
+		return nil, fmt.Errorf("invalid vulnerability level %s", vulnerabilityLevel)
+	}
+	var all []imageFinding
+	for _, res := range results {
+		seen := map[string]bool{}
+		for _, sev := range orderedSeverities {
+			if SeverityLevels[sev] < minLevel {
+				continue
+			}
+			for _, m := range res.CalculatedResults.GetVulnerabilityMatches(sev) {
+				f := imageFinding{image: res, severity: sev, Matches: m}
+				key := m.Vulnerabilities.ID + " " + f.packageRef()
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				all = append(all, f)
+			}
+		}
+	}
+	return all, nil
+}
+
+// EncodeReport encodes the vulnerabilities of vulnerabilityLevel and higher found in the images in a standard report
+// format, sarif or cyclonedx-vex.  The tool version is recorded as the version of ace-dt.
+func EncodeReport(format string, results []*ArtifactDetails, vulnerabilityLevel, toolVersion string) ([]byte, error) {
+	switch format {
+	case ReportFormatSARIF:
+		return encodeSARIF(results, vulnerabilityLevel, toolVersion)
+	case ReportFormatVEX:
+		return encodeVEX(results, vulnerabilityLevel, toolVersion)
+	default:
+		return nil, fmt.Errorf("unknown report format %q, expected one of %v", format, ReportFormats)
+	}
+}
+
+// PrintReport prints the vulnerability results in a standard report format to the io.Writer defined.
+func PrintReport(out io.Writer, format string, results []*ArtifactDetails, vulnerabilityLevel, toolVersion string) error {
+	data, err := EncodeReport(format, results, vulnerabilityLevel, toolVersion)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(out, string(data)); err != nil {
+		return fmt.Errorf("printing %s report: %w", format, err)
+	}
+	return nil
+}
+
+// AttachReports pushes a report in the standard report format for each image as a referrer of the image.
+func AttachReports(ctx context.Context, format string, results []*ArtifactDetails, vulnerabilityLevel, toolVersion string) error {
+	if !slices.Contains(ReportFormats, format) {
+		return fmt.Errorf("unknown report format %q, expected one of %v", format, ReportFormats)
+	}
+	artifactType, mediaType := ArtifactTypeSARIF, ArtifactTypeSARIF
+	if format == ReportFormatVEX {
+		artifactType, mediaType = ArtifactTypeVEX, MediaTypeVEX
+	}
+
+	for _, res := range results {
+		data, err := EncodeReport(format, []*ArtifactDetails{res}, vulnerabilityLevel, toolVersion)
+		if err != nil {
+			return err
+		}
+		annotations := map[string]string{}
+		if res.Database != nil {
+			annotations[AnnotationGrypeDatabaseChecksum] = res.Database.Checksum
+		}
+		desc, err := attachDocument(ctx, res.repository, res.desc, data, mediaType, artifactType, annotations)
+		if err != nil {
+			return fmt.Errorf("attaching the %s report to %s: %w", format, res.originatingReference, err)
+		}
+		logger.FromContext(ctx).InfoContext(ctx, "pushed report", "format", format, "reference", res.originatingReference, "reportDigest", desc.Digest)
+	}
+	return nil
+}
+
+// attachDocument pushes the document as the layer of a manifest referring to the subject.
+func attachDocument(ctx context.Context, target oras.GraphTarget, subject ocispec.Descriptor, data []byte, mediaType, artifactType string, annotations map[string]string) (ocispec.Descriptor, error) {
+	layer, err := oras.PushBytes(ctx, target, mediaType, data)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("pushing %s: %w", artifactType, err)
+	}
+	subject = ocispec.Descriptor{MediaType: subject.MediaType, Digest: subject.Digest, Size: subject.Size}
+	maniDesc, err := oras.PackManifest(ctx, target, oras.PackManifestVersion1_1, artifactType, oras.PackManifestOptions{
+		Subject:             &subject,
+		Layers:              []ocispec.Descriptor{layer},
+		ManifestAnnotations: annotations,
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("pushing %s manifest: %w", artifactType, err)
+	}
+	return maniDesc, nil
+}
@@ -0,0 +1,127 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry"
+)
+
+// testResults returns the scan results of an image pushed to the storage.
+func testResults(ctx context.Context, t *testing.T, storage *memory.Store) *ArtifactDetails {
+	t.Helper()
+	desc, err := oras.PackManifest(ctx, storage, oras.PackManifestVersion1_1, "application/vnd.example.image", oras.PackManifestOptions{})
+	require.NoError(t, err)
+
+	openssl := Artifact{Name: "openssl", Version: "3.0.11-1", Type: "deb", PURL: "pkg:deb/debian/openssl@3.0.11-1?distro=debian-12",
+		Locations: []Location{{Path: "/var/lib/dpkg/status"}}}
+	return &ArtifactDetails{
+		repository:           storage,
+		desc:                 desc,
+		originatingReference: "reg.example.com/app:v1",
+		Database:             &DatabaseStatus{Checksum: "sha256:0123"},
+		CalculatedResults: ArtifactScanReport{
+			CriticalVulnerabilities: []Matches{{
+				Vulnerabilities: Vulnerability{ID: "CVE-2024-0001", Severity: "Critical", Source: "https://security-tracker.debian.org/tracker/CVE-2024-0001",
+					Description: "A buffer overflow", Fix: Fix{State: "fixed", Versions: []string{"3.0.13-1"}}},
+				Artifact: openssl,
+			}},
+			MediumVulnerabilities: []Matches{
+				{Vulnerabilities: Vulnerability{ID: "CVE-2024-0002", Severity: "Medium", Fix: Fix{State: "not-fixed"}}, Artifact: openssl},
+				{Vulnerabilities: Vulnerability{ID: "CVE-2024-0002", Severity: "Medium", Fix: Fix{State: "not-fixed"}},
+					Artifact: Artifact{Name: "libssl3", Version: "3.0.11-1", PURL: "pkg:deb/debian/libssl3@3.0.11-1?distro=debian-12"}},
+			},
+			LowVulnerabilities: []Matches{{Vulnerabilities: Vulnerability{ID: "CVE-2024-0003", Severity: "Low"}, Artifact: openssl}},
+		},
+	}
+}
+
+func TestReports(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+	res := testResults(ctx, t, storage)
+	results := []*ArtifactDetails{res}
+
+	t.Run("sarif", func(t *testing.T) {
+		data, err := EncodeReport(ReportFormatSARIF, results, "medium", "v1.2.3")
+		require.NoError(t, err)
+		var doc sarifDocument
+		require.NoError(t, json.Unmarshal(data, &doc))
+		assert.Equal(t, "2.1.0", doc.Version)
+		require.Len(t, doc.Runs, 1)
+		run := doc.Runs[0]
+		assert.Equal(t, "v1.2.3", run.Tool.Driver.Version)
+		// the low vulnerability is below the level
+		assert.Len(t, run.Tool.Driver.Rules, 2)
+		require.Len(t, run.Results, 3)
+
+		r := run.Results[0]
+		assert.Equal(t, "CVE-2024-0001", r.RuleID)
+		assert.Equal(t, "error", r.Level)
+		assert.Equal(t, res.desc.Digest.String(), r.Properties.ImageDigest)
+		assert.Equal(t, "reg.example.com/app:v1", r.Properties.SourceReference)
+		assert.Equal(t, "var/lib/dpkg/status", r.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, "reg.example.com/app:v1@"+res.desc.Digest.String(), r.Locations[0].LogicalLocations[0].FullyQualifiedName)
+		assert.Contains(t, r.Message.Text, "fixed in 3.0.13-1")
+		assert.Equal(t, "warning", run.Results[1].Level)
+		assert.Equal(t, run.Results[1].RuleIndex, run.Results[2].RuleIndex)
+	})
+
+	t.Run("cyclonedx-vex", func(t *testing.T) {
+		data, err := EncodeReport(ReportFormatVEX, results, "low", "v1.2.3")
+		require.NoError(t, err)
+		var doc vexDocument
+		require.NoError(t, json.Unmarshal(data, &doc))
+		assert.Equal(t, "CycloneDX", doc.BOMFormat)
+		assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, doc.SerialNumber)
+
+		require.Len(t, doc.Components, 1)
+		image := doc.Components[0]
+		assert.Equal(t, "container", image.Type)
+		assert.Equal(t, "reg.example.com/app:v1", image.Name)
+		assert.Equal(t, res.desc.Digest.String(), image.Version)
+		assert.Len(t, image.Components, 2)
+
+		require.Len(t, doc.Vulnerabilities, 3)
+		v := doc.Vulnerabilities[1]
+		assert.Equal(t, "CVE-2024-0002", v.ID)
+		assert.Equal(t, "medium", v.Ratings[0].Severity)
+		assert.Equal(t, "in_triage", v.Analysis.State)
+		require.Len(t, v.Affects, 2)
+		for _, a := range v.Affects {
+			assert.Contains(t, []string{image.Components[0].BOMRef, image.Components[1].BOMRef}, a.Ref)
+		}
+		assert.Equal(t, "Upgrade openssl to 3.0.13-1", doc.Vulnerabilities[0].Recommendation)
+	})
+
+	t.Run("push", func(t *testing.T) {
+		for _, format := range ReportFormats {
+			require.NoError(t, AttachReports(ctx, format, results, "medium", "v1.2.3"))
+		}
+		for _, artifactType := range []string{ArtifactTypeSARIF, ArtifactTypeVEX} {
+			referrers, err := registry.Referrers(ctx, storage, res.desc, artifactType)
+			require.NoError(t, err)
+			require.Len(t, referrers, 1, artifactType)
+			assert.Equal(t, "sha256:0123", referrers[0].Annotations[AnnotationGrypeDatabaseChecksum])
+			assert.False(t, IsSBOM(referrers[0].ArtifactType), "reports must not be scanned as SBOMs")
+
+			data, err := content.FetchAll(ctx, storage, referrers[0])
+			require.NoError(t, err)
+			var manifest ocispec.Manifest
+			require.NoError(t, json.Unmarshal(data, &manifest))
+			require.Len(t, manifest.Layers, 1)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := EncodeReport("spdx", results, "medium", "v1.2.3")
+		assert.ErrorContains(t, err, "unknown report format")
+	})
+}
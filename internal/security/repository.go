@@ -39,6 +39,8 @@ type ArtifactDetails struct {
 	originatingReference string             // only needed for gather artifacts
 	shortenedName        string             // needed for graphing in mermaid
 	CalculatedResults    ArtifactScanReport `json:"results"`
+	Database             *DatabaseStatus    `json:"database,omitempty"` // the vulnerability database scanned with
+	Policy               *PolicyResult      `json:"policy,omitempty"`   // the result of the vulnerability policy
 }
 
 // GetArtifactDetails fetches the ArtifactDetails for a given reference.
@@ -147,12 +149,17 @@ func GetArtifactDetails( //nolint:gocognit
 	return maniDetails, nil
 }
 
+// OriginatingReference returns the reference the artifact was scanned from, the source reference of gathered artifacts.
+func (ad *ArtifactDetails) OriginatingReference() string {
+	return ad.originatingReference
+}
+
 func (ad *ArtifactDetails) handlePredecessors(checksum string) {
 	for _, p := range ad.predecessors {
 		switch p.ArtifactType {
 		case notationreg.ArtifactTypeNotation:
 			ad.signatureDigest = p.Digest.String()
-		case ArtifactTypeSPDX, ArtifactTypeHarborSBOM:
+		case ArtifactTypeSPDX, ArtifactTypeCycloneDX, ArtifactTypeHarborSBOM:
 			ad.manifestDigestSBOM = p.Digest.String()
 		case ArtifactTypeVulnerabilityReport:
 			if p.Annotations[AnnotationGrypeDatabaseChecksum] == checksum {
@@ -0,0 +1,203 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// sarifDocument is a SARIF 2.1.0 log, with the subset of the schema used for vulnerability results.
+type sarifDocument struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifRuleProps     `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProps struct {
+	SecuritySeverity string   `json:"security-severity"`
+	Tags             []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifResultProps  `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifResultProps struct {
+	ImageDigest      string   `json:"imageDigest"`
+	SourceReference  string   `json:"sourceReference"`
+	Package          string   `json:"package"`
+	InstalledVersion string   `json:"installedVersion"`
+	PURL             string   `json:"purl,omitempty"`
+	FixedVersions    []string `json:"fixedVersions,omitempty"`
+}
+
+// sarifLevels are the SARIF levels of the severities.
+var sarifLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+}
+
+// sarifSecuritySeverities are the scores code scanning dashboards rank the severities by.
+var sarifSecuritySeverities = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "2.0",
+}
+
+// encodeSARIF encodes the vulnerabilities found in the images as a SARIF log with a rule for each vulnerability and a
+// result for each vulnerable package.  Results are located in the image by the image digest and source reference.
+func encodeSARIF(results []*ArtifactDetails, vulnerabilityLevel, toolVersion string) ([]byte, error) {
+	all, err := findings(results, vulnerabilityLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "ace-dt",
+			Version:        toolVersion,
+			InformationURI: "https://github.com/act3-ai/data-tool",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	rules := map[string]int{}
+	for _, f := range all {
+		v := f.Vulnerabilities
+		level, ok := sarifLevels[f.severity]
+		if !ok {
+			level = "note"
+		}
+		score, ok := sarifSecuritySeverities[f.severity]
+		if !ok {
+			score = "0.0"
+		}
+
+		i, ok := rules[v.ID]
+		if !ok {
+			i = len(run.Tool.Driver.Rules)
+			rules[v.ID] = i
+			description := v.Description
+			if description == "" {
+				description = v.ID
+			}
+			rule := sarifRule{
+				ID:                   v.ID,
+				ShortDescription:     sarifMessage{Text: fmt.Sprintf("%s %s vulnerability", v.ID, f.severity)},
+				FullDescription:      sarifMessage{Text: description},
+				HelpURI:              v.Source,
+				DefaultConfiguration: sarifConfiguration{Level: level},
+				Properties:           sarifRuleProps{SecuritySeverity: score, Tags: []string{"security", "vulnerability", f.severity}},
+			}
+			if rule.HelpURI == "" && len(v.URLs) > 0 {
+				rule.HelpURI = v.URLs[0]
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		imageDigest := f.image.imageDigest().String()
+		message := fmt.Sprintf("%s in %s %s of %s", v.ID, f.Artifact.Name, f.Artifact.Version, f.image.originatingReference)
+		if v.Fix.Available() && len(v.Fix.Versions) > 0 {
+			message += ", fixed in " + strings.Join(v.Fix.Versions, ", ")
+		}
+		// the location of the package in the image, or the image itself
+		uri := f.image.originatingReference
+		if len(f.Artifact.Locations) > 0 {
+			uri = strings.TrimPrefix(f.Artifact.Locations[0].Path, "/")
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    v.ID,
+			RuleIndex: i,
+			Level:     level,
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               f.image.originatingReference,
+					FullyQualifiedName: f.image.originatingReference + "@" + imageDigest,
+					Kind:               "image",
+				}},
+			}},
+			PartialFingerprints: map[string]string{"vulnerability/package": v.ID + " " + f.packageRef()},
+			Properties: sarifResultProps{
+				ImageDigest:      imageDigest,
+				SourceReference:  f.image.originatingReference,
+				Package:          f.Artifact.Name,
+				InstalledVersion: f.Artifact.Version,
+				PURL:             f.Artifact.PURL,
+				FixedVersions:    v.Fix.Versions,
+			},
+		})
+	}
+
+	doc := sarifDocument{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding SARIF: %w", err)
+	}
+	return data, nil
+}
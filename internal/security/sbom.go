@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os/exec"
+	"time"
 
 	notationreg "github.com/notaryproject/notation-go/registry"
 	"github.com/opencontainers/go-digest"
@@ -17,9 +20,22 @@ import (
 	"github.com/act3-ai/go-common/pkg/logger"
 
 	"github.com/act3-ai/data-tool/internal/mirror/encoding"
+	"github.com/act3-ai/data-tool/internal/sbom/catalog"
 )
 
-func extractAndGrypeSBOMs(ctx context.Context, subjectDescriptor ocispec.Descriptor, target oras.GraphTarget, digestSBOM, grypeDBChecksum string, pushReport bool) ([]Results, error) {
+// Catalogers of the packages of images for SBOMs.
+const (
+	// CatalogerSyft generates SBOMs with the syft binary.
+	CatalogerSyft = "syft"
+	// CatalogerBuiltin generates SBOMs in-process, for hosts without syft.  It catalogs fewer ecosystems than syft, so
+	// the vulnerabilities of the packages it does not catalog are not found.
+	CatalogerBuiltin = "builtin"
+)
+
+// Catalogers are the catalogers of SBOMs, syft being the default.
+var Catalogers = []string{CatalogerSyft, CatalogerBuiltin}
+
+func extractAndGrypeSBOMs(ctx context.Context, subjectDescriptor ocispec.Descriptor, target oras.GraphTarget, digestSBOM, grypeDBChecksum string, pushReport bool, maxDBAge time.Duration) ([]Results, error) {
 	log := logger.FromContext(ctx)
 	results := []Results{}
 	// try and extract sbom
@@ -36,7 +52,7 @@ func extractAndGrypeSBOMs(ctx context.Context, subjectDescriptor ocispec.Descrip
 		if err != nil {
 			return nil, fmt.Errorf("fetching layer for %s: %w", digestSBOM, err)
 		}
-		res, err := grypeSBOM(ctx, rc)
+		res, err := grypeSBOM(ctx, rc, maxDBAge)
 		if err != nil {
 			return nil, err
 		}
@@ -66,13 +82,17 @@ func extractAndGrypeSBOMs(ctx context.Context, subjectDescriptor ocispec.Descrip
 }
 
 // GenerateSBOM will generate and attach an SBOM for a given artifact.
-// It will grype the SBOM inline and return a map of the SBOM descriptor and results.
+// It will grype the SBOM inline and return a map of the SBOM descriptor and results.  The cataloger generating the SBOM
+// is one of Catalogers, syft if empty.  A maxDBAge greater than 0 is the maximum database age already checked, so
+// grype does not check the age of the database itself.
 func GenerateSBOM( //nolint:gocognit
 	ctx context.Context,
 	reference,
 	grypeDBChecksum string,
 	repository oras.GraphTarget,
-	pushReport bool) (map[*ocispec.Descriptor]*Results, error) {
+	pushReport bool,
+	cataloger string,
+	maxDBAge time.Duration) (map[*ocispec.Descriptor]*Results, error) {
 	results := map[*ocispec.Descriptor]*Results{}
 	log := logger.FromContext(ctx)
 	// fetch the main descriptor
@@ -115,7 +135,7 @@ func GenerateSBOM( //nolint:gocognit
 		}
 		// get the manifests
 		for _, man := range idx.Manifests {
-			if man.ArtifactType == notationreg.ArtifactTypeNotation || man.ArtifactType == ArtifactTypeSPDX || man.ArtifactType == "application/vnd.in-toto+json" {
+			if man.ArtifactType == notationreg.ArtifactTypeNotation || IsSBOM(man.ArtifactType) || man.ArtifactType == "application/vnd.in-toto+json" {
 				continue
 			}
 			refMan := registry.Reference{
@@ -124,7 +144,7 @@ func GenerateSBOM( //nolint:gocognit
 				Reference:  man.Digest.String(),
 			}
 
-			res, err := GenerateSBOM(ctx, refMan.String(), grypeDBChecksum, repository, pushReport)
+			res, err := GenerateSBOM(ctx, refMan.String(), grypeDBChecksum, repository, pushReport, cataloger, maxDBAge)
 			if err != nil {
 				return nil, err
 			}
@@ -133,36 +153,23 @@ func GenerateSBOM( //nolint:gocognit
 			}
 		}
 	} else {
-		// exec out to syft to generate the SBOM
-		res, err := syftReference(ctx, reference)
+		res, err := imageSBOM(ctx, repository, desc, reference, cataloger)
 		if err != nil {
-			// syft regularly fails if passed a signature, helmfile, or bottle manifest. Ignore but log the error.
-			log.InfoContext(ctx, "failed SBOM generation", "reference", reference, "error", err)
-			return nil, nil
-		}
-
-		// Upload the SBOM... create a manifest and encode SBOM into a layer.
-		// The SBOM manifest Subject field must point to the digest of the main reference passed.
-		descSBOM, err := oras.PushBytes(ctx, repository, ocispec.MediaTypeImageLayer, res)
-		if err != nil {
-			return nil, fmt.Errorf("pushing SBOM: %w", err)
+			return nil, err
 		}
-
-		packOpts := oras.PackManifestOptions{
-			Subject:          &desc,
-			Layers:           []ocispec.Descriptor{descSBOM},
-			ConfigDescriptor: &descCfg,
+		if res == nil {
+			return nil, nil
 		}
 
 		log.InfoContext(ctx, "pushing SBOM", "reference", reference)
-		maniDesc, err := oras.PackManifest(ctx, repository, oras.PackManifestVersion1_1, ArtifactTypeSPDX, packOpts)
+		maniDesc, err := AttachSBOM(ctx, repository, desc, res, ArtifactTypeSPDX)
 		if err != nil {
-			return nil, fmt.Errorf("pushing SBOM manifest: %w", err)
+			return nil, err
 		}
 		log.InfoContext(ctx, "SBOM pushed", "reference", reference, "manifest", maniDesc.Digest.String())
 
 		// grype the SBOM and attach the results
-		grypeResults, err := grypeSBOM(ctx, io.NopCloser(bytes.NewReader(res)))
+		grypeResults, err := grypeSBOM(ctx, io.NopCloser(bytes.NewReader(res)), maxDBAge)
 		if err != nil {
 			return nil, err
 		}
@@ -185,9 +192,76 @@ func GenerateSBOM( //nolint:gocognit
 	return results, nil
 }
 
+// imageSBOM generates the SPDX SBOM of the image with the manifest desc and the reference with the cataloger.  Nil is
+// returned for artifacts that are not images, such as signatures, helm charts and bottles.
+func imageSBOM(ctx context.Context, repository oras.GraphTarget, desc ocispec.Descriptor, reference, cataloger string) ([]byte, error) {
+	log := logger.FromContext(ctx)
+
+	if cataloger == CatalogerBuiltin {
+		// catalog the image in-process, reading its layers through the repository
+		cat, err := catalog.Image(ctx, repository, desc, reference)
+		if errors.Is(err, catalog.ErrNotImage) {
+			// signatures, helm charts and bottles have no SBOM. Ignore but log them.
+			log.InfoContext(ctx, "skipping SBOM generation", "reference", reference, "error", err)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("generating SBOM for %s: %w", reference, err)
+		}
+		return cat.Encode(catalog.FormatSPDX, "ace-dt")
+	}
+
+	// exec out to syft to generate the SBOM
+	res, err := SyftSBOM(ctx, "registry:"+reference, catalog.FormatSPDX, "")
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		// syft regularly fails if passed a signature, helmfile, or bottle manifest. Ignore but log the error.
+		log.InfoContext(ctx, "failed SBOM generation", "reference", reference, "error", err)
+		return nil, nil
+	}
+	return res, nil
+}
+
+// AttachSBOM pushes the SBOM document with the artifact type to the target, as a referrer of the subject.  It returns
+// the descriptor of the SBOM manifest.
+func AttachSBOM(ctx context.Context, target oras.GraphTarget, subject ocispec.Descriptor, sbom []byte, artifactType string) (ocispec.Descriptor, error) {
+	descCfg, err := generateEmptyBlobDescriptor(ocispec.MediaTypeImageConfig, digest.Canonical)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	cfgExists, err := target.Exists(ctx, descCfg)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("checking existence of config: %w", err)
+	}
+	if !cfgExists {
+		if err := target.Push(ctx, descCfg, bytes.NewReader([]byte("{}"))); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("pushing empty config: %w", err)
+		}
+	}
+
+	// Create a manifest and encode SBOM into a layer.
+	// The SBOM manifest Subject field must point to the digest of the main reference passed.
+	descSBOM, err := oras.PushBytes(ctx, target, ocispec.MediaTypeImageLayer, sbom)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("pushing SBOM: %w", err)
+	}
+	packOpts := oras.PackManifestOptions{
+		Subject:          &subject,
+		Layers:           []ocispec.Descriptor{descSBOM},
+		ConfigDescriptor: &descCfg,
+	}
+	maniDesc, err := oras.PackManifest(ctx, target, oras.PackManifestVersion1_1, artifactType, packOpts)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("pushing SBOM manifest: %w", err)
+	}
+	return maniDesc, nil
+}
+
 // IsSBOM is a helper function that identifies whether the given artifact type belongs to an ASCE or Harbor SBOM.
 func IsSBOM(artifactType string) bool {
-	if artifactType == ArtifactTypeSPDX || artifactType == ArtifactTypeHarborSBOM {
+	if artifactType == ArtifactTypeSPDX || artifactType == ArtifactTypeCycloneDX || artifactType == ArtifactTypeHarborSBOM {
 		return true
 	}
 	return false
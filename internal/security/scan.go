@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/sync/errgroup"
@@ -26,6 +27,12 @@ type ScanOptions struct {
 	VulnerabilityLevel      string
 	DryRun                  bool
 	PushReport              bool
+
+	// Cataloger generates the SBOMs of the images, one of Catalogers, syft if empty
+	Cataloger string
+
+	// MaxDatabaseAge fails the scan if the vulnerability database is older, 0 accepts databases of any age
+	MaxDatabaseAge time.Duration
 }
 
 // ScanArtifacts will fetch the artifact details for each image in a source file or a mirror (gather) artifact.
@@ -67,11 +74,15 @@ func scan(ctx context.Context, //nolint:gocognit
 	g, gctx := errgroup.WithContext(ctx)
 	g.SetLimit(concurrency)
 
-	// get the grype db checksum
-	checksum, err := getGrypeDBChecksum(ctx)
+	// get the grype db checksum, refusing to scan with an outdated database
+	db, err := GetDatabaseStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err := db.CheckAge(opts.MaxDatabaseAge); err != nil {
+		return nil, err
+	}
+	checksum := db.Checksum
 
 	for i, source := range m {
 		g.Go(func() error {
@@ -101,6 +112,7 @@ func scan(ctx context.Context, //nolint:gocognit
 			}
 
 			artifactDetails.originatingReference = source[0]
+			artifactDetails.Database = db
 			// load the predecessor digests
 			artifactDetails.handlePredecessors(checksum)
 			if artifactDetails.resultsReport != nil {
@@ -136,7 +148,7 @@ func scan(ctx context.Context, //nolint:gocognit
 			switch {
 			case !opts.DryRun && artifactDetails.manifestDigestSBOM == "":
 				log.InfoContext(ctx, "Generating SBOM(s)...", "reference", artifactDetails.originatingReference)
-				grypeResults, err := GenerateSBOM(ctx, source[1], checksum, artifactDetails.repository, opts.PushReport)
+				grypeResults, err := GenerateSBOM(ctx, source[1], checksum, artifactDetails.repository, opts.PushReport, opts.Cataloger, opts.MaxDatabaseAge)
 				if err != nil {
 					return err
 				}
@@ -153,7 +165,7 @@ func scan(ctx context.Context, //nolint:gocognit
 
 			case artifactDetails.manifestDigestSBOM != "":
 				log.Info("SBOM Manifest found", "reference", artifactDetails.originatingReference, "digest", artifactDetails.manifestDigestSBOM)
-				grypeRes, err := extractAndGrypeSBOMs(gctx, artifactDetails.desc, artifactDetails.repository, artifactDetails.manifestDigestSBOM, checksum, opts.PushReport)
+				grypeRes, err := extractAndGrypeSBOMs(gctx, artifactDetails.desc, artifactDetails.repository, artifactDetails.manifestDigestSBOM, checksum, opts.PushReport, opts.MaxDatabaseAge)
 				if err != nil {
 					return err
 				}
@@ -171,7 +183,7 @@ func scan(ctx context.Context, //nolint:gocognit
 			default:
 				// use the reference from the *remote.Repository created by getManifestDetails, ensuring our reference
 				// contains the correct endpoint if it was changed
-				result, err := grypeReference(gctx, source[1])
+				result, err := grypeReference(gctx, source[1], opts.MaxDatabaseAge)
 				if err != nil {
 					return fmt.Errorf("gryping reference %s: %w", source[0], err)
 				}
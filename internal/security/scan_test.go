@@ -1,9 +1,14 @@
 package security
 
 import (
+	"context"
+	"os/exec"
 	"testing"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/act3-ai/data-tool/internal/sbom/catalog"
 )
 
 func TestScan(t *testing.T) {
@@ -18,3 +23,14 @@ func TestScan(t *testing.T) {
 	})
 
 }
+
+func TestSyftNotFound(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	_, err := SyftSBOM(context.Background(), "registry:reg.example.com/image:v1", catalog.FormatSPDX, "")
+	assert.ErrorIs(t, err, exec.ErrNotFound)
+	assert.ErrorContains(t, err, CatalogerBuiltin, "expected the builtin cataloger to be suggested")
+
+	// a missing syft fails the scan, instead of skipping the image as an artifact syft cannot catalog
+	_, err = imageSBOM(context.Background(), nil, ocispec.Descriptor{}, "reg.example.com/image:v1", CatalogerSyft)
+	assert.ErrorIs(t, err, exec.ErrNotFound)
+}
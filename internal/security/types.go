@@ -1,14 +1,29 @@
 package security
 
+import "github.com/act3-ai/data-tool/internal/sbom/catalog"
+
 const (
 	// ArtifactTypeSPDX is the standard artifact type for SPDX formatted SBOMs.
-	ArtifactTypeSPDX = "application/spdx+json"
+	ArtifactTypeSPDX = catalog.ArtifactTypeSPDX
+	// ArtifactTypeCycloneDX is the standard artifact type for CycloneDX formatted SBOMs.
+	ArtifactTypeCycloneDX = catalog.ArtifactTypeCycloneDX
 	// ArtifactTypeHarborSBOM is the artifact type given to SBOMs generated via the Harbor UI.
 	ArtifactTypeHarborSBOM = "application/vnd.goharbor.harbor.sbom.v1"
 	// ArtifactTypeVulnerabilityReport is the artifact type given to ASCE vulnerability results.
 	ArtifactTypeVulnerabilityReport = "application/vnd.act3-ace.data.cve.results+json"
 	// AnnotationGrypeDatabaseChecksum is the checksum of the grype database that is attached to the vulnerability results.
 	AnnotationGrypeDatabaseChecksum = "vnd.act3-ace.scan.database.checksum"
+	// AnnotationPolicyResult is the result of the vulnerability policy, pass or fail, of an image flagged by mirror gather.
+	AnnotationPolicyResult = "vnd.act3-ace.scan.policy.result"
+	// AnnotationPolicyViolations are the vulnerability policy violations of an image flagged by mirror gather.
+	AnnotationPolicyViolations = "vnd.act3-ace.scan.policy.violations"
+	// ArtifactTypeSARIF is the artifact type of vulnerability results in SARIF format.
+	ArtifactTypeSARIF = "application/sarif+json"
+	// ArtifactTypeVEX is the artifact type of vulnerability results in CycloneDX VEX format, which differs from the
+	// artifact type of CycloneDX SBOMs so that they are not scanned.
+	ArtifactTypeVEX = "application/vnd.act3-ace.data.cve.vex+json"
+	// MediaTypeVEX is the media type of CycloneDX VEX documents.
+	MediaTypeVEX = "application/vnd.cyclonedx+json"
 	// MediaTypeHelmChartConfig defines the expected media type of a helm chart config manifest.
 	MediaTypeHelmChartConfig = "application/vnd.cncf.helm.config.v1+json"
 )
@@ -26,18 +41,40 @@ type Matches struct {
 
 // Vulnerability represents a specific vulnerability for a given artifact.
 type Vulnerability struct {
-	ID          string `json:"id"`
-	Source      string `json:"dataSource"`
-	Severity    string `json:"severity"`
-	Description string `json:"description"`
-	State       string `json:"state"`
+	ID          string   `json:"id"`
+	Source      string   `json:"dataSource"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	Fix         Fix      `json:"fix"`
+	URLs        []string `json:"urls,omitempty"`
+}
+
+// Fix represents the fix state of a vulnerability.
+type Fix struct {
+	Versions []string `json:"versions,omitempty"`
+	State    string   `json:"state"` // fixed, not-fixed, wont-fix or unknown
+}
+
+// Available reports whether a fixed version is available.
+func (f Fix) Available() bool {
+	return f.State == "fixed"
 }
 
 // Artifact represents the identifying details for a given artifact.
 type Artifact struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Version   string     `json:"version"`
+	Type      string     `json:"type,omitempty"`
+	PURL      string     `json:"purl,omitempty"`
+	Locations []Location `json:"locations,omitempty"`
+}
+
+// Location represents where an artifact was found in an image.
+type Location struct {
+	Path    string `json:"path"`
+	LayerID string `json:"layerID,omitempty"`
 }
 
 // ArtifactScanReport formats the artifact's pertinent grype JSON results for printing.
@@ -59,3 +96,6 @@ var SeverityLevels = map[string]int{
 	"negligible": 1,
 	"unknown":    0,
 }
+
+// orderedSeverities lists the severity levels from the highest to the lowest.
+var orderedSeverities = []string{"critical", "high", "medium", "low", "negligible", "unknown"}
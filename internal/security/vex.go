@@ -0,0 +1,176 @@
+package security
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// vexDocument is a CycloneDX 1.5 BOM of the scanned images and their vulnerabilities.
+type vexDocument struct {
+	BOMFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	SerialNumber    string             `json:"serialNumber"`
+	Version         int                `json:"version"`
+	Metadata        vexMetadata        `json:"metadata"`
+	Components      []vexComponent     `json:"components"`
+	Vulnerabilities []vexVulnerability `json:"vulnerabilities"`
+}
+
+type vexMetadata struct {
+	Timestamp string   `json:"timestamp"`
+	Tools     vexTools `json:"tools"`
+}
+
+type vexTools struct {
+	Components []vexComponent `json:"components"`
+}
+
+type vexComponent struct {
+	BOMRef     string         `json:"bom-ref,omitempty"`
+	Type       string         `json:"type"`
+	Name       string         `json:"name"`
+	Version    string         `json:"version,omitempty"`
+	PURL       string         `json:"purl,omitempty"`
+	Components []vexComponent `json:"components,omitempty"`
+}
+
+type vexVulnerability struct {
+	ID             string        `json:"id"`
+	Source         *vexSource    `json:"source,omitempty"`
+	Ratings        []vexRating   `json:"ratings"`
+	Description    string        `json:"description,omitempty"`
+	Recommendation string        `json:"recommendation,omitempty"`
+	Analysis       vexAnalysis   `json:"analysis"`
+	Affects        []vexAffected `json:"affects"`
+}
+
+type vexSource struct {
+	URL string `json:"url"`
+}
+
+type vexRating struct {
+	Severity string `json:"severity"`
+	Method   string `json:"method"`
+}
+
+type vexAnalysis struct {
+	State string `json:"state"`
+}
+
+type vexAffected struct {
+	Ref      string              `json:"ref"`
+	Versions []vexAffectedStatus `json:"versions"`
+}
+
+type vexAffectedStatus struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// vexSeverity returns the CycloneDX severity of the severity.
+func vexSeverity(severity string) string {
+	if severity == "negligible" {
+		return "info"
+	}
+	return severity
+}
+
+// encodeVEX encodes the vulnerabilities found in the images as a CycloneDX VEX document.  Each image is a container
+// component, versioned by its digest and named by its source reference, with the vulnerable packages as its
+// components.  The vulnerabilities affect the packages, and are in triage until they are analyzed.
+func encodeVEX(results []*ArtifactDetails, vulnerabilityLevel, toolVersion string) ([]byte, error) {
+	all, err := findings(results, vulnerabilityLevel)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := vexDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: serial,
+		Version:      1,
+		Metadata: vexMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     vexTools{Components: []vexComponent{{Type: "application", Name: "ace-dt", Version: toolVersion}}},
+		},
+		Components:      []vexComponent{},
+		Vulnerabilities: []vexVulnerability{},
+	}
+
+	images := map[*ArtifactDetails]int{}
+	packages := map[string]bool{}
+	vulnerabilities := map[string]int{}
+	for _, f := range all {
+		i, ok := images[f.image]
+		if !ok {
+			i = len(doc.Components)
+			images[f.image] = i
+			imageDigest := f.image.imageDigest().String()
+			doc.Components = append(doc.Components, vexComponent{
+				BOMRef:  f.image.originatingReference + "@" + imageDigest,
+				Type:    "container",
+				Name:    f.image.originatingReference,
+				Version: imageDigest,
+			})
+		}
+		ref := f.packageRef()
+		if !packages[ref] {
+			packages[ref] = true
+			doc.Components[i].Components = append(doc.Components[i].Components, vexComponent{
+				BOMRef:  ref,
+				Type:    "library",
+				Name:    f.Artifact.Name,
+				Version: f.Artifact.Version,
+				PURL:    f.Artifact.PURL,
+			})
+		}
+
+		v := f.Vulnerabilities
+		j, ok := vulnerabilities[v.ID]
+		if !ok {
+			j = len(doc.Vulnerabilities)
+			vulnerabilities[v.ID] = j
+			vuln := vexVulnerability{
+				ID:          v.ID,
+				Ratings:     []vexRating{{Severity: vexSeverity(f.severity), Method: "other"}},
+				Description: v.Description,
+				Analysis:    vexAnalysis{State: "in_triage"},
+			}
+			if v.Source != "" {
+				vuln.Source = &vexSource{URL: v.Source}
+			}
+			doc.Vulnerabilities = append(doc.Vulnerabilities, vuln)
+		}
+		vuln := &doc.Vulnerabilities[j]
+		vuln.Affects = append(vuln.Affects, vexAffected{
+			Ref:      ref,
+			Versions: []vexAffectedStatus{{Version: f.Artifact.Version, Status: "affected"}},
+		})
+		if vuln.Recommendation == "" && v.Fix.Available() && len(v.Fix.Versions) > 0 {
+			vuln.Recommendation = fmt.Sprintf("Upgrade %s to %s", f.Artifact.Name, v.Fix.Versions[0])
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding CycloneDX VEX: %w", err)
+	}
+	return data, nil
+}
+
+// serialNumber returns a random UUID URN for a CycloneDX document.
+func serialNumber() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", fmt.Errorf("generating serial number: %w", err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
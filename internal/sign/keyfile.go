@@ -0,0 +1,264 @@
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	notationx509 "github.com/notaryproject/notation-core-go/x509"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of encrypted private keys.  The passphrase is
+// prompted for on the terminal if it is not set.
+const PassphraseEnv = "ACE_DT_KEY_PASSPHRASE"
+
+// encryptedPrivateKeyType is the PEM type of PKCS #8 encrypted private keys, as written by openssl.
+const encryptedPrivateKeyType = "ENCRYPTED PRIVATE KEY"
+
+// pbkdf2Iterations is the PBKDF2 iteration count used when encrypting private keys.
+const pbkdf2Iterations = 600000
+
+// maxPBKDF2Iterations bounds the PBKDF2 iteration count of encrypted private keys, so that a crafted key file cannot
+// stall decryption.
+const maxPBKDF2Iterations = 10000000
+
+// ErrIncorrectPassphrase is returned when an encrypted private key cannot be decrypted with the passphrase.
+var ErrIncorrectPassphrase = errors.New("incorrect passphrase for private key")
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo is the PKCS #8 EncryptedPrivateKeyInfo structure, see RFC 5208.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params are the PBES2 parameters, see RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the PBKDF2 parameters, see RFC 8018.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// EncryptPrivateKeyPEM encrypts a private key with a passphrase, returning a PKCS #8 "ENCRYPTED PRIVATE KEY" PEM
+// block.  The key is encrypted with AES-256-CBC, keyed with PBKDF2-HMAC-SHA256 of the passphrase, which openssl and
+// most other tools can read.
+func EncryptPrivateKeyPEM(key crypto.PrivateKey, passphrase []byte) ([]byte, error) {
+	plaintext, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling private key: %w", err)
+	}
+
+	salt := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("generating IV: %w", err)
+	}
+
+	block, err := pbes2Cipher(passphrase, salt, pbkdf2Iterations)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := append(plaintext, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pbkdf2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding PBKDF2 parameters: %w", err)
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, fmt.Errorf("encoding IV: %w", err)
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding PBES2 parameters: %w", err)
+	}
+	der, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: ciphertext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding encrypted private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: encryptedPrivateKeyType, Bytes: der}), nil
+}
+
+// DecryptPrivateKeyPEM decrypts a PKCS #8 "ENCRYPTED PRIVATE KEY" PEM block with the passphrase.  Only the PBES2
+// scheme with PBKDF2-HMAC-SHA256 and AES-256-CBC, as written by EncryptPrivateKeyPEM and openssl by default, is
+// supported.
+func DecryptPrivateKeyPEM(data, passphrase []byte) (crypto.PrivateKey, error) {
+	pemBlock, _ := pem.Decode(data)
+	if pemBlock == nil || pemBlock.Type != encryptedPrivateKeyType {
+		return nil, errors.New("no encrypted private key PEM block found")
+	}
+
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(pemBlock.Bytes, &info); err != nil {
+		return nil, fmt.Errorf("parsing encrypted private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption algorithm %s", info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("parsing PBES2 parameters: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) || !params.EncryptionScheme.Algorithm.Equal(oidAES256CBC) {
+		return nil, fmt.Errorf("unsupported PBES2 scheme %s with %s", params.KeyDerivationFunc.Algorithm, params.EncryptionScheme.Algorithm)
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, fmt.Errorf("parsing PBKDF2 parameters: %w", err)
+	}
+	if !kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA256) {
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudorandom function %s", kdfParams.PRF.Algorithm)
+	}
+	if kdfParams.IterationCount < 1 || kdfParams.IterationCount > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("unsupported PBKDF2 iteration count %d, must be at most %d", kdfParams.IterationCount, maxPBKDF2Iterations)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, errors.New("invalid AES-256-CBC IV")
+	}
+	if len(info.EncryptedData) == 0 || len(info.EncryptedData)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted private key length")
+	}
+
+	block, err := pbes2Cipher(passphrase, kdfParams.Salt, kdfParams.IterationCount)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.EncryptedData)
+
+	// a wrong passphrase is detected by invalid padding, or failing to parse the decrypted key
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrIncorrectPassphrase
+	}
+	key, err := x509.ParsePKCS8PrivateKey(plaintext[:len(plaintext)-padding])
+	if err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	return key, nil
+}
+
+// pbes2Cipher returns the AES-256 cipher keyed with PBKDF2-HMAC-SHA256 of the passphrase.
+func pbes2Cipher(passphrase, salt []byte, iterations int) (cipher.Block, error) {
+	key, err := pbkdf2.Key(sha256.New, string(passphrase), salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key from passphrase: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return block, nil
+}
+
+// IsEncryptedPrivateKeyPEM returns true if the PEM data is an encrypted private key.
+func IsEncryptedPrivateKeyPEM(data []byte) bool {
+	block, _ := pem.Decode(data)
+	return block != nil && block.Type == encryptedPrivateKeyType
+}
+
+// ReadPassphrase returns the passphrase for encrypted private keys from PassphraseEnv, or prompts for it on the
+// terminal, asking for it twice if confirm is set.
+func ReadPassphrase(confirm bool) ([]byte, error) {
+	if pass, ok := os.LookupEnv(PassphraseEnv); ok {
+		return []byte(pass), nil
+	}
+	return GetPassFromTerm(confirm)
+}
+
+// LoadPrivateKeyFile loads a PEM private key file, decrypting it with the passphrase from ReadPassphrase if it is
+// encrypted.
+func LoadPrivateKeyFile(path string) (crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading private key: %w", err)
+	}
+	if !IsEncryptedPrivateKeyPEM(data) {
+		return notationx509.ParsePrivateKeyPEM(data)
+	}
+	passphrase, err := ReadPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptPrivateKeyPEM(data, passphrase)
+}
+
+// MakeEcdsaCA creates a self-signed CA certificate for the private key, for issuing signing certificates with
+// MakeEcdsaCertPair.  The CA may only issue leaf certificates, and is valid for validity.
+func MakeEcdsaCA(cn string, privateKey *ecdsa.PrivateKey, validity time.Duration) (EcdsaCertPair, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return EcdsaCertPair{}, fmt.Errorf("generating serial number: %w", err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"act3-ace"}, CommonName: cn},
+		NotBefore:             now,
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return EcdsaCertPair{}, fmt.Errorf("creating CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return EcdsaCertPair{}, fmt.Errorf("parsing CA certificate: %w", err)
+	}
+	return EcdsaCertPair{Cert: cert, PrivateKey: privateKey}, nil
+}
+
+// CertificatesPEM encodes certificates as PEM, such as a certificate chain from the signing certificate to the root.
+func CertificatesPEM(certs ...*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,130 @@
+package sign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	notationx509 "github.com/notaryproject/notation-core-go/x509"
+)
+
+func TestEncryptPrivateKeyPEM(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := EncryptPrivateKeyPEM(key, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncryptedPrivateKeyPEM(data) {
+		t.Fatal("expected an encrypted private key")
+	}
+
+	t.Run("correct passphrase", func(t *testing.T) {
+		got, err := DecryptPrivateKeyPEM(data, []byte("correct horse"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.Equal(got) {
+			t.Error("decrypted key does not match the original key")
+		}
+	})
+
+	t.Run("incorrect passphrase", func(t *testing.T) {
+		if _, err := DecryptPrivateKeyPEM(data, []byte("battery staple")); !errors.Is(err, ErrIncorrectPassphrase) {
+			t.Errorf("expected ErrIncorrectPassphrase, got %v", err)
+		}
+	})
+
+	t.Run("excessive iteration count", func(t *testing.T) {
+		tampered := withIterationCount(t, data, maxPBKDF2Iterations+1)
+		if _, err := DecryptPrivateKeyPEM(tampered, []byte("correct horse")); err == nil || errors.Is(err, ErrIncorrectPassphrase) {
+			t.Errorf("expected the iteration count to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("load file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "encrypted.key")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(PassphraseEnv, "correct horse")
+		got, err := LoadPrivateKeyFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !key.Equal(got) {
+			t.Error("loaded key does not match the original key")
+		}
+	})
+}
+
+// withIterationCount returns the encrypted private key PEM block with the PBKDF2 iteration count replaced.
+func withIterationCount(t *testing.T, data []byte, iterations int) []byte {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	var info encryptedPrivateKeyInfo
+	var params pbes2Params
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		t.Fatal(err)
+	}
+
+	kdfParams.IterationCount = iterations
+	var err error
+	if params.KeyDerivationFunc.Parameters.FullBytes, err = asn1.Marshal(kdfParams); err != nil {
+		t.Fatal(err)
+	}
+	if info.Algorithm.Parameters.FullBytes, err = asn1.Marshal(params); err != nil {
+		t.Fatal(err)
+	}
+	if block.Bytes, err = asn1.Marshal(info); err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(block)
+}
+
+func TestMakeEcdsaCA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := MakeEcdsaCA("Test CA", key, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ca.Cert.IsCA || ca.Cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		t.Fatal("expected a CA certificate that can sign certificates")
+	}
+
+	signingKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := MakeEcdsaCertPair("signer", signingKey, &ca)
+	if !leaf.PrivateKey.PublicKey.Equal(leaf.Cert.PublicKey) {
+		t.Error("issued certificate is not for the signing key")
+	}
+	if leaf.Cert.NotAfter.After(ca.Cert.NotAfter) {
+		t.Error("issued certificate outlives the CA certificate")
+	}
+	chain := []*x509.Certificate{leaf.Cert, ca.Cert}
+	if err := notationx509.ValidateCodeSigningCertChain(chain, nil); err != nil {
+		t.Errorf("issued certificate chain is not valid for code signing: %v", err)
+	}
+}
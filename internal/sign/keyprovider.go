@@ -1,6 +1,7 @@
 package sign
 
 import (
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -18,6 +19,8 @@ import (
 	"time"
 
 	notationx509 "github.com/notaryproject/notation-core-go/x509"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/signer"
 	"github.com/opencontainers/go-digest"
 
 	"golang.org/x/term"
@@ -26,8 +29,17 @@ import (
 const privateKeyType = "EC PRIVATE KEY"
 const publicKeyType = "PUBLIC KEY"
 
+// SignerProvider provides a notation signer for a signing key.
+type SignerProvider interface {
+	// NotationSigner returns a notation signer for the key. The signer signs with certChain if it is not empty, or
+	// the certificate of the key otherwise.  Signers that produce their own certificate chain ignore certChain.
+	NotationSigner(ctx context.Context, certChain []*x509.Certificate) (notation.Signer, error)
+}
+
 // PrivateKeyProvider provide methods for accessing a private key and its corresponding public key.
 type PrivateKeyProvider interface {
+	SignerProvider
+
 	// PrivateKey returns a pointer to an ecdsa private key for signing. NOTE: consider opts for future expansion.
 	PrivateKey( /*opts ...PrivateKeyOption*/ ) (crypto.PrivateKey, error)
 	// PublicKeyPEM returns a PEM public key based on a private key. This can load the private key if necessary.
@@ -93,13 +105,14 @@ func (pkf *filePrivateKeyProvider) PublicKeyPEM() ([]byte, error) {
 }
 
 // PrivateKey returns the raw ecdsa private key, which may require loading from the
-// keyPath if it is not already present in its raw format.
+// keyPath if it is not already present in its raw format.  Encrypted keys are decrypted with the passphrase from
+// ReadPassphrase.
 func (pkf *filePrivateKeyProvider) PrivateKey() (crypto.PrivateKey, error) {
 	if pkf.pKey != nil {
 		return pkf.pKey, nil
 	}
 	var err error
-	pkf.pKey, err = notationx509.ReadPrivateKeyFile(pkf.privKeyPath)
+	pkf.pKey, err = LoadPrivateKeyFile(pkf.privKeyPath)
 	if err != nil {
 		return nil, fmt.Errorf("parsing private key from path %s: %w", pkf.privKeyPath, err)
 	}
@@ -119,6 +132,22 @@ func (pkf *filePrivateKeyProvider) Certificate() (*x509.Certificate, error) {
 	return pkf.cert, nil
 }
 
+// NotationSigner returns a notation signer for the private key.  Without certChain the signer signs with the
+// certificate chain in the cert file, such as a signing certificate followed by the CA certificate that issued it.
+func (pkf *filePrivateKeyProvider) NotationSigner(ctx context.Context, certChain []*x509.Certificate) (notation.Signer, error) {
+	if len(certChain) == 0 {
+		certs, err := notationx509.ReadCertificateFile(pkf.certPath)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate from path %s: %w", pkf.certPath, err)
+		}
+		if len(certs) == 0 {
+			return nil, fmt.Errorf("no certificates found in %s", pkf.certPath)
+		}
+		certChain = certs
+	}
+	return newGenericSigner(pkf, certChain)
+}
+
 // privateKeyProvider provides a private key.
 type privateKeyProvider struct {
 	pKey crypto.PrivateKey
@@ -170,6 +199,37 @@ func (pk *privateKeyProvider) Certificate() (*x509.Certificate, error) {
 	return nil, nil
 }
 
+// NotationSigner returns a notation signer for the private key.
+func (pk *privateKeyProvider) NotationSigner(ctx context.Context, certChain []*x509.Certificate) (notation.Signer, error) {
+	return newGenericSigner(pk, certChain)
+}
+
+// newGenericSigner creates a notation signer signing with the private key of pkProvider.  If certChain is empty the
+// certificate of pkProvider is used.
+func newGenericSigner(pkProvider PrivateKeyProvider, certChain []*x509.Certificate) (notation.Signer, error) {
+	privKey, err := pkProvider.PrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain private key for signing: %w", err)
+	}
+	if len(certChain) == 0 {
+		cert, err := pkProvider.Certificate()
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("retrieving certificate for notary signing: %w", err)
+		case cert == nil:
+			return nil, fmt.Errorf("unable to locate certificate for notary signing")
+		default:
+			certChain = []*x509.Certificate{cert}
+		}
+	}
+
+	notarySigner, err := signer.NewGenericSigner(privKey, certChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notary signer: %w", err)
+	}
+	return notarySigner, nil
+}
+
 // NewPrivateKeyProvider creates a simple private key provider with the raw private key.
 func NewPrivateKeyProvider(key *ecdsa.PrivateKey) PrivateKeyProvider {
 	return &privateKeyProvider{pKey: key}
@@ -251,34 +311,52 @@ type EcdsaCertPair struct {
 }
 
 // MakeEcdsaCertPair creates a new certificate and associates it with a private key.  If issuer is not nil, then the
-// provided issuer certificate is a parent certificate, otherwise a root certificate is created.
+// provided issuer certificate is a parent certificate, otherwise a root certificate is created.  It panics if the
+// certificate can not be created, see NewEcdsaCertPair.
 func MakeEcdsaCertPair(cn string, privateKey PrivateKeyProvider, issuer *EcdsaCertPair) EcdsaCertPair {
+	certPair, err := NewEcdsaCertPair(cn, privateKey, issuer)
+	if err != nil {
+		panic(err)
+	}
+	return certPair
+}
+
+// NewEcdsaCertPair is like MakeEcdsaCertPair, but returns an error if the certificate can not be created, such as when
+// the private key of the issuer does not match its certificate.
+func NewEcdsaCertPair(cn string, privateKey PrivateKeyProvider, issuer *EcdsaCertPair) (EcdsaCertPair, error) {
 	if privateKey == nil {
 		privateKey, _ = GenerateKeyPair()
 	}
 	privKey, _ := privateKey.PrivateKey()
 	ecdsaprivKey, ok := privKey.(*ecdsa.PrivateKey)
 	if !ok {
-		panic("private key is not ecdsa in MakeEcdsaCertPair")
+		return EcdsaCertPair{}, errors.New("private key is not ecdsa")
 	}
 	template := makeCertTemplate(cn, issuer == nil)
 
 	var certBytes []byte
 	var err error
 	if issuer != nil {
-		certBytes, err = x509.CreateCertificate(rand.Reader, template, issuer.Cert, &issuer.PrivateKey.PublicKey, issuer.PrivateKey)
+		// certificates must not outlive their issuer
+		if template.NotAfter.After(issuer.Cert.NotAfter) {
+			template.NotAfter = issuer.Cert.NotAfter
+		}
+		certBytes, err = x509.CreateCertificate(rand.Reader, template, issuer.Cert, &ecdsaprivKey.PublicKey, issuer.PrivateKey)
 	} else {
 		certBytes, err = x509.CreateCertificate(rand.Reader, template, template, &ecdsaprivKey.PublicKey, privKey)
 	}
 	if err != nil {
-		panic(err)
+		return EcdsaCertPair{}, fmt.Errorf("creating certificate: %w", err)
 	}
 
-	cert, _ := x509.ParseCertificate(certBytes)
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return EcdsaCertPair{}, fmt.Errorf("parsing certificate: %w", err)
+	}
 	return EcdsaCertPair{
 		Cert:       cert,
 		PrivateKey: ecdsaprivKey,
-	}
+	}, nil
 }
 
 func makeCertTemplate(cn string, isRoot bool) *x509.Certificate {
@@ -45,24 +45,9 @@ func (cbkr *certBasicKeyRetriever) RetrieveVerifier(hashFunc crypto.Hash) (Verif
 
 // NewCertRetriever constructs a certificate based key retriever for a jws or cose envelope.
 func NewCertRetriever(mediatype string, payload []byte) (KeyRetriever, error) {
-	var env signature.Envelope
-	var err error
-	switch mediatype {
-	case jws.MediaTypeEnvelope:
-		env, err = jws.ParseEnvelope(payload)
-		if err != nil {
-			return nil, fmt.Errorf("parsing jws signature envelope: %w", err)
-		}
-	case cose.MediaTypeEnvelope:
-		env, err = cose.ParseEnvelope(payload)
-		if err != nil {
-			return nil, fmt.Errorf("parsing cose signature envelope: %w", err)
-		}
-	default:
-		return nil, &signature.UnsupportedSignatureFormatError{MediaType: mediatype}
-	}
+	env, err := parseEnvelope(mediatype, payload)
 	if err != nil {
-		return nil, fmt.Errorf("parsing signature envelope: %w", err)
+		return nil, err
 	}
 
 	content, err := env.Content()
@@ -76,3 +61,23 @@ func NewCertRetriever(mediatype string, payload []byte) (KeyRetriever, error) {
 		cert: rootCA,
 	}, nil
 }
+
+// parseEnvelope parses a jws or cose signature envelope.
+func parseEnvelope(mediatype string, payload []byte) (signature.Envelope, error) {
+	switch mediatype {
+	case jws.MediaTypeEnvelope:
+		env, err := jws.ParseEnvelope(payload)
+		if err != nil {
+			return nil, fmt.Errorf("parsing jws signature envelope: %w", err)
+		}
+		return env, nil
+	case cose.MediaTypeEnvelope:
+		env, err := cose.ParseEnvelope(payload)
+		if err != nil {
+			return nil, fmt.Errorf("parsing cose signature envelope: %w", err)
+		}
+		return env, nil
+	default:
+		return nil, &signature.UnsupportedSignatureFormatError{MediaType: mediatype}
+	}
+}
@@ -23,7 +23,7 @@ const (
 )
 
 // LoadLocalSignatures loads a signature image manifest at a local path, returning a handler for the signature collection.
-func LoadLocalSignatures(ctx context.Context, targetDescriptor ocispec.Descriptor, localPath string) (SigsHandler, error) {
+func LoadLocalSignatures(ctx context.Context, targetDescriptor ocispec.Descriptor, localPath string) (*NotarySignatures, error) {
 	log := logger.FromContext(ctx)
 
 	log.InfoContext(ctx, "Loading existing signatures")
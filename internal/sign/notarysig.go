@@ -10,13 +10,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/notaryproject/notation-core-go/signature"
 	"github.com/notaryproject/notation-core-go/signature/cose"
 	"github.com/notaryproject/notation-core-go/signature/jws"
 	"github.com/notaryproject/notation-go"
 	notationreg "github.com/notaryproject/notation-go/registry"
-	"github.com/notaryproject/notation-go/signer"
 	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/specs-go"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -40,6 +40,9 @@ type NotarySignatures struct {
 	// Certificate chain for signing
 	CertChain []*x509.Certificate
 
+	// ExpiryDuration is how long new signatures are valid after signing, signatures do not expire if zero.
+	ExpiryDuration time.Duration
+
 	// LocalPath is the local path to the signature image directory.
 	LocalPath string
 
@@ -50,10 +53,11 @@ type NotarySignatures struct {
 	SigFailed []digest.Digest
 }
 
-// Sign signs a manifest digest along with annotations using the private key provided.
+// Sign signs a manifest digest along with annotations using the signing key provided.
 // Sign implements the SigsHandler interface.
-// The LoadLocalSignatures function should be used prior to calling this method.
-func (notarySigs *NotarySignatures) Sign(ctx context.Context, pkProvider PrivateKeyProvider, unsignedAnnos map[string]string, signedAnnos map[string]string) error {
+// The LoadLocalSignatures function should be used prior to calling this method.  Without a LocalPath the signature
+// is only held in memory, see Push.
+func (notarySigs *NotarySignatures) Sign(ctx context.Context, signerProvider SignerProvider, unsignedAnnos map[string]string, signedAnnos map[string]string) error {
 	log := logger.FromContext(ctx)
 
 	if unsignedAnnos == nil {
@@ -63,30 +67,14 @@ func (notarySigs *NotarySignatures) Sign(ctx context.Context, pkProvider Private
 		signedAnnos = make(map[string]string)
 	}
 
-	privKey, err := pkProvider.PrivateKey()
-	if err != nil {
-		return fmt.Errorf("failed to obtain private key for signing: %w", err)
-	}
-	if notarySigs.CertChain == nil {
-		cert, err := pkProvider.Certificate()
-		switch {
-		case err != nil:
-			return fmt.Errorf("retrieving certificate for notary signing: %w", err)
-		case cert == nil:
-			return fmt.Errorf("unable to locate certificate for notary signing")
-		default:
-			notarySigs.CertChain = []*x509.Certificate{cert}
-		}
-	}
-
-	notarySigner, err := signer.NewGenericSigner(privKey, notarySigs.CertChain)
+	notarySigner, err := signerProvider.NotationSigner(ctx, notarySigs.CertChain)
 	if err != nil {
-		return fmt.Errorf("failed to create notary signer: %w", err)
+		return err
 	}
 
 	sigOpts := notation.SignerSignOptions{
 		SignatureMediaType: jws.MediaTypeEnvelope, // we always sign with jws, but support verification for cose as well
-		ExpiryDuration:     0,
+		ExpiryDuration:     notarySigs.ExpiryDuration,
 		PluginConfig:       nil,
 		SigningAgent:       "ace-dt sign agent",
 	}
@@ -110,6 +98,11 @@ func (notarySigs *NotarySignatures) Sign(ctx context.Context, pkProvider Private
 	// add sigManifest to SigManifests
 	notarySigs.SigManifests = append(notarySigs.SigManifests, sigManifest)
 
+	// signatures of remote artifacts are held in memory until pushed
+	if notarySigs.LocalPath == "" {
+		return nil
+	}
+
 	// write the signature layer, and manifest.
 	log.InfoContext(ctx, "Writing signature and updating sig manifest")
 	err = notarySigs.WriteDisk(notarySigs.SignedSubject().Digest)
@@ -0,0 +1,73 @@
+package sign
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/signer"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// PluginBinaryPrefix is the prefix of the executable name of notation plugins, followed by the plugin name.
+const PluginBinaryPrefix = "notation-"
+
+// pluginSignerProvider provides a signer for a key held by a notation signer plugin.  The private key never leaves
+// the plugin, which generates the signature and provides the certificate chain.
+type pluginSignerProvider struct {
+	name   string
+	keyID  string
+	config map[string]string
+}
+
+// NewPluginSignerProvider creates a signer provider for the key keyID held by the notation signer plugin name.  The
+// plugin is located when signing, and receives config with each request.
+func NewPluginSignerProvider(name, keyID string, config map[string]string) SignerProvider {
+	return &pluginSignerProvider{
+		name:   name,
+		keyID:  keyID,
+		config: config,
+	}
+}
+
+// NotationSigner returns a notation signer delegating to the plugin.  The certificate chain is provided by the
+// plugin, so certChain is ignored.
+func (p *pluginSignerProvider) NotationSigner(ctx context.Context, certChain []*x509.Certificate) (notation.Signer, error) {
+	pl, err := FindPlugin(ctx, p.name)
+	if err != nil {
+		return nil, err
+	}
+	notarySigner, err := signer.NewPluginSigner(pl, p.keyID, p.config)
+	if err != nil {
+		return nil, fmt.Errorf("creating signer for plugin %s: %w", p.name, err)
+	}
+	return notarySigner, nil
+}
+
+// FindPlugin locates the notation plugin name, looking first in the notation plugin directory, as installed by
+// "notation plugin install", and then for a notation-<name> executable on the PATH.
+func FindPlugin(ctx context.Context, name string) (pluginframework.Plugin, error) {
+	pl, err := plugin.NewCLIManager(dir.PluginFS()).Get(ctx, name)
+	if err == nil {
+		return pl, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("loading plugin %s: %w", name, err)
+	}
+
+	path, err := exec.LookPath(PluginBinaryPrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s not found in the notation plugin directory or on the PATH: %w", name, err)
+	}
+	cliPlugin, err := plugin.NewCLIPlugin(ctx, name, path)
+	if err != nil {
+		return nil, fmt.Errorf("loading plugin %s: %w", name, err)
+	}
+	return cliPlugin, nil
+}
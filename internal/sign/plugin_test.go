@@ -0,0 +1,127 @@
+package sign
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/notaryproject/notation-plugin-framework-go/cli"
+
+	"github.com/act3-ai/data-tool/internal/sign/testplugin"
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+// runTestPluginEnv is set for the test binary to run as the test signer plugin when executed by ace-dt.
+const runTestPluginEnv = "ACE_DT_RUN_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runTestPluginEnv) != "" {
+		pluginCLI, err := cli.New(testplugin.New())
+		if err != nil {
+			os.Exit(1)
+		}
+		pluginCLI.Execute(context.Background(), os.Args)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// installTestPlugin puts the test binary on the PATH as the test signer plugin executable.
+func installTestPlugin(t *testing.T) {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	binDir := t.TempDir()
+	if err := os.Symlink(exe, filepath.Join(binDir, PluginBinaryPrefix+testplugin.Name)); err != nil {
+		t.Skipf("creating plugin symlink: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+	t.Setenv(runTestPluginEnv, "1")
+}
+
+func TestPluginSign(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	installTestPlugin(t)
+
+	bottleDir := t.TempDir()
+	subject := CreateSampleBottle(t, bottleDir)
+	sigDir := filepath.Join(bottleDir, ".signature")
+
+	ca := makeTestCA(t, "Test Root CA")
+	release := makeTestSigner(t, "Release", &ca)
+
+	keyDir := t.TempDir()
+	keyDER, err := x509.MarshalECPrivateKey(release.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, keyDir, "release.key", privateKeyType, keyDER)
+	var chainPEM []byte
+	for _, cert := range release.chain {
+		chainPEM = append(chainPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	if err := os.WriteFile(filepath.Join(keyDir, "release.crt"), chainPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, keyDir, "ca.crt", "CERTIFICATE", ca.Cert.Raw)
+
+	handler, err := LoadLocalSignatures(ctx, subject, sigDir)
+	if err != nil {
+		t.Fatalf("loading signatures handler: %v", err)
+	}
+	provider := NewPluginSignerProvider(testplugin.Name, filepath.Join(keyDir, "release.key"), nil)
+	if err := handler.Sign(ctx, provider, map[string]string{AnnotationUserID: "release"}, nil); err != nil {
+		t.Fatalf("signing with plugin: %v", err)
+	}
+
+	// the signature is verified from disk like any other
+	handler, err = LoadLocalSignatures(ctx, subject, sigDir)
+	if err != nil {
+		t.Fatalf("loading signatures handler: %v", err)
+	}
+	if pass, err := handler.Verify(ctx); !pass || err != nil {
+		t.Fatalf("verifying plugin signature: pass = %v, err = %v", pass, err)
+	}
+
+	policyFile := filepath.Join(keyDir, "trustpolicy.yaml")
+	if err := os.WriteFile(policyFile, []byte(`
+version: "1.0"
+trustStores:
+  release:
+    certificates: [ca.crt]
+trustPolicies:
+- name: release
+  registryScopes: ["*"]
+  trustStores: [release]
+  trustedIdentities: ["x509.subject: CN=Release"]
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadTrustPolicy(policyFile)
+	if err != nil {
+		t.Fatalf("loading trust policy: %v", err)
+	}
+	result, err := policy.Verify(subject, "", handler.Signatures())
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := result.Err(); err != nil {
+		t.Errorf("plugin signature not trusted: %v", err)
+	}
+}
+
+func TestPluginNotFound(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	t.Setenv("PATH", t.TempDir())
+
+	provider := NewPluginSignerProvider("missing", "key", nil)
+	if _, err := provider.NotationSigner(ctx, nil); err == nil {
+		t.Error("expected error for a missing plugin")
+	}
+}
@@ -0,0 +1,577 @@
+package sign
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Trusted identity prefixes of a trust policy statement.
+const (
+	IdentityX509Subject = "x509.subject:" // IdentityX509Subject matches attributes of the signing certificate subject.
+	IdentityUser        = "identity:"     // IdentityUser matches the identity a trusted key is bound to.
+)
+
+// TrustPolicy is a trust policy document for verifying bottle signatures, modeled after the notation trust policy.
+// Trust stores hold trusted root certificates and pinned public keys, and statements select the trust stores,
+// trusted identities, and the number of signatures required for the repositories in their registry scopes.
+type TrustPolicy struct {
+	Version       string                `json:"version"`
+	TrustStores   map[string]TrustStore `json:"trustStores"`
+	TrustPolicies []TrustStatement      `json:"trustPolicies"`
+
+	// RevocationLists are the lists of revoked signing keys consulted during verification
+	RevocationLists []RevocationListSource `json:"revocationLists,omitempty"`
+
+	stores  map[string]*loadedStore
+	revoked map[digest.Digest]RevokedKey // by fingerprint
+	now     func() time.Time
+}
+
+// RevocationListSource locates a revocation list, either a local file or a signed revocation list artifact in a
+// registry.
+type RevocationListSource struct {
+	// Path to a local revocation list file in YAML or JSON
+	Path string `json:"path,omitempty"`
+
+	// Reference of a revocation list artifact, its signatures must satisfy the trust policy statement for its
+	// repository
+	Reference string `json:"reference,omitempty"`
+}
+
+// TrustStore is a named set of trusted root certificates and public keys.
+type TrustStore struct {
+	// Certificates are paths to PEM files of trusted root CA certificates
+	Certificates []string `json:"certificates,omitempty"`
+
+	// Keys are trusted public keys, trusting signatures of certificates for the key regardless of their issuer
+	Keys []TrustedKey `json:"keys,omitempty"`
+}
+
+// TrustedKey is a pinned public key, optionally bound to the signature annotations of its owner.  A signature of the
+// key must not claim another identity, key ID, or verify API than the key is bound to.
+type TrustedKey struct {
+	// Path to a PEM public key, or a PEM certificate of the key
+	Path string `json:"path"`
+
+	// Identity is the key owner's identity, see AnnotationUserID
+	Identity string `json:"identity,omitempty"`
+
+	// KeyID is the title of the key, see AnnotationKeyID
+	KeyID string `json:"keyID,omitempty"`
+
+	// VerifyAPI is the API used to access the key, see AnnotationVerifyAPI
+	VerifyAPI string `json:"verifyAPI,omitempty"`
+}
+
+// TrustStatement is a trust policy for the repositories in its registry scopes.
+type TrustStatement struct {
+	// Name identifies the statement in verification reports
+	Name string `json:"name"`
+
+	// RegistryScopes are repositories, such as "registry.example.com/project/bottle", path patterns such as
+	// "registry.example.com/project/*", or "*" for all repositories, including local bottles
+	RegistryScopes []string `json:"registryScopes"`
+
+	// TrustStores are the names of the trust stores trusted by the statement
+	TrustStores []string `json:"trustStores"`
+
+	// TrustedIdentities are the signers trusted by the statement, "*" for any signer with a trusted key or
+	// certificate, "x509.subject: DN" for signing certificates with the subject attributes, or "identity: NAME" for
+	// trusted keys bound to the identity
+	TrustedIdentities []string `json:"trustedIdentities"`
+
+	// RequiredSignatures is the number of distinct signers required, 1 if zero
+	RequiredSignatures int `json:"requiredSignatures,omitempty"`
+}
+
+// loadedStore is a trust store with its certificates and keys loaded.
+type loadedStore struct {
+	roots *x509.CertPool
+	keys  map[digest.Digest]TrustedKey // by fingerprint
+}
+
+// LoadTrustPolicy loads and validates a trust policy document in YAML or JSON, with the certificates and keys of its
+// trust stores.  Relative paths are relative to the directory of the document.
+func LoadTrustPolicy(file string) (*TrustPolicy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading trust policy: %w", err)
+	}
+	policy, err := ParseTrustPolicy(data, filepath.Dir(file))
+	if err != nil {
+		return nil, fmt.Errorf("trust policy %s: %w", file, err)
+	}
+	return policy, nil
+}
+
+// ParseTrustPolicy parses and validates a trust policy document, loading the certificates and keys of its trust stores
+// relative to dir.
+func ParseTrustPolicy(data []byte, dir string) (*TrustPolicy, error) {
+	policy := &TrustPolicy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("parsing trust policy: %w", err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
+	policy.stores = make(map[string]*loadedStore, len(policy.TrustStores))
+	for name, store := range policy.TrustStores {
+		loaded, err := store.load(dir)
+		if err != nil {
+			return nil, fmt.Errorf("trust store %s: %w", name, err)
+		}
+		policy.stores[name] = loaded
+	}
+
+	policy.revoked = make(map[digest.Digest]RevokedKey)
+	policy.now = time.Now
+	for _, src := range policy.RevocationLists {
+		if src.Path == "" {
+			continue // fetched by the caller, see Revoke
+		}
+		list, err := LoadRevocationList(resolvePath(dir, src.Path))
+		if err != nil {
+			return nil, err
+		}
+		policy.Revoke(list)
+	}
+	return policy, nil
+}
+
+// Revoke rejects all signatures of the keys in the revocation list, and signatures with a certificate chain
+// containing one of the keys.
+func (p *TrustPolicy) Revoke(list *RevocationList) {
+	for _, key := range list.Revoked {
+		p.revoked[key.Fingerprint] = key
+	}
+}
+
+// validate checks the statements of the trust policy.
+func (p *TrustPolicy) validate() error {
+	if p.Version != "" && p.Version != "1.0" {
+		return fmt.Errorf("unsupported trust policy version %q", p.Version)
+	}
+	if len(p.TrustPolicies) == 0 {
+		return errors.New("no trust policy statements")
+	}
+
+	names := make(map[string]bool, len(p.TrustPolicies))
+	scopes := make(map[string]string)
+	for _, s := range p.TrustPolicies {
+		switch {
+		case s.Name == "":
+			return errors.New("trust policy statement without a name")
+		case names[s.Name]:
+			return fmt.Errorf("duplicate trust policy statement %s", s.Name)
+		case len(s.RegistryScopes) == 0:
+			return fmt.Errorf("trust policy statement %s has no registry scopes", s.Name)
+		case len(s.TrustStores) == 0:
+			return fmt.Errorf("trust policy statement %s has no trust stores", s.Name)
+		case len(s.TrustedIdentities) == 0:
+			return fmt.Errorf("trust policy statement %s has no trusted identities", s.Name)
+		case s.RequiredSignatures < 0:
+			return fmt.Errorf("trust policy statement %s requires a negative number of signatures", s.Name)
+		}
+		names[s.Name] = true
+
+		for _, scope := range s.RegistryScopes {
+			if other, ok := scopes[scope]; ok {
+				return fmt.Errorf("registry scope %s is in trust policy statements %s and %s", scope, other, s.Name)
+			}
+			if _, err := path.Match(scope, ""); err != nil {
+				return fmt.Errorf("trust policy statement %s: invalid registry scope %s: %w", s.Name, scope, err)
+			}
+			scopes[scope] = s.Name
+		}
+		for _, store := range s.TrustStores {
+			if _, ok := p.TrustStores[store]; !ok {
+				return fmt.Errorf("trust policy statement %s: unknown trust store %s", s.Name, store)
+			}
+		}
+		for _, identity := range s.TrustedIdentities {
+			switch {
+			case identity == "*", strings.HasPrefix(identity, IdentityUser):
+			case strings.HasPrefix(identity, IdentityX509Subject):
+				if attrs, err := dnAttributes(strings.TrimPrefix(identity, IdentityX509Subject)); err != nil || len(attrs) == 0 {
+					return fmt.Errorf("trust policy statement %s: invalid distinguished name in trusted identity %q", s.Name, identity)
+				}
+			default:
+				return fmt.Errorf("trust policy statement %s: invalid trusted identity %q", s.Name, identity)
+			}
+		}
+	}
+	for i, src := range p.RevocationLists {
+		if (src.Path == "") == (src.Reference == "") {
+			return fmt.Errorf("revocation list %d must have either a path or a reference", i)
+		}
+	}
+	return nil
+}
+
+// load loads the certificates and keys of the trust store.
+func (s TrustStore) load(dir string) (*loadedStore, error) {
+	loaded := &loadedStore{
+		roots: x509.NewCertPool(),
+		keys:  make(map[digest.Digest]TrustedKey, len(s.Keys)),
+	}
+	for _, file := range s.Certificates {
+		data, err := os.ReadFile(resolvePath(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("reading certificates: %w", err)
+		}
+		if !loaded.roots.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates in %s", file)
+		}
+	}
+	for _, key := range s.Keys {
+		data, err := os.ReadFile(resolvePath(dir, key.Path))
+		if err != nil {
+			return nil, fmt.Errorf("reading key: %w", err)
+		}
+		pub, err := parsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", key.Path, err)
+		}
+		fp, err := FingerprintECDSA(pub)
+		if err != nil {
+			return nil, fmt.Errorf("fingerprinting key %s: %w", key.Path, err)
+		}
+		loaded.keys[fp] = key
+	}
+	return loaded, nil
+}
+
+// resolvePath returns the path relative to dir, unless it is absolute.
+func resolvePath(dir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+// parsePublicKeyPEM returns the public key of a PEM public key or certificate.
+func parsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	switch block.Type {
+	case publicKeyType:
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+		return pub, nil
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate: %w", err)
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %s", block.Type)
+	}
+}
+
+// Statement returns the trust policy statement for a repository, such as "registry.example.com/project/bottle".  A
+// statement with the repository in its registry scopes takes precedence over one with a matching pattern, the
+// longest matching pattern taking precedence over shorter ones, which takes precedence over the "*" statement.  An
+// empty repository, as for local bottles, only matches the "*" statement.
+func (p *TrustPolicy) Statement(repository string) (*TrustStatement, error) {
+	var pattern, wildcard *TrustStatement
+	var patternLen int
+	for i, s := range p.TrustPolicies {
+		for _, scope := range s.RegistryScopes {
+			switch {
+			case scope == "*":
+				wildcard = &p.TrustPolicies[i]
+			case repository == "":
+			case scope == repository:
+				return &p.TrustPolicies[i], nil
+			case len(scope) > patternLen:
+				if ok, _ := path.Match(scope, repository); ok {
+					pattern, patternLen = &p.TrustPolicies[i], len(scope)
+				}
+			}
+		}
+	}
+	switch {
+	case pattern != nil:
+		return pattern, nil
+	case wildcard != nil:
+		return wildcard, nil
+	case repository == "":
+		return nil, errors.New("no trust policy statement with registry scope \"*\" for local bottles")
+	default:
+		return nil, fmt.Errorf("no trust policy statement for repository %s", repository)
+	}
+}
+
+// SignatureResult is the outcome of verifying one signature against a trust policy statement.
+type SignatureResult struct {
+	Digest      digest.Digest // digest of the signature layer
+	Fingerprint digest.Digest // fingerprint of the signing key, see FingerprintECDSA
+	Signer      string        // trusted identity of the signer, if the signature is trusted
+	Err         error         // nil if the signature satisfies the statement
+}
+
+// PolicyResult is the outcome of verifying the signatures of a subject against a trust policy statement.
+type PolicyResult struct {
+	Statement  *TrustStatement
+	Signatures []SignatureResult
+}
+
+// Required returns the number of distinct signers the statement requires.
+func (r *PolicyResult) Required() int {
+	return max(r.Statement.RequiredSignatures, 1)
+}
+
+// Signers returns the number of distinct signers with signatures satisfying the statement.
+func (r *PolicyResult) Signers() int {
+	signers := make(map[digest.Digest]bool, len(r.Signatures))
+	for _, sig := range r.Signatures {
+		if sig.Err == nil {
+			signers[sig.Fingerprint] = true
+		}
+	}
+	return len(signers)
+}
+
+// Err returns an error if the statement is not satisfied.
+func (r *PolicyResult) Err() error {
+	if signers := r.Signers(); signers < r.Required() {
+		return fmt.Errorf("trust policy statement %s requires %d trusted signers, found %d", r.Statement.Name, r.Required(), signers)
+	}
+	return nil
+}
+
+// Verify verifies the signatures of the subject against the trust policy statement for the repository, see
+// Statement.  Each signature is checked for integrity, expiry, revocation, a signing certificate chaining to a trusted
+// root or a pinned public key, and a trusted signer identity.
+func (p *TrustPolicy) Verify(subject ocispec.Descriptor, repository string, sigs []Signature) (*PolicyResult, error) {
+	statement, err := p.Statement(repository)
+	if err != nil {
+		return nil, err
+	}
+	result := &PolicyResult{Statement: statement, Signatures: make([]SignatureResult, 0, len(sigs))}
+	for _, sig := range sigs {
+		sr := SignatureResult{Digest: sig.GetDescriptor().Digest}
+		sr.Signer, sr.Fingerprint, sr.Err = p.verifySignature(statement, subject, sig)
+		result.Signatures = append(result.Signatures, sr)
+	}
+	return result, nil
+}
+
+// signedPayload is the notation signature payload.
+type signedPayload struct {
+	TargetArtifact ocispec.Descriptor `json:"targetArtifact"`
+}
+
+// verifySignature verifies one signature against the statement, returning the trusted signer identity and the
+// fingerprint of the signing key.
+func (p *TrustPolicy) verifySignature(statement *TrustStatement, subject ocispec.Descriptor, sig Signature) (string, digest.Digest, error) {
+	data, err := sig.GetPayload()
+	if err != nil {
+		return "", "", fmt.Errorf("getting signature payload: %w", err)
+	}
+	env, err := parseEnvelope(sig.GetDescriptor().MediaType, data)
+	if err != nil {
+		return "", "", err
+	}
+	content, err := env.Verify()
+	if err != nil {
+		return "", "", fmt.Errorf("verifying signature integrity: %w", err)
+	}
+	var payload signedPayload
+	if err := json.Unmarshal(content.Payload.Content, &payload); err != nil {
+		return "", "", fmt.Errorf("decoding signed payload: %w", err)
+	}
+	if payload.TargetArtifact.Digest != subject.Digest || payload.TargetArtifact.Size != subject.Size {
+		return "", "", fmt.Errorf("signature is for %s, not %s", payload.TargetArtifact.Digest, subject.Digest)
+	}
+
+	chain := content.SignerInfo.CertificateChain
+	if len(chain) == 0 {
+		return "", "", errors.New("signature without a certificate chain")
+	}
+	leaf := chain[0]
+	fp, err := FingerprintECDSA(leaf.PublicKey)
+	if err != nil {
+		return "", "", fmt.Errorf("fingerprinting signing key: %w", err)
+	}
+	if err := p.checkRevoked(chain); err != nil {
+		return "", fp, err
+	}
+	if err := p.checkValidity(chain, content.SignerInfo.SignedAttributes); err != nil {
+		return "", fp, err
+	}
+
+	annotations, err := sig.Annotations()
+	if err != nil {
+		return "", "", fmt.Errorf("getting signature annotations: %w", err)
+	}
+	key, pinned, err := p.trust(statement, chain, fp, annotations)
+	if err != nil {
+		return "", fp, err
+	}
+
+	signer := "x509.subject: " + leaf.Subject.String()
+	if pinned && key.Identity != "" {
+		signer = "identity: " + key.Identity
+	}
+	for _, identity := range statement.TrustedIdentities {
+		switch {
+		case identity == "*":
+			return signer, fp, nil
+		case strings.HasPrefix(identity, IdentityX509Subject):
+			if subjectMatches(strings.TrimPrefix(identity, IdentityX509Subject), leaf.Subject.String()) {
+				return signer, fp, nil
+			}
+		case strings.HasPrefix(identity, IdentityUser):
+			if pinned && key.Identity != "" && strings.TrimSpace(strings.TrimPrefix(identity, IdentityUser)) == key.Identity {
+				return signer, fp, nil
+			}
+		}
+	}
+	return "", fp, fmt.Errorf("signer %s is not a trusted identity of trust policy statement %s", signer, statement.Name)
+}
+
+// trust checks that the signing certificate chains to a trusted root, or has a pinned key, of the trust stores of
+// the statement.  For a pinned key, the key is returned and the annotations must match its bindings.
+func (p *TrustPolicy) trust(statement *TrustStatement, chain []*x509.Certificate, fp digest.Digest, annotations map[string]string) (TrustedKey, bool, error) {
+	for _, name := range statement.TrustStores {
+		if key, ok := p.stores[name].keys[fp]; ok {
+			return key, true, checkBindings(key, annotations)
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	var errs []error
+	for _, name := range statement.TrustStores {
+		_, err := chain[0].Verify(x509.VerifyOptions{
+			CurrentTime:   p.now(),
+			Roots:         p.stores[name].roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		})
+		if err == nil {
+			return TrustedKey{}, false, nil
+		}
+		errs = append(errs, fmt.Errorf("trust store %s: %w", name, err))
+	}
+	return TrustedKey{}, false, fmt.Errorf("signing certificate is not trusted: %w", errors.Join(errs...))
+}
+
+// checkRevoked returns an error if the key of any certificate in the chain is revoked.
+func (p *TrustPolicy) checkRevoked(chain []*x509.Certificate) error {
+	for i, cert := range chain {
+		fp, err := FingerprintECDSA(cert.PublicKey)
+		if err != nil {
+			continue // keys that cannot be fingerprinted cannot be revoked
+		}
+		key, ok := p.revoked[fp]
+		if !ok {
+			continue
+		}
+		what := "signing key"
+		if i > 0 {
+			what = "key of issuer " + cert.Subject.String()
+		}
+		if key.Reason != "" {
+			return fmt.Errorf("%s %s is revoked: %s", what, fp, key.Reason)
+		}
+		return fmt.Errorf("%s %s is revoked", what, fp)
+	}
+	return nil
+}
+
+// checkValidity returns an error if the signature is expired, was signed outside the validity period of the signing
+// certificate, or a certificate of the chain is not currently valid.
+func (p *TrustPolicy) checkValidity(chain []*x509.Certificate, attrs signature.SignedAttributes) error {
+	now := p.now()
+	if !attrs.Expiry.IsZero() && now.After(attrs.Expiry) {
+		return fmt.Errorf("signature expired at %s", attrs.Expiry.Format(time.RFC3339))
+	}
+	leaf := chain[0]
+	if attrs.SigningTime.Before(leaf.NotBefore) || attrs.SigningTime.After(leaf.NotAfter) {
+		return fmt.Errorf("signed at %s, outside the validity period of the signing certificate from %s to %s",
+			attrs.SigningTime.Format(time.RFC3339), leaf.NotBefore.Format(time.RFC3339), leaf.NotAfter.Format(time.RFC3339))
+	}
+	for _, cert := range chain {
+		if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+			return fmt.Errorf("certificate %s is only valid from %s to %s", cert.Subject,
+				cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// checkBindings returns an error if the signature annotations claim another owner than the pinned key is bound to.
+func checkBindings(key TrustedKey, annotations map[string]string) error {
+	bindings := []struct{ annotation, bound string }{
+		{AnnotationUserID, key.Identity},
+		{AnnotationKeyID, key.KeyID},
+		{AnnotationVerifyAPI, key.VerifyAPI},
+	}
+	for _, b := range bindings {
+		if b.bound != "" && annotations[b.annotation] != b.bound {
+			return fmt.Errorf("signature annotation %s is %q, but the trusted key is bound to %q", b.annotation, annotations[b.annotation], b.bound)
+		}
+	}
+	return nil
+}
+
+// subjectMatches returns true if the certificate subject has all attributes of the distinguished name, such as
+// "CN=Release, O=ACT3".
+func subjectMatches(dn, subject string) bool {
+	have, err := dnAttributes(subject)
+	if err != nil {
+		return false
+	}
+	want, err := dnAttributes(dn)
+	if err != nil || len(want) == 0 {
+		return false
+	}
+	for _, attr := range want {
+		if !slices.Contains(have, attr) {
+			return false
+		}
+	}
+	return true
+}
+
+// dnAttributes parses a distinguished name in the format of RFC 4514 into its normalized "TYPE=value" attributes,
+// with the escaping of the values removed.
+func dnAttributes(dn string) ([]string, error) {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return nil, fmt.Errorf("parsing distinguished name %q: %w", dn, err)
+	}
+	var attrs []string
+	for _, rdn := range parsed.RDNs {
+		for _, attr := range rdn.Attributes {
+			attrs = append(attrs, strings.ToUpper(attr.Type)+"="+attr.Value)
+		}
+	}
+	return attrs, nil
+}
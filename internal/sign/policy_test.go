@@ -0,0 +1,338 @@
+package sign
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+// makeTestCA creates a root CA certificate able to issue signing certificates.
+func makeTestCA(t *testing.T, cn string) EcdsaCertPair {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"act3-ace"}, CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return EcdsaCertPair{Cert: cert, PrivateKey: key}
+}
+
+// testSigner is a signing key with its certificate chain.
+type testSigner struct {
+	key   *ecdsa.PrivateKey
+	chain []*x509.Certificate
+}
+
+// makeTestSigner creates a signing certificate issued by ca, or a self-signed certificate if ca is nil.
+func makeTestSigner(t *testing.T, cn string, ca *EcdsaCertPair) testSigner {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{Organization: []string{"act3-ace"}, CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		BasicConstraintsValid: true,
+	}
+	parent, parentKey := template, crypto.Signer(key)
+	if ca != nil {
+		parent, parentKey = ca.Cert, ca.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ca != nil {
+		return testSigner{key: key, chain: []*x509.Certificate{cert, ca.Cert}}
+	}
+	return testSigner{key: key, chain: []*x509.Certificate{cert}}
+}
+
+// signTestBottle signs the bottle manifest with the signer, returning the new signature.
+func signTestBottle(ctx context.Context, t *testing.T, dir string, subject ocispec.Descriptor, signer testSigner, annos map[string]string) Signature {
+	t.Helper()
+	notarySigs, err := LoadLocalSignatures(ctx, subject, dir)
+	if err != nil {
+		t.Fatalf("loading signatures handler: %v", err)
+	}
+	notarySigs.CertChain = signer.chain
+	pkp := &filePrivateKeyProvider{pKey: signer.key, cert: signer.chain[0]}
+	if err := notarySigs.Sign(ctx, pkp, annos, nil); err != nil {
+		t.Fatalf("signing test bottle: %v", err)
+	}
+	sigs := notarySigs.Signatures()
+	return sigs[len(sigs)-1]
+}
+
+// writePEM writes a PEM block to a file in dir.
+func writePEM(t *testing.T, dir, name, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrustPolicyVerify(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+
+	bottleDir := t.TempDir()
+	subject := CreateSampleBottle(t, bottleDir)
+	sigDir := filepath.Join(bottleDir, ".signature")
+
+	ca := makeTestCA(t, "Test Root CA")
+	release := makeTestSigner(t, "Release", &ca)
+	other := makeTestSigner(t, "Other", &ca)
+	pinned := makeTestSigner(t, "Pinned", nil)
+	untrusted := makeTestSigner(t, "Untrusted", nil)
+
+	releaseSig := signTestBottle(ctx, t, sigDir, subject, release, nil)
+	otherSig := signTestBottle(ctx, t, sigDir, subject, other, nil)
+	pinnedSig := signTestBottle(ctx, t, sigDir, subject, pinned, map[string]string{
+		AnnotationUserID:    "alice@example.com",
+		AnnotationKeyID:     "alice",
+		AnnotationVerifyAPI: "cert-basic",
+	})
+	spoofedSig := signTestBottle(ctx, t, sigDir, subject, pinned, map[string]string{
+		AnnotationUserID: "bob@example.com",
+	})
+	untrustedSig := signTestBottle(ctx, t, sigDir, subject, untrusted, nil)
+
+	policyDir := t.TempDir()
+	writePEM(t, policyDir, "ca.crt", "CERTIFICATE", ca.Cert.Raw)
+	pubDER, err := x509.MarshalPKIXPublicKey(&pinned.key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, policyDir, "alice.pub", publicKeyType, pubDER)
+
+	policyFile := filepath.Join(policyDir, "trustpolicy.yaml")
+	if err := os.WriteFile(policyFile, []byte(`
+version: "1.0"
+trustStores:
+  release:
+    certificates: [ca.crt]
+  alice:
+    keys:
+    - path: alice.pub
+      identity: alice@example.com
+      keyID: alice
+trustPolicies:
+- name: release
+  registryScopes: ["reg.example.com/release/*"]
+  trustStores: [release]
+  trustedIdentities: ["x509.subject: CN=Release, O=act3-ace"]
+- name: two-person
+  registryScopes: ["reg.example.com/release/critical"]
+  trustStores: [release, alice]
+  trustedIdentities: ["x509.subject: CN=Release", "identity: alice@example.com"]
+  requiredSignatures: 2
+- name: default
+  registryScopes: ["*"]
+  trustStores: [release, alice]
+  trustedIdentities: ["*"]
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadTrustPolicy(policyFile)
+	if err != nil {
+		t.Fatalf("loading trust policy: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		repository string
+		sigs       []Signature
+		statement  string
+		trusted    []bool
+		wantErr    bool
+	}{
+		{"ca signed", "reg.example.com/release/app", []Signature{releaseSig}, "release", []bool{true}, false},
+		{"untrusted subject", "reg.example.com/release/app", []Signature{otherSig}, "release", []bool{false}, true},
+		{"pinned key not in store", "reg.example.com/release/app", []Signature{pinnedSig}, "release", []bool{false}, true},
+		{"two signers", "reg.example.com/release/critical", []Signature{releaseSig, pinnedSig}, "two-person", []bool{true, true}, false},
+		{"same signer twice", "reg.example.com/release/critical", []Signature{releaseSig, releaseSig}, "two-person", []bool{true, true}, true},
+		{"spoofed identity", "reg.example.com/release/critical", []Signature{releaseSig, spoofedSig}, "two-person", []bool{true, false}, true},
+		{"local bottle", "", []Signature{releaseSig, otherSig, pinnedSig, untrustedSig}, "default", []bool{true, true, true, false}, false},
+		{"untrusted only", "", []Signature{untrustedSig}, "default", []bool{false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := policy.Verify(subject, tt.repository, tt.sigs)
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if result.Statement.Name != tt.statement {
+				t.Errorf("statement = %s, want %s", result.Statement.Name, tt.statement)
+			}
+			for i, sig := range result.Signatures {
+				if (sig.Err == nil) != tt.trusted[i] {
+					t.Errorf("signature %d trusted = %v, want %v: %v", i, sig.Err == nil, tt.trusted[i], sig.Err)
+				}
+			}
+			if err := result.Err(); (err != nil) != tt.wantErr {
+				t.Errorf("result error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("wrong subject", func(t *testing.T) {
+		result, err := policy.Verify(ocispec.Descriptor{Digest: "sha256:0000", Size: 1}, "", []Signature{releaseSig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := result.Signatures[0].Err; err == nil || !strings.Contains(err.Error(), "signature is for") {
+			t.Errorf("expected subject mismatch, got %v", err)
+		}
+	})
+}
+
+func TestParseTrustPolicyInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		errMsg string
+	}{
+		{"no statements", `version: "1.0"`, "no trust policy statements"},
+		{"unknown store", `
+trustPolicies:
+- name: a
+  registryScopes: ["*"]
+  trustStores: [missing]
+  trustedIdentities: ["*"]
+`, "unknown trust store missing"},
+		{"duplicate scope", `
+trustStores:
+  s: {}
+trustPolicies:
+- name: a
+  registryScopes: ["*"]
+  trustStores: [s]
+  trustedIdentities: ["*"]
+- name: b
+  registryScopes: ["*"]
+  trustStores: [s]
+  trustedIdentities: ["*"]
+`, "registry scope * is in trust policy statements a and b"},
+		{"bad identity", `
+trustStores:
+  s: {}
+trustPolicies:
+- name: a
+  registryScopes: ["*"]
+  trustStores: [s]
+  trustedIdentities: ["CN=foo"]
+`, "invalid trusted identity"},
+		{"bad distinguished name", `
+trustStores:
+  s: {}
+trustPolicies:
+- name: a
+  registryScopes: ["*"]
+  trustStores: [s]
+  trustedIdentities: ["x509.subject: CN"]
+`, "invalid distinguished name"},
+		{"unknown field", `
+trustPolicy: []
+`, "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTrustPolicy([]byte(tt.policy), t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTrustPolicyStatement(t *testing.T) {
+	policy := &TrustPolicy{TrustPolicies: []TrustStatement{
+		{Name: "registry", RegistryScopes: []string{"reg.example.com/*/*"}},
+		{Name: "project", RegistryScopes: []string{"reg.example.com/release/*"}},
+		{Name: "exact", RegistryScopes: []string{"reg.example.com/release/critical"}},
+		{Name: "default", RegistryScopes: []string{"*"}},
+	}}
+	tests := []struct {
+		repository string
+		statement  string
+	}{
+		{"reg.example.com/release/critical", "exact"},
+		{"reg.example.com/release/app", "project"},
+		{"reg.example.com/dev/app", "registry"},
+		{"other.example.com/app", "default"},
+		{"", "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repository, func(t *testing.T) {
+			statement, err := policy.Statement(tt.repository)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if statement.Name != tt.statement {
+				t.Errorf("statement = %s, want %s", statement.Name, tt.statement)
+			}
+		})
+	}
+}
+
+func TestSubjectMatches(t *testing.T) {
+	subject := pkix.Name{CommonName: "Release, Signing", Organization: []string{"act3-ace"}}
+	tests := []struct {
+		dn   string
+		want bool
+	}{
+		{`CN=Release\, Signing, O=act3-ace`, true},
+		{`o=act3-ace`, true},
+		{`CN=Release`, false},
+		{`CN=Release, O=act3-ace`, false},
+		{`CN`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dn, func(t *testing.T) {
+			if got := subjectMatches(tt.dn, subject.String()); got != tt.want {
+				t.Errorf("subjectMatches(%q, %q) = %v, want %v", tt.dn, subject.String(), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package sign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"sigs.k8s.io/yaml"
+)
+
+// ArtifactTypeRevocationList is the artifact type of revocation list artifacts, and the media type of their layer.
+const ArtifactTypeRevocationList = "application/vnd.act3-ace.signature.revocation-list.v1+json"
+
+// RevocationList is a list of revoked signing keys.  Signatures of a revoked key fail trust policy verification,
+// regardless of when they were made.
+type RevocationList struct {
+	Revoked []RevokedKey `json:"revoked"`
+}
+
+// RevokedKey is a revoked signing key.
+type RevokedKey struct {
+	// Fingerprint of the public key, see FingerprintECDSA and FingerprintPEM
+	Fingerprint digest.Digest `json:"fingerprint"`
+
+	// Reason the key was revoked, reported for rejected signatures
+	Reason string `json:"reason,omitempty"`
+
+	// RevokedAt is when the key was revoked
+	RevokedAt time.Time `json:"revokedAt"`
+}
+
+// LoadRevocationList loads a revocation list file in YAML or JSON.
+func LoadRevocationList(file string) (*RevocationList, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading revocation list: %w", err)
+	}
+	list, err := ParseRevocationList(data)
+	if err != nil {
+		return nil, fmt.Errorf("revocation list %s: %w", file, err)
+	}
+	return list, nil
+}
+
+// ParseRevocationList parses and validates a revocation list in YAML or JSON.
+func ParseRevocationList(data []byte) (*RevocationList, error) {
+	list := &RevocationList{}
+	if err := yaml.UnmarshalStrict(data, list); err != nil {
+		return nil, fmt.Errorf("parsing revocation list: %w", err)
+	}
+	for _, key := range list.Revoked {
+		if err := key.Fingerprint.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid fingerprint %q: %w", key.Fingerprint, err)
+		}
+	}
+	return list, nil
+}
+
+// Add revokes a key, updating the reason if the key is already revoked.
+func (l *RevocationList) Add(fingerprint digest.Digest, reason string) error {
+	if err := fingerprint.Validate(); err != nil {
+		return fmt.Errorf("invalid fingerprint %q: %w", fingerprint, err)
+	}
+	i := slices.IndexFunc(l.Revoked, func(key RevokedKey) bool { return key.Fingerprint == fingerprint })
+	if i >= 0 {
+		if reason != "" {
+			l.Revoked[i].Reason = reason
+		}
+		return nil
+	}
+	l.Revoked = append(l.Revoked, RevokedKey{
+		Fingerprint: fingerprint,
+		Reason:      reason,
+		RevokedAt:   time.Now().UTC().Truncate(time.Second),
+	})
+	return nil
+}
+
+// Write writes the revocation list to a file in YAML.
+func (l *RevocationList) Write(file string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("encoding revocation list: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return fmt.Errorf("writing revocation list: %w", err)
+	}
+	return nil
+}
+
+// PackRevocationList pushes the revocation list to storage as a revocation list artifact, returning the descriptor
+// of its manifest.
+func PackRevocationList(ctx context.Context, storage content.Pusher, list *RevocationList) (ocispec.Descriptor, error) {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("encoding revocation list: %w", err)
+	}
+	layer := content.NewDescriptorFromBytes(ArtifactTypeRevocationList, data)
+	if err := storage.Push(ctx, layer, bytes.NewReader(data)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return ocispec.Descriptor{}, fmt.Errorf("pushing revocation list: %w", err)
+	}
+
+	desc, err := oras.PackManifest(ctx, storage, oras.PackManifestVersion1_1, ArtifactTypeRevocationList, oras.PackManifestOptions{
+		Layers: []ocispec.Descriptor{layer},
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("packing revocation list manifest: %w", err)
+	}
+	return desc, nil
+}
+
+// FetchRevocationList fetches the revocation list artifact desc from source.  The revocation list is only returned if
+// its signatures satisfy the trust policy statement for its repository.
+func FetchRevocationList(ctx context.Context, source content.ReadOnlyGraphStorage, desc ocispec.Descriptor, policy *TrustPolicy, repository string) (*RevocationList, error) {
+	data, err := content.FetchAll(ctx, source, desc)
+	if err != nil {
+		return nil, fmt.Errorf("fetching revocation list manifest: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing revocation list manifest: %w", err)
+	}
+	if manifest.ArtifactType != ArtifactTypeRevocationList || len(manifest.Layers) != 1 {
+		return nil, fmt.Errorf("%s is not a revocation list artifact", desc.Digest)
+	}
+
+	sigs, err := FetchSignatures(ctx, source, desc)
+	if err != nil {
+		return nil, err
+	}
+	result, err := policy.Verify(desc, repository, sigs.Signatures())
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("revocation list %s is not trusted: %w", repository, err)
+	}
+
+	data, err = content.FetchAll(ctx, source, manifest.Layers[0])
+	if err != nil {
+		return nil, fmt.Errorf("fetching revocation list: %w", err)
+	}
+	return ParseRevocationList(data)
+}
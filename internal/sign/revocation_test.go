@@ -0,0 +1,189 @@
+package sign
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/act3-ai/go-common/pkg/logger"
+	tlog "github.com/act3-ai/go-common/pkg/test"
+)
+
+// writeTestPolicy writes a trust policy trusting signers issued by ca to dir, with the revocation list sources.
+func writeTestPolicy(t *testing.T, dir string, ca EcdsaCertPair, revocationLists string) string {
+	t.Helper()
+	writePEM(t, dir, "ca.crt", "CERTIFICATE", ca.Cert.Raw)
+	policyFile := filepath.Join(dir, "trustpolicy.yaml")
+	if err := os.WriteFile(policyFile, []byte(`
+version: "1.0"
+trustStores:
+  release:
+    certificates: [ca.crt]
+trustPolicies:
+- name: release
+  registryScopes: ["*"]
+  trustStores: [release]
+  trustedIdentities: ["*"]
+`+revocationLists), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return policyFile
+}
+
+func TestTrustPolicyRevocation(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+
+	bottleDir := t.TempDir()
+	subject := CreateSampleBottle(t, bottleDir)
+	sigDir := filepath.Join(bottleDir, ".signature")
+
+	ca := makeTestCA(t, "Test Root CA")
+	revoked := makeTestSigner(t, "Revoked", &ca)
+	other := makeTestSigner(t, "Other", &ca)
+	revokedSig := signTestBottle(ctx, t, sigDir, subject, revoked, nil)
+	otherSig := signTestBottle(ctx, t, sigDir, subject, other, nil)
+
+	revokedFP, err := FingerprintECDSA(&revoked.key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyDir := t.TempDir()
+	list := &RevocationList{}
+	if err := list.Add(revokedFP, "key compromised"); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Write(filepath.Join(policyDir, "revoked.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := LoadTrustPolicy(writeTestPolicy(t, policyDir, ca, `
+revocationLists:
+- path: revoked.yaml
+`))
+	if err != nil {
+		t.Fatalf("loading trust policy: %v", err)
+	}
+	result, err := policy.Verify(subject, "", []Signature{revokedSig, otherSig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := result.Signatures[0].Err; err == nil || !strings.Contains(err.Error(), "is revoked: key compromised") {
+		t.Errorf("expected revoked signature, got %v", err)
+	}
+	if result.Signatures[0].Fingerprint != revokedFP {
+		t.Errorf("fingerprint = %s, want %s", result.Signatures[0].Fingerprint, revokedFP)
+	}
+	if err := result.Signatures[1].Err; err != nil {
+		t.Errorf("expected trusted signature, got %v", err)
+	}
+
+	t.Run("revoked issuer", func(t *testing.T) {
+		caFP, err := FingerprintECDSA(&ca.PrivateKey.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policy.Revoke(&RevocationList{Revoked: []RevokedKey{{Fingerprint: caFP}}})
+		result, err := policy.Verify(subject, "", []Signature{otherSig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := result.Signatures[0].Err; err == nil || !strings.Contains(err.Error(), "key of issuer") {
+			t.Errorf("expected revoked issuer, got %v", err)
+		}
+	})
+}
+
+func TestTrustPolicyExpiry(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+
+	bottleDir := t.TempDir()
+	subject := CreateSampleBottle(t, bottleDir)
+
+	ca := makeTestCA(t, "Test Root CA")
+	signer := makeTestSigner(t, "Release", &ca)
+	notarySigs, err := LoadLocalSignatures(ctx, subject, filepath.Join(bottleDir, ".signature"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	notarySigs.CertChain = signer.chain
+	notarySigs.ExpiryDuration = time.Hour
+	if err := notarySigs.Sign(ctx, &filePrivateKeyProvider{pKey: signer.key, cert: signer.chain[0]}, nil, nil); err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	sigs := notarySigs.Signatures()
+
+	policy, err := LoadTrustPolicy(writeTestPolicy(t, t.TempDir(), ca, ""))
+	if err != nil {
+		t.Fatalf("loading trust policy: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		errMsg string
+	}{
+		{"valid", time.Now(), ""},
+		{"expired signature", time.Now().Add(2 * time.Hour), "signature expired at"},
+		{"expired certificate", time.Now().AddDate(2, 0, 0), "signature expired at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy.now = func() time.Time { return tt.at }
+			result, err := policy.Verify(subject, "", sigs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = result.Signatures[0].Err
+			switch {
+			case tt.errMsg == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)):
+				t.Errorf("error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestFetchRevocationList(t *testing.T) {
+	ctx := logger.NewContext(context.Background(), tlog.Logger(t, -2))
+	store := memory.New()
+
+	ca := makeTestCA(t, "Test Root CA")
+	security := makeTestSigner(t, "Security", &ca)
+	policy, err := LoadTrustPolicy(writeTestPolicy(t, t.TempDir(), ca, ""))
+	if err != nil {
+		t.Fatalf("loading trust policy: %v", err)
+	}
+
+	list := &RevocationList{}
+	if err := list.Add("sha256:5baaf83dc508530e90467d90579d5abb2458cd3ebdfec249e3bf76cf451a4728", "retired"); err != nil {
+		t.Fatal(err)
+	}
+	desc, err := PackRevocationList(ctx, store, list)
+	if err != nil {
+		t.Fatalf("packing revocation list: %v", err)
+	}
+
+	if _, err := FetchRevocationList(ctx, store, desc, policy, ""); err == nil || !strings.Contains(err.Error(), "not trusted") {
+		t.Errorf("expected unsigned revocation list to be rejected, got %v", err)
+	}
+
+	sigRoot := t.TempDir()
+	signTestBottle(ctx, t, filepath.Join(sigRoot, ".signature"), desc, security, nil)
+	if err := PrepareSigsGraph(ctx, sigRoot, store, desc); err != nil {
+		t.Fatalf("pushing signature: %v", err)
+	}
+
+	fetched, err := FetchRevocationList(ctx, store, desc, policy, "")
+	if err != nil {
+		t.Fatalf("fetching revocation list: %v", err)
+	}
+	if len(fetched.Revoked) != 1 || fetched.Revoked[0].Reason != "retired" {
+		t.Errorf("fetched revocation list = %+v", fetched)
+	}
+}
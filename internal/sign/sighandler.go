@@ -13,7 +13,7 @@ type SigsHandler interface {
 	// Sign signs a manifest digest. unsignedAnnos allows unsigned annotations to be included with a signature layer
 	// (such as userid/verify api), while signedAnnos are additional metadata to be included in the signed payload,
 	// such as attestation data.
-	Sign(ctx context.Context, pkProvider PrivateKeyProvider, unsignedAnnos, signedAnnos map[string]string) error
+	Sign(ctx context.Context, signerProvider SignerProvider, unsignedAnnos, signedAnnos map[string]string) error
 
 	// Verify verifies ALL existing signatures, using optional locally provided keys.  Returns true if all signatures
 	// verify or pass integrity (when the only public key is untrusted).  Additional details can be returned via errors
@@ -0,0 +1,30 @@
+// notation-ace-dt-test is a notation signer plugin backed by a local key file, for testing signer plugins.
+//
+// Install it in the notation plugin directory or on the PATH, then sign with a key such as:
+//
+//	keys:
+//	- alias: test
+//	  plugin: ace-dt-test
+//	  keyid: /path/to/signing.key
+//	  api: cert-basic
+//	  userid: tester
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/notaryproject/notation-plugin-framework-go/cli"
+
+	"github.com/act3-ai/data-tool/internal/sign/testplugin"
+)
+
+func main() {
+	pluginCLI, err := cli.New(testplugin.New())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	pluginCLI.Execute(context.Background(), os.Args)
+}
@@ -0,0 +1,179 @@
+// Package testplugin implements a notation signer plugin backed by a local key file.  It allows signer plugins to be
+// exercised end to end without a key management system or hardware token, and must not be used for release keys.
+package testplugin
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"path/filepath"
+	"strings"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	notationx509 "github.com/notaryproject/notation-core-go/x509"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// Name is the name of the plugin, its executable is notation-ace-dt-test.
+const Name = "ace-dt-test"
+
+// ConfigCertificate is the plugin config key for the path of the PEM certificate chain of the key.  It defaults to the
+// key path with a .crt extension.
+const ConfigCertificate = "certificate"
+
+// Plugin is a notation signer plugin signing with PEM encoded ECDSA private keys on disk.  The key ID is the path to
+// the private key.
+type Plugin struct{}
+
+// New creates the test signer plugin.
+func New() *Plugin {
+	return &Plugin{}
+}
+
+// GetMetadata returns the metadata of the plugin.
+func (p *Plugin) GetMetadata(ctx context.Context, req *plugin.GetMetadataRequest) (*plugin.GetMetadataResponse, error) {
+	return &plugin.GetMetadataResponse{
+		Name:                      Name,
+		Description:               "ace-dt test signer plugin backed by a local key file",
+		Version:                   "1.0.0",
+		URL:                       "https://github.com/act3-ai/data-tool",
+		SupportedContractVersions: []string{plugin.ContractVersion},
+		Capabilities:              []plugin.Capability{plugin.CapabilitySignatureGenerator},
+	}, nil
+}
+
+// DescribeKey returns the key spec of the key.
+func (p *Plugin) DescribeKey(ctx context.Context, req *plugin.DescribeKeyRequest) (*plugin.DescribeKeyResponse, error) {
+	key, err := loadKey(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	keySpec, err := encodeKeySpec(key)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.DescribeKeyResponse{
+		KeyID:   req.KeyID,
+		KeySpec: keySpec,
+	}, nil
+}
+
+// GenerateSignature signs the payload with the key, returning the raw signature and the certificate chain of the key.
+func (p *Plugin) GenerateSignature(ctx context.Context, req *plugin.GenerateSignatureRequest) (*plugin.GenerateSignatureResponse, error) {
+	key, err := loadKey(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	keySpec, err := encodeKeySpec(key)
+	if err != nil {
+		return nil, err
+	}
+	if keySpec != req.KeySpec {
+		return nil, plugin.NewValidationErrorf("key spec %s does not match the key %s", req.KeySpec, keySpec)
+	}
+
+	certPath := req.PluginConfig[ConfigCertificate]
+	if certPath == "" {
+		certPath = strings.TrimSuffix(req.KeyID, filepath.Ext(req.KeyID)) + ".crt"
+	}
+	certs, err := notationx509.ReadCertificateFile(certPath)
+	if err != nil {
+		return nil, plugin.NewGenericErrorf("reading certificate chain %s: %v", certPath, err)
+	}
+	certChain := make([][]byte, 0, len(certs))
+	for _, cert := range certs {
+		certChain = append(certChain, cert.Raw)
+	}
+
+	sig, err := signRaw(key, req.Hash, req.Payload)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := signingAlgorithm(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin.GenerateSignatureResponse{
+		KeyID:            req.KeyID,
+		Signature:        sig,
+		SigningAlgorithm: alg,
+		CertificateChain: certChain,
+	}, nil
+}
+
+// GenerateEnvelope is not supported, the plugin only generates raw signatures.
+func (p *Plugin) GenerateEnvelope(ctx context.Context, req *plugin.GenerateEnvelopeRequest) (*plugin.GenerateEnvelopeResponse, error) {
+	return nil, plugin.NewUnsupportedError("generate-envelope")
+}
+
+// VerifySignature is not supported, the plugin is only a signer.
+func (p *Plugin) VerifySignature(ctx context.Context, req *plugin.VerifySignatureRequest) (*plugin.VerifySignatureResponse, error) {
+	return nil, plugin.NewUnsupportedError("verify-signature")
+}
+
+// loadKey loads the ECDSA private key at path.
+func loadKey(path string) (*ecdsa.PrivateKey, error) {
+	key, err := notationx509.ReadPrivateKeyFile(path)
+	if err != nil {
+		return nil, plugin.NewGenericErrorf("reading private key %s: %v", path, err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, plugin.NewValidationErrorf("private key %s is not ecdsa", path)
+	}
+	return ecdsaKey, nil
+}
+
+// encodeKeySpec returns the plugin key spec of the key.
+func encodeKeySpec(key *ecdsa.PrivateKey) (plugin.KeySpec, error) {
+	keySpec, err := proto.EncodeKeySpec(signature.KeySpec{Type: signature.KeyTypeEC, Size: key.Curve.Params().BitSize})
+	if err != nil {
+		return "", plugin.NewValidationErrorf("unsupported key: %v", err)
+	}
+	return keySpec, nil
+}
+
+// signRaw signs the digest of payload, returning the signature as the concatenation of r and s, each padded to the
+// key size, as required for JWS and COSE envelopes.
+func signRaw(key *ecdsa.PrivateKey, hashAlg plugin.HashAlgorithm, payload []byte) ([]byte, error) {
+	var hash crypto.Hash
+	switch hashAlg {
+	case plugin.HashAlgorithmSHA256:
+		hash = crypto.SHA256
+	case plugin.HashAlgorithmSHA384:
+		hash = crypto.SHA384
+	case plugin.HashAlgorithmSHA512:
+		hash = crypto.SHA512
+	default:
+		return nil, plugin.NewValidationErrorf("unsupported hash algorithm %s", hashAlg)
+	}
+	h := hash.New()
+	h.Write(payload)
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, h.Sum(nil))
+	if err != nil {
+		return nil, plugin.NewGenericErrorf("signing payload: %v", err)
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	sig := make([]byte, 2*size)
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+	return sig, nil
+}
+
+// signingAlgorithm returns the ECDSA signing algorithm for the hash algorithm.
+func signingAlgorithm(hashAlg plugin.HashAlgorithm) (plugin.SignatureAlgorithm, error) {
+	switch hashAlg {
+	case plugin.HashAlgorithmSHA256:
+		return plugin.SignatureAlgorithmECDSA_SHA256, nil
+	case plugin.HashAlgorithmSHA384:
+		return plugin.SignatureAlgorithmECDSA_SHA384, nil
+	case plugin.HashAlgorithmSHA512:
+		return plugin.SignatureAlgorithmECDSA_SHA512, nil
+	default:
+		return "", plugin.NewValidationErrorf("unsupported hash algorithm %s", hashAlg)
+	}
+}
@@ -27,38 +27,100 @@ import (
 func Pull(ctx context.Context, btlPath string, source content.ReadOnlyGraphStorage, subject ocispec.Descriptor) error {
 	log := logger.FromContext(ctx)
 
+	sigsHandler, err := FetchSignatures(ctx, source, subject)
+	if err != nil {
+		return err
+	}
+	if len(sigsHandler.SigManifests) == 0 {
+		return nil
+	}
+	sigsHandler.LocalPath = bottle.SigDir(btlPath)
+
+	log.InfoContext(ctx, "Writing signatures to disk")
+	if err := sigsHandler.WriteDisk(subject.Digest); err != nil {
+		return fmt.Errorf("writing signatures: %w", err)
+	}
+
+	return nil
+}
+
+// FetchSignatures fetches all signatures referring to the subject descriptor from source, returning a handler for
+// the signatures held in memory.  The handler has no local path, so it is not written to disk unless one is set.
+func FetchSignatures(ctx context.Context, source content.ReadOnlyGraphStorage, subject ocispec.Descriptor) (*NotarySignatures, error) {
+	log := logger.FromContext(ctx)
+
 	log.InfoContext(ctx, "Discovering Bottle Signatures")
 	sigManDescs, err := registry.Referrers(ctx, source, subject, notationreg.ArtifactTypeNotation)
 	if err != nil {
-		return fmt.Errorf("resolving bottle signature referrers: %w", err)
+		return nil, fmt.Errorf("resolving bottle signature referrers: %w", err)
 	}
 
+	sigsHandler := &NotarySignatures{
+		Subject:      subject,
+		SigManifests: make([]SigsManifestHandler, 0, len(sigManDescs)),
+		HashFunc:     crypto.SHA256, // TODO: Cryptographic agility
+	}
 	if len(sigManDescs) == 0 {
 		log.InfoContext(ctx, "No signatures Found")
-		return nil
+		return sigsHandler, nil
 	}
 	log.InfoContext(ctx, "Fetching signatures", "signaturesFound", len(sigManDescs))
 
-	sigsHandler := NotarySignatures{
-		Subject:   subject,
-		HashFunc:  crypto.SHA256, // TODO: Cryptographic agility
-		LocalPath: bottle.SigDir(btlPath),
-	}
-
 	for _, desc := range sigManDescs {
 		log.InfoContext(ctx, "Fetching signature", "sigManifestDigest", desc.Digest)
 		handler, err := fetchNotarySig(ctx, source, desc)
 		if err != nil {
-			return fmt.Errorf("fetching notary signature: %w", err)
+			return nil, fmt.Errorf("fetching notary signature: %w", err)
 		}
 		sigsHandler.SigManifests = append(sigsHandler.SigManifests, handler)
 	}
 
-	log.InfoContext(ctx, "Writing signatures to disk")
-	if err := sigsHandler.WriteDisk(subject.Digest); err != nil {
-		return fmt.Errorf("writing signatures: %w", err)
+	return sigsHandler, nil
+}
+
+// Push pushes the signatures held in memory to storage as referrers of the subject, such as signatures made by Sign
+// without a LocalPath.  Signatures already in storage are skipped.
+func (notarySigs *NotarySignatures) Push(ctx context.Context, storage content.Storage) error {
+	log := logger.V(logger.FromContext(ctx), 1)
+
+	for _, sigManifest := range notarySigs.SigManifests {
+		manifestDesc := sigManifest.GetManifestDescriptor()
+		exists, err := storage.Exists(ctx, manifestDesc)
+		if err != nil {
+			return fmt.Errorf("checking for signature manifest: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		// notation signature manifests have the empty config
+		if err := pushIfNotExist(ctx, storage, ocispec.DescriptorEmptyJSON, ocispec.DescriptorEmptyJSON.Data); err != nil {
+			return fmt.Errorf("pushing notation config: %w", err)
+		}
+		rawLayers := sigManifest.GetRawLayers()
+		for _, layerDesc := range sigManifest.GetLayerDescriptors() {
+			if err := pushIfNotExist(ctx, storage, layerDesc, rawLayers[layerDesc.Digest]); err != nil {
+				return fmt.Errorf("pushing signature: %w", err)
+			}
+		}
+
+		manifestRaw, err := sigManifest.GetManifestRaw()
+		if err != nil {
+			return fmt.Errorf("encoding signature manifest: %w", err)
+		}
+		if err := pushIfNotExist(ctx, storage, manifestDesc, manifestRaw); err != nil {
+			return fmt.Errorf("pushing signature manifest: %w", err)
+		}
+		log.InfoContext(ctx, "pushed signature manifest", "digest", manifestDesc.Digest, "subject", notarySigs.Subject.Digest)
 	}
+	return nil
+}
 
+// pushIfNotExist pushes data to storage, ignoring content that already exists.
+func pushIfNotExist(ctx context.Context, storage content.Pusher, desc ocispec.Descriptor, data []byte) error {
+	if err := storage.Push(ctx, desc, bytes.NewReader(data)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,155 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/cache"
+	"github.com/act3-ai/data-tool/internal/ref"
+	reg "github.com/act3-ai/data-tool/pkg/registry"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// ExportOptions are the options for copying a bottle into an OCI layout.
+type ExportOptions struct {
+	// Concurrency is the number of concurrent blob copies
+	Concurrency int
+
+	// Selector selects the parts included in the copy, all parts are included if nil
+	Selector bottle.PartSelectorFunc
+}
+
+// ExportBottle copies a committed local bottle, with its signatures, into dst and tags it.  Parts missing from the
+// cache, such as virtual parts, are copied from their known locations using gt.
+func ExportBottle(ctx context.Context, btl *bottle.Bottle, dst oras.Target, tag string, gt reg.ReadOnlyGraphTargeter,
+	opts ExportOptions,
+) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "preparing bottle referrers")
+	if err := withSignatures()(ctx, btl); err != nil {
+		return fmt.Errorf("preparing bottle referrers: %w", err)
+	}
+
+	log.InfoContext(ctx, "preparing bottle metadata")
+	if err := AddBottleMetadataToStore(ctx, btl); err != nil {
+		return fmt.Errorf("preparing bottle metadata: %w", err)
+	}
+
+	return copyBottle(ctx, btl, btl.GetCache(), btl.Manifest.GetManifestDescriptor(), dst, tag, opts,
+		preExport(btl, dst, gt))
+}
+
+// CopyBottle copies the bottle with the manifest descriptor, along with its signatures and other referrers, from src
+// to dst and tags it.  The bottle provides the part information used for part selection.
+func CopyBottle(ctx context.Context, btl *bottle.Bottle, src content.ReadOnlyGraphStorage, desc ocispec.Descriptor,
+	dst oras.Target, tag string, opts ExportOptions,
+) error {
+	return copyBottle(ctx, btl, src, desc, dst, tag, opts, nil)
+}
+
+func copyBottle(ctx context.Context, btl *bottle.Bottle, src content.ReadOnlyGraphStorage, desc ocispec.Descriptor,
+	dst oras.Target, tag string, opts ExportOptions, preCopy func(context.Context, ocispec.Descriptor) error,
+) error {
+	log := logger.FromContext(ctx)
+
+	extCopyOpts := oras.ExtendedCopyGraphOptions{
+		CopyGraphOptions: oras.CopyGraphOptions{
+			Concurrency:    opts.Concurrency,
+			PreCopy:        preCopy,
+			FindSuccessors: exportSuccessors(btl, desc, opts.Selector),
+		},
+	}
+
+	log.InfoContext(ctx, "copying bottle", "bottleID", btl.GetBottleID(), "manDescDigest", desc.Digest)
+	if err := oras.ExtendedCopyGraph(ctx, src, dst, desc, extCopyOpts); err != nil {
+		return fmt.Errorf("copying bottle: %w", err)
+	}
+
+	if err := dst.Tag(ctx, desc, tag); err != nil {
+		return fmt.Errorf("tagging bottle manifest: %w", err)
+	}
+	return nil
+}
+
+// exportSuccessors returns an oras.CopyGraphOptions FindSuccessors func, which leaves the parts of the bottle that are
+// not selected out of the copy.  Successors of other nodes, such as signatures, are not changed.
+func exportSuccessors(btl *bottle.Bottle, manDesc ocispec.Descriptor, selector bottle.PartSelectorFunc,
+) func(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	return func(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		successors, err := content.Successors(ctx, fetcher, desc)
+		if err != nil {
+			return nil, fmt.Errorf("finding successors for %s: %w", desc.Digest, err)
+		}
+		if selector == nil || desc.Digest != manDesc.Digest {
+			return successors, nil
+		}
+
+		selected := make([]ocispec.Descriptor, 0, len(successors))
+		for _, s := range successors {
+			if !bottle.IsLayer(s.MediaType) {
+				selected = append(selected, s)
+				continue
+			}
+			partInfo := btl.GetPartByLayerDescriptor(s)
+			if partInfo == nil {
+				return nil, fmt.Errorf("part referenced in manifest does not exist in bottle config: layer digest = %s", s.Digest)
+			}
+			if selector(partInfo) {
+				selected = append(selected, s)
+			} else {
+				logger.FromContext(ctx).InfoContext(ctx, "did not select part", "part", partInfo.GetName())
+			}
+		}
+		return selected, nil
+	}
+}
+
+// preExport returns an oras.CopyGraphOptions PreCopy func, which copies parts missing from the cache from their known
+// locations directly into dst.
+func preExport(btl *bottle.Bottle, dst content.Pusher, gt reg.ReadOnlyGraphTargeter) func(ctx context.Context, desc ocispec.Descriptor) error {
+	return func(ctx context.Context, desc ocispec.Descriptor) error {
+		log := logger.FromContext(ctx).With("digest", desc.Digest)
+
+		if !bottle.IsLayer(desc.MediaType) {
+			return nil
+		}
+		exists, err := btl.GetCache().Exists(ctx, desc)
+		switch {
+		case err != nil:
+			return fmt.Errorf("checking for descriptor in bottle datastore: %w", err)
+		case exists:
+			return nil
+		}
+
+		log.DebugContext(ctx, "part not found in cache, resolving sources")
+		sources := cache.LocateLayer(ctx, btl.BIC(), desc, ref.Ref{}, false)
+		errs := []error{fmt.Errorf("part %s is neither cached nor available from a known location", desc.Digest)}
+		for _, source := range sources {
+			srcRepo, err := gt.ReadOnlyGraphTarget(ctx, source.String())
+			if err != nil {
+				errs = append(errs, fmt.Errorf("configuring source repository '%s': %w", source, err))
+				continue
+			}
+			rc, err := srcRepo.Fetch(ctx, desc)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("fetching part from source '%s': %w", source, err))
+				continue
+			}
+			err = dst.Push(ctx, desc, rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("copying part from source '%s': %w", source, err)
+			}
+			log.DebugContext(ctx, "copied virtual part", "source", source.String())
+			return oras.SkipNode
+		}
+		return errors.Join(errs...)
+	}
+}
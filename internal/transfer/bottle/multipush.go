@@ -0,0 +1,379 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/cache"
+	"github.com/act3-ai/data-tool/internal/mirror/encoding"
+	"github.com/act3-ai/data-tool/internal/orasutil"
+	"github.com/act3-ai/data-tool/internal/ref"
+	dtreg "github.com/act3-ai/data-tool/internal/registry"
+	reg "github.com/act3-ai/data-tool/pkg/registry"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// PushResult is the outcome of pushing a bottle to one destination.
+type PushResult struct {
+	Reference string // destination bottle reference
+	Err       error  // nil if the bottle was pushed and tagged
+}
+
+// errNoDestinations stops a push once it failed for every destination.
+var errNoDestinations = errors.New("bottle push failed for all destinations")
+
+// PushBottles copies a bottle to several remote locations in one oras.ExtendedCopyGraph, reading each blob from the
+// cache once.  Blobs are streamed to one destination per registry and mounted from there into the other destinations
+// of the same registry, and parts with known locations in a destination registry are mounted instead of copied.  A
+// failure of one destination doesn't stop the push to the others, so the result of each destination is returned, in
+// the order of the references.  The error is for failures before pushing to any destination.
+func PushBottles(ctx context.Context, btl *bottle.Bottle, gt reg.GraphTargeter, references []string, pushCfg PushOptions, rOpts ...ReferrerOption) ([]PushResult, error) {
+	log := logger.FromContext(ctx)
+
+	if err := preparePush(ctx, btl, rOpts...); err != nil {
+		return nil, err
+	}
+
+	fan := &fanout{btl: btl, gt: gt}
+	for _, reference := range references {
+		dest := &destination{reference: reference, has: make(map[digest.Digest]bool)}
+		fan.dests = append(fan.dests, dest)
+
+		var err error
+		dest.ref, err = ref.FromString(reference)
+		if err != nil {
+			dest.err = fmt.Errorf("parsing destination repository reference: %w", err)
+			continue
+		}
+		dest.target, err = gt.GraphTarget(ctx, dest.ref.String())
+		if err != nil {
+			dest.err = fmt.Errorf("creating repository reference: %w", err)
+		}
+	}
+
+	extCopyOpts := oras.ExtendedCopyGraphOptions{
+		CopyGraphOptions: oras.CopyGraphOptions{
+			Concurrency: pushCfg.Concurrency,
+			PreCopy:     fan.preCopy, // virtual part handling
+		},
+	}
+
+	manDesc := btl.Manifest.GetManifestDescriptor()
+	if live := fan.live(); len(live) > 0 {
+		log.InfoContext(ctx, "pushing bottle", "bottleID", btl.GetBottleID(), "manDescDigest", manDesc.Digest, "destinations", len(live)) //nolint
+		if err := oras.ExtendedCopyGraph(ctx, btl.GetCache(), fan, manDesc, extCopyOpts); err != nil {
+			for _, dest := range fan.live() {
+				fan.fail(dest, fmt.Errorf("pushing bottle: %w", err))
+			}
+		}
+	}
+
+	results := make([]PushResult, len(fan.dests))
+	for i, dest := range fan.dests {
+		if dest.err == nil {
+			log.InfoContext(ctx, "tagging bottle manifest", "reference", dest.reference)
+			dest.err = tagDestination(ctx, gt, dest, manDesc)
+		}
+		if dest.err == nil {
+			// later pushes may mount the parts from this destination
+			for _, layer := range btl.Manifest.GetLayerDescriptors() {
+				cache.RecordLayerSource(ctx, btl.BIC(), layer, dest.ref)
+			}
+		}
+		results[i] = PushResult{Reference: dest.reference, Err: dest.err}
+	}
+	return results, nil
+}
+
+// tagDestination tags the bottle manifest in the destination repository.
+func tagDestination(ctx context.Context, gt reg.GraphTargeter, dest *destination, manDesc ocispec.Descriptor) error {
+	// resolve the endpoint if necessary
+	regRef, err := dtreg.ParseEndpointOrDefault(gt, dest.ref.String())
+	if err != nil {
+		return err
+	}
+	if err := dest.target.Tag(ctx, manDesc, regRef.String()); err != nil {
+		return fmt.Errorf("tagging bottle manifest: %w", err)
+	}
+	return nil
+}
+
+// destination is a repository a bottle is pushed to by PushBottles.
+type destination struct {
+	reference string
+	ref       ref.Ref
+	target    oras.GraphTarget
+
+	err error                  // first failure, no more content is pushed after it
+	has map[digest.Digest]bool // known existence of content in the repository
+}
+
+// fanout is a content.Storage pushing content to all destinations that lack it.  A failure of one destination is
+// recorded with it instead of being returned, unless every destination failed.
+type fanout struct {
+	btl *bottle.Bottle
+	gt  reg.GraphTargeter
+
+	mu    sync.Mutex // guards the err and has fields of the destinations
+	dests []*destination
+}
+
+// live returns the destinations without failures.
+func (f *fanout) live() []*destination {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.DeleteFunc(slices.Clone(f.dests), func(d *destination) bool { return d.err != nil })
+}
+
+// failed returns true if the destination failed.
+func (f *fanout) failed(d *destination) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return d.err != nil
+}
+
+// fail records the failure of a destination.
+func (f *fanout) fail(d *destination, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// record records the outcome of pushing content to a destination.
+func (f *fanout) record(d *destination, desc ocispec.Descriptor, err error) {
+	if err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		f.fail(d, fmt.Errorf("pushing %s: %w", desc.Digest, err))
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d.has[desc.Digest] = true
+}
+
+// missing returns the destinations without failures that lack the content.
+func (f *fanout) missing(ctx context.Context, desc ocispec.Descriptor) []*destination {
+	var missing []*destination
+	for _, d := range f.live() {
+		f.mu.Lock()
+		has, known := d.has[desc.Digest]
+		f.mu.Unlock()
+		if !known {
+			var err error
+			has, err = d.target.Exists(ctx, desc)
+			if err != nil {
+				f.fail(d, fmt.Errorf("checking existence of %s: %w", desc.Digest, err))
+				continue
+			}
+			f.mu.Lock()
+			d.has[desc.Digest] = has
+			f.mu.Unlock()
+		}
+		if !has {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+// Exists implements content.Storage.  Content exists if every destination without failures has it.
+func (f *fanout) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, error) {
+	if len(f.live()) == 0 {
+		return false, errNoDestinations
+	}
+	return len(f.missing(ctx, desc)) == 0, nil
+}
+
+// Fetch implements content.Storage, reading the content from the bottle cache.
+func (f *fanout) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	return f.btl.GetCache().Fetch(ctx, desc) //nolint:wrapcheck
+}
+
+// Push implements content.Storage.  Blobs are mounted from known locations where possible, then streamed to one
+// destination per registry and mounted from there into the others.  Manifests are streamed to every destination.
+func (f *fanout) Push(ctx context.Context, desc ocispec.Descriptor, r io.Reader) error {
+	pending := f.missing(ctx, desc)
+	if encoding.IsManifest(desc.MediaType) {
+		f.stream(ctx, desc, r, pending)
+		return f.check()
+	}
+
+	pending = slices.DeleteFunc(pending, func(d *destination) bool {
+		return f.mount(ctx, d, desc, cache.LocateLayer(ctx, f.btl.BIC(), desc, d.ref, true))
+	})
+
+	var streamed, mounted []*destination
+	for _, d := range pending {
+		if slices.ContainsFunc(streamed, func(s *destination) bool { return s.ref.Match(d.ref, ref.RefMatchReg) }) {
+			mounted = append(mounted, d)
+		} else {
+			streamed = append(streamed, d)
+		}
+	}
+	f.stream(ctx, desc, r, streamed)
+
+	for _, d := range mounted {
+		i := slices.IndexFunc(streamed, func(s *destination) bool { return s.ref.Match(d.ref, ref.RefMatchReg) })
+		if f.failed(streamed[i]) {
+			// the blob isn't in the registry after all
+			f.record(d, desc, f.pushFromCache(ctx, d, desc))
+			continue
+		}
+		err := orasutil.Mount(ctx, d.target, desc, streamed[i].ref.MountRef(), func() (io.ReadCloser, error) {
+			return f.btl.GetCache().Fetch(ctx, desc) //nolint:wrapcheck
+		})
+		f.record(d, desc, err)
+	}
+	return f.check()
+}
+
+// check returns errNoDestinations once every destination failed.
+func (f *fanout) check() error {
+	if len(f.live()) == 0 {
+		return errNoDestinations
+	}
+	return nil
+}
+
+// pushFromCache pushes content from the bottle cache to the destination.
+func (f *fanout) pushFromCache(ctx context.Context, d *destination, desc ocispec.Descriptor) error {
+	rc, err := f.btl.GetCache().Fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("fetching from cache: %w", err)
+	}
+	defer rc.Close()
+	return d.target.Push(ctx, desc, rc) //nolint:wrapcheck
+}
+
+// mount mounts the blob into the destination from the first source repository in the same registry that has it.
+// Returns true once mounted.
+func (f *fanout) mount(ctx context.Context, d *destination, desc ocispec.Descriptor, sources []ref.Ref) bool {
+	log := logger.FromContext(ctx).With("digest", desc.Digest, "destination", d.reference)
+	for _, source := range sources {
+		// without getContent the blob is fetched from the source repository if the registry doesn't mount it
+		if err := orasutil.Mount(ctx, d.target, desc, source.MountRef(), nil); err != nil {
+			log.DebugContext(ctx, "cross-repo mount failed", "source", source.String(), "error", err)
+			continue
+		}
+		log.DebugContext(ctx, "mounted part", "source", source.String())
+		f.record(d, desc, nil)
+		return true
+	}
+	return false
+}
+
+// stream pushes the content to the destinations concurrently, reading it once.
+func (f *fanout) stream(ctx context.Context, desc ocispec.Descriptor, r io.Reader, dests []*destination) {
+	switch len(dests) {
+	case 0:
+		return
+	case 1:
+		f.record(dests[0], desc, dests[0].target.Push(ctx, desc, r))
+		return
+	}
+
+	var wg sync.WaitGroup
+	w := &fanoutWriter{writers: make([]*io.PipeWriter, len(dests))}
+	for i, d := range dests {
+		pr, pw := io.Pipe()
+		w.writers[i] = pw
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := d.target.Push(ctx, desc, pr)
+			// unblock the writer if the push stopped reading early
+			pr.CloseWithError(err)
+			f.record(d, desc, err)
+		}()
+	}
+
+	_, err := io.Copy(w, r)
+	for _, pw := range w.writers {
+		pw.CloseWithError(err) // EOF if reading succeeded
+	}
+	wg.Wait()
+}
+
+// fanoutWriter writes to all writers, ignoring the writers that fail.
+type fanoutWriter struct {
+	writers []*io.PipeWriter
+}
+
+// Write implements io.Writer.  It only fails once all writers failed.
+func (w *fanoutWriter) Write(p []byte) (int, error) {
+	var err error
+	live := 0
+	for _, pw := range w.writers {
+		if _, err = pw.Write(p); err != nil {
+			continue
+		}
+		live++
+	}
+	if live == 0 {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// preCopy handles virtual parts, which are not in the cache.  They are mounted from their known locations in the
+// destination registry, or copied from another registry, for each destination separately.
+func (f *fanout) preCopy(ctx context.Context, desc ocispec.Descriptor) error {
+	log := logger.FromContext(ctx).With("digest", desc.Digest)
+
+	if !bottle.IsLayer(desc.MediaType) {
+		return nil
+	}
+
+	// prefer copying from cache over another registry
+	exists, err := f.btl.GetCache().Exists(ctx, desc)
+	switch {
+	case err != nil:
+		return fmt.Errorf("checking for descriptor in bottle datastore: %w", err)
+	case exists:
+		log.DebugContext(ctx, "part found in cache, resuming copy from cache")
+		return nil
+	}
+
+	for _, d := range f.missing(ctx, desc) {
+		sources := cache.LocateLayer(ctx, f.btl.BIC(), desc, d.ref, false)
+		local := slices.DeleteFunc(slices.Clone(sources), func(source ref.Ref) bool { return !d.ref.Match(source, ref.RefMatchReg) })
+		if f.mount(ctx, d, desc, local) {
+			continue
+		}
+
+		errs := []error{fmt.Errorf("virtual part %s not found in any known location", desc.Digest)}
+		for _, source := range sources {
+			if d.ref.Match(source, ref.RefMatchReg) {
+				continue
+			}
+			log.DebugContext(ctx, "attempting cross-registry copy of virtual part", "source", source.String(), "destination", d.reference)
+			err := copyFromSource(ctx, f.gt, source, d.target, desc)
+			if err == nil {
+				errs = nil
+				break
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) > 0 {
+			f.fail(d, errors.Join(errs...))
+			continue
+		}
+		f.record(d, desc, nil)
+	}
+
+	if err := f.check(); err != nil {
+		return err
+	}
+	return oras.SkipNode
+}
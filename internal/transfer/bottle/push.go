@@ -10,14 +10,18 @@ import (
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/act3-ai/data-telemetry/v3/pkg/types"
 
-	"github.com/act3-ai/bottle-schema/pkg/mediatype"
 	"github.com/act3-ai/data-tool/internal/bottle"
 	"github.com/act3-ai/data-tool/internal/cache"
 	"github.com/act3-ai/data-tool/internal/ref"
 	dtreg "github.com/act3-ai/data-tool/internal/registry"
 	sigcustom "github.com/act3-ai/data-tool/internal/sign"
 	reg "github.com/act3-ai/data-tool/pkg/registry"
+	telem "github.com/act3-ai/data-tool/pkg/telemetry"
 	"github.com/act3-ai/go-common/pkg/logger"
 )
 
@@ -26,19 +30,8 @@ import (
 func PushBottle(ctx context.Context, btl *bottle.Bottle, gt reg.GraphTargeter, reference string, pushCfg PushOptions, rOpts ...ReferrerOption) error {
 	log := logger.FromContext(ctx)
 
-	// prepare referrers
-	log.InfoContext(ctx, "preparing bottle referrers")
-	rOpts = append(rOpts, withSignatures()) // always push with signatures
-	for _, o := range rOpts {
-		if err := o(ctx, btl); err != nil {
-			return fmt.Errorf("preparing bottle referrers: %w", err)
-		}
-	}
-
-	// prep bottle, parts should have already been prepped via commit
-	log.InfoContext(ctx, "preparing bottle metadata")
-	if err := AddBottleMetadataToStore(ctx, btl); err != nil {
-		return fmt.Errorf("preparing bottle metadata: %w", err)
+	if err := preparePush(ctx, btl, rOpts...); err != nil {
+		return err
 	}
 
 	destRef, err := ref.FromString(reference)
@@ -80,6 +73,86 @@ func PushBottle(ctx context.Context, btl *bottle.Bottle, gt reg.GraphTargeter, r
 	return nil
 }
 
+// CheckNoOverwrite returns an error if the bottle reference already exists.
+func CheckNoOverwrite(ctx context.Context, gt reg.GraphTargeter, reference string) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "checking for existing bottles", "reference", reference)
+	destRef, err := ref.FromString(reference)
+	if err != nil {
+		return fmt.Errorf("parsing destination repository reference: %w", err)
+	}
+	repo, err := gt.GraphTarget(ctx, destRef.String())
+	if err != nil {
+		return fmt.Errorf("creating repository reference: %w", err)
+	}
+	regRef, err := dtreg.ParseEndpointOrDefault(gt, destRef.String())
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.Resolve(ctx, regRef.String())
+	switch {
+	case errors.Is(err, errdef.ErrNotFound):
+		return nil
+	case err != nil:
+		// unwanted error
+		return fmt.Errorf("checking if reference exists: %w", err)
+	default:
+		// reference already exists
+		return fmt.Errorf("bottle reference %s already exists. Please choose another repository or tag before pushing", reference)
+	}
+}
+
+// NotifyPush sends a push event for each of the references the bottle was pushed to, to the telemetry hosts of the
+// adapter.  Returns the URLs of the bottle on the telemetry hosts.
+func NotifyPush(ctx context.Context, btl *bottle.Bottle, adapter *telem.Adapter, references []string) ([]string, error) {
+	rawManifest, err := btl.Manifest.GetManifestRaw()
+	if err != nil {
+		return nil, fmt.Errorf("getting bottle manifest: %w", err)
+	}
+
+	var telemURLs []string
+	for _, reference := range references {
+		r, err := ref.FromString(reference)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bottle reference %s: %w", reference, err)
+		}
+
+		event := adapter.NewEvent(r.String(), rawManifest, types.EventPush)
+		urls, err := adapter.NotifyTelemetry(ctx, btl.GetCache(), btl.Manifest.GetManifestDescriptor(), btl.GetPath(), event)
+		if err != nil {
+			return nil, fmt.Errorf("notifying telemetry: %w", err)
+		}
+		// each destination gets an event, but the bottle has the same URLs
+		if telemURLs == nil {
+			telemURLs = urls
+		}
+	}
+	return telemURLs, nil
+}
+
+// preparePush adds the bottle metadata and referrers to the bottle cache, to be copied from there.
+func preparePush(ctx context.Context, btl *bottle.Bottle, rOpts ...ReferrerOption) error {
+	log := logger.FromContext(ctx)
+
+	// prepare referrers
+	log.InfoContext(ctx, "preparing bottle referrers")
+	rOpts = append(rOpts, withSignatures()) // always push with signatures
+	for _, o := range rOpts {
+		if err := o(ctx, btl); err != nil {
+			return fmt.Errorf("preparing bottle referrers: %w", err)
+		}
+	}
+
+	// prep bottle, parts should have already been prepped via commit
+	log.InfoContext(ctx, "preparing bottle metadata")
+	if err := AddBottleMetadataToStore(ctx, btl); err != nil {
+		return fmt.Errorf("preparing bottle metadata: %w", err)
+	}
+	return nil
+}
+
 // ReferrerOption prepares a bottle's referrers for transfer via oras.ExtendedCopyGraph.
 type ReferrerOption func(ctx context.Context, btl *bottle.Bottle) error
 
@@ -149,7 +222,7 @@ func pushMountFrom(btl *bottle.Bottle, dest ref.Ref) func(ctx context.Context, d
 		log := logger.FromContext(ctx).With("digest", desc.Digest)
 
 		bicSources := cache.LocateLayer(ctx, btl.BIC(), desc, dest, true)
-		if !mediatype.IsLayer(desc.MediaType) && len(bicSources) < 1 {
+		if !bottle.IsLayer(desc.MediaType) && len(bicSources) < 1 {
 			// no sources available for cross-repo mounting
 			return []string{}, nil
 		}
@@ -185,7 +258,7 @@ func prePush(btl *bottle.Bottle, dest ref.Ref, gt reg.GraphTargeter) func(ctx co
 	return func(ctx context.Context, desc ocispec.Descriptor) error {
 		log := logger.FromContext(ctx).With("digest", desc.Digest)
 
-		if !mediatype.IsLayer(desc.MediaType) {
+		if !bottle.IsLayer(desc.MediaType) {
 			return nil
 		}
 
@@ -210,38 +283,19 @@ func prePush(btl *bottle.Bottle, dest ref.Ref, gt reg.GraphTargeter) func(ctx co
 				// virtual part is from the same registry, and should have already been handled by the MountFrom func
 				continue
 			}
-			src := source.String()
 			// ensure we've attempted to copy from another registry at least once
-			log.DebugContext(ctx, "attempting cross-registry copy of virtual part", "source", src)
+			log.DebugContext(ctx, "attempting cross-registry copy of virtual part", "source", source.String())
 
-			// connect to source & dest
-			srcRepo, err := gt.GraphTarget(ctx, src)
-			if err != nil {
-				errs = append(errs, fmt.Errorf("configuring source repository '%s': %w", src, err))
-				continue
-			}
 			destRepo, err := gt.GraphTarget(ctx, dest.RepoString())
 			if err != nil {
 				// should be impossible, as the calling fn has already successfully connected to the desintation
 				return fmt.Errorf("configuring destination repository: %w", err)
 			}
-
-			// copy from source to dest
-			rc, err := srcRepo.Fetch(ctx, desc)
-			if err != nil {
-				errs = append(errs, fmt.Errorf("fetching part from source '%s': %w", src, err))
-				continue
-			}
-			if err := destRepo.Push(ctx, desc, rc); err != nil {
-				errs = append(errs, fmt.Errorf("pushing part to destination: %w", err))
+			if err := copyFromSource(ctx, gt, source, destRepo, desc); err != nil {
+				errs = append(errs, err)
 				continue
 			}
 
-			err = rc.Close()
-			if err != nil {
-				return fmt.Errorf("closing part fetcher: %w", err)
-			}
-
 			log.DebugContext(ctx, "successfully completed cross-registry copy of virtual part")
 			return oras.SkipNode
 
@@ -250,3 +304,22 @@ func prePush(btl *bottle.Bottle, dest ref.Ref, gt reg.GraphTargeter) func(ctx co
 		return errors.Join(errs...)
 	}
 }
+
+// copyFromSource copies a blob from a source repository to the destination.
+func copyFromSource(ctx context.Context, gt reg.GraphTargeter, source ref.Ref, dest content.Pusher, desc ocispec.Descriptor) error {
+	src := source.String()
+	srcRepo, err := gt.GraphTarget(ctx, src)
+	if err != nil {
+		return fmt.Errorf("configuring source repository '%s': %w", src, err)
+	}
+
+	rc, err := srcRepo.Fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("fetching part from source '%s': %w", src, err)
+	}
+	defer rc.Close()
+	if err := dest.Push(ctx, desc, rc); err != nil {
+		return fmt.Errorf("pushing part to destination: %w", err)
+	}
+	return nil
+}
@@ -55,6 +55,16 @@ type ConfigurationSpec struct {
 
 	// SigningKeys is a list of signing key metadata.
 	SigningKeys []SigningKey `json:"keys,omitempty"`
+
+	// EncryptionKeys is a list of keys for encrypting and decrypting bottle parts.
+	EncryptionKeys []EncryptionKey `json:"encryptionKeys,omitempty"`
+
+	// PushDestinations is a list of bottle references that bottles are pushed to when none are given
+	PushDestinations []string `json:"pushDestinations,omitempty"`
+
+	// VulnerabilityDBMaxAge is the maximum age of the vulnerability database used by "ace-dt security scan".  Unset
+	// or 0 accepts databases of any age.
+	VulnerabilityDBMaxAge *metav1.Duration `json:"vulnerabilityDBMaxAge,omitempty"`
 }
 
 // FIXME redact the telemetry config secrets
@@ -96,6 +106,20 @@ kind: Configuration
 #   userid: your-gitlab-username
 #   keyid: key-title
 
+# Encryption configuration
+# encryptionKeys:
+# - alias: exportControlled
+#   recipient: path/to/public.pem
+#   path: path/to/private.key
+
+# Push destinations, used by "ace-dt bottle push" when no bottle references are given
+# pushDestinations:
+# - registry.example.com/project/bottle:latest
+# - mirror.example.com/project/bottle:latest
+
+# VulnerabilityDBMaxAge fails security scans when the vulnerability database was built longer ago
+# vulnerabilityDBMaxAge: 168h
+
 # Registry configuration
 # registryConfig:
 #   registries:
@@ -135,6 +159,25 @@ type SigningKey struct {
 
 	// Title of the key as indicated by the api.
 	KeyID string `json:"keyid"`
+
+	// Plugin is the name of a notation signer plugin holding the key, used instead of Path.  The plugin executable
+	// notation-<plugin> is found in the notation plugin directory or on the PATH, and signs with the key KeyID.
+	Plugin string `json:"plugin,omitempty"`
+
+	// PluginConfig is passed to the signer plugin with each request.
+	PluginConfig map[string]string `json:"pluginConfig,omitempty"`
+}
+
+// EncryptionKey is a key for encrypting and decrypting bottle parts.
+type EncryptionKey struct {
+	// Alias is the unique user-defined name for the key, used to select recipients when committing.
+	Alias string `json:"alias,omitempty"`
+
+	// Path to the recipient used when encrypting, a PEM encoded public key or X.509 certificate.
+	RecipientPath string `json:"recipient,omitempty"`
+
+	// Path to the private key used when decrypting, optional if the key is only used for encrypting.
+	KeyPath string `json:"path,omitempty"`
 }
 
 // RegistryConfig holds the custom configuration data for registries and repositories.
@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	"github.com/act3-ai/data-telemetry/v3/pkg/apis/config.telemetry.act3-ace.io/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -58,8 +59,25 @@ func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	if in.SigningKeys != nil {
 		in, out := &in.SigningKeys, &out.SigningKeys
 		*out = make([]SigningKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EncryptionKeys != nil {
+		in, out := &in.EncryptionKeys, &out.EncryptionKeys
+		*out = make([]EncryptionKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.PushDestinations != nil {
+		in, out := &in.PushDestinations, &out.PushDestinations
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.VulnerabilityDBMaxAge != nil {
+		in, out := &in.VulnerabilityDBMaxAge, &out.VulnerabilityDBMaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
@@ -72,6 +90,21 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionKey) DeepCopyInto(out *EncryptionKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionKey.
+func (in *EncryptionKey) DeepCopy() *EncryptionKey {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EndpointConfig) DeepCopyInto(out *EndpointConfig) {
 	*out = *in
@@ -151,6 +184,13 @@ func (in *RegistryConfig) DeepCopy() *RegistryConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SigningKey) DeepCopyInto(out *SigningKey) {
 	*out = *in
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKey.
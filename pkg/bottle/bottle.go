@@ -0,0 +1,260 @@
+// Package bottle provides a library for authoring bottles and publishing them to an OCI registry.  It is the
+// programmatic equivalent of the ace-dt bottle init, metadata editing, commit, and push commands, and follows the
+// same semantics.  Pulling bottles is provided by the pkg/transfer/bottle package.
+package bottle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/opencontainers/go-digest"
+	v1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+
+	cfgdef "github.com/act3-ai/bottle-schema/pkg/apis/data.act3-ace.io/v1"
+	"github.com/act3-ai/bottle-schema/pkg/mediatype"
+	ibottle "github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/util"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Author describes an author of a bottle.
+type Author = cfgdef.Author
+
+// Metric describes a metric recorded in a bottle.
+type Metric = cfgdef.Metric
+
+// Source describes a source of the data in a bottle.
+type Source = cfgdef.Source
+
+// Bottle is a bottle in a local directory.  Metadata changes are made in memory and are written to the bottle
+// directory by Save, Commit, or Push.
+type Bottle struct {
+	btl       *ibottle.Bottle
+	cachePath string
+}
+
+type options struct {
+	cachePath string
+	force     bool
+}
+
+// Option configures how a bottle is initialized or loaded.
+type Option func(o *options)
+
+// WithCachePath sets the path of the blob cache used when committing and pushing.  The default is the
+// ace-dt cache directory within the user's cache directory.
+func WithCachePath(path string) Option {
+	return func(o *options) {
+		o.cachePath = path
+	}
+}
+
+// WithForce recreates the bottle metadata when initializing a directory that already contains a bottle.
+func WithForce() Option {
+	return func(o *options) {
+		o.force = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		cachePath: filepath.Join(xdg.CacheHome, "ace", "dt"),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Init initializes a bottle in dir, creating the directory if it does not exist, and discovers its parts.
+func Init(ctx context.Context, dir string, opts ...Option) (*Bottle, error) {
+	o := newOptions(opts)
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving bottle path to absolute path: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, fmt.Errorf("creating bottle directory %s: %w", dir, err)
+	}
+	if err := ibottle.CreateBottle(dir, o.force); err != nil {
+		return nil, fmt.Errorf("could not create bottle config at path %s: %w", dir, err)
+	}
+
+	btl, err := ibottle.NewBottle(
+		ibottle.WithLocalPath(dir),
+		ibottle.WithCachePath(o.cachePath),
+		ibottle.WithBlobInfoCache(o.cachePath),
+	)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	b, err := newBottle(ctx, btl, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Save(ctx); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Load loads the bottle containing dir and discovers new, changed, and removed parts.
+func Load(ctx context.Context, dir string, opts ...Option) (*Bottle, error) {
+	root, err := ibottle.FindBottleRootDir(dir)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	o := newOptions(opts)
+
+	logger.FromContext(ctx).InfoContext(ctx, "loading bottle information from specified path", "path", root)
+
+	btl, err := ibottle.LoadBottle(root,
+		ibottle.WithCachePath(o.cachePath),
+		ibottle.WithBlobInfoCache(o.cachePath),
+	)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return newBottle(ctx, btl, o)
+}
+
+// newBottle discovers the parts of btl and loads their labels.
+func newBottle(ctx context.Context, btl *ibottle.Bottle, o options) (*Bottle, error) {
+	_, _, err := ibottle.InspectBottleFiles(ctx, btl, ibottle.Options{Visitor: ibottle.PrepareUpdatedParts(ctx, btl)})
+	if err != nil {
+		return nil, fmt.Errorf("failed while checking for updated bottle parts: %w", err)
+	}
+
+	// labels are read after all parts are known
+	if err := btl.LoadLocalLabels(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &Bottle{btl: btl, cachePath: o.cachePath}, nil
+}
+
+// Dir returns the root directory of the bottle.
+func (b *Bottle) Dir() string {
+	return b.btl.GetPath()
+}
+
+// BottleID returns the digest of the bottle configuration, or an empty digest if the bottle has not been committed.
+func (b *Bottle) BottleID() digest.Digest {
+	return b.btl.GetBottleID()
+}
+
+// SetDescription sets the description of the bottle.
+func (b *Bottle) SetDescription(description string) {
+	b.btl.SetDescription(description)
+}
+
+// AddAuthor adds an author to the bottle, replacing an author with the same name.
+func (b *Bottle) AddAuthor(author Author) error {
+	return b.btl.AddAuthorInfo(author) //nolint:wrapcheck
+}
+
+// RemoveAuthor removes the author with the given name.
+func (b *Bottle) RemoveAuthor(name string) error {
+	return b.btl.RemoveAuthorInfo(name) //nolint:wrapcheck
+}
+
+// SetLabel sets a label on the bottle.
+func (b *Bottle) SetLabel(key, value string) error {
+	if err := validateLabel(key, value); err != nil {
+		return err
+	}
+	b.btl.AddLabel(key, value)
+	return nil
+}
+
+// RemoveLabel removes a label from the bottle.
+func (b *Bottle) RemoveLabel(key string) error {
+	return b.btl.RemoveLabel(key) //nolint:wrapcheck
+}
+
+// SetPartLabel sets a label on a part.  The part path is relative to the bottle directory.
+func (b *Bottle) SetPartLabel(ctx context.Context, part, key, value string) error {
+	if err := validateLabel(key, value); err != nil {
+		return err
+	}
+	if err := b.btl.AddPartLabel(ctx, key, value, b.btl.NativePath(part)); err != nil {
+		return fmt.Errorf("failed to add label to part: %w", err)
+	}
+	return nil
+}
+
+// RemovePartLabel removes a label from a part.  The part path is relative to the bottle directory.
+func (b *Bottle) RemovePartLabel(ctx context.Context, part, key string) error {
+	return b.btl.RemovePartLabel(ctx, key, b.btl.NativePath(part)) //nolint:wrapcheck
+}
+
+// SetAnnotation sets an annotation on the bottle.
+func (b *Bottle) SetAnnotation(key, value string) {
+	b.btl.AddAnnotation(key, value)
+}
+
+// RemoveAnnotation removes an annotation from the bottle.
+func (b *Bottle) RemoveAnnotation(key string) error {
+	return b.btl.RemoveAnnotation(key) //nolint:wrapcheck
+}
+
+// AddMetric adds a metric to the bottle, replacing a metric with the same name.
+func (b *Bottle) AddMetric(metric Metric) error {
+	return b.btl.AddMetricInfo(metric) //nolint:wrapcheck
+}
+
+// RemoveMetric removes the metric with the given name.
+func (b *Bottle) RemoveMetric(name string) error {
+	return b.btl.RemoveMetricInfo(name) //nolint:wrapcheck
+}
+
+// AddSource adds a source to the bottle, replacing a source with the same name.
+func (b *Bottle) AddSource(source Source) error {
+	return b.btl.AddSourceInfo(source) //nolint:wrapcheck
+}
+
+// RemoveSource removes the source with the given name.
+func (b *Bottle) RemoveSource(name string) error {
+	return b.btl.RemoveSourceInfo(name) //nolint:wrapcheck
+}
+
+// SetArtifact adds or replaces a public artifact.  The path is relative to the bottle directory and must
+// refer to a file within the bottle.  If mediaType is empty, it is determined from the file name.
+func (b *Bottle) SetArtifact(name, path, mediaType string) error {
+	pth := b.btl.NativePath(path)
+	dgst, err := util.DigestFile(pth)
+	if err != nil {
+		return fmt.Errorf("artifact digest: %w", err)
+	}
+	if mediaType == "" {
+		mediaType = mediatype.DetermineType(pth)
+	}
+	return b.btl.AddArtifact(name, pth, mediaType, dgst) //nolint:wrapcheck
+}
+
+// RemoveArtifact removes the public artifact with the given path, relative to the bottle directory.
+func (b *Bottle) RemoveArtifact(path string) error {
+	return b.btl.RemoveArtifact(b.btl.NativePath(path)) //nolint:wrapcheck
+}
+
+// Save writes the bottle metadata to the bottle directory without archiving or digesting parts.
+func (b *Bottle) Save(ctx context.Context) error {
+	if err := ibottle.SaveUpdatesToSet(ctx, b.btl, ibottle.SaveOptions{
+		NoArchive: true,
+		NoDigest:  true,
+		NoCommit:  true,
+	}); err != nil {
+		return fmt.Errorf("failed while saving bottle at %s: %w", b.btl.GetPath(), err)
+	}
+	return nil
+}
+
+// validateLabel validates a label key and value.
+func validateLabel(key, value string) error {
+	return v1validation.ValidateLabels(map[string]string{key: value}, nil).ToAggregate() //nolint:wrapcheck
+}
@@ -0,0 +1,183 @@
+package bottle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ibottle "github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/data-tool/pkg/conf"
+	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
+)
+
+// setupRegistry starts an in-memory registry, returning its host and a configuration for accessing it over plain-http.
+func setupRegistry(t *testing.T, cachePath string) (string, *conf.Configuration) {
+	t.Helper()
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+
+	config := conf.New()
+	config.AddConfigOverride(
+		conf.WithRegistryConfig(v1alpha1.RegistryConfig{
+			Configs: map[string]v1alpha1.Registry{
+				u.Host: {Endpoints: []string{"http://" + u.Host}},
+			},
+		}),
+		conf.WithCachePath(cachePath),
+	)
+	return u.Host, config
+}
+
+func setupBottleDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.txt"), []byte("some data"), 0o666))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "model"), 0o777))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "model", "weights.bin"), []byte("weights"), 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Example"), 0o666))
+	return dir
+}
+
+func TestBottleMetadata(t *testing.T) {
+	ctx := context.Background()
+	dir := setupBottleDir(t)
+	cachePath := t.TempDir()
+
+	btl, err := Init(ctx, dir, WithCachePath(cachePath))
+	require.NoError(t, err)
+	assert.Equal(t, dir, btl.Dir())
+
+	btl.SetDescription("an example bottle")
+	require.NoError(t, btl.AddAuthor(Author{Name: "Jane Doe", Email: "jane@example.com"}))
+	require.NoError(t, btl.SetLabel("stage", "test"))
+	require.Error(t, btl.SetLabel("bad key!", "value"))
+	require.NoError(t, btl.SetPartLabel(ctx, "model", "kind", "weights"))
+	require.Error(t, btl.SetPartLabel(ctx, "missing.txt", "kind", "weights"))
+	require.NoError(t, btl.AddMetric(Metric{Name: "accuracy", Value: "0.9"}))
+	require.NoError(t, btl.AddSource(Source{Name: "origin", URI: "https://example.com/data"}))
+	require.NoError(t, btl.SetArtifact("readme", "README.md", ""))
+	btl.SetAnnotation("note", "value")
+	require.NoError(t, btl.Save(ctx))
+
+	// the metadata survives a reload
+	loaded, err := Load(ctx, filepath.Join(dir, "model"), WithCachePath(cachePath))
+	require.NoError(t, err)
+	def := loaded.btl.Definition
+	assert.Equal(t, "an example bottle", def.Description)
+	assert.Equal(t, "test", def.Labels["stage"])
+	assert.Equal(t, "value", def.Annotations["note"])
+	require.Len(t, def.Authors, 1)
+	assert.Equal(t, "Jane Doe", def.Authors[0].Name)
+	require.Len(t, def.Metrics, 1)
+	require.Len(t, def.Sources, 1)
+	require.Len(t, def.PublicArtifacts, 1)
+	assert.Equal(t, "README.md", def.PublicArtifacts[0].Path)
+	assert.NotEmpty(t, def.PublicArtifacts[0].MediaType)
+	assert.NotEmpty(t, def.PublicArtifacts[0].Digest)
+
+	require.NoError(t, loaded.RemoveLabel("stage"))
+	require.NoError(t, loaded.RemoveAnnotation("note"))
+	require.NoError(t, loaded.RemoveAuthor("Jane Doe"))
+	require.NoError(t, loaded.RemoveMetric("accuracy"))
+	require.NoError(t, loaded.RemoveSource("origin"))
+	require.NoError(t, loaded.RemoveArtifact("README.md"))
+	require.NoError(t, loaded.RemovePartLabel(ctx, "model", "kind"))
+	require.NoError(t, loaded.Save(ctx))
+
+	loaded, err = Load(ctx, dir, WithCachePath(cachePath))
+	require.NoError(t, err)
+	def = loaded.btl.Definition
+	assert.Empty(t, def.Labels)
+	assert.Empty(t, def.Authors)
+	assert.Empty(t, def.Metrics)
+	assert.Empty(t, def.Sources)
+	assert.Empty(t, def.PublicArtifacts)
+}
+
+func TestBottlePush(t *testing.T) {
+	ctx := context.Background()
+	dir := setupBottleDir(t)
+	cachePath := t.TempDir()
+	host, config := setupRegistry(t, cachePath)
+	reference := host + "/sdk/example:v1"
+
+	btl, err := Init(ctx, dir, WithCachePath(cachePath))
+	require.NoError(t, err)
+	require.NoError(t, btl.SetLabel("stage", "test"))
+	require.NoError(t, btl.SetPartLabel(ctx, "model", "kind", "weights"))
+
+	require.Error(t, btl.Commit(ctx, CommitOptions{Fidelity: "bogus"}))
+	require.NoError(t, btl.Push(ctx, config, reference, PushOptions{}))
+	bottleID := btl.BottleID()
+	assert.NotEmpty(t, bottleID)
+
+	// pushing again is rejected when overwriting is disabled
+	err = btl.Push(ctx, config, reference, PushOptions{NoOverwrite: true})
+	require.ErrorContains(t, err, "already exists")
+
+	// pull the bottle back
+	src, desc, err := tbottle.Resolve(ctx, reference, config, tbottle.TransferOptions{})
+	require.NoError(t, err)
+	pullDir := t.TempDir()
+	require.NoError(t, tbottle.Pull(ctx, src, desc, pullDir, tbottle.PullOptions{
+		TransferOptions: tbottle.TransferOptions{CachePath: cachePath},
+	}))
+
+	data, err := os.ReadFile(filepath.Join(pullDir, "model", "weights.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, "weights", string(data))
+
+	pulled, err := Load(ctx, pullDir, WithCachePath(cachePath))
+	require.NoError(t, err)
+	assert.Equal(t, bottleID, pulled.BottleID())
+	assert.Equal(t, "test", pulled.btl.Definition.Labels["stage"])
+}
+
+func TestBottleCommitOptions(t *testing.T) {
+	ctx := context.Background()
+	dir := setupBottleDir(t)
+	cachePath := t.TempDir()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	recipient := filepath.Join(t.TempDir(), "recipient.pem")
+	require.NoError(t, os.WriteFile(recipient, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	btl, err := Init(ctx, dir, WithCachePath(cachePath))
+	require.NoError(t, err)
+
+	require.Error(t, btl.Commit(ctx, CommitOptions{Encryption: &Encryption{Parts: []string{"data.txt"}}}))
+	require.NoError(t, btl.Commit(ctx, CommitOptions{
+		Encryption: &Encryption{Recipients: []string{recipient}, Parts: []string{"data.txt"}},
+		Message:    "first commit",
+		Stats:      true,
+	}))
+
+	for _, part := range btl.btl.Parts {
+		assert.Equal(t, part.Name == "data.txt", part.Encryption != nil, "only the selected part is encrypted: %s", part.Name)
+		assert.NotNil(t, part.GetStats(), "part statistics are computed: %s", part.Name)
+	}
+
+	history, err := ibottle.ReadHistory(dir)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "first commit", history[0].Message)
+	assert.Equal(t, btl.BottleID(), history[0].BottleID)
+}
@@ -0,0 +1,107 @@
+package bottle_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+
+	"github.com/act3-ai/data-tool/pkg/apis/config.dt.act3-ace.io/v1alpha1"
+	"github.com/act3-ai/data-tool/pkg/bottle"
+	"github.com/act3-ai/data-tool/pkg/conf"
+)
+
+func Example() {
+	ctx := context.Background()
+
+	// a registry for the example, normally this is a remote registry
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		panic(err)
+	}
+
+	// the bottle directory and its data
+	dir, err := os.MkdirTemp("", "bottle-*")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "data.csv"), []byte("a,b\n1,2\n"), 0o666); err != nil {
+		panic(err)
+	}
+
+	cachePath, err := os.MkdirTemp("", "cache-*")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(cachePath)
+
+	btl, err := bottle.Init(ctx, dir, bottle.WithCachePath(cachePath))
+	if err != nil {
+		panic(err)
+	}
+
+	btl.SetDescription("An example dataset")
+	if err := btl.AddAuthor(bottle.Author{Name: "Jane Doe", Email: "jane@example.com"}); err != nil {
+		panic(err)
+	}
+	if err := btl.SetLabel("stage", "example"); err != nil {
+		panic(err)
+	}
+	if err := btl.SetPartLabel(ctx, "data.csv", "format", "csv"); err != nil {
+		panic(err)
+	}
+
+	// the configuration resolves registry endpoints and credentials, like the ace-dt configuration file
+	config := conf.New()
+	config.AddConfigOverride(conf.WithRegistryConfig(v1alpha1.RegistryConfig{
+		Configs: map[string]v1alpha1.Registry{
+			u.Host: {Endpoints: []string{"http://" + u.Host}}, // plain-http for the example registry
+		},
+	}))
+
+	if err := btl.Push(ctx, config, u.Host+"/example/dataset:v1", bottle.PushOptions{}); err != nil {
+		panic(err)
+	}
+	fmt.Println("pushed", btl.BottleID() != "")
+
+	// Output: pushed true
+}
+
+func ExampleLoad() {
+	ctx := context.Background()
+
+	dir, err := os.MkdirTemp("", "bottle-*")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := bottle.Init(ctx, dir); err != nil {
+		panic(err)
+	}
+
+	// edit the metadata of an existing bottle
+	btl, err := bottle.Load(ctx, dir)
+	if err != nil {
+		panic(err)
+	}
+	if err := btl.AddMetric(bottle.Metric{Name: "accuracy", Value: "0.93"}); err != nil {
+		panic(err)
+	}
+	if err := btl.AddSource(bottle.Source{Name: "upstream", URI: "https://example.com/data"}); err != nil {
+		panic(err)
+	}
+	if err := btl.Save(ctx); err != nil {
+		panic(err)
+	}
+	fmt.Println("saved")
+
+	// Output: saved
+}
@@ -0,0 +1,164 @@
+package bottle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	telemv1alpha2 "github.com/act3-ai/data-telemetry/v3/pkg/apis/config.telemetry.act3-ace.io/v1alpha2"
+
+	ibottle "github.com/act3-ai/data-tool/internal/bottle"
+	"github.com/act3-ai/data-tool/internal/encrypt"
+	tbtl "github.com/act3-ai/data-tool/internal/transfer/bottle"
+	"github.com/act3-ai/data-tool/pkg/registry"
+	telem "github.com/act3-ai/data-tool/pkg/telemetry"
+	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
+	"github.com/act3-ai/go-common/pkg/logger"
+)
+
+// Fidelity levels for archiving directory parts, see CommitOptions.
+const (
+	// FidelityNone archives directory parts with portable permissions, following symbolic links.
+	FidelityNone = ibottle.FidelityNone
+	// FidelityLinks preserves symbolic links, hard links, and exact permissions in directory parts.
+	FidelityLinks = ibottle.FidelityLinks
+	// FidelityXattrs additionally preserves extended attributes in directory parts.
+	FidelityXattrs = ibottle.FidelityXattrs
+)
+
+// CommitOptions configures a bottle commit.
+type CommitOptions struct {
+	// CompressionLevel is the zstd compression level for parts, one of "min", "normal" (default), or "max"
+	CompressionLevel string
+
+	// Fidelity is the fidelity level for directory parts.  An empty value keeps the level of each part.
+	Fidelity string
+
+	// NoDeprecate disables deprecation of the previously committed version of the bottle
+	NoDeprecate bool
+
+	// Encryption encrypts the selected parts for its recipients.  Nil keeps the encryption of each part.
+	Encryption *Encryption
+
+	// Message describes the commit in the local history of the bottle
+	Message string
+
+	// Stats computes the statistics of the parts, such as file counts, media types, and rows
+	Stats bool
+}
+
+// Encryption selects the parts to encrypt and the recipients they are encrypted for.
+type Encryption struct {
+	// Recipients are the PEM encoded public key, certificate, or private key files of the recipients.  No recipients
+	// removes the encryption of all parts.
+	Recipients []string
+
+	// Selectors are label selectors for the parts to encrypt
+	Selectors []string
+
+	// Parts are the names of the parts to encrypt.  All parts are encrypted if there are no selectors or parts.
+	Parts []string
+}
+
+// PushOptions configures a bottle push.
+type PushOptions struct {
+	CommitOptions
+
+	// Concurrency is the number of blobs pushed concurrently, zero uses the default
+	Concurrency int
+
+	// NoOverwrite fails the push if the reference already exists
+	NoOverwrite bool
+
+	// Telemetry are the telemetry hosts notified of the push, none by default
+	Telemetry []telemv1alpha2.Location
+
+	// TelemetryUserName is the user name reported to the telemetry hosts
+	TelemetryUserName string
+}
+
+// Commit archives, digests, and caches the parts of the bottle and saves its metadata.
+func (b *Bottle) Commit(ctx context.Context, options CommitOptions) error {
+	if err := ibottle.ValidateFidelity(options.Fidelity); err != nil {
+		return err //nolint:wrapcheck
+	}
+	encryptOpts, err := options.Encryption.encryptOptions(ctx)
+	if err != nil {
+		return err
+	}
+	return ibottle.Commit(ctx, b.btl, ibottle.CommitOptions{ //nolint:wrapcheck
+		CompressLevel: options.CompressionLevel,
+		Fidelity:      options.Fidelity,
+		Encryption:    encryptOpts,
+		NoDeprecate:   options.NoDeprecate,
+		Message:       options.Message,
+		Stats:         options.Stats,
+	})
+}
+
+// encryptOptions returns the bottle encryption options, or nil if the parts should keep their current encryption.
+func (e *Encryption) encryptOptions(ctx context.Context) (*ibottle.EncryptOptions, error) {
+	switch {
+	case e == nil:
+		return nil, nil
+	case len(e.Recipients) == 0 && (len(e.Selectors) > 0 || len(e.Parts) > 0):
+		return nil, errors.New("recipients are required for selecting parts to encrypt")
+	}
+
+	options := &ibottle.EncryptOptions{}
+	for _, path := range e.Recipients {
+		rcpt, err := encrypt.LoadRecipient(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading recipient %q: %w", path, err)
+		}
+		options.Recipients = append(options.Recipients, rcpt)
+	}
+	if len(e.Selectors) > 0 || len(e.Parts) > 0 {
+		selectorOpts := ibottle.PartSelectorOptions{Labels: e.Selectors, Names: e.Parts}
+		selector, err := selectorOpts.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating part selector: %w", err)
+		}
+		options.Selector = selector
+	}
+	return options, nil
+}
+
+// Push commits the bottle and pushes it, along with its signatures, to reference.  The telemetry hosts, if any, are
+// notified of the push.
+func (b *Bottle) Push(ctx context.Context, gt registry.GraphTargeter, reference string, options PushOptions) error {
+	log := logger.FromContext(ctx)
+
+	log.InfoContext(ctx, "committing bottle")
+	if err := b.Commit(ctx, options.CommitOptions); err != nil {
+		return err
+	}
+
+	if options.NoOverwrite {
+		if err := tbtl.CheckNoOverwrite(ctx, gt, reference); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	log.InfoContext(ctx, "pushing bottle with signatures")
+	pushOpts := tbtl.PushOptions{
+		TransferOptions: tbottle.TransferOptions{
+			Concurrency: options.Concurrency,
+			CachePath:   b.cachePath,
+		},
+	}
+	if err := tbtl.PushBottle(ctx, b.btl, gt, reference, pushOpts); err != nil {
+		return fmt.Errorf("pushing bottle and signatures: %w", err)
+	}
+
+	if len(options.Telemetry) == 0 {
+		return nil
+	}
+	adapter := telem.NewAdapter(ctx, options.Telemetry, options.TelemetryUserName)
+	urls, err := tbtl.NotifyPush(ctx, b.btl, adapter, []string{reference})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	log.InfoContext(ctx, "notified telemetry", "urls", urls)
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/opencontainers/go-digest"
@@ -325,6 +326,66 @@ func (a *Adapter) FindBottle(ctx context.Context, refSpec ref.Ref) ([]ref.Ref, e
 	return refList, nil
 }
 
+// GetBottleConfig returns the raw bottle config of the bottle with the bottle ID, as known by the telemetry host.  The
+// error wraps errdef.ErrNotFound if no telemetry host knows the bottle.
+func (a *Adapter) GetBottleConfig(ctx context.Context, bottleID digest.Digest) ([]byte, error) {
+	if a == nil {
+		return nil, errdef.ErrNotFound
+	}
+	cfgBytes, err := a.client.GetBottle(ctx, bottleID)
+	switch {
+	case isNotFound(err):
+		return nil, fmt.Errorf("retrieving bottle config: %w", errdef.ErrNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("retrieving bottle config: %w", err)
+	}
+	return cfgBytes, nil
+}
+
+// isNotFound returns true if the telemetry request failed because the object is unknown.  The telemetry client only
+// reports the status code of failed responses in the error message.
+func isNotFound(err error) bool {
+	return err != nil && (errors.Is(err, client.ErrNotFound) ||
+		strings.HasPrefix(err.Error(), fmt.Sprintf("failed loading: %d,", http.StatusNotFound)))
+}
+
+// walkBottlesBatchSize is the number of bottles requested at a time by WalkBottles.
+const walkBottlesBatchSize = 100
+
+// WalkBottles calls fn with the bottle ID and raw bottle config of every bottle known by the telemetry host, oldest
+// first.
+func (a *Adapter) WalkBottles(ctx context.Context, fn func(bottleID digest.Digest, cfgBytes []byte) error) error {
+	if a == nil {
+		return nil
+	}
+
+	seen := make(map[digest.Digest]bool)
+	var since time.Time
+	for {
+		entries, err := a.client.ListBottles(ctx, since, walkBottlesBatchSize)
+		if err != nil {
+			return fmt.Errorf("listing bottles: %w", err)
+		}
+		unseen := 0
+		for _, entry := range entries {
+			since = entry.CreatedAt
+			// bottles created at the same time as the last one of a batch are listed again
+			id := digest.FromBytes(entry.Data)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			unseen++
+			if err := fn(id, entry.Data); err != nil {
+				return err
+			}
+		}
+		if len(entries) < walkBottlesBatchSize || unseen == 0 {
+			return nil
+		}
+	}
+}
+
 // NewEvent creates a telemetry Event based on bottle metdata and an event action type.
 func (a *Adapter) NewEvent(location string, rawManifest []byte, action types.EventAction) types.Event {
 	loc := ref.RepoFromString(location)
@@ -477,7 +538,7 @@ func preCopyOnlyMetadata(ctx context.Context, desc ocispec.Descriptor) error {
 	case mediatype.IsBottleConfig(desc.MediaType):
 		// noop, copy bottle config
 		return nil
-	case mediatype.IsLayer(desc.MediaType):
+	case bottle.IsLayer(desc.MediaType):
 		return oras.SkipNode
 	default:
 		return fmt.Errorf("unexpected descriptor mediatype '%s'", desc.MediaType)
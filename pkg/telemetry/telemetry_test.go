@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/errdef"
 	"oras.land/oras-go/v2/registry/remote"
 
 	"github.com/act3-ai/bottle-schema/pkg/mediatype"
@@ -24,6 +26,7 @@ import (
 	"github.com/act3-ai/data-tool/pkg/conf"
 	tbottle "github.com/act3-ai/data-tool/pkg/transfer/bottle"
 	"github.com/act3-ai/go-common/pkg/logger"
+	"github.com/act3-ai/go-common/pkg/redact"
 )
 
 type DestHelper struct {
@@ -257,3 +260,27 @@ func pushManifest(ctx context.Context, target oras.Target, tag string) (ocispec.
 	}
 	return desc, nil
 }
+
+func TestGetBottleConfigNotFound(t *testing.T) {
+	ctx := context.Background()
+	status := http.StatusNotFound
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(status), status)
+	}))
+	defer s.Close()
+
+	adapter := NewAdapter(ctx, []telemv1alpha2.Location{{Name: "test", URL: redact.SecretURL(s.URL)}}, "")
+	bottleID := digest.FromString("bottle")
+
+	_, err := adapter.GetBottleConfig(ctx, bottleID)
+	if !errors.Is(err, errdef.ErrNotFound) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	// other failures are not reported as unknown bottles
+	status = http.StatusInternalServerError
+	_, err = adapter.GetBottleConfig(ctx, bottleID)
+	if err == nil || errors.Is(err, errdef.ErrNotFound) {
+		t.Errorf("expected the server error, got %v", err)
+	}
+}
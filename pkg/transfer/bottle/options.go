@@ -3,6 +3,9 @@
 package bottle
 
 import (
+	"crypto"
+
+	"github.com/act3-ai/data-tool/internal/archive"
 	"github.com/act3-ai/data-tool/internal/bottle"
 )
 
@@ -39,4 +42,25 @@ type PartSelectorOptions = bottle.PartSelectorOptions
 type PullOptions struct {
 	TransferOptions
 	PartSelectorOptions
+
+	// Optional, restores the extended attributes of parts committed with the xattrs fidelity level
+	RestoreXattrs bool
+
+	// Optional, writes blocks of zeros in extracted directory parts as holes
+	SparseFiles bool
+
+	// Optional, private keys for decrypting encrypted parts
+	DecryptionKeys []crypto.PrivateKey
+}
+
+// extractOptions returns the bottle options for extracting parts.
+func (p *PullOptions) extractOptions() bottle.BOption {
+	var options []archive.ExtractOption
+	if p.RestoreXattrs {
+		options = append(options, archive.WithRestoreXattrs())
+	}
+	if p.SparseFiles {
+		options = append(options, archive.WithSparseFiles())
+	}
+	return bottle.WithExtractOptions(options...)
 }
@@ -160,6 +160,8 @@ func pull(ctx context.Context, target content.ReadOnlyStorage, desc ocispec.Desc
 		bottle.WithCachePath(pullOpts.CachePath),
 		bottle.WithBlobInfoCache(pullOpts.CachePath),
 		bottle.WithVirtualParts,
+		pullOpts.extractOptions(),
+		bottle.WithDecryptionKeys(pullOpts.DecryptionKeys...),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("bottle initialization failed: %w", err)
@@ -317,7 +319,7 @@ func prePullParts(progress *ui.Progress) func(ctx context.Context, desc ocispec.
 			return oras.SkipNode // manifest already handled and we don't want to cache it
 		case mediatype.IsBottleConfig(desc.MediaType):
 			return oras.SkipNode // config already handled and shouldn't be in the successor list, i.e. reaching here should be impossible
-		case mediatype.IsLayer(desc.MediaType):
+		case bottle.IsLayer(desc.MediaType):
 			progress.Update(0, desc.Size)
 		default:
 			logger.FromContext(ctx).DebugContext(ctx, "unsupported mediatype encountered pre copy", "mediatype",
@@ -336,7 +338,7 @@ func postPull(progress *ui.Progress, btl *bottle.Bottle, btlPartMutex *sync.Mute
 			// noop
 		case mediatype.IsBottleConfig(desc.MediaType):
 			// noop
-		case mediatype.IsLayer(desc.MediaType):
+		case bottle.IsLayer(desc.MediaType):
 			btlPartMutex.Lock()
 			name := btl.GetPartByLayerDescriptor(desc).GetName()
 			btlPartMutex.Unlock()
@@ -384,7 +386,7 @@ func selectPartSuccessors(btl *bottle.Bottle, selector bottle.PartSelectorFunc)
 			case mediatype.IsBottleConfig(s.MediaType):
 				// do not select config, this should have already been handled
 				log.DebugContext(ctx, "removing config from successors")
-			case mediatype.IsLayer(s.MediaType):
+			case bottle.IsLayer(s.MediaType):
 				if selector == nil {
 					// skip selection if no selector was provided
 					continue